@@ -30,6 +30,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/internal/era"
+	"github.com/ethereum/go-ethereum/internal/era/execdb"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/internal/flags"
 	"github.com/ethereum/go-ethereum/params"
@@ -42,12 +43,12 @@ var app = flags.NewApp("go-ethereum era tool")
 var (
 	dirFlag = &cli.StringFlag{
 		Name:  "dir",
-		Usage: "directory storing all relevant era1 files",
+		Usage: "directory storing all relevant era1 and erae files",
 		Value: "eras",
 	}
 	networkFlag = &cli.StringFlag{
 		Name:  "network",
-		Usage: "network name associated with era1 files",
+		Usage: "network name associated with era1 and erae files",
 		Value: "mainnet",
 	}
 	eraSizeFlag = &cli.IntFlag{
@@ -80,7 +81,7 @@ var (
 	verifyCommand = &cli.Command{
 		Name:      "verify",
 		ArgsUsage: "<expected>",
-		Usage:     "verifies each era1 against expected accumulator root",
+		Usage:     "verifies each era1 and erae against expected accumulator root",
 		Action:    verify,
 	}
 )
@@ -105,7 +106,16 @@ func main() {
 	}
 }
 
-// block prints the specified block from an era1 store.
+// archive is the common interface of Era1 and EraE archives.
+type archive interface {
+	GetBlockByNumber(num uint64) (*types.Block, error)
+	Accumulator() (common.Hash, error)
+	Start() uint64
+	Count() uint64
+	Close() error
+}
+
+// block prints the specified block from an era store.
 func block(ctx *cli.Context) error {
 	num, err := strconv.ParseUint(ctx.Args().First(), 10, 64)
 	if err != nil {
@@ -113,7 +123,7 @@ func block(ctx *cli.Context) error {
 	}
 	e, err := open(ctx, num/uint64(ctx.Int(eraSizeFlag.Name)))
 	if err != nil {
-		return fmt.Errorf("error opening era1: %w", err)
+		return fmt.Errorf("error opening era: %w", err)
 	}
 	defer e.Close()
 	// Read block with number.
@@ -131,7 +141,7 @@ func block(ctx *cli.Context) error {
 	return nil
 }
 
-// info prints some high-level information about the era1 file.
+// info prints some high-level information about the era file.
 func info(ctx *cli.Context) error {
 	epoch, err := strconv.ParseUint(ctx.Args().First(), 10, 64)
 	if err != nil {
@@ -146,41 +156,75 @@ func info(ctx *cli.Context) error {
 	if err != nil {
 		return fmt.Errorf("error reading accumulator: %w", err)
 	}
-	td, err := e.InitialTD()
-	if err != nil {
-		return fmt.Errorf("error reading total difficulty: %w", err)
-	}
 	info := struct {
+		Format          string      `json:"format"`
 		Accumulator     common.Hash `json:"accumulator"`
-		TotalDifficulty *big.Int    `json:"totalDifficulty"`
+		TotalDifficulty *big.Int    `json:"totalDifficulty,omitempty"`
 		StartBlock      uint64      `json:"startBlock"`
 		Count           uint64      `json:"count"`
 	}{
-		acc, td, e.Start(), e.Count(),
+		Format:      "erae",
+		Accumulator: acc,
+		StartBlock:  e.Start(),
+		Count:       e.Count(),
+	}
+	if e1, ok := e.(*era.Era); ok {
+		info.Format = "era1"
+		if info.TotalDifficulty, err = e1.InitialTD(); err != nil {
+			return fmt.Errorf("error reading total difficulty: %w", err)
+		}
 	}
 	b, _ := json.MarshalIndent(info, "", "  ")
 	fmt.Println(string(b))
 	return nil
 }
 
-// open opens an era1 file at a certain epoch.
-func open(ctx *cli.Context, epoch uint64) (*era.Era, error) {
+// readDir lists the era1 and erae files in the configured directory, ordered by
+// epoch. The erae files must continue where the era1 files end. It returns the
+// epoch of the first file and the number of era1 files in the list.
+func readDir(ctx *cli.Context) (uint64, []string, int, error) {
 	var (
 		dir     = ctx.String(dirFlag.Name)
 		network = ctx.String(networkFlag.Name)
 	)
-	entries, err := era.ReadDir(dir, network)
+	era1s, err := era.ReadDir(dir, network)
 	if err != nil {
-		return nil, fmt.Errorf("error reading era dir: %w", err)
+		return 0, nil, 0, fmt.Errorf("error reading era dir: %w", err)
 	}
-	if epoch >= uint64(len(entries)) {
-		return nil, fmt.Errorf("epoch out-of-bounds: last %d, want %d", len(entries)-1, epoch)
+	first, eraes, err := execdb.ReadDir(dir, network)
+	if err != nil {
+		return 0, nil, 0, fmt.Errorf("error reading era dir: %w", err)
+	}
+	if len(era1s) > 0 {
+		if len(eraes) > 0 && first != uint64(len(era1s)) {
+			return 0, nil, 0, fmt.Errorf("erae files starting at epoch %d don't follow era1 files", first)
+		}
+		first = 0
 	}
-	return era.Open(filepath.Join(dir, entries[epoch]))
+	return first, append(era1s, eraes...), len(era1s), nil
 }
 
-// verify checks each era1 file in a directory to ensure it is well-formed and
-// that the accumulator matches the expected value.
+// open opens an era1 or erae file at a certain epoch.
+func open(ctx *cli.Context, epoch uint64) (archive, error) {
+	first, entries, era1s, err := readDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if epoch < first || epoch >= first+uint64(len(entries)) {
+		return nil, fmt.Errorf("epoch out-of-bounds: available [%d, %d), want %d", first, first+uint64(len(entries)), epoch)
+	}
+	var (
+		index = int(epoch - first)
+		path  = filepath.Join(ctx.String(dirFlag.Name), entries[index])
+	)
+	if index < era1s {
+		return era.Open(path)
+	}
+	return execdb.Open(path)
+}
+
+// verify checks each era1 and erae file in a directory to ensure it is
+// well-formed and that the accumulator matches the expected value.
 func verify(ctx *cli.Context) error {
 	if ctx.Args().Len() != 1 {
 		return errors.New("missing accumulators file")
@@ -193,18 +237,17 @@ func verify(ctx *cli.Context) error {
 
 	var (
 		dir      = ctx.String(dirFlag.Name)
-		network  = ctx.String(networkFlag.Name)
 		start    = time.Now()
 		reported = time.Now()
 	)
 
-	entries, err := era.ReadDir(dir, network)
+	_, entries, era1s, err := readDir(ctx)
 	if err != nil {
-		return fmt.Errorf("error reading %s: %w", dir, err)
+		return err
 	}
 
 	if len(entries) != len(roots) {
-		return errors.New("number of era1 files should match the number of accumulator hashes")
+		return errors.New("number of era files should match the number of accumulator hashes")
 	}
 
 	// Verify each epoch matches the expected root.
@@ -212,9 +255,17 @@ func verify(ctx *cli.Context) error {
 		// Wrap in function so defers don't stack.
 		err := func() error {
 			name := entries[i]
-			e, err := era.Open(filepath.Join(dir, name))
+			var (
+				e   archive
+				err error
+			)
+			if i < era1s {
+				e, err = era.Open(filepath.Join(dir, name))
+			} else {
+				e, err = execdb.Open(filepath.Join(dir, name))
+			}
 			if err != nil {
-				return fmt.Errorf("error opening era1 file %s: %w", name, err)
+				return fmt.Errorf("error opening era file %s: %w", name, err)
 			}
 			defer e.Close()
 			// Read accumulator and check against expected.
@@ -224,12 +275,18 @@ func verify(ctx *cli.Context) error {
 				return fmt.Errorf("invalid root %s: got %s, want %s", name, got, want)
 			}
 			// Recompute accumulator.
-			if err := checkAccumulator(e); err != nil {
-				return fmt.Errorf("error verify era1 file %s: %w", name, err)
+			switch e := e.(type) {
+			case *era.Era:
+				err = checkAccumulator(e)
+			case *execdb.Era:
+				_, err = e.Verify(trie.NewStackTrie(nil))
+			}
+			if err != nil {
+				return fmt.Errorf("error verify era file %s: %w", name, err)
 			}
 			// Give the user some feedback that something is happening.
 			if time.Since(reported) >= 8*time.Second {
-				fmt.Printf("Verifying Era1 files \t\t verified=%d,\t elapsed=%s\n", i, common.PrettyDuration(time.Since(start)))
+				fmt.Printf("Verifying Era files \t\t verified=%d,\t elapsed=%s\n", i, common.PrettyDuration(time.Since(start)))
 				reported = time.Now()
 			}
 			return nil
@@ -0,0 +1,330 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/trie/bintrie"
+	"github.com/ethereum/go-ethereum/trie/trienode"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/urfave/cli/v2"
+)
+
+// binaryFlushThreshold is the number of converted leaves after which the binary
+// tree is flushed into the database, bounding the memory used by the conversion.
+const binaryFlushThreshold = 1_000_000
+
+var (
+	binaryCommand = &cli.Command{
+		Name:        "bintrie",
+		Usage:       "A set of experimental binary tree (EIP-7864) commands",
+		Description: "",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "convert",
+				Usage:     "Convert the merkle state into a binary tree in a separate database",
+				ArgsUsage: "<destination> [<root>]",
+				Action:    convertBinary,
+				Flags:     slices.Concat(utils.NetworkFlags, utils.DatabaseFlags),
+				Description: `
+geth bintrie convert <destination> [<state-root>]
+will convert the merkle state with the given root into a binary tree, stored
+in a new database at the destination directory. The default conversion target
+is the HEAD state.
+
+As the binary tree is keyed by the addresses and storage slots, the preimages
+of the hashed trie keys must be available, i.e. the chain must have been
+processed with --cache.preimages.
+`,
+			},
+			{
+				Name:      "witness",
+				Usage:     "Generate the witness of an account in a converted binary tree",
+				ArgsUsage: "<database> <root> <address> [<slot> ...]",
+				Action:    binaryWitness,
+				Flags:     slices.Concat(utils.NetworkFlags, utils.DatabaseFlags),
+				Description: `
+geth bintrie witness <database> <root> <address> [<slot> ...]
+will generate the proof of the account header and the given storage slots in
+the binary tree database created by 'geth bintrie convert', and report the
+number of nodes and the total size of the witness.
+`,
+			},
+		},
+	}
+)
+
+// convertBinary converts the merkle state into a binary tree.
+func convertBinary(ctx *cli.Context) error {
+	if ctx.NArg() < 1 || ctx.NArg() > 2 {
+		return errors.New("need <destination> and optional <root> args")
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chaindb := utils.MakeChainDatabase(ctx, stack, true)
+	defer chaindb.Close()
+
+	srcdb := utils.MakeTrieDatabase(ctx, chaindb, false, true, false)
+	defer srcdb.Close()
+
+	var root common.Hash
+	if ctx.NArg() == 2 {
+		var err error
+		if root, err = parseRoot(ctx.Args().Get(1)); err != nil {
+			return err
+		}
+	} else {
+		head := rawdb.ReadHeadBlock(chaindb)
+		if head == nil {
+			return errors.New("no head block")
+		}
+		root = head.Root()
+	}
+	kvdb, err := openKeyValueStore(ctx, rawdb.DBPebble, ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	destdb := triedb.NewDatabase(rawdb.NewDatabase(kvdb), triedb.BinaryDefaults)
+	defer kvdb.Close()
+	defer destdb.Close()
+
+	binaryRoot, err := convertToBinary(chaindb, srcdb, root, destdb)
+	if err != nil {
+		return err
+	}
+	log.Info("Converted state into binary tree", "root", root, "binary", binaryRoot)
+	return nil
+}
+
+// binaryConverter inserts the merkle state into a binary tree, flushing the tree
+// into the destination database periodically.
+type binaryConverter struct {
+	db     *triedb.Database
+	tree   *bintrie.BinaryTrie
+	root   common.Hash
+	leaves int // Number of leaves inserted since the last flush
+	block  uint64
+}
+
+// flush commits the pending changes of the tree into the database and reopens
+// the tree from the new root, releasing the resolved nodes.
+func (c *binaryConverter) flush() error {
+	if c.leaves == 0 {
+		return nil
+	}
+	root, nodes := c.tree.Commit(false)
+	c.block++
+	if err := c.db.Update(root, c.root, c.block, trienode.NewWithNodeSet(nodes), triedb.NewStateSet()); err != nil {
+		return err
+	}
+	if err := c.db.Commit(root, false); err != nil {
+		return err
+	}
+	tree, err := bintrie.NewBinaryTrie(root, c.db)
+	if err != nil {
+		return err
+	}
+	c.tree, c.root, c.leaves = tree, root, 0
+	return nil
+}
+
+// inserted tracks the number of the inserted leaves and flushes the tree if the
+// threshold is reached.
+func (c *binaryConverter) inserted(leaves int) error {
+	c.leaves += leaves
+	if c.leaves < binaryFlushThreshold {
+		return nil
+	}
+	return c.flush()
+}
+
+// convertToBinary converts the merkle state with the given root into a binary
+// tree in the destination database, returning the binary tree root.
+func convertToBinary(chaindb ethdb.Database, srcdb *triedb.Database, root common.Hash, destdb *triedb.Database) (common.Hash, error) {
+	tree, err := bintrie.NewBinaryTrie(types.EmptyBinaryHash, destdb)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	c := &binaryConverter{db: destdb, tree: tree}
+
+	t, err := trie.NewStateTrie(trie.StateTrieID(root), srcdb)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	acctIt, err := t.NodeIterator(nil)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	var (
+		accounts int
+		slots    int
+		start    = time.Now()
+		logged   = time.Now()
+		accIter  = trie.NewIterator(acctIt)
+	)
+	for accIter.Next() {
+		var acc types.StateAccount
+		if err := rlp.DecodeBytes(accIter.Value, &acc); err != nil {
+			return common.Hash{}, fmt.Errorf("invalid account %x: %v", accIter.Key, err)
+		}
+		preimage := rawdb.ReadPreimage(chaindb, common.BytesToHash(accIter.Key))
+		if len(preimage) != common.AddressLength {
+			return common.Hash{}, fmt.Errorf("missing preimage of account %x", accIter.Key)
+		}
+		var (
+			addr = common.BytesToAddress(preimage)
+			code []byte
+		)
+		if !bytes.Equal(acc.CodeHash, types.EmptyCodeHash.Bytes()) {
+			code = rawdb.ReadCode(chaindb, common.BytesToHash(acc.CodeHash))
+			if len(code) == 0 {
+				return common.Hash{}, fmt.Errorf("missing code %x of account %x", acc.CodeHash, addr)
+			}
+		}
+		if err := c.tree.UpdateAccount(addr, &acc, len(code)); err != nil {
+			return common.Hash{}, err
+		}
+		if len(code) > 0 {
+			if err := c.tree.UpdateContractCode(addr, common.BytesToHash(acc.CodeHash), code); err != nil {
+				return common.Hash{}, err
+			}
+		}
+		if err := c.inserted(2 + (len(code)+30)/31); err != nil {
+			return common.Hash{}, err
+		}
+		if acc.Root != types.EmptyRootHash {
+			id := trie.StorageTrieID(root, common.BytesToHash(accIter.Key), acc.Root)
+			storageTrie, err := trie.NewStateTrie(id, srcdb)
+			if err != nil {
+				return common.Hash{}, err
+			}
+			storageIt, err := storageTrie.NodeIterator(nil)
+			if err != nil {
+				return common.Hash{}, err
+			}
+			storageIter := trie.NewIterator(storageIt)
+			for storageIter.Next() {
+				key := rawdb.ReadPreimage(chaindb, common.BytesToHash(storageIter.Key))
+				if len(key) != common.HashLength {
+					return common.Hash{}, fmt.Errorf("missing preimage of slot %x of account %x", storageIter.Key, addr)
+				}
+				_, value, _, err := rlp.Split(storageIter.Value)
+				if err != nil {
+					return common.Hash{}, fmt.Errorf("invalid slot %x of account %x: %v", key, addr, err)
+				}
+				if err := c.tree.UpdateStorage(addr, key, value); err != nil {
+					return common.Hash{}, err
+				}
+				if err := c.inserted(1); err != nil {
+					return common.Hash{}, err
+				}
+				slots++
+			}
+			if storageIter.Err != nil {
+				return common.Hash{}, storageIter.Err
+			}
+		}
+		accounts++
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Converting state into binary tree", "accounts", accounts, "slots", slots, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if accIter.Err != nil {
+		return common.Hash{}, accIter.Err
+	}
+	if err := c.flush(); err != nil {
+		return common.Hash{}, err
+	}
+	log.Info("Converted state into binary tree", "accounts", accounts, "slots", slots, "elapsed", common.PrettyDuration(time.Since(start)))
+	return c.root, nil
+}
+
+// binaryWitness generates the witness of the account and storage slots in the
+// binary tree.
+func binaryWitness(ctx *cli.Context) error {
+	if ctx.NArg() < 3 {
+		return errors.New("need <database> <root> <address> [<slot> ...] args")
+	}
+	root, err := parseRoot(ctx.Args().Get(1))
+	if err != nil {
+		return err
+	}
+	if !common.IsHexAddress(ctx.Args().Get(2)) {
+		return fmt.Errorf("invalid address %s", ctx.Args().Get(2))
+	}
+	addr := common.HexToAddress(ctx.Args().Get(2))
+
+	keys := [][]byte{bintrie.BasicDataKey(addr), bintrie.CodeHashKey(addr)}
+	for _, arg := range ctx.Args().Slice()[3:] {
+		slot, err := parseRoot(arg)
+		if err != nil {
+			return fmt.Errorf("invalid slot %s: %v", arg, err)
+		}
+		keys = append(keys, bintrie.StorageSlotKey(addr, slot.Bytes()))
+	}
+	kvdb, err := openKeyValueStore(ctx, rawdb.DBPebble, ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	defer kvdb.Close()
+
+	tdb := triedb.NewDatabase(rawdb.NewDatabase(kvdb), triedb.BinaryDefaults)
+	defer tdb.Close()
+
+	tree, err := bintrie.NewBinaryTrie(root, tdb)
+	if err != nil {
+		return err
+	}
+	proof := rawdb.NewMemoryDatabase()
+	for _, key := range keys {
+		if err := tree.Prove(key, proof); err != nil {
+			return err
+		}
+		value, err := bintrie.VerifyProof(root, key, proof)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%x: %x\n", key, value)
+	}
+	var (
+		nodes int
+		size  int
+		it    = proof.NewIterator(nil, nil)
+	)
+	defer it.Release()
+	for it.Next() {
+		nodes++
+		size += len(it.Value())
+	}
+	fmt.Printf("Witness: %d nodes, %d bytes\n", nodes, size)
+	return nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/holiman/uint256"
+)
+
+func TestConvertToBinary(t *testing.T) {
+	var (
+		chaindb    = rawdb.NewMemoryDatabase()
+		srcdb      = triedb.NewDatabase(chaindb, &triedb.Config{Preimages: true, HashDB: triedb.HashDefaults.HashDB})
+		statedb, _ = state.New(types.EmptyRootHash, state.NewDatabase(srcdb, nil))
+		code       = bytes.Repeat([]byte{0x60, 0x01}, 3000)
+	)
+	for i := byte(1); i <= 20; i++ {
+		addr := common.Address{i}
+		statedb.SetBalance(addr, uint256.NewInt(uint64(i)*100), tracing.BalanceChangeUnspecified)
+		statedb.SetNonce(addr, uint64(i), tracing.NonceChangeUnspecified)
+		if i%5 == 0 {
+			statedb.SetCode(addr, code)
+		}
+		for j := byte(0); j < i; j++ {
+			statedb.SetState(addr, common.Hash{j}, common.Hash{i, j})
+		}
+	}
+	root, err := statedb.Commit(0, false, false)
+	if err != nil {
+		t.Fatalf("Failed to commit state: %v", err)
+	}
+	if err := srcdb.Commit(root, false); err != nil {
+		t.Fatalf("Failed to commit trie database: %v", err)
+	}
+	destdb := triedb.NewDatabase(rawdb.NewMemoryDatabase(), triedb.BinaryDefaults)
+	binaryRoot, err := convertToBinary(chaindb, srcdb, root, destdb)
+	if err != nil {
+		t.Fatalf("Failed to convert state: %v", err)
+	}
+	converted, err := state.New(binaryRoot, state.NewDatabase(destdb, nil))
+	if err != nil {
+		t.Fatalf("Failed to open binary state: %v", err)
+	}
+	for i := byte(1); i <= 20; i++ {
+		addr := common.Address{i}
+		if have, want := converted.GetBalance(addr), statedb.GetBalance(addr); !have.Eq(want) {
+			t.Fatalf("Balance mismatch of %x: have %v, want %v", addr, have, want)
+		}
+		if have, want := converted.GetNonce(addr), statedb.GetNonce(addr); have != want {
+			t.Fatalf("Nonce mismatch of %x: have %d, want %d", addr, have, want)
+		}
+		if have, want := converted.GetCodeHash(addr), statedb.GetCodeHash(addr); have != want {
+			t.Fatalf("Code hash mismatch of %x: have %x, want %x", addr, have, want)
+		}
+		for j := byte(0); j < i; j++ {
+			if have := converted.GetState(addr, common.Hash{j}); have != (common.Hash{i, j}) {
+				t.Fatalf("Storage mismatch of %x: have %x, want %x", addr, have, common.Hash{i, j})
+			}
+		}
+	}
+	// Converting without the preimages must fail
+	if _, err := convertToBinary(rawdb.NewMemoryDatabase(), srcdb, root, triedb.NewDatabase(rawdb.NewMemoryDatabase(), triedb.BinaryDefaults)); err == nil {
+		t.Fatal("Conversion without preimages succeeded")
+	}
+}
@@ -273,7 +273,11 @@ func makeFullNode(ctx *cli.Context) *node.Node {
 	}
 	utils.RegisterSyncOverrideService(stack, eth, synctarget, ctx.Bool(utils.ExitWhenSyncedFlag.Name))
 
-	if ctx.IsSet(utils.DeveloperFlag.Name) {
+	if cfg.Eth.DatabaseSecondary != "" {
+		// The secondary node follows the chain imported by the primary node, it's
+		// not driven by a consensus client.
+		log.Info("Following primary node database", "path", cfg.Eth.DatabaseSecondary)
+	} else if ctx.IsSet(utils.DeveloperFlag.Name) {
 		// Start dev mode.
 		simBeacon, err := catalyst.NewSimulatedBeacon(ctx.Uint64(utils.DeveloperPeriodFlag.Name), cfg.Eth.Miner.PendingFeeRecipient, eth)
 		if err != nil {
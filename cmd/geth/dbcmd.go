@@ -18,13 +18,17 @@ package main
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -32,15 +36,20 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/console/prompt"
+	"github.com/ethereum/go-ethereum/core/filtermaps"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state/snapshot"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/leveldb"
+	"github.com/ethereum/go-ethereum/ethdb/pebble"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/trie"
 	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/ethereum/go-ethereum/triedb/pathdb"
 	"github.com/olekukonko/tablewriter"
 	"github.com/urfave/cli/v2"
 )
@@ -83,6 +92,11 @@ Remove blockchain and state databases`,
 			dbMetadataCmd,
 			dbCheckStateContentCmd,
 			dbInspectHistoryCmd,
+			dbBackupCmd,
+			dbRestoreCmd,
+			dbMigrateCmd,
+			dbRecompressCmd,
+			dbVerifyCmd,
 		},
 	}
 	dbInspectCmd = &cli.Command{
@@ -207,6 +221,108 @@ WARNING: This is a low-level operation which may cause database corruption!`,
 		}, utils.NetworkFlags, utils.DatabaseFlags),
 		Description: "This command queries the history of the account or storage slot within the specified block range",
 	}
+	dbBackupCmd = &cli.Command{
+		Action:    dbBackup,
+		Name:      "backup",
+		Usage:     "Create a consistent backup of the chain database",
+		ArgsUsage: "<backup directory>",
+		Flags:     slices.Concat(utils.NetworkFlags, utils.DatabaseFlags),
+		Description: `This command creates a point-in-time copy of the chain database, including the
+ancient chain segments and the state history of the path-based state scheme, in the
+given directory which must not exist yet. Only pebble databases are supported. The
+backup of a running node can be taken via admin_backup.`,
+	}
+	dbRestoreCmd = &cli.Command{
+		Action:    dbRestore,
+		Name:      "restore",
+		Usage:     "Restore the chain database from a backup",
+		ArgsUsage: "<backup directory>",
+		Flags:     slices.Concat(utils.NetworkFlags, utils.DatabaseFlags),
+		Description: `This command validates the backup created by 'geth db backup' or admin_backup and
+copies it into the data directory, which must not contain a chain database yet. The
+restored database is validated again afterwards.`,
+	}
+	dbMigrateCmd = &cli.Command{
+		Action:    dbMigrate,
+		Name:      "migrate",
+		Usage:     "Migrate the chain database to a different engine or state scheme",
+		ArgsUsage: "",
+		Flags: slices.Concat([]cli.Flag{
+			&cli.StringFlag{
+				Name:  "to",
+				Usage: "Database engine to migrate the key-value store to ('leveldb' or 'pebble')",
+			},
+			&cli.StringFlag{
+				Name:  "scheme",
+				Usage: "State scheme to convert the state to (only 'path' is supported)",
+			},
+		}, utils.NetworkFlags, utils.DatabaseFlags),
+		Description: `This command migrates the chain database offline, avoiding a full resync.
+
+With --to, all the key-value data is copied into a new database of the given engine,
+which replaces the original one afterwards. The migration can be interrupted and is
+resumed if the command is run again. The original database is retained in the
+'chaindata.old' folder and can be removed once the migrated database is verified.
+
+With --scheme path, the trie nodes of the head state are regenerated from the state
+snapshot in the path-based scheme, and the legacy hash-based trie nodes are removed.
+The historical states of the hash-based scheme are not available afterwards.
+
+The ancient store is left untouched in both cases.`,
+	}
+	dbRecompressCmd = &cli.Command{
+		Action:    dbRecompress,
+		Name:      "recompress",
+		Usage:     "Rewrite the items of freezer tables with a different compression",
+		ArgsUsage: "<freezer-type> <table-type> [<table-type>...]",
+		Flags: slices.Concat([]cli.Flag{
+			&cli.StringFlag{
+				Name:  "compression",
+				Usage: "Compression algorithm of the table items ('snappy' or 'zstd')",
+				Value: "zstd",
+			},
+		}, utils.NetworkFlags, utils.DatabaseFlags),
+		Description: `This command rewrites the data files of the given freezer tables in place,
+converting the stored items into the specified compression. The compression is also
+applied to the items appended to the tables afterwards.
+
+When switching a table to zstd for the first time, a compression dictionary is trained
+from the sampled items of the table. The rewrite can be interrupted and is completed
+when the table is opened again, running the command again resumes the conversion.
+Tables with data files offloaded to an object store can't be recompressed.
+
+Note, tables converted to zstd can't be opened by releases without zstd support
+anymore. Converting them back to snappy restores the compatibility.`,
+	}
+	dbVerifyCmd = &cli.Command{
+		Action:    dbVerify,
+		Name:      "verify",
+		Usage:     "Check the consistency of the chain data, indexes and state histories",
+		ArgsUsage: "",
+		Flags: slices.Concat([]cli.Flag{
+			&cli.Uint64Flag{
+				Name:  "start",
+				Usage: "Lowest block number to verify the chain data from",
+			},
+			&cli.BoolFlag{
+				Name:  "repair",
+				Usage: "Rewrite the inconsistent index data, derivable from the chain",
+			},
+		}, utils.NetworkFlags, utils.DatabaseFlags),
+		Description: `This command walks the canonical chain backwards from the head block and checks
+that the canonical hash and hash-to-number mappings match the headers, that the
+transaction lookup entries point to the blocks containing the transactions, that the
+number of receipts matches the number of transactions, and that the chain continues
+across the boundary of the ancient store and the key-value store.
+
+The log index is checked to cover the indexed range with maps pointing to canonical
+blocks. In the path-based state scheme, the state histories are checked to be
+contiguous and to cover the persisted state.
+
+With --repair, the mappings and transaction lookup entries are rewritten from the
+chain data and an inconsistent log index is removed, to be regenerated on the next
+start. Missing chain data and broken state histories can't be repaired.`,
+	}
 )
 
 func removeDB(ctx *cli.Context) error {
@@ -215,14 +331,8 @@ func removeDB(ctx *cli.Context) error {
 	// Resolve folder paths.
 	var (
 		rootDir    = stack.ResolvePath("chaindata")
-		ancientDir = config.Eth.DatabaseFreezer
+		ancientDir = resolveAncientDir(stack, config)
 	)
-	switch {
-	case ancientDir == "":
-		ancientDir = filepath.Join(stack.ResolvePath("chaindata"), "ancient")
-	case !filepath.IsAbs(ancientDir):
-		ancientDir = config.Node.ResolvePath(ancientDir)
-	}
 	// Delete state data
 	statePaths := []string{
 		rootDir,
@@ -240,6 +350,18 @@ func removeDB(ctx *cli.Context) error {
 	return nil
 }
 
+// resolveAncientDir returns the absolute path of the root ancient directory.
+func resolveAncientDir(stack *node.Node, config gethConfig) string {
+	ancientDir := config.Eth.DatabaseFreezer
+	switch {
+	case ancientDir == "":
+		ancientDir = filepath.Join(stack.ResolvePath("chaindata"), "ancient")
+	case !filepath.IsAbs(ancientDir):
+		ancientDir = config.Node.ResolvePath(ancientDir)
+	}
+	return ancientDir
+}
+
 // removeFolder deletes all files (not folders) inside the directory 'dir' (but
 // not files in subfolders).
 func removeFolder(dir string) {
@@ -605,6 +727,26 @@ func freezerInspect(ctx *cli.Context) error {
 	return rawdb.InspectFreezerTable(ancient, freezer, table, start, end)
 }
 
+func dbRecompress(ctx *cli.Context) error {
+	if ctx.NArg() < 2 {
+		return fmt.Errorf("required arguments: %v", ctx.Command.ArgsUsage)
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	var (
+		ancient = stack.ResolveAncient("chaindata", ctx.String(utils.AncientFlag.Name))
+		freezer = ctx.Args().Get(0)
+	)
+	for _, table := range ctx.Args().Slice()[1:] {
+		log.Info("Recompressing freezer table", "freezer", freezer, "table", table, "compression", ctx.String("compression"))
+		if err := rawdb.RecompressFreezerTable(ancient, freezer, table, ctx.String("compression")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func importLDBdata(ctx *cli.Context) error {
 	start := 0
 	switch ctx.NArg() {
@@ -907,3 +1049,727 @@ func inspectHistory(ctx *cli.Context) error {
 	}
 	return inspectStorage(triedb, start, end, address, slot, ctx.Bool("raw"))
 }
+
+func dbBackup(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("required arguments: %v", ctx.Command.ArgsUsage)
+	}
+	dir, err := filepath.Abs(ctx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, false)
+	defer db.Close()
+
+	triedb := utils.MakeTrieDatabase(ctx, db, false, true, false)
+	defer triedb.Close()
+
+	start := time.Now()
+	if err := triedb.Checkpoint(dir); err != nil {
+		return err
+	}
+	log.Info("Created database backup", "dir", dir, "elapsed", common.PrettyDuration(time.Since(start)))
+	return validateBackup(dir)
+}
+
+func dbRestore(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("required arguments: %v", ctx.Command.ArgsUsage)
+	}
+	dir, err := filepath.Abs(ctx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	// Validate the backup before touching the data directory
+	if err := validateBackup(dir); err != nil {
+		return fmt.Errorf("invalid backup: %v", err)
+	}
+	stack, config := makeConfigNode(ctx)
+	defer stack.Close()
+
+	var (
+		rootDir    = stack.ResolvePath("chaindata")
+		ancientDir = resolveAncientDir(stack, config)
+		freezers   = []string{rawdb.ChainFreezerName, rawdb.MerkleStateFreezerName, rawdb.VerkleStateFreezerName}
+	)
+	for _, path := range []string{rootDir, filepath.Join(ancientDir, rawdb.ChainFreezerName), filepath.Join(ancientDir, rawdb.MerkleStateFreezerName), filepath.Join(ancientDir, rawdb.VerkleStateFreezerName)} {
+		if entries, err := os.ReadDir(path); err == nil && len(entries) > 0 {
+			return fmt.Errorf("database directory %s is not empty", path)
+		}
+	}
+	start := time.Now()
+	if err := copyDir(dir, rootDir, "ancient"); err != nil {
+		return err
+	}
+	// The state history freezers are only present in the backups of databases
+	// using the path-based state scheme.
+	for _, name := range freezers {
+		src := filepath.Join(dir, "ancient", name)
+		if _, err := os.Stat(src); name != rawdb.ChainFreezerName && os.IsNotExist(err) {
+			continue
+		}
+		if err := copyDir(src, filepath.Join(ancientDir, name), ""); err != nil {
+			return err
+		}
+	}
+	log.Info("Restored database backup", "dir", dir, "elapsed", common.PrettyDuration(time.Since(start)))
+
+	// Validate the restored database, opened the same way as by the node
+	db := utils.MakeChainDatabase(ctx, stack, true)
+	defer db.Close()
+	return checkChainData(db)
+}
+
+// validateBackup opens the database backup in the given directory in read-only
+// mode and checks the integrity of the chain data within.
+func validateBackup(dir string) error {
+	kvdb, err := pebble.New(dir, 16, 16, "", true)
+	if err != nil {
+		return err
+	}
+	db, err := rawdb.Open(kvdb, rawdb.OpenOptions{Ancient: filepath.Join(dir, "ancient"), ReadOnly: true})
+	if err != nil {
+		kvdb.Close()
+		return err
+	}
+	defer db.Close()
+	return checkChainData(db)
+}
+
+// checkChainData performs a sanity check of the chain data in the database,
+// ensuring that the head block is present and the canonical chain is linked up
+// from the head down to the ancient store.
+func checkChainData(db ethdb.Database) error {
+	head := rawdb.ReadHeadBlockHash(db)
+	if head == (common.Hash{}) {
+		return errors.New("head block is not set")
+	}
+	number, ok := rawdb.ReadHeaderNumber(db, head)
+	if !ok {
+		return fmt.Errorf("head block %x is unknown", head)
+	}
+	if rawdb.ReadBlock(db, head, number) == nil {
+		return fmt.Errorf("head block #%d [%x] is missing", number, head)
+	}
+	frozen, err := db.Ancients()
+	if err != nil {
+		return err
+	}
+	tail, err := db.Tail()
+	if err != nil {
+		return err
+	}
+	// Walk the canonical chain from the head down into the ancient store
+	hash := head
+	for n := number; n > 0 && n+1 >= frozen; n-- {
+		header := rawdb.ReadHeader(db, hash, n)
+		if header == nil {
+			return fmt.Errorf("header #%d [%x] is missing", n, hash)
+		}
+		if canon := rawdb.ReadCanonicalHash(db, n); canon != hash {
+			return fmt.Errorf("canonical hash #%d mismatch, have %x, want %x", n, canon, hash)
+		}
+		if n >= tail && !rawdb.HasBody(db, hash, n) {
+			return fmt.Errorf("block body #%d [%x] is missing", n, hash)
+		}
+		hash = header.ParentHash
+	}
+	log.Info("Chain data is consistent", "head", number, "hash", head, "ancients", frozen, "tail", tail)
+	return nil
+}
+
+// copyDir recursively copies the content of the source directory into the
+// destination directory, skipping the given top-level entry if non-empty.
+func copyDir(src, dst string, skip string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if skip != "" && rel == skip {
+			return filepath.SkipDir
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		in, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+
+		out, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+
+		if _, err := io.Copy(out, in); err != nil {
+			return err
+		}
+		return out.Sync()
+	})
+}
+
+func dbMigrate(ctx *cli.Context) error {
+	engine, scheme := ctx.String("to"), ctx.String("scheme")
+	if engine == "" && scheme == "" {
+		return errors.New("either --to or --scheme is required")
+	}
+	if engine != "" && engine != rawdb.DBLeveldb && engine != rawdb.DBPebble {
+		return fmt.Errorf("invalid database engine '%s', allowed 'leveldb' or 'pebble'", engine)
+	}
+	if scheme != "" && scheme != rawdb.PathScheme {
+		return fmt.Errorf("invalid state scheme '%s', only 'path' is supported", scheme)
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	if engine != "" {
+		if err := migrateEngine(ctx, stack, engine); err != nil {
+			return err
+		}
+	}
+	if scheme != "" {
+		db := utils.MakeChainDatabase(ctx, stack, false)
+		defer db.Close()
+
+		if err := migrateScheme(db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// interruptible returns a channel which is closed once the process receives an
+// interrupt signal, and a function to stop listening for the signals.
+func interruptible(task string) (chan struct{}, func()) {
+	var (
+		interrupt = make(chan os.Signal, 1)
+		stop      = make(chan struct{})
+	)
+	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		if _, ok := <-interrupt; ok {
+			log.Info(fmt.Sprintf("Interrupted during %s, stopping at next batch", task))
+		}
+		close(stop)
+	}()
+	return stop, func() {
+		signal.Stop(interrupt)
+		close(interrupt)
+	}
+}
+
+// openKeyValueStore opens the key-value store of the given engine at the path.
+func openKeyValueStore(ctx *cli.Context, engine string, path string) (ethdb.KeyValueStore, error) {
+	var (
+		cache   = ctx.Int(utils.CacheFlag.Name) * ctx.Int(utils.CacheDatabaseFlag.Name) / 100
+		handles = utils.MakeDatabaseHandles(ctx.Int(utils.FDLimitFlag.Name))
+	)
+	if engine == rawdb.DBLeveldb {
+		return leveldb.New(path, cache/2, handles/2, "", false)
+	}
+	return pebble.New(path, cache/2, handles/2, "", false)
+}
+
+// migrateEngine copies the key-value store of the chain database into a new
+// database of the given engine and swaps it in place of the original one. The
+// ancient store, if located in the default folder, is moved over as is.
+func migrateEngine(ctx *cli.Context, stack *node.Node, engine string) error {
+	var (
+		rootDir   = stack.ResolvePath("chaindata")
+		targetDir = rootDir + ".migrate"
+		oldDir    = rootDir + ".old"
+	)
+	current := rawdb.PreexistingDatabase(rootDir)
+	switch current {
+	case "":
+		return fmt.Errorf("no database found in %s", rootDir)
+	case engine:
+		return fmt.Errorf("database is already using %s", engine)
+	}
+	if common.FileExist(oldDir) {
+		return fmt.Errorf("directory %s of a previous migration exists, remove it first", oldDir)
+	}
+	src, err := openKeyValueStore(ctx, current, rootDir)
+	if err != nil {
+		return err
+	}
+	dst, err := openKeyValueStore(ctx, engine, targetDir)
+	if err != nil {
+		src.Close()
+		return err
+	}
+	log.Info("Migrating key-value store", "from", current, "to", engine, "target", targetDir)
+	stop, release := interruptible("key-value store migration")
+	err = rawdb.MigrateKeyValueStore(src, dst, stop)
+	release()
+	src.Close()
+	dst.Close()
+	if err != nil {
+		if errors.Is(err, rawdb.ErrMigrationInterrupted) {
+			log.Info("Rerun the command to resume the migration")
+		}
+		return err
+	}
+	// Swap the migrated key-value store in place and move the ancient store
+	// into it, leaving only the original key-value data in the old folder.
+	if err := os.Rename(rootDir, oldDir); err != nil {
+		return err
+	}
+	if err := os.Rename(targetDir, rootDir); err != nil {
+		return err
+	}
+	if ancient := filepath.Join(oldDir, "ancient"); common.FileExist(ancient) {
+		if err := os.Rename(ancient, filepath.Join(rootDir, "ancient")); err != nil {
+			return err
+		}
+	}
+	log.Info("Migrated database engine", "engine", engine, "old", oldDir)
+	if ctx.IsSet(utils.DBEngineFlag.Name) {
+		log.Warn("Update the database engine flag to match the migrated database", "flag", utils.DBEngineFlag.Name, "engine", engine)
+	}
+	return nil
+}
+
+// migrateScheme converts the head state stored in the hash-based scheme into
+// the path-based scheme, regenerating the trie nodes from the state snapshot.
+// The conversion itself is not interruptible, the subsequent removal of the
+// legacy trie nodes is resumed if invoked again on the converted state.
+func migrateScheme(db ethdb.Database) error {
+	scheme := rawdb.ReadStateScheme(db)
+	switch scheme {
+	case rawdb.PathScheme, rawdb.HashScheme:
+	default:
+		return fmt.Errorf("state scheme %q can't be converted", scheme)
+	}
+	head := rawdb.ReadHeadBlock(db)
+	if head == nil {
+		return errors.New("head block is not available")
+	}
+	if scheme == rawdb.PathScheme {
+		log.Info("State is already in path scheme, removing leftover legacy trie nodes")
+		return deleteLegacyTrieNodes(db, head.Root())
+	}
+	var (
+		root  = head.Root()
+		start = time.Now()
+	)
+	tdb := triedb.NewDatabase(db, triedb.HashDefaults)
+	defer tdb.Close()
+
+	snaptree, err := snapshot.New(snapshot.Config{CacheSize: 256, NoBuild: true}, db, tdb, root)
+	if err != nil {
+		return fmt.Errorf("state snapshot is required for the conversion: %v", err)
+	}
+	// Flatten the snapshot diff layers, so that the persisted snapshot matches
+	// the converted state and can be adopted by the path-based trie database.
+	if rawdb.ReadSnapshotRoot(db) != root {
+		if err := snaptree.Cap(root, 0); err != nil {
+			return err
+		}
+	}
+	log.Info("Converting state to path scheme", "number", head.NumberU64(), "root", root)
+	writer := &batchWriter{batch: db.NewBatch()}
+	if err := snapshot.GeneratePathTrie(snaptree, root, db, writer); err != nil {
+		return err
+	}
+	if err := writer.flush(); err != nil {
+		return err
+	}
+	rawdb.DeleteSnapshotJournal(db)
+	log.Info("Converted state to path scheme", "root", root, "elapsed", common.PrettyDuration(time.Since(start)))
+
+	return deleteLegacyTrieNodes(db, root)
+}
+
+// referencedCodeHashes collects the hashes of all the contract codes referenced
+// by the accounts of the given path-based state.
+func referencedCodeHashes(db ethdb.Database, root common.Hash) (map[common.Hash]struct{}, error) {
+	tdb := triedb.NewDatabase(db, &triedb.Config{PathDB: pathdb.ReadOnly})
+	defer tdb.Close()
+
+	it, err := tdb.AccountIterator(root, common.Hash{})
+	if err != nil {
+		return nil, fmt.Errorf("state of %x can't be iterated: %v", root, err)
+	}
+	defer it.Release()
+
+	codes := make(map[common.Hash]struct{})
+	for it.Next() {
+		account, err := types.FullAccount(it.Account())
+		if err != nil {
+			return nil, err
+		}
+		if hash := common.BytesToHash(account.CodeHash); hash != types.EmptyCodeHash {
+			codes[hash] = struct{}{}
+		}
+	}
+	return codes, it.Error()
+}
+
+// deleteLegacyTrieNodes removes all the trie nodes stored in the hash-based
+// scheme from the database. Legacy databases may store contract codes in the
+// same unprefixed form, the ones referenced by the state at the given root are
+// moved to the prefixed code storage instead of being deleted.
+func deleteLegacyTrieNodes(db ethdb.Database, root common.Hash) error {
+	codes, err := referencedCodeHashes(db, root)
+	if err != nil {
+		return err
+	}
+	stop, release := interruptible("legacy trie node deletion")
+	defer release()
+
+	var (
+		it     = db.NewIterator(nil, nil)
+		batch  = db.NewBatch()
+		count  int
+		moved  int
+		size   common.StorageSize
+		start  = time.Now()
+		logged = time.Now()
+	)
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()
+		if !rawdb.IsLegacyTrieNode(key, it.Value()) {
+			continue
+		}
+		if hash := common.BytesToHash(key); !rawdb.HasCodeWithPrefix(db, hash) {
+			if _, ok := codes[hash]; ok {
+				rawdb.WriteCode(batch, hash, it.Value())
+				moved += 1
+			}
+		}
+		count += 1
+		size += common.StorageSize(len(key) + len(it.Value()))
+		batch.Delete(key)
+
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+
+			select {
+			case <-stop:
+				log.Info("Rerun the command to resume the deletion", "nodes", count, "size", size)
+				return errors.New("legacy trie node deletion interrupted")
+			default:
+			}
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Deleting legacy trie nodes", "nodes", count, "size", size, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	log.Info("Deleted legacy trie nodes", "nodes", count, "size", size, "codes", moved, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// batchWriter is a thread-safe key-value writer, accumulating the writes into
+// a batch which is flushed into the database once it grows large enough.
+type batchWriter struct {
+	batch ethdb.Batch
+	lock  sync.Mutex
+}
+
+// Put implements ethdb.KeyValueWriter.
+func (w *batchWriter) Put(key []byte, value []byte) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if err := w.batch.Put(key, value); err != nil {
+		return err
+	}
+	return w.maybeFlush()
+}
+
+// Delete implements ethdb.KeyValueWriter.
+func (w *batchWriter) Delete(key []byte) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if err := w.batch.Delete(key); err != nil {
+		return err
+	}
+	return w.maybeFlush()
+}
+
+// maybeFlush writes out the batch if it exceeds the ideal batch size. The lock
+// is assumed to be held.
+func (w *batchWriter) maybeFlush() error {
+	if w.batch.ValueSize() < ethdb.IdealBatchSize {
+		return nil
+	}
+	if err := w.batch.Write(); err != nil {
+		return err
+	}
+	w.batch.Reset()
+	return nil
+}
+
+// flush writes out all the pending writes.
+func (w *batchWriter) flush() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if err := w.batch.Write(); err != nil {
+		return err
+	}
+	w.batch.Reset()
+	return nil
+}
+
+// verifyReport accumulates the inconsistencies found by the database verifier.
+type verifyReport struct {
+	issues   int // number of inconsistencies found
+	repaired int // number of inconsistencies repaired
+}
+
+// fail records an inconsistency, logging it with the given context.
+func (r *verifyReport) fail(repaired bool, msg string, ctx ...any) {
+	r.issues++
+	if repaired {
+		r.repaired++
+		ctx = append(ctx, "repaired", true)
+	}
+	log.Error(msg, ctx...)
+}
+
+func dbVerify(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	repair := ctx.Bool("repair")
+	db := utils.MakeChainDatabase(ctx, stack, !repair)
+	defer db.Close()
+
+	var (
+		start  = time.Now()
+		report = new(verifyReport)
+	)
+	head, err := verifyChain(db, ctx.Uint64("start"), repair, report)
+	if err != nil {
+		return err
+	}
+	verifyLogIndex(db, head, repair, report)
+	if err := verifyStateHistories(db, report); err != nil {
+		return err
+	}
+	if report.issues == report.repaired {
+		log.Info("Verified database", "issues", report.issues, "repaired", report.repaired, "elapsed", common.PrettyDuration(time.Since(start)))
+		return nil
+	}
+	if !repair {
+		log.Info("Rerun the command with --repair to fix the index data")
+	}
+	return fmt.Errorf("database is inconsistent, %d issues found, %d repaired", report.issues, report.repaired)
+}
+
+// verifyChain walks the canonical chain from the head block back to the given
+// start block, checking the chain data and the index data derived from it. The
+// number of the head block is returned.
+func verifyChain(db ethdb.Database, start uint64, repair bool, report *verifyReport) (uint64, error) {
+	hash := rawdb.ReadHeadBlockHash(db)
+	if hash == (common.Hash{}) {
+		return 0, errors.New("head block is not found")
+	}
+	number, ok := rawdb.ReadHeaderNumber(db, hash)
+	if !ok {
+		return 0, fmt.Errorf("number of head block %x is not found", hash)
+	}
+	frozen, err := db.Ancients()
+	if err != nil {
+		return 0, err
+	}
+	tail, err := db.Tail()
+	if err != nil {
+		return 0, err
+	}
+	if frozen > number+1 {
+		report.fail(false, "Ancient store is ahead of the head block", "frozen", frozen, "head", number)
+	}
+	var (
+		head    = number
+		txTail  = rawdb.ReadTxIndexTail(db)
+		batch   = db.NewBatch()
+		began   = time.Now()
+		logged  = time.Now()
+		checked uint64
+	)
+	log.Info("Verifying chain data", "head", head, "start", start, "frozen", frozen, "tail", tail)
+	for {
+		header := rawdb.ReadHeader(db, hash, number)
+		if header == nil {
+			if number+1 == frozen {
+				report.fail(false, "Chain is discontinuous at the ancient store boundary", "number", number, "hash", hash)
+			} else {
+				report.fail(false, "Header is missing", "number", number, "hash", hash)
+			}
+			break
+		}
+		// Check the mappings between the block numbers and hashes. The canonical
+		// hashes of the ancient blocks are derived from the headers in there.
+		if canonical := rawdb.ReadCanonicalHash(db, number); canonical != hash {
+			fix := repair && number >= frozen
+			if fix {
+				rawdb.WriteCanonicalHash(batch, hash, number)
+			}
+			report.fail(fix, "Canonical hash mismatch", "number", number, "have", canonical, "want", hash)
+		}
+		if n, ok := rawdb.ReadHeaderNumber(db, hash); !ok || n != number {
+			if repair {
+				rawdb.WriteHeaderNumber(batch, hash, number)
+			}
+			report.fail(repair, "Header number mismatch", "number", number, "hash", hash, "have", n, "exist", ok)
+		}
+		// Check the block bodies, receipts and transaction indexes within the
+		// range of the retained chain history.
+		if number >= tail {
+			if body := rawdb.ReadBody(db, hash, number); body == nil {
+				report.fail(false, "Block body is missing", "number", number, "hash", hash)
+			} else {
+				verifyReceipts(db, hash, number, len(body.Transactions), report)
+				if txTail != nil && number >= *txTail && !verifyTxLookups(db, number, body.Transactions) {
+					if repair {
+						rawdb.WriteTxLookupEntriesByBlock(batch, types.NewBlockWithHeader(header).WithBody(*body))
+					}
+					report.fail(repair, "Transaction lookup entries mismatch", "number", number, "hash", hash)
+				}
+			}
+		}
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return 0, err
+			}
+			batch.Reset()
+		}
+		checked++
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Verifying chain data", "number", number, "checked", checked, "issues", report.issues, "elapsed", common.PrettyDuration(time.Since(began)))
+			logged = time.Now()
+		}
+		if number == 0 || number <= start {
+			break
+		}
+		hash, number = header.ParentHash, number-1
+	}
+	if batch.ValueSize() > 0 {
+		if err := batch.Write(); err != nil {
+			return 0, err
+		}
+	}
+	log.Info("Verified chain data", "blocks", checked, "issues", report.issues, "elapsed", common.PrettyDuration(time.Since(began)))
+	return head, nil
+}
+
+// verifyReceipts checks that the receipts of the block are present and match
+// the transactions in number.
+func verifyReceipts(db ethdb.Reader, hash common.Hash, number uint64, txs int, report *verifyReport) {
+	blob := rawdb.ReadReceiptsRLP(db, hash, number)
+	if len(blob) == 0 {
+		report.fail(false, "Receipts are missing", "number", number, "hash", hash)
+		return
+	}
+	content, _, err := rlp.SplitList(blob)
+	if err != nil {
+		report.fail(false, "Receipts are corrupted", "number", number, "hash", hash, "err", err)
+		return
+	}
+	receipts, err := rlp.CountValues(content)
+	if err != nil {
+		report.fail(false, "Receipts are corrupted", "number", number, "hash", hash, "err", err)
+		return
+	}
+	if receipts != txs {
+		report.fail(false, "Receipt count mismatch", "number", number, "hash", hash, "receipts", receipts, "txs", txs)
+	}
+}
+
+// verifyTxLookups reports whether the lookup entries of the transactions point
+// to the given block. An entry pointing to a later canonical block containing
+// the same transaction is accepted, as the lookups of the transactions included
+// more than once refer to the last inclusion.
+func verifyTxLookups(db ethdb.Reader, number uint64, txs types.Transactions) bool {
+	for _, tx := range txs {
+		n := rawdb.ReadTxLookupEntry(db, tx.Hash())
+		if n == nil || *n < number {
+			return false
+		}
+		if *n == number {
+			continue
+		}
+		body := rawdb.ReadBody(db, rawdb.ReadCanonicalHash(db, *n), *n)
+		if body == nil || !slices.ContainsFunc(body.Transactions, func(other *types.Transaction) bool {
+			return other.Hash() == tx.Hash()
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// verifyLogIndex checks the log index against the canonical chain, removing it
+// if inconsistent and repair is requested. The log index is regenerated on the
+// next start.
+func verifyLogIndex(db ethdb.Database, head uint64, repair bool, report *verifyReport) {
+	blocks, err := filtermaps.VerifyIndex(db, head)
+	if err == nil {
+		if blocks.IsEmpty() {
+			log.Info("Log index is not available")
+		} else {
+			log.Info("Verified log index", "first", blocks.First(), "last", blocks.Last())
+		}
+		return
+	}
+	if repair {
+		hashScheme := rawdb.ReadStateScheme(db) != rawdb.PathScheme
+		if derr := rawdb.DeleteFilterMapsDb(db, hashScheme, nil); derr != nil {
+			log.Error("Failed to remove log index", "err", derr)
+			repair = false
+		}
+	}
+	report.fail(repair, "Log index is inconsistent", "err", err)
+}
+
+// verifyStateHistories checks the integrity of the state histories in the
+// path-based state scheme.
+func verifyStateHistories(db ethdb.Database, report *verifyReport) error {
+	if rawdb.ReadStateScheme(db) != rawdb.PathScheme {
+		return nil
+	}
+	ancient, err := db.AncientDatadir()
+	if err != nil {
+		log.Info("State histories are not available", "err", err)
+		return nil
+	}
+	freezer, err := rawdb.NewStateFreezer(ancient, false, true)
+	if err != nil {
+		return err
+	}
+	defer freezer.Close()
+
+	count, err := pathdb.VerifyHistories(db, freezer)
+	if err != nil {
+		report.fail(false, "State histories are inconsistent", "err", err)
+		return nil
+	}
+	log.Info("Verified state histories", "count", count)
+	return nil
+}
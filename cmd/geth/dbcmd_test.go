@@ -0,0 +1,181 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/triedb"
+)
+
+// newVerifyTestChain creates a database containing a canonical chain with a
+// transaction in every block, along with the receipts and lookup entries.
+func newVerifyTestChain(t *testing.T, n int) (ethdb.Database, []*types.Block) {
+	var (
+		key, _  = crypto.GenerateKey()
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		signer  = types.LatestSigner(params.TestChainConfig)
+		gspec   = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  types.GenesisAlloc{address: {Balance: big.NewInt(params.Ether)}},
+		}
+	)
+	db, err := rawdb.NewDatabaseWithFreezer(rawdb.NewMemoryDatabase(), "", "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesis := gspec.MustCommit(db, triedb.NewDatabase(db, triedb.HashDefaults))
+	blocks, receipts := core.GenerateChain(gspec.Config, genesis, ethash.NewFaker(), db, n, func(i int, gen *core.BlockGen) {
+		tx, err := types.SignTx(types.NewTransaction(gen.TxNonce(address), common.Address{0x01}, big.NewInt(1), params.TxGas, gen.BaseFee(), nil), signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gen.AddTx(tx)
+	})
+	for i, block := range blocks {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), receipts[i])
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteTxLookupEntriesByBlock(db, block)
+	}
+	rawdb.WriteHeadBlockHash(db, blocks[len(blocks)-1].Hash())
+	rawdb.WriteTxIndexTail(db, 0)
+	return db, blocks
+}
+
+func TestVerifyChain(t *testing.T) {
+	db, blocks := newVerifyTestChain(t, 10)
+
+	report := new(verifyReport)
+	head, err := verifyChain(db, 0, false, report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head != 10 || report.issues != 0 {
+		t.Fatalf("consistent chain: head %d, issues %d", head, report.issues)
+	}
+	// Corrupt the index data derivable from the chain.
+	rawdb.WriteCanonicalHash(db, common.Hash{0x01}, 3)
+	rawdb.DeleteHeaderNumber(db, blocks[4].Hash())
+	rawdb.DeleteTxLookupEntry(db, blocks[6].Transactions()[0].Hash())
+
+	report = new(verifyReport)
+	if _, err := verifyChain(db, 0, false, report); err != nil {
+		t.Fatal(err)
+	}
+	if report.issues != 3 || report.repaired != 0 {
+		t.Fatalf("corrupted chain: issues %d, repaired %d", report.issues, report.repaired)
+	}
+	// The verification of a partial range must skip the corruptions below.
+	report = new(verifyReport)
+	if _, err := verifyChain(db, 8, false, report); err != nil {
+		t.Fatal(err)
+	}
+	if report.issues != 0 {
+		t.Fatalf("partial range: issues %d", report.issues)
+	}
+	report = new(verifyReport)
+	if _, err := verifyChain(db, 0, true, report); err != nil {
+		t.Fatal(err)
+	}
+	if report.issues != 3 || report.repaired != 3 {
+		t.Fatalf("repaired chain: issues %d, repaired %d", report.issues, report.repaired)
+	}
+	report = new(verifyReport)
+	if _, err := verifyChain(db, 0, false, report); err != nil {
+		t.Fatal(err)
+	}
+	if report.issues != 0 {
+		t.Fatalf("repaired chain: issues %d", report.issues)
+	}
+	// Missing receipts can't be repaired.
+	rawdb.DeleteReceipts(db, blocks[2].Hash(), 3)
+	report = new(verifyReport)
+	if _, err := verifyChain(db, 0, true, report); err != nil {
+		t.Fatal(err)
+	}
+	if report.issues != 1 || report.repaired != 0 {
+		t.Fatalf("missing receipts: issues %d, repaired %d", report.issues, report.repaired)
+	}
+}
+
+// TestMigrateSchemeLegacyCode tests that the contract codes stored in the legacy
+// unprefixed form survive the removal of the hash-based trie nodes.
+func TestMigrateSchemeLegacyCode(t *testing.T) {
+	var (
+		code  = []byte{0x60, 0x01, 0x60, 0x00, 0x55, 0x00}
+		hash  = crypto.Keccak256Hash(code)
+		gspec = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: types.GenesisAlloc{
+				common.Address{0x01}: {Balance: big.NewInt(params.Ether)},
+				common.Address{0x02}: {Balance: big.NewInt(1), Code: code},
+			},
+		}
+	)
+	db, err := rawdb.NewDatabaseWithFreezer(rawdb.NewMemoryDatabase(), "", "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// Initialize the chain in the hash scheme, so that the state snapshot is
+	// generated and journaled.
+	chain, err := core.NewBlockChain(db, gspec, ethash.NewFaker(), core.DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := chain.CurrentBlock().Root
+	chain.Stop()
+
+	// Move the code into the legacy unprefixed storage, both before converting
+	// the state and before removing the leftovers of a converted state.
+	for i := 0; i < 2; i++ {
+		rawdb.DeleteCode(db, hash)
+		if err := db.Put(hash.Bytes(), code); err != nil {
+			t.Fatal(err)
+		}
+		if !rawdb.IsLegacyTrieNode(hash.Bytes(), code) {
+			t.Fatal("legacy code not recognized as a trie node candidate")
+		}
+		if err := migrateScheme(db); err != nil {
+			t.Fatalf("run %d: failed to migrate state scheme: %v", i, err)
+		}
+		if scheme := rawdb.ReadStateScheme(db); scheme != rawdb.PathScheme {
+			t.Fatalf("run %d: unexpected state scheme %q", i, scheme)
+		}
+		if rawdb.HasLegacyTrieNode(db, root) {
+			t.Fatalf("run %d: legacy trie nodes not deleted", i)
+		}
+		if have, _ := db.Get(hash.Bytes()); len(have) != 0 {
+			t.Fatalf("run %d: legacy code entry not deleted", i)
+		}
+		if have := rawdb.ReadCodeWithPrefix(db, hash); !bytes.Equal(have, code) {
+			t.Fatalf("run %d: contract code lost, have %x, want %x", i, have, code)
+		}
+	}
+}
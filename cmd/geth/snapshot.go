@@ -18,11 +18,14 @@ package main
 
 import (
 	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"os"
 	"slices"
+	"strconv"
 	"time"
 
 	"github.com/ethereum/go-ethereum/cmd/utils"
@@ -36,10 +39,41 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/triedb/pathdb"
+	"github.com/olekukonko/tablewriter"
 	"github.com/urfave/cli/v2"
 )
 
 var (
+	snapshotRootFlag = &cli.StringFlag{
+		Name:  "root",
+		Usage: "State root to use (defaults to the state of the head block)",
+	}
+
+	inspectTopFlag = &cli.IntFlag{
+		Name:  "top",
+		Usage: "Number of the top contracts to report",
+		Value: 20,
+	}
+	inspectOrderFlag = &cli.StringFlag{
+		Name:  "sort",
+		Usage: "Order of the top contracts (slots, bytes, growth)",
+		Value: utils.StorageOrderSlots,
+	}
+	inspectFormatFlag = &cli.StringFlag{
+		Name:  "format",
+		Usage: "Output format (table, csv, json)",
+		Value: "table",
+	}
+	inspectStartFlag = &cli.Uint64Flag{
+		Name:  "start",
+		Usage: "First block of the range for the storage growth, requires state history",
+	}
+	inspectEndFlag = &cli.Uint64Flag{
+		Name:  "end",
+		Usage: "Last block of the range for the storage growth (defaults to the latest history)",
+	}
+
 	snapshotCommand = &cli.Command{
 		Name:        "snapshot",
 		Usage:       "A set of commands based on the snapshot",
@@ -159,6 +193,68 @@ block is used.
 				Description: `
 The export-preimages command exports hash preimages to a flat file, in exactly
 the expected order for the overlay tree migration.
+`,
+			},
+			{
+				Action:    snapshotInspectStorage,
+				Name:      "inspect-storage",
+				Usage:     "Report the contracts owning the most storage",
+				ArgsUsage: "",
+				Flags: slices.Concat([]cli.Flag{
+					snapshotRootFlag,
+					inspectTopFlag,
+					inspectOrderFlag,
+					inspectFormatFlag,
+					inspectStartFlag,
+					inspectEndFlag,
+				}, utils.NetworkFlags, utils.DatabaseFlags),
+				Description: `
+geth snapshot inspect-storage [--root <state-root>] [--top N] [--sort slots|bytes|growth]
+will iterate the storage of all the contracts based on the snapshot and report
+the top contracts by the number of storage slots or by the storage size. The
+default inspection target is the HEAD state.
+
+If --start or --end is given, the number of slots created and deleted by each
+contract within the block range is reported too, based on the state histories.
+It's only supported in path mode(--state.scheme=path) and limited to the blocks
+whose state histories are retained.
+
+The report can be printed as a table, or in CSV or JSON format.
+`,
+			},
+			{
+				Action:    snapshotExport,
+				Name:      "export",
+				Usage:     "Export the flat state with range proofs into a portable file",
+				ArgsUsage: "<dumpfile>",
+				Flags: slices.Concat([]cli.Flag{
+					snapshotRootFlag,
+				}, utils.NetworkFlags, utils.DatabaseFlags),
+				Description: `
+geth snapshot export [--root <state-root>] <dumpfile>
+will export all the accounts, storage slots and contract codes of the specified
+state into a single file, along with the Merkle proofs of the chunk boundaries.
+The default export target is the HEAD state. If the file name ends with .gz,
+the output is gzipped.
+
+The exported file can be imported by another node with 'geth snapshot import'.
+`,
+			},
+			{
+				Action:    snapshotImport,
+				Name:      "import",
+				Usage:     "Import the flat state from a file produced by 'snapshot export'",
+				ArgsUsage: "<dumpfile>",
+				Flags:     slices.Concat(utils.NetworkFlags, utils.DatabaseFlags),
+				Description: `
+geth snapshot import <dumpfile>
+will verify every chunk of the exported state against the state root recorded
+in the file and build the path-based state from it, replacing any existing
+state in the database. If the block of the exported state is present in the
+database, it's set as the head block. Otherwise the chain needs to be synced
+to that block afterwards.
+
+WARNING: it's only supported in path mode(--state.scheme=path).
 `,
 			},
 		},
@@ -671,6 +767,161 @@ func snapshotExportPreimages(ctx *cli.Context) error {
 	return utils.ExportSnapshotPreimages(chaindb, snaptree, ctx.Args().First(), root)
 }
 
+func snapshotInspectStorage(ctx *cli.Context) error {
+	if ctx.NArg() > 0 {
+		log.Error("Too many arguments given")
+		return errors.New("too many arguments")
+	}
+	format := ctx.String(inspectFormatFlag.Name)
+	if format != "table" && format != "csv" && format != "json" {
+		return fmt.Errorf("unknown format %q", format)
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chaindb := utils.MakeChainDatabase(ctx, stack, true)
+	defer chaindb.Close()
+
+	triedb := utils.MakeTrieDatabase(ctx, chaindb, false, true, false)
+	defer triedb.Close()
+
+	var (
+		root common.Hash
+		err  error
+	)
+	if ctx.IsSet(snapshotRootFlag.Name) {
+		root, err = parseRoot(ctx.String(snapshotRootFlag.Name))
+		if err != nil {
+			log.Error("Failed to resolve state root", "err", err)
+			return err
+		}
+	} else {
+		headBlock := rawdb.ReadHeadBlock(chaindb)
+		if headBlock == nil {
+			log.Error("Failed to load head block")
+			return errors.New("no head block")
+		}
+		root = headBlock.Root()
+	}
+	var growth *pathdb.StorageGrowthStats
+	if ctx.IsSet(inspectStartFlag.Name) || ctx.IsSet(inspectEndFlag.Name) {
+		end := uint64(math.MaxUint64)
+		if ctx.IsSet(inspectEndFlag.Name) {
+			end = ctx.Uint64(inspectEndFlag.Name)
+		}
+		growth, err = triedb.StorageGrowth(ctx.Uint64(inspectStartFlag.Name), end)
+		if err != nil {
+			log.Error("Failed to inspect storage growth", "err", err)
+			return err
+		}
+	}
+	report, err := utils.InspectStorage(chaindb, triedb, root, ctx.Int(inspectTopFlag.Name), ctx.String(inspectOrderFlag.Name), growth)
+	if err != nil {
+		return err
+	}
+	switch format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		w.Write([]string{"rank", "hash", "address", "slots", "bytes", "created", "deleted"})
+		for i, stats := range report.Top {
+			w.Write(storageStatsRow(i, stats))
+		}
+		w.Flush()
+		return w.Error()
+
+	default:
+		fmt.Printf("State %x: %d contracts, %d slots, %v\n", report.Root, report.Contracts, report.Slots, common.StorageSize(report.Bytes))
+		if growth != nil {
+			fmt.Printf("Storage growth in blocks [#%d-#%d]\n", growth.Start, growth.End)
+		}
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Rank", "Hash", "Address", "Slots", "Bytes", "Created", "Deleted"})
+		for i, stats := range report.Top {
+			table.Append(storageStatsRow(i, stats))
+		}
+		table.Render()
+		return nil
+	}
+}
+
+// storageStatsRow formats the storage statistics of the contract ranked at
+// the given position.
+func storageStatsRow(i int, stats *utils.StorageStats) []string {
+	address := ""
+	if stats.Address != nil {
+		address = stats.Address.Hex()
+	}
+	return []string{
+		strconv.Itoa(i + 1),
+		stats.Hash.Hex(),
+		address,
+		strconv.FormatUint(stats.Slots, 10),
+		strconv.FormatUint(stats.Bytes, 10),
+		strconv.FormatUint(stats.Created, 10),
+		strconv.FormatUint(stats.Deleted, 10),
+	}
+}
+
+func snapshotExport(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chaindb := utils.MakeChainDatabase(ctx, stack, true)
+	defer chaindb.Close()
+
+	triedb := utils.MakeTrieDatabase(ctx, chaindb, false, true, false)
+	defer triedb.Close()
+
+	var (
+		root   common.Hash
+		header *types.Header
+		err    error
+	)
+	if ctx.IsSet(snapshotRootFlag.Name) {
+		root, err = parseRoot(ctx.String(snapshotRootFlag.Name))
+		if err != nil {
+			log.Error("Failed to resolve state root", "err", err)
+			return err
+		}
+		// Record the block if the state belongs to the head block
+		if head := rawdb.ReadHeadHeader(chaindb); head != nil && head.Root == root {
+			header = head
+		}
+	} else {
+		header = rawdb.ReadHeadHeader(chaindb)
+		if header == nil {
+			log.Error("Failed to load head block")
+			return errors.New("no head block")
+		}
+		root = header.Root
+	}
+	return utils.ExportSnapshot(chaindb, triedb, root, header, ctx.Args().First())
+}
+
+func snapshotImport(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chaindb := utils.MakeChainDatabase(ctx, stack, false)
+	defer chaindb.Close()
+
+	triedb := utils.MakeTrieDatabase(ctx, chaindb, false, false, false)
+	defer triedb.Close()
+
+	return utils.ImportSnapshot(chaindb, triedb, ctx.Args().First())
+}
+
 // checkAccount iterates the snap data layers, and looks up the given account
 // across all layers.
 func checkAccount(ctx *cli.Context) error {
@@ -44,10 +44,12 @@ import (
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/internal/debug"
 	"github.com/ethereum/go-ethereum/internal/era"
+	"github.com/ethereum/go-ethereum/internal/era/execdb"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
 	"github.com/urfave/cli/v2"
 )
 
@@ -245,17 +247,37 @@ func readList(filename string) ([]string, error) {
 	return strings.Split(string(b), "\n"), nil
 }
 
-// ImportHistory imports Era1 files containing historical block information,
-// starting from genesis. The assumption is held that the provided chain
-// segment in Era1 file should all be canonical and verified.
+// historyIterator is the common interface of the Era1 and EraE archive iterators.
+type historyIterator interface {
+	Next() bool
+	Number() uint64
+	Block() (*types.Block, error)
+	Receipts() (types.Receipts, error)
+}
+
+// ImportHistory imports Era1 and EraE files containing historical block
+// information. Era1 archives can only be imported starting from genesis, while
+// EraE archives may also extend the local chain from its current snap head or
+// restore pruned history below it. EraE archives are verified against their
+// header accumulator and the blocks already known locally before any of their
+// content is imported.
 func ImportHistory(chain *core.BlockChain, dir string, network string) error {
-	if chain.CurrentSnapBlock().Number.BitLen() != 0 {
-		return errors.New("history import only supported when starting from genesis")
+	era1s, err := era.ReadDir(dir, network)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", dir, err)
 	}
-	entries, err := era.ReadDir(dir, network)
+	first, eraes, err := execdb.ReadDir(dir, network)
 	if err != nil {
 		return fmt.Errorf("error reading %s: %w", dir, err)
 	}
+	if len(era1s) > 0 && len(eraes) > 0 && first != uint64(len(era1s)) {
+		return fmt.Errorf("erae archives starting at epoch %d don't follow era1 archives ending at epoch %d", first, len(era1s)-1)
+	}
+	head := chain.CurrentSnapBlock().Number.Uint64()
+	if len(era1s) > 0 && head != 0 {
+		return errors.New("history import only supported when starting from genesis")
+	}
+	entries := append(era1s, eraes...)
 	checksums, err := readList(filepath.Join(dir, "checksums.txt"))
 	if err != nil {
 		return fmt.Errorf("unable to read checksums.txt: %w", err)
@@ -288,22 +310,48 @@ func ImportHistory(chain *core.BlockChain, dir string, network string) error {
 			h.Reset()
 			buf.Reset()
 
-			// Import all block data from Era1.
-			e, err := era.From(f)
-			if err != nil {
-				return fmt.Errorf("error opening era: %w", err)
-			}
-			it, err := era.NewIterator(e)
-			if err != nil {
-				return fmt.Errorf("error making era reader: %w", err)
+			// Import all block data from the archive.
+			var it historyIterator
+			if i < len(era1s) {
+				e, err := era.From(f)
+				if err != nil {
+					return fmt.Errorf("error opening era: %w", err)
+				}
+				if it, err = era.NewIterator(e); err != nil {
+					return fmt.Errorf("error making era reader: %w", err)
+				}
+			} else {
+				e, err := execdb.From(f)
+				if err != nil {
+					return fmt.Errorf("error opening era: %w", err)
+				}
+				if err := verifyEraE(chain, e, network, first+uint64(i-len(era1s)), filename); err != nil {
+					return err
+				}
+				if it, err = execdb.NewIterator(e); err != nil {
+					return fmt.Errorf("error making era reader: %w", err)
+				}
 			}
+			var (
+				cutoff, _ = chain.HistoryPruningCutoff()
+				restore   bool
+			)
 			for it.Next() {
 				block, err := it.Block()
 				if err != nil {
 					return fmt.Errorf("error reading block %d: %w", it.Number(), err)
 				}
-				if block.Number().BitLen() == 0 {
-					continue // skip genesis
+				if number := block.NumberU64(); number <= head {
+					if number == 0 || len(chain.GetBodyRLP(block.Hash())) > 0 {
+						continue // skip genesis and already present blocks
+					}
+					if number >= cutoff {
+						return fmt.Errorf("missing body of block %d above the history cutoff %d", number, cutoff)
+					}
+					// The block was pruned, its history can be restored by
+					// making the archive available to the era store.
+					restore = true
+					continue
 				}
 				receipts, err := it.Receipts()
 				if err != nil {
@@ -322,6 +370,14 @@ func ImportHistory(chain *core.BlockChain, dir string, network string) error {
 					reported = time.Now()
 				}
 			}
+			if restore {
+				if i < len(era1s) {
+					return errors.New("pruned history can only be restored from erae archives")
+				}
+				if err := chain.ImportEraHistory(filepath.Join(dir, filename)); err != nil {
+					return fmt.Errorf("error restoring pruned history from %s: %w", filename, err)
+				}
+			}
 			return nil
 		}()
 		if err != nil {
@@ -332,6 +388,30 @@ func ImportHistory(chain *core.BlockChain, dir string, network string) error {
 	return nil
 }
 
+// verifyEraE checks that the given EraE archive is well-formed, that its name
+// matches the epoch and header accumulator it contains, and that the blocks it
+// archives don't conflict with the canonical chain known locally.
+func verifyEraE(chain *core.BlockChain, e *execdb.Era, network string, epoch uint64, filename string) error {
+	hashes, err := e.Verify(trie.NewStackTrie(nil))
+	if err != nil {
+		return fmt.Errorf("error verifying %s: %w", filename, err)
+	}
+	root, err := e.Accumulator()
+	if err != nil {
+		return fmt.Errorf("error reading accumulator of %s: %w", filename, err)
+	}
+	if want := execdb.Filename(network, int(epoch), root); filename != want {
+		return fmt.Errorf("archive name mismatch: have %s, want %s", filename, want)
+	}
+	for i, hash := range hashes {
+		number := e.Start() + uint64(i)
+		if have := chain.GetCanonicalHash(number); have != (common.Hash{}) && have != hash {
+			return fmt.Errorf("block %d in %s conflicts with local chain: have %x, archived %x", number, filename, have, hash)
+		}
+	}
+	return nil
+}
+
 func missingBlocks(chain *core.BlockChain, blocks []*types.Block) []*types.Block {
 	head := chain.CurrentBlock()
 	for i, block := range blocks {
@@ -402,7 +482,8 @@ func ExportAppendChain(blockchain *core.BlockChain, fn string, first uint64, las
 }
 
 // ExportHistory exports blockchain history into the specified directory,
-// following the Era format.
+// following the Era format. Epochs containing post-merge blocks are exported
+// as EraE archives, all others as Era1 archives.
 func ExportHistory(bc *core.BlockChain, dir string, first, last, step uint64) error {
 	log.Info("Exporting blockchain history", "dir", dir)
 	if head := bc.CurrentBlock().Number.Uint64(); head < last {
@@ -429,14 +510,37 @@ func ExportHistory(bc *core.BlockChain, dir string, first, last, step uint64) er
 	}
 	for i := first; i <= last; i += step {
 		err := func() error {
-			filename := filepath.Join(dir, era.Filename(network, int(i/step), common.Hash{}))
+			// Post-merge blocks carry no difficulty, archive the epoch in the
+			// EraE format if it ends with one.
+			var (
+				header    = bc.GetHeaderByNumber(min(i+step-1, last))
+				postMerge = header != nil && header.Difficulty.Sign() == 0
+				name      = era.Filename
+			)
+			if postMerge {
+				name = execdb.Filename
+			}
+			filename := filepath.Join(dir, name(network, int(i/step), common.Hash{}))
 			f, err := os.Create(filename)
 			if err != nil {
 				return fmt.Errorf("could not create era file: %w", err)
 			}
 			defer f.Close()
 
-			w := era.NewBuilder(f)
+			var (
+				add      func(block *types.Block, receipts types.Receipts) error
+				finalize func() (common.Hash, error)
+			)
+			if postMerge {
+				w := execdb.NewBuilder(f)
+				add, finalize = w.Add, w.Finalize
+			} else {
+				w := era.NewBuilder(f)
+				add = func(block *types.Block, receipts types.Receipts) error {
+					return w.Add(block, receipts, new(big.Int).Set(td))
+				}
+				finalize = w.Finalize
+			}
 			for j := uint64(0); j < step && j <= last-i; j++ {
 				var (
 					n     = i + j
@@ -450,18 +554,18 @@ func ExportHistory(bc *core.BlockChain, dir string, first, last, step uint64) er
 					return fmt.Errorf("export failed on #%d: receipts not found", n)
 				}
 				td.Add(td, block.Difficulty())
-				if err := w.Add(block, receipts, new(big.Int).Set(td)); err != nil {
+				if err := add(block, receipts); err != nil {
 					return err
 				}
 			}
-			root, err := w.Finalize()
+			root, err := finalize()
 			if err != nil {
 				return fmt.Errorf("export failed to finalize %d: %w", step/i, err)
 			}
 			// Set correct filename with root.
-			os.Rename(filename, filepath.Join(dir, era.Filename(network, int(i/step), root)))
+			os.Rename(filename, filepath.Join(dir, name(network, int(i/step), root)))
 
-			// Compute checksum of entire Era1.
+			// Compute checksum of entire archive.
 			if _, err := f.Seek(0, io.SeekStart); err != nil {
 				return err
 			}
@@ -113,7 +113,29 @@ var (
 	}
 	EraFlag = &flags.DirectoryFlag{
 		Name:     "datadir.era",
-		Usage:    "Root directory for era1 history (default = inside ancient/chain)",
+		Usage:    "Root directory for era1/erae history (default = inside ancient/chain)",
+		Category: flags.EthCategory,
+	}
+	AncientRemoteFlag = &cli.StringFlag{
+		Name:     "datadir.ancient.remote",
+		Usage:    "S3-compatible object store to offload sealed ancient segments to (s3://bucket/prefix?region=<region>&endpoint=<url>&timeout=<duration>, default timeout = 1m)",
+		Category: flags.EthCategory,
+	}
+	AncientRemoteCacheFlag = &cli.IntFlag{
+		Name:     "datadir.ancient.remote.cache",
+		Usage:    "Megabytes of memory allocated to caching the offloaded ancient segments",
+		Value:    ethconfig.Defaults.DatabaseFreezerRemoteCache,
+		Category: flags.EthCategory,
+	}
+	DatabaseSecondaryFlag = &flags.DirectoryFlag{
+		Name:     "db.secondary",
+		Usage:    "Chain database directory of a primary node to follow read-only, instead of syncing (both nodes require --gcmode=archive and --state.scheme=hash)",
+		Category: flags.EthCategory,
+	}
+	DatabaseSecondaryIntervalFlag = &cli.DurationFlag{
+		Name:     "db.secondary.interval",
+		Usage:    "Time interval at which the secondary node catches up with the database of the primary node",
+		Value:    ethconfig.Defaults.DatabaseSecondaryInterval,
 		Category: flags.EthCategory,
 	}
 	MinFreeDiskSpaceFlag = &flags.DirectoryFlag{
@@ -270,6 +292,17 @@ var (
 		Usage:    "Scheme to use for storing ethereum state ('hash' or 'path')",
 		Category: flags.StateCategory,
 	}
+	StatePruneOnlineFlag = &cli.BoolFlag{
+		Name:     "state.prune.online",
+		Usage:    "Delete stale states in the background, only relevant in state.scheme=hash with gcmode=full",
+		Category: flags.StateCategory,
+	}
+	StatePruneRateFlag = &cli.IntFlag{
+		Name:     "state.prune.rate",
+		Usage:    "Maximum number of trie nodes deleted per second by the online state pruning",
+		Value:    ethconfig.Defaults.OnlinePruningRate,
+		Category: flags.StateCategory,
+	}
 	StateHistoryFlag = &cli.Uint64Flag{
 		Name:     "history.state",
 		Usage:    "Number of recent blocks to retain state history for, only relevant in state.scheme=path (default = 90,000 blocks, 0 = entire chain)",
@@ -284,10 +317,22 @@ var (
 	}
 	ChainHistoryFlag = &cli.StringFlag{
 		Name:     "history.chain",
-		Usage:    `Blockchain history retention ("all" or "postmerge")`,
+		Usage:    `Blockchain history retention ("all", "postmerge" or "recent")`,
 		Value:    ethconfig.Defaults.HistoryMode.String(),
 		Category: flags.StateCategory,
 	}
+	ChainHistoryBlocksFlag = &cli.Uint64Flag{
+		Name:     "history.chain.blocks",
+		Usage:    `Number of recent blocks to retain bodies and receipts for, only relevant in history.chain=recent (0 = no block limit)`,
+		Value:    ethconfig.Defaults.HistoryRetentionBlocks,
+		Category: flags.StateCategory,
+	}
+	ChainHistoryDaysFlag = &cli.Uint64Flag{
+		Name:     "history.chain.days",
+		Usage:    `Number of days of recent blocks to retain bodies and receipts for, only relevant in history.chain=recent (0 = no time limit)`,
+		Value:    ethconfig.Defaults.HistoryRetentionDays,
+		Category: flags.StateCategory,
+	}
 	LogHistoryFlag = &cli.Uint64Flag{
 		Name:     "history.logs",
 		Usage:    "Number of recent blocks to maintain log search index for (default = about one year, 0 = entire chain)",
@@ -534,6 +579,11 @@ var (
 		Usage:    "0x prefixed public address for the pending block producer (not used for actual block production)",
 		Category: flags.MinerCategory,
 	}
+	MinerForcedSendersFlag = &cli.StringFlag{
+		Name:     "miner.forcedsenders",
+		Usage:    "Comma separated accounts whose pending transactions are always included first into built payloads",
+		Category: flags.MinerCategory,
+	}
 
 	// Account settings
 	PasswordFileFlag = &cli.PathFlag{
@@ -565,6 +615,29 @@ var (
 		Value:    "{}",
 		Category: flags.VMCategory,
 	}
+	// State diff export settings
+	StateDiffPathFlag = &flags.DirectoryFlag{
+		Name:     "statediff.path",
+		Usage:    "Directory of the rotating files to export the state diff of every imported block into",
+		Category: flags.StateCategory,
+	}
+	StateDiffSocketFlag = &cli.StringFlag{
+		Name:     "statediff.socket",
+		Usage:    "Path of the unix socket to stream the state diff of every imported block on",
+		Category: flags.StateCategory,
+	}
+	StateDiffFormatFlag = &cli.StringFlag{
+		Name:     "statediff.format",
+		Usage:    `Encoding of the exported state diffs ("json" or "rlp")`,
+		Value:    ethconfig.Defaults.StateDiffFormat,
+		Category: flags.StateCategory,
+	}
+	StateDiffMaxSizeFlag = &cli.IntFlag{
+		Name:     "statediff.maxsize",
+		Usage:    "Maximum size in megabytes of a state diff file before it gets rotated",
+		Value:    ethconfig.Defaults.StateDiffMaxSize,
+		Category: flags.StateCategory,
+	}
 	// API options.
 	RPCGlobalGasCapFlag = &cli.Uint64Flag{
 		Name:     "rpc.gascap",
@@ -584,6 +657,11 @@ var (
 		Value:    ethconfig.Defaults.RPCTxFeeCap,
 		Category: flags.APICategory,
 	}
+	RPCDatabaseWriteFlag = &cli.BoolFlag{
+		Name:     "rpc.dbwrite",
+		Usage:    "Allow modifying the database through the debug_db* RPC APIs",
+		Category: flags.APICategory,
+	}
 	// Authenticated RPC HTTP settings
 	AuthListenFlag = &cli.StringFlag{
 		Name:     "authrpc.addr",
@@ -983,6 +1061,10 @@ var (
 		DataDirFlag,
 		AncientFlag,
 		EraFlag,
+		AncientRemoteFlag,
+		AncientRemoteCacheFlag,
+		DatabaseSecondaryFlag,
+		DatabaseSecondaryIntervalFlag,
 		RemoteDBFlag,
 		DBEngineFlag,
 		StateSchemeFlag,
@@ -1529,6 +1611,15 @@ func setMiner(ctx *cli.Context, cfg *miner.Config) {
 		log.Warn("The flag --miner.newpayload-timeout is deprecated and will be removed, please use --miner.recommit")
 		cfg.Recommit = ctx.Duration(MinerNewPayloadTimeoutFlag.Name)
 	}
+	if ctx.IsSet(MinerForcedSendersFlag.Name) {
+		for _, account := range strings.Split(ctx.String(MinerForcedSendersFlag.Name), ",") {
+			if trimmed := strings.TrimSpace(account); !common.IsHexAddress(trimmed) {
+				Fatalf("Invalid account in --%s: %s", MinerForcedSendersFlag.Name, trimmed)
+			} else {
+				cfg.ForcedSenders = append(cfg.ForcedSenders, common.HexToAddress(trimmed))
+			}
+		}
+	}
 }
 
 func setRequiredBlocks(ctx *cli.Context, cfg *ethconfig.Config) {
@@ -1608,6 +1699,12 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 			Fatalf("--%s: %v", ChainHistoryFlag.Name, err)
 		}
 	}
+	if ctx.IsSet(ChainHistoryBlocksFlag.Name) {
+		cfg.HistoryRetentionBlocks = ctx.Uint64(ChainHistoryBlocksFlag.Name)
+	}
+	if ctx.IsSet(ChainHistoryDaysFlag.Name) {
+		cfg.HistoryRetentionDays = ctx.Uint64(ChainHistoryDaysFlag.Name)
+	}
 
 	if ctx.IsSet(NetworkIdFlag.Name) {
 		cfg.NetworkId = ctx.Uint64(NetworkIdFlag.Name)
@@ -1622,6 +1719,21 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(EraFlag.Name) {
 		cfg.DatabaseEra = ctx.String(EraFlag.Name)
 	}
+	if ctx.IsSet(AncientRemoteFlag.Name) {
+		cfg.DatabaseFreezerRemote = ctx.String(AncientRemoteFlag.Name)
+	}
+	if ctx.IsSet(AncientRemoteCacheFlag.Name) {
+		cfg.DatabaseFreezerRemoteCache = ctx.Int(AncientRemoteCacheFlag.Name)
+	}
+	if ctx.IsSet(DatabaseSecondaryFlag.Name) {
+		cfg.DatabaseSecondary = ctx.String(DatabaseSecondaryFlag.Name)
+	}
+	if ctx.IsSet(DatabaseSecondaryIntervalFlag.Name) {
+		cfg.DatabaseSecondaryInterval = ctx.Duration(DatabaseSecondaryIntervalFlag.Name)
+		if cfg.DatabaseSecondaryInterval <= 0 {
+			Fatalf("--%s must be positive", DatabaseSecondaryIntervalFlag.Name)
+		}
+	}
 
 	if gcmode := ctx.String(GCModeFlag.Name); gcmode != "full" && gcmode != "archive" {
 		Fatalf("--%s must be either 'full' or 'archive'", GCModeFlag.Name)
@@ -1629,9 +1741,18 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(GCModeFlag.Name) {
 		cfg.NoPruning = ctx.String(GCModeFlag.Name) == "archive"
 	}
+	if cfg.DatabaseSecondary != "" && !cfg.NoPruning {
+		Fatalf("--%s requires --%s=archive", DatabaseSecondaryFlag.Name, GCModeFlag.Name)
+	}
 	if ctx.IsSet(CacheNoPrefetchFlag.Name) {
 		cfg.NoPrefetch = ctx.Bool(CacheNoPrefetchFlag.Name)
 	}
+	if ctx.IsSet(StatePruneOnlineFlag.Name) {
+		cfg.OnlinePruning = ctx.Bool(StatePruneOnlineFlag.Name)
+	}
+	if ctx.IsSet(StatePruneRateFlag.Name) {
+		cfg.OnlinePruningRate = ctx.Int(StatePruneRateFlag.Name)
+	}
 	// Read the value from the flag no matter if it's set or not.
 	cfg.Preimages = ctx.Bool(CachePreimagesFlag.Name)
 	if cfg.NoPruning && !cfg.Preimages {
@@ -1716,6 +1837,9 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(RPCGlobalTxFeeCapFlag.Name) {
 		cfg.RPCTxFeeCap = ctx.Float64(RPCGlobalTxFeeCapFlag.Name)
 	}
+	if ctx.IsSet(RPCDatabaseWriteFlag.Name) {
+		cfg.RPCDatabaseWrite = ctx.Bool(RPCDatabaseWriteFlag.Name)
+	}
 	if ctx.IsSet(NoDiscoverFlag.Name) {
 		cfg.EthDiscoveryURLs, cfg.SnapDiscoveryURLs = []string{}, []string{}
 	} else if ctx.IsSet(DNSDiscoveryFlag.Name) {
@@ -1852,6 +1976,19 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 			cfg.VMTraceJsonConfig = ctx.String(VMTraceJsonConfigFlag.Name)
 		}
 	}
+	// State diff export config.
+	if ctx.IsSet(StateDiffPathFlag.Name) {
+		cfg.StateDiffPath = ctx.String(StateDiffPathFlag.Name)
+	}
+	if ctx.IsSet(StateDiffSocketFlag.Name) {
+		cfg.StateDiffSocket = ctx.String(StateDiffSocketFlag.Name)
+	}
+	if ctx.IsSet(StateDiffFormatFlag.Name) {
+		cfg.StateDiffFormat = ctx.String(StateDiffFormatFlag.Name)
+	}
+	if ctx.IsSet(StateDiffMaxSizeFlag.Name) {
+		cfg.StateDiffMaxSize = ctx.Int(StateDiffMaxSizeFlag.Name)
+	}
 }
 
 // MakeBeaconLightConfig constructs a beacon light client config based on the
@@ -2091,12 +2228,14 @@ func MakeChainDatabase(ctx *cli.Context, stack *node.Node, readonly bool) ethdb.
 		chainDb = remotedb.New(client)
 	default:
 		options := node.DatabaseOptions{
-			ReadOnly:          readonly,
-			Cache:             cache,
-			Handles:           handles,
-			AncientsDirectory: ctx.String(AncientFlag.Name),
-			MetricsNamespace:  "eth/db/chaindata/",
-			EraDirectory:      ctx.String(EraFlag.Name),
+			ReadOnly:            readonly,
+			Cache:               cache,
+			Handles:             handles,
+			AncientsDirectory:   ctx.String(AncientFlag.Name),
+			MetricsNamespace:    "eth/db/chaindata/",
+			EraDirectory:        ctx.String(EraFlag.Name),
+			AncientsRemote:      ctx.String(AncientRemoteFlag.Name),
+			AncientsRemoteCache: ctx.Int(AncientRemoteCacheFlag.Name),
 		}
 		chainDb, err = stack.OpenDatabaseWithOptions("chaindata", options)
 	}
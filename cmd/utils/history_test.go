@@ -27,12 +27,15 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/history"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/internal/era"
+	"github.com/ethereum/go-ethereum/internal/era/execdb"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/trie"
 	"github.com/ethereum/go-ethereum/triedb"
@@ -177,3 +180,215 @@ func TestHistoryImportAndExport(t *testing.T) {
 		t.Fatalf("imported chain does not match expected, have (%d, %s) want (%d, %s)", have.Number, have.Hash(), want.Number, want.Hash())
 	}
 }
+
+func TestHistoryImportAndExportPostMerge(t *testing.T) {
+	var (
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		genesis = &core.Genesis{
+			Config:  params.MergedTestChainConfig,
+			Alloc:   types.GenesisAlloc{address: {Balance: big.NewInt(1000000000000000000)}},
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		signer = types.LatestSigner(genesis.Config)
+		engine = beacon.New(ethash.NewFaker())
+	)
+	db, blocks, _ := core.GenerateChainWithGenesis(genesis, engine, int(count), func(i int, g *core.BlockGen) {
+		tx, err := types.SignNewTx(key, signer, &types.DynamicFeeTx{
+			ChainID:   genesis.Config.ChainID,
+			Nonce:     uint64(i),
+			GasTipCap: common.Big0,
+			GasFeeCap: g.BaseFee(),
+			Gas:       50000,
+			To:        &common.Address{0xaa},
+			Value:     big.NewInt(int64(i)),
+		})
+		if err != nil {
+			t.Fatalf("error creating tx: %v", err)
+		}
+		g.AddTx(tx)
+	})
+	chain, err := core.NewBlockChain(db, genesis, engine, nil)
+	if err != nil {
+		t.Fatalf("unable to initialize chain: %v", err)
+	}
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("error inserting chain: %v", err)
+	}
+	dir := t.TempDir()
+	if err := ExportHistory(chain, dir, 0, count, step); err != nil {
+		t.Fatalf("error exporting history: %v", err)
+	}
+	// All epochs contain post-merge blocks, they must be archived as EraE.
+	if entries, _ := era.ReadDir(dir, "mainnet"); len(entries) != 0 {
+		t.Fatalf("unexpected era1 archives: %v", entries)
+	}
+	first, entries, err := execdb.ReadDir(dir, "mainnet")
+	if err != nil {
+		t.Fatalf("error reading erae archives: %v", err)
+	}
+	if first != 0 || len(entries) != int(count/step)+1 {
+		t.Fatalf("unexpected erae archives from epoch %d: %v", first, entries)
+	}
+	for i, filename := range entries {
+		e, err := execdb.Open(filepath.Join(dir, filename))
+		if err != nil {
+			t.Fatalf("error opening era: %v", err)
+		}
+		hashes, err := e.Verify(trie.NewStackTrie(nil))
+		e.Close()
+		if err != nil {
+			t.Fatalf("error verifying era %s: %v", filename, err)
+		}
+		for j, hash := range hashes {
+			if want := chain.GetCanonicalHash(uint64(i)*step + uint64(j)); hash != want {
+				t.Fatalf("block %d hash mismatch: have %x, want %x", uint64(i)*step+uint64(j), hash, want)
+			}
+		}
+	}
+	// Import the archives into a fresh chain.
+	db2, err := rawdb.Open(rawdb.NewMemoryDatabase(), rawdb.OpenOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db2.Close()
+	})
+	genesis.MustCommit(db2, triedb.NewDatabase(db2, triedb.HashDefaults))
+	imported, err := core.NewBlockChain(db2, genesis, engine, nil)
+	if err != nil {
+		t.Fatalf("unable to initialize chain: %v", err)
+	}
+	if err := ImportHistory(imported, dir, "mainnet"); err != nil {
+		t.Fatalf("failed to import chain: %v", err)
+	}
+	if have, want := imported.CurrentHeader(), chain.CurrentHeader(); have.Hash() != want.Hash() {
+		t.Fatalf("imported chain does not match expected, have (%d, %s) want (%d, %s)", have.Number, have.Hash(), want.Number, want.Hash())
+	}
+}
+
+// TestHistoryImportPruned tests that importing EraE archives into a chain with
+// pruned history verifies them against the local chain and restores the bodies
+// and receipts of the pruned blocks.
+func TestHistoryImportPruned(t *testing.T) {
+	var (
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		genesis = &core.Genesis{
+			Config:  params.MergedTestChainConfig,
+			Alloc:   types.GenesisAlloc{address: {Balance: big.NewInt(1000000000000000000)}},
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		signer  = types.LatestSigner(genesis.Config)
+		engine  = beacon.New(ethash.NewFaker())
+		frozen  = uint64(96)
+		pruned  = uint64(64)
+		datadir = t.TempDir()
+		eradir  = filepath.Join(datadir, "era")
+	)
+	db, blocks, receipts := core.GenerateChainWithGenesis(genesis, engine, int(count), func(i int, g *core.BlockGen) {
+		tx, err := types.SignNewTx(key, signer, &types.DynamicFeeTx{
+			ChainID:   genesis.Config.ChainID,
+			Nonce:     uint64(i),
+			GasTipCap: common.Big0,
+			GasFeeCap: g.BaseFee(),
+			Gas:       50000,
+			To:        &common.Address{0xaa},
+			Value:     big.NewInt(int64(i)),
+		})
+		if err != nil {
+			t.Fatalf("error creating tx: %v", err)
+		}
+		g.AddTx(tx)
+	})
+	chain, err := core.NewBlockChain(db, genesis, engine, nil)
+	if err != nil {
+		t.Fatalf("unable to initialize chain: %v", err)
+	}
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("error inserting chain: %v", err)
+	}
+	// Only archives aligned to the era store epochs can restore pruned history.
+	dir := t.TempDir()
+	if err := ExportHistory(chain, dir, 0, count, uint64(execdb.MaxEraESize)); err != nil {
+		t.Fatalf("error exporting history: %v", err)
+	}
+	// Snap sync a second chain and prune its history.
+	db2, err := rawdb.Open(rawdb.NewMemoryDatabase(), rawdb.OpenOptions{Ancient: datadir, Era: eradir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db2.Close()
+	})
+	synced, err := core.NewBlockChain(db2, genesis, engine, nil)
+	if err != nil {
+		t.Fatalf("unable to initialize chain: %v", err)
+	}
+	if n, err := synced.InsertReceiptChain(blocks, types.EncodeBlockReceiptLists(receipts), frozen); err != nil {
+		t.Fatalf("failed to insert receipt %d: %v", n, err)
+	}
+	synced.Stop()
+	if _, err := db2.TruncateTail(pruned); err != nil {
+		t.Fatalf("failed to truncate history: %v", err)
+	}
+	config := core.DefaultConfig()
+	config.ChainHistoryMode = history.KeepRecent
+	config.ChainHistoryBlocks = 2 * count
+	imported, err := core.NewBlockChain(db2, genesis, engine, config)
+	if err != nil {
+		t.Fatalf("unable to initialize chain: %v", err)
+	}
+	defer imported.Stop()
+
+	if cutoff, _ := imported.HistoryPruningCutoff(); cutoff != pruned {
+		t.Fatalf("unexpected history cutoff: have %d, want %d", cutoff, pruned)
+	}
+	if floor := imported.HistoryFloor(); floor != pruned {
+		t.Fatalf("unexpected history floor: have %d, want %d", floor, pruned)
+	}
+	// Archives of a different chain must be rejected.
+	forkGenesis := &core.Genesis{
+		Config:  genesis.Config,
+		Alloc:   types.GenesisAlloc{address: {Balance: big.NewInt(2000000000000000000)}},
+		BaseFee: genesis.BaseFee,
+	}
+	forkDB, forkBlocks, _ := core.GenerateChainWithGenesis(forkGenesis, engine, int(count), nil)
+	fork, err := core.NewBlockChain(forkDB, forkGenesis, engine, nil)
+	if err != nil {
+		t.Fatalf("unable to initialize chain: %v", err)
+	}
+	if _, err := fork.InsertChain(forkBlocks); err != nil {
+		t.Fatalf("error inserting chain: %v", err)
+	}
+	forkDir := t.TempDir()
+	if err := ExportHistory(fork, forkDir, 0, count, uint64(execdb.MaxEraESize)); err != nil {
+		t.Fatalf("error exporting history: %v", err)
+	}
+	if err := ImportHistory(imported, forkDir, "mainnet"); err == nil {
+		t.Fatal("expected conflicting archive to be rejected")
+	}
+	if floor := imported.HistoryFloor(); floor != pruned {
+		t.Fatalf("unexpected history floor after rejected import: have %d, want %d", floor, pruned)
+	}
+	// Importing the archives twice must be a noop the second time.
+	for i := 0; i < 2; i++ {
+		if err := ImportHistory(imported, dir, "mainnet"); err != nil {
+			t.Fatalf("failed to import history: %v", err)
+		}
+	}
+	if floor := imported.HistoryFloor(); floor != 0 {
+		t.Fatalf("unexpected history floor after import: have %d, want 0", floor)
+	}
+	for i, block := range blocks {
+		number := block.NumberU64()
+		body := rawdb.ReadBody(db2, block.Hash(), number)
+		if body == nil || types.DeriveSha(types.Transactions(body.Transactions), trie.NewStackTrie(nil)) != block.TxHash() {
+			t.Fatalf("block %d body not restored", number)
+		}
+		have := rawdb.ReadReceipts(db2, block.Hash(), number, block.Time(), genesis.Config)
+		if have == nil || types.DeriveSha(have, trie.NewStackTrie(nil)) != types.DeriveSha(receipts[i], trie.NewStackTrie(nil)) {
+			t.Fatalf("block %d receipts not restored", number)
+		}
+	}
+}
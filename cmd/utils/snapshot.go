@@ -0,0 +1,814 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state/snapshot"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/trie/trienode"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/ethereum/go-ethereum/triedb/pathdb"
+)
+
+// The state snapshot file is an RLP stream, optionally compressed with gzip if
+// the file name has the .gz suffix. It starts with a snapshotFileHeader, which
+// is followed by the chunks of the state:
+//
+//   - an account chunk, carrying a range of accounts in the full consensus
+//     encoding, keyed by the account hash
+//   - the storage chunks of the accounts in the preceding account chunk with
+//     non-empty storage, in the order of the accounts, carrying a range of the
+//     storage slots keyed by the slot hash
+//   - a code chunk, carrying the contract codes of the accounts in the preceding
+//     account chunk, which have not been exported yet, keyed by the code hash
+//
+// Each account and storage chunk covers the keys starting from its origin up to
+// the last key included, with the Merkle proofs of the origin and the last key.
+// The proofs are omitted if the chunk covers the entire trie. The ranges of the
+// subsequent chunks are adjacent, making it possible to verify all of them with
+// trie.VerifyRangeProof against the state root, or the storage root.
+const (
+	snapshotFileVersion = 1
+
+	snapshotChunkAccounts = 0 // Chunk of accounts
+	snapshotChunkStorage  = 1 // Chunk of storage slots of a single account
+	snapshotChunkCodes    = 2 // Chunk of contract codes
+)
+
+var (
+	snapshotAccountChunkSize = 16384 // Number of accounts in a chunk, variable for testing
+	snapshotStorageChunkSize = 16384 // Number of storage slots in a chunk, variable for testing
+)
+
+// snapshotFileHeader is the header of the state snapshot file.
+type snapshotFileHeader struct {
+	Version uint64      // Version of the file format
+	Root    common.Hash // State root of the exported state
+	Number  uint64      // Number of the block with the exported state, zero if unknown
+	Hash    common.Hash // Hash of the block with the exported state, zero if unknown
+}
+
+// snapshotChunk is a range of the accounts, the storage slots of an account or
+// a set of contract codes.
+type snapshotChunk struct {
+	Kind    uint64        // Type of the chunk
+	Account common.Hash   // Hash of the account owning the storage chunk
+	Origin  common.Hash   // First key of the range covered by the chunk
+	Keys    []common.Hash // Sorted keys of the entries in the chunk
+	Values  [][]byte      // Values of the entries in the chunk
+	Proof   [][]byte      // Merkle proofs of the origin and the last key
+}
+
+// flatIterator is the common interface of the flat state iterators of the hash
+// and path based schemes.
+type flatIterator interface {
+	Next() bool
+	Error() error
+	Hash() common.Hash
+	Release()
+}
+
+// flatState provides the flat state of the given root from the snapshot in hash
+// scheme, or from the trie database in path scheme.
+type flatState struct {
+	root     common.Hash
+	snaptree *snapshot.Tree
+	triedb   *triedb.Database
+}
+
+func newFlatState(db ethdb.Database, tdb *triedb.Database, root common.Hash) (*flatState, error) {
+	if tdb.Scheme() == rawdb.PathScheme {
+		return &flatState{root: root, triedb: tdb}, nil
+	}
+	snaptree, err := snapshot.New(snapshot.Config{CacheSize: 256, NoBuild: true}, db, tdb, root)
+	if err != nil {
+		return nil, err
+	}
+	return &flatState{root: root, snaptree: snaptree}, nil
+}
+
+// accounts returns the iterator of the accounts and a function to retrieve the
+// value of the current account.
+func (s *flatState) accounts() (flatIterator, func() []byte, error) {
+	if s.snaptree != nil {
+		it, err := s.snaptree.AccountIterator(s.root, common.Hash{})
+		if err != nil {
+			return nil, nil, err
+		}
+		return it, it.Account, nil
+	}
+	it, err := s.triedb.AccountIterator(s.root, common.Hash{})
+	if err != nil {
+		return nil, nil, err
+	}
+	return it, it.Account, nil
+}
+
+// storage returns the iterator of the storage slots of the given account and a
+// function to retrieve the value of the current slot.
+func (s *flatState) storage(account common.Hash) (flatIterator, func() []byte, error) {
+	if s.snaptree != nil {
+		it, err := s.snaptree.StorageIterator(s.root, account, common.Hash{})
+		if err != nil {
+			return nil, nil, err
+		}
+		return it, it.Slot, nil
+	}
+	it, err := s.triedb.StorageIterator(s.root, account, common.Hash{})
+	if err != nil {
+		return nil, nil, err
+	}
+	return it, it.Slot, nil
+}
+
+// ExportSnapshot exports the state with the given root into the specified file,
+// along with the range proofs needed to verify it on import. The header of the
+// block with the state is recorded if given.
+func ExportSnapshot(db ethdb.Database, tdb *triedb.Database, root common.Hash, header *types.Header, fn string) error {
+	if tdb.IsVerkle() {
+		return errors.New("verkle state is not supported")
+	}
+	state, err := newFlatState(db, tdb, root)
+	if err != nil {
+		return err
+	}
+	accTrie, err := trie.New(trie.StateTrieID(root), tdb)
+	if err != nil {
+		return err
+	}
+	log.Info("Exporting state snapshot", "root", root, "file", fn)
+
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	// Enable gzip compressing if file name has gz suffix.
+	var (
+		writer io.Writer = fh
+		gz     *gzip.Writer
+	)
+	if strings.HasSuffix(fn, ".gz") {
+		gz = gzip.NewWriter(writer)
+		writer = gz
+	}
+	buf := bufio.NewWriter(writer)
+	writer = buf
+
+	head := snapshotFileHeader{Version: snapshotFileVersion, Root: root}
+	if header != nil {
+		head.Number, head.Hash = header.Number.Uint64(), header.Hash()
+	}
+	if err := rlp.Encode(writer, &head); err != nil {
+		return err
+	}
+	accIt, accValue, err := state.accounts()
+	if err != nil {
+		return err
+	}
+	defer accIt.Release()
+
+	var (
+		start    = time.Now()
+		logged   = time.Now()
+		accounts int
+		slots    int
+		codes    = make(map[common.Hash]struct{})
+	)
+	err = exportRanges(accIt, accValue, snapshotAccountChunkSize, accTrie, func(chunk *snapshotChunk) error {
+		chunk.Kind = snapshotChunkAccounts
+
+		// Convert the accounts into the consensus encoding, as stored in the trie
+		var (
+			storages []common.Hash
+			roots    []common.Hash
+			code     = &snapshotChunk{Kind: snapshotChunkCodes}
+		)
+		for i, blob := range chunk.Values {
+			account, err := types.FullAccount(blob)
+			if err != nil {
+				return err
+			}
+			if chunk.Values[i], err = rlp.EncodeToBytes(account); err != nil {
+				return err
+			}
+			if account.Root != types.EmptyRootHash {
+				storages, roots = append(storages, chunk.Keys[i]), append(roots, account.Root)
+			}
+			codeHash := common.BytesToHash(account.CodeHash)
+			if codeHash == types.EmptyCodeHash {
+				continue
+			}
+			if _, ok := codes[codeHash]; ok {
+				continue
+			}
+			blob := rawdb.ReadCode(db, codeHash)
+			if len(blob) == 0 {
+				return fmt.Errorf("contract code %x is missing", codeHash)
+			}
+			codes[codeHash] = struct{}{}
+			code.Keys, code.Values = append(code.Keys, codeHash), append(code.Values, blob)
+		}
+		if err := rlp.Encode(writer, chunk); err != nil {
+			return err
+		}
+		accounts += len(chunk.Keys)
+
+		// Export the storages of the accounts in the chunk
+		for i, account := range storages {
+			stTrie, err := trie.New(trie.StorageTrieID(root, account, roots[i]), tdb)
+			if err != nil {
+				return err
+			}
+			stIt, stValue, err := state.storage(account)
+			if err != nil {
+				return err
+			}
+			err = exportRanges(stIt, stValue, snapshotStorageChunkSize, stTrie, func(chunk *snapshotChunk) error {
+				chunk.Kind, chunk.Account = snapshotChunkStorage, account
+				slots += len(chunk.Keys)
+				return rlp.Encode(writer, chunk)
+			})
+			stIt.Release()
+			if err != nil {
+				return err
+			}
+		}
+		if err := rlp.Encode(writer, code); err != nil {
+			return err
+		}
+		if time.Since(logged) > 8*time.Second && len(chunk.Keys) > 0 {
+			log.Info("Exporting state snapshot", "at", chunk.Keys[len(chunk.Keys)-1], "accounts", accounts, "slots", slots, "codes", len(codes), "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	// Flush and close the file explicitly, the export is incomplete if any of
+	// the buffered data fails to be written.
+	if err := buf.Flush(); err != nil {
+		return err
+	}
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			return err
+		}
+	}
+	if err := fh.Close(); err != nil {
+		return err
+	}
+	log.Info("Exported state snapshot", "root", root, "accounts", accounts, "slots", slots, "codes", len(codes), "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// exportRanges splits the entries of the iterator into chunks of the given size,
+// attaching the range proofs generated from the trie. The trie is guaranteed to
+// be non-empty, an empty chunk is exported for the empty account trie.
+func exportRanges(it flatIterator, value func() []byte, size int, tr *trie.Trie, export func(chunk *snapshotChunk) error) error {
+	var (
+		chunk = new(snapshotChunk)
+		next  = it.Next()
+	)
+	for {
+		for next && len(chunk.Keys) < size {
+			chunk.Keys = append(chunk.Keys, it.Hash())
+			chunk.Values = append(chunk.Values, common.CopyBytes(value()))
+			next = it.Next()
+		}
+		if err := it.Error(); err != nil {
+			return err
+		}
+		// The proofs can be omitted if the chunk contains the entire trie
+		if chunk.Origin != (common.Hash{}) || next {
+			proof := trienode.NewProofSet()
+			if err := tr.Prove(chunk.Origin.Bytes(), proof); err != nil {
+				return err
+			}
+			if len(chunk.Keys) > 0 {
+				if err := tr.Prove(chunk.Keys[len(chunk.Keys)-1].Bytes(), proof); err != nil {
+					return err
+				}
+			}
+			chunk.Proof = proof.List()
+		}
+		if err := export(chunk); err != nil {
+			return err
+		}
+		if !next {
+			return nil
+		}
+		chunk = &snapshotChunk{Origin: incHash(chunk.Keys[len(chunk.Keys)-1])}
+	}
+}
+
+// incHash returns the hash directly following the given one.
+func incHash(h common.Hash) common.Hash {
+	for i := len(h) - 1; i >= 0; i-- {
+		h[i]++
+		if h[i] != 0 {
+			break
+		}
+	}
+	return h
+}
+
+// ImportSnapshot imports the state from the specified snapshot file into the
+// database in path scheme, replacing the existing state. All the ranges of the
+// state are verified against the state root recorded in the file, before the
+// existing state is touched. If the block with the imported state is available
+// in the database, it's set as the head block, otherwise the state is adopted
+// once the chain reaches that block.
+func ImportSnapshot(db ethdb.Database, tdb *triedb.Database, fn string) error {
+	if tdb.Scheme() != rawdb.PathScheme {
+		return errors.New("state snapshot import is only supported in path scheme")
+	}
+	if tdb.IsVerkle() {
+		return errors.New("verkle state is not supported")
+	}
+	// Verify the entire file first, a truncated or corrupted file must not leave
+	// the node without a usable state.
+	log.Info("Verifying state snapshot", "file", fn)
+	if _, _, err := importSnapshotFile(db, fn, discardBatch{}); err != nil {
+		return err
+	}
+	// Drop the existing state, the stale trie nodes and flat states would
+	// otherwise be mixed with the imported ones.
+	log.Info("Importing state snapshot", "file", fn)
+	if err := wipePathState(db); err != nil {
+		return err
+	}
+	head, imp, err := importSnapshotFile(db, fn, db.NewBatch())
+	if err != nil {
+		return err
+	}
+	// Reset the trie database with the imported state as the persistent one
+	if err := tdb.Enable(head.Root); err != nil {
+		return err
+	}
+	if head.Hash != (common.Hash{}) {
+		if header := rawdb.ReadHeader(db, head.Hash, head.Number); header != nil && header.Root == head.Root && rawdb.ReadCanonicalHash(db, head.Number) == head.Hash {
+			rawdb.WriteHeadBlockHash(db, head.Hash)
+			rawdb.WriteHeadFastBlockHash(db, head.Hash)
+			log.Info("Updated head block", "number", head.Number, "hash", head.Hash)
+		} else {
+			log.Warn("Block of the imported state is not available", "number", head.Number, "hash", head.Hash)
+		}
+	}
+	log.Info("Imported state snapshot", "root", head.Root, "accounts", imp.accounts, "slots", imp.slots, "codes", imp.codes, "elapsed", common.PrettyDuration(time.Since(imp.start)))
+	return nil
+}
+
+// importSnapshotFile verifies the chunks of the snapshot file and writes the
+// state into the given batch, which is flushed into the database periodically.
+func importSnapshotFile(db ethdb.Database, fn string, batch ethdb.Batch) (*snapshotFileHeader, *snapshotImporter, error) {
+	fh, err := os.Open(fn)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer fh.Close()
+
+	var reader io.Reader = bufio.NewReader(fh)
+	if strings.HasSuffix(fn, ".gz") {
+		if reader, err = gzip.NewReader(reader); err != nil {
+			return nil, nil, err
+		}
+	}
+	stream := rlp.NewStream(reader, 0)
+
+	var head snapshotFileHeader
+	if err := stream.Decode(&head); err != nil {
+		return nil, nil, fmt.Errorf("invalid snapshot file header: %v", err)
+	}
+	if head.Version != snapshotFileVersion {
+		return nil, nil, fmt.Errorf("unsupported snapshot file version %d", head.Version)
+	}
+	imp := newSnapshotImporter(db, head.Root, batch)
+	for {
+		chunk := new(snapshotChunk)
+		if err := stream.Decode(chunk); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, nil, err
+		}
+		if err := imp.process(chunk); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := imp.finish(); err != nil {
+		return nil, nil, err
+	}
+	return &head, imp, nil
+}
+
+// discardBatch is a batch dropping all the writes, used to verify the snapshot
+// file without modifying the database.
+type discardBatch struct{}
+
+func (discardBatch) Put(key, value []byte) error         { return nil }
+func (discardBatch) Delete(key []byte) error             { return nil }
+func (discardBatch) DeleteRange(start, end []byte) error { return nil }
+func (discardBatch) ValueSize() int                      { return 0 }
+func (discardBatch) Write() error                        { return nil }
+func (discardBatch) Reset()                              {}
+func (discardBatch) Replay(w ethdb.KeyValueWriter) error { return nil }
+
+// wipePathState deletes all the trie nodes and flat states of the path scheme.
+func wipePathState(db ethdb.KeyValueStore) error {
+	for _, item := range []struct {
+		prefix []byte
+		match  func(key []byte) bool
+	}{
+		{rawdb.TrieNodeAccountPrefix, rawdb.IsAccountTrieNode},
+		{rawdb.TrieNodeStoragePrefix, rawdb.IsStorageTrieNode},
+		{rawdb.SnapshotAccountPrefix, func(key []byte) bool { return len(key) == len(rawdb.SnapshotAccountPrefix)+common.HashLength }},
+		{rawdb.SnapshotStoragePrefix, func(key []byte) bool { return len(key) == len(rawdb.SnapshotStoragePrefix)+2*common.HashLength }},
+	} {
+		it := db.NewIterator(item.prefix, nil)
+		batch := db.NewBatch()
+		for it.Next() {
+			if !item.match(it.Key()) {
+				continue
+			}
+			batch.Delete(it.Key())
+			if batch.ValueSize() >= ethdb.IdealBatchSize {
+				if err := batch.Write(); err != nil {
+					it.Release()
+					return err
+				}
+				batch.Reset()
+			}
+		}
+		it.Release()
+		if err := batch.Write(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// snapshotImporter verifies the chunks of the state snapshot file and writes
+// the trie nodes and the flat states into the database.
+type snapshotImporter struct {
+	db    ethdb.Database
+	root  common.Hash
+	batch ethdb.Batch
+
+	accTrie  *trie.StackTrie
+	accNext  common.Hash // Origin of the next account chunk
+	accDone  bool        // Whether all the accounts have been imported
+	pending  []common.Hash
+	roots    map[common.Hash]common.Hash // Storage roots of the pending accounts
+	codeReqs map[common.Hash]struct{}    // Contract codes yet to be imported
+	codeDone map[common.Hash]struct{}    // Contract codes already imported
+
+	stTrie *trie.StackTrie // Storage trie of the first pending account
+	stNext common.Hash     // Origin of the next storage chunk
+
+	accounts, slots, codes int
+	start, logged          time.Time
+}
+
+func newSnapshotImporter(db ethdb.Database, root common.Hash, batch ethdb.Batch) *snapshotImporter {
+	imp := &snapshotImporter{
+		db:       db,
+		root:     root,
+		batch:    batch,
+		roots:    make(map[common.Hash]common.Hash),
+		codeReqs: make(map[common.Hash]struct{}),
+		codeDone: make(map[common.Hash]struct{}),
+		start:    time.Now(),
+		logged:   time.Now(),
+	}
+	imp.accTrie = trie.NewStackTrie(func(path []byte, hash common.Hash, blob []byte) {
+		rawdb.WriteAccountTrieNode(imp.batch, path, blob)
+	})
+	return imp
+}
+
+// process verifies and imports a chunk.
+func (imp *snapshotImporter) process(chunk *snapshotChunk) error {
+	switch chunk.Kind {
+	case snapshotChunkAccounts:
+		if len(imp.pending) > 0 {
+			return fmt.Errorf("storage of account %x is incomplete", imp.pending[0])
+		}
+		if imp.accDone || chunk.Origin != imp.accNext {
+			return fmt.Errorf("unexpected account range at %x", chunk.Origin)
+		}
+		more, err := verifyChunk(imp.root, chunk)
+		if err != nil {
+			return fmt.Errorf("invalid account range at %x: %v", chunk.Origin, err)
+		}
+		for i, key := range chunk.Keys {
+			var account types.StateAccount
+			if err := rlp.DecodeBytes(chunk.Values[i], &account); err != nil {
+				return err
+			}
+			rawdb.WriteAccountSnapshot(imp.batch, key, types.SlimAccountRLP(account))
+			if err := imp.accTrie.Update(key.Bytes(), chunk.Values[i]); err != nil {
+				return err
+			}
+			if account.Root != types.EmptyRootHash {
+				imp.pending = append(imp.pending, key)
+				imp.roots[key] = account.Root
+			}
+			if codeHash := common.BytesToHash(account.CodeHash); codeHash != types.EmptyCodeHash {
+				if _, ok := imp.codeDone[codeHash]; !ok && !rawdb.HasCode(imp.db, codeHash) {
+					imp.codeReqs[codeHash] = struct{}{}
+				}
+			}
+		}
+		imp.accounts += len(chunk.Keys)
+		imp.accDone = !more
+		if more {
+			imp.accNext = incHash(chunk.Keys[len(chunk.Keys)-1])
+		}
+		imp.stNext = common.Hash{}
+
+	case snapshotChunkStorage:
+		if len(imp.pending) == 0 || chunk.Account != imp.pending[0] {
+			return fmt.Errorf("unexpected storage of account %x", chunk.Account)
+		}
+		if chunk.Origin != imp.stNext {
+			return fmt.Errorf("unexpected storage range of account %x at %x", chunk.Account, chunk.Origin)
+		}
+		root := imp.roots[chunk.Account]
+		more, err := verifyChunk(root, chunk)
+		if err != nil {
+			return fmt.Errorf("invalid storage range of account %x at %x: %v", chunk.Account, chunk.Origin, err)
+		}
+		if imp.stTrie == nil {
+			owner := chunk.Account
+			imp.stTrie = trie.NewStackTrie(func(path []byte, hash common.Hash, blob []byte) {
+				rawdb.WriteStorageTrieNode(imp.batch, owner, path, blob)
+			})
+		}
+		for i, key := range chunk.Keys {
+			rawdb.WriteStorageSnapshot(imp.batch, chunk.Account, key, chunk.Values[i])
+			if err := imp.stTrie.Update(key.Bytes(), chunk.Values[i]); err != nil {
+				return err
+			}
+		}
+		imp.slots += len(chunk.Keys)
+		if more {
+			imp.stNext = incHash(chunk.Keys[len(chunk.Keys)-1])
+			break
+		}
+		if hash := imp.stTrie.Hash(); hash != root {
+			return fmt.Errorf("storage root mismatch of account %x, want %x, got %x", chunk.Account, root, hash)
+		}
+		delete(imp.roots, chunk.Account)
+		imp.pending, imp.stTrie, imp.stNext = imp.pending[1:], nil, common.Hash{}
+
+	case snapshotChunkCodes:
+		if len(chunk.Keys) != len(chunk.Values) {
+			return errors.New("invalid code chunk")
+		}
+		for i, hash := range chunk.Keys {
+			if crypto.Keccak256Hash(chunk.Values[i]) != hash {
+				return fmt.Errorf("invalid contract code %x", hash)
+			}
+			rawdb.WriteCode(imp.batch, hash, chunk.Values[i])
+			delete(imp.codeReqs, hash)
+			imp.codeDone[hash] = struct{}{}
+		}
+		imp.codes += len(chunk.Keys)
+
+	default:
+		return fmt.Errorf("unknown chunk type %d", chunk.Kind)
+	}
+	if imp.batch.ValueSize() >= ethdb.IdealBatchSize {
+		if err := imp.batch.Write(); err != nil {
+			return err
+		}
+		imp.batch.Reset()
+	}
+	if time.Since(imp.logged) > 8*time.Second {
+		log.Info("Importing state snapshot", "at", imp.accNext, "accounts", imp.accounts, "slots", imp.slots, "codes", imp.codes, "elapsed", common.PrettyDuration(time.Since(imp.start)))
+		imp.logged = time.Now()
+	}
+	return nil
+}
+
+// finish ensures the entire state is imported and flushes the remaining data.
+func (imp *snapshotImporter) finish() error {
+	if !imp.accDone {
+		return fmt.Errorf("accounts are incomplete, next range at %x", imp.accNext)
+	}
+	if len(imp.pending) > 0 {
+		return fmt.Errorf("storage of account %x is incomplete", imp.pending[0])
+	}
+	for hash := range imp.codeReqs {
+		return fmt.Errorf("contract code %x is missing", hash)
+	}
+	if hash := imp.accTrie.Hash(); hash != imp.root {
+		return fmt.Errorf("state root mismatch, want %x, got %x", imp.root, hash)
+	}
+	return imp.batch.Write()
+}
+
+// verifyChunk verifies the range of the chunk against the given trie root,
+// returning whether there are more entries after the range.
+func verifyChunk(root common.Hash, chunk *snapshotChunk) (bool, error) {
+	if len(chunk.Keys) != len(chunk.Values) {
+		return false, errors.New("key/value count mismatch")
+	}
+	keys := make([][]byte, len(chunk.Keys))
+	for i, key := range chunk.Keys {
+		keys[i] = key.Bytes()
+	}
+	// The values must be checked for the empty trie, as the range proof
+	// doesn't permit zero entries without proofs.
+	if len(chunk.Keys) == 0 && len(chunk.Proof) == 0 {
+		if root != types.EmptyRootHash || chunk.Origin != (common.Hash{}) {
+			return false, errors.New("missing proof")
+		}
+		return false, nil
+	}
+	var proof ethdb.KeyValueReader
+	if len(chunk.Proof) > 0 {
+		nodes := make(trienode.ProofList, 0, len(chunk.Proof))
+		for _, node := range chunk.Proof {
+			nodes = append(nodes, node)
+		}
+		proof = nodes.Set()
+	}
+	return trie.VerifyRangeProof(root, chunk.Origin.Bytes(), keys, chunk.Values, proof)
+}
+
+const (
+	StorageOrderSlots  = "slots"  // Rank the contracts by the number of storage slots
+	StorageOrderBytes  = "bytes"  // Rank the contracts by the size of storage slots
+	StorageOrderGrowth = "growth" // Rank the contracts by the net number of created slots
+)
+
+// StorageStats contains the storage statistics of a contract.
+type StorageStats struct {
+	Hash    common.Hash     `json:"hash"`
+	Address *common.Address `json:"address,omitempty"` // Nil if the preimage is unknown
+	Slots   uint64          `json:"slots"`
+	Bytes   uint64          `json:"bytes"`             // Size of the slot hashes and the encoded values
+	Created uint64          `json:"created,omitempty"` // Slots created within the growth range
+	Deleted uint64          `json:"deleted,omitempty"` // Slots deleted within the growth range
+}
+
+// growth returns the net number of the slots created within the growth range.
+func (s *StorageStats) growth() int64 {
+	return int64(s.Created) - int64(s.Deleted)
+}
+
+// StorageReport contains the storage statistics of a state.
+type StorageReport struct {
+	Root      common.Hash     `json:"root"`
+	Contracts uint64          `json:"contracts"`            // Number of contracts with non-empty storage
+	Slots     uint64          `json:"slots"`                // Total number of storage slots
+	Bytes     uint64          `json:"bytes"`                // Total size of storage slots
+	From      *uint64         `json:"growthFrom,omitempty"` // First block of the growth range
+	To        *uint64         `json:"growthTo,omitempty"`   // Last block of the growth range
+	Order     string          `json:"order"`
+	Top       []*StorageStats `json:"top"`
+}
+
+// InspectStorage iterates the storage of all the contracts in the state with the
+// given root, and reports the top contracts in the specified order. The growth
+// of the storage is attached to the contracts if given.
+func InspectStorage(db ethdb.Database, tdb *triedb.Database, root common.Hash, top int, order string, growth *pathdb.StorageGrowthStats) (*StorageReport, error) {
+	if top <= 0 {
+		return nil, fmt.Errorf("invalid number of top contracts %d", top)
+	}
+	var less func(a, b *StorageStats) bool
+	switch order {
+	case StorageOrderSlots:
+		less = func(a, b *StorageStats) bool { return a.Slots < b.Slots }
+	case StorageOrderBytes:
+		less = func(a, b *StorageStats) bool { return a.Bytes < b.Bytes }
+	case StorageOrderGrowth:
+		if growth == nil {
+			return nil, errors.New("growth order requires the growth range")
+		}
+		less = func(a, b *StorageStats) bool { return a.growth() < b.growth() }
+	default:
+		return nil, fmt.Errorf("unknown order %q", order)
+	}
+	state, err := newFlatState(db, tdb, root)
+	if err != nil {
+		return nil, err
+	}
+	report := &StorageReport{Root: root, Order: order, Top: []*StorageStats{}}
+
+	addresses := make(map[common.Hash]common.Address)
+	if growth != nil {
+		report.From, report.To = &growth.Start, &growth.End
+		for addr := range growth.Contracts {
+			addresses[crypto.Keccak256Hash(addr.Bytes())] = addr
+		}
+	}
+	accIt, accValue, err := state.accounts()
+	if err != nil {
+		return nil, err
+	}
+	defer accIt.Release()
+
+	var (
+		start  = time.Now()
+		logged = time.Now()
+	)
+	for accIt.Next() {
+		account, err := types.FullAccount(accValue())
+		if err != nil {
+			return nil, err
+		}
+		if account.Root == types.EmptyRootHash {
+			continue
+		}
+		stats := &StorageStats{Hash: accIt.Hash()}
+		stIt, stValue, err := state.storage(stats.Hash)
+		if err != nil {
+			return nil, err
+		}
+		for stIt.Next() {
+			stats.Slots++
+			stats.Bytes += uint64(common.HashLength + len(stValue()))
+		}
+		err = stIt.Error()
+		stIt.Release()
+		if err != nil {
+			return nil, err
+		}
+		if addr, ok := addresses[stats.Hash]; ok {
+			stats.Address = &addr
+			stats.Created, stats.Deleted = growth.Contracts[addr].Created, growth.Contracts[addr].Deleted
+		}
+		report.Contracts++
+		report.Slots += stats.Slots
+		report.Bytes += stats.Bytes
+
+		// Maintain the top contracts in descending order
+		if len(report.Top) < top || less(report.Top[len(report.Top)-1], stats) {
+			pos, _ := slices.BinarySearchFunc(report.Top, stats, func(a, b *StorageStats) int {
+				if less(b, a) {
+					return -1
+				}
+				return 1
+			})
+			report.Top = slices.Insert(report.Top, pos, stats)
+			if len(report.Top) > top {
+				report.Top = report.Top[:top]
+			}
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Inspecting contract storage", "at", stats.Hash, "contracts", report.Contracts, "slots", report.Slots, "size", common.StorageSize(report.Bytes), "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := accIt.Error(); err != nil {
+		return nil, err
+	}
+	// Resolve the addresses of the top contracts from the preimages
+	for _, stats := range report.Top {
+		if stats.Address != nil {
+			continue
+		}
+		if blob := rawdb.ReadPreimage(db, stats.Hash); len(blob) == common.AddressLength {
+			addr := common.BytesToAddress(blob)
+			stats.Address = &addr
+		}
+	}
+	log.Info("Inspected contract storage", "root", root, "contracts", report.Contracts, "slots", report.Slots, "size", common.StorageSize(report.Bytes), "elapsed", common.PrettyDuration(time.Since(start)))
+	return report, nil
+}
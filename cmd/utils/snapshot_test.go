@@ -0,0 +1,278 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/state/snapshot"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/ethereum/go-ethereum/triedb/hashdb"
+	"github.com/ethereum/go-ethereum/triedb/pathdb"
+	"github.com/holiman/uint256"
+)
+
+// snapshotTestAccount returns the address of the i-th test account.
+func snapshotTestAccount(i int) common.Address {
+	return common.BigToAddress(uint256.NewInt(uint64(0x1000 + i)).ToBig())
+}
+
+// makeSnapshotTestState creates a state with plain accounts and contracts with
+// storage, some of them sharing the same code.
+func makeSnapshotTestState(t *testing.T, scheme string) (ethdb.Database, *triedb.Database, common.Hash) {
+	t.Helper()
+
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		config = &triedb.Config{PathDB: pathdb.Defaults}
+	)
+	if scheme == rawdb.HashScheme {
+		config = &triedb.Config{HashDB: hashdb.Defaults}
+	}
+	tdb := triedb.NewDatabase(db, config)
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(tdb, nil))
+	for i := 0; i < 100; i++ {
+		addr := snapshotTestAccount(i)
+		statedb.SetBalance(addr, uint256.NewInt(uint64(i+1)), tracing.BalanceChangeUnspecified)
+		if i%10 == 0 {
+			statedb.SetCode(addr, []byte{0x60, byte(i % 20), 0xf3})
+			for j := 0; j < i+1; j++ {
+				statedb.SetState(addr, common.BigToHash(uint256.NewInt(uint64(j)).ToBig()), common.BigToHash(uint256.NewInt(uint64(j+1)).ToBig()))
+			}
+		}
+	}
+	root, err := statedb.Commit(0, false, false)
+	if err != nil {
+		t.Fatalf("Failed to commit state: %v", err)
+	}
+	if err := tdb.Commit(root, false); err != nil {
+		t.Fatalf("Failed to commit trie: %v", err)
+	}
+	return db, tdb, root
+}
+
+func TestSnapshotExportImport(t *testing.T) {
+	defer func(accounts, slots int) {
+		snapshotAccountChunkSize, snapshotStorageChunkSize = accounts, slots
+	}(snapshotAccountChunkSize, snapshotStorageChunkSize)
+
+	// Split the state into multiple chunks to cover the range proofs
+	snapshotAccountChunkSize, snapshotStorageChunkSize = 16, 8
+
+	for _, scheme := range []string{rawdb.HashScheme, rawdb.PathScheme} {
+		for _, name := range []string{"state.rlp", "state.rlp.gz"} {
+			srcdb, srctdb, root := makeSnapshotTestState(t, scheme)
+			if scheme == rawdb.HashScheme {
+				// Generate the flat state required for the export in hash scheme
+				snaptree, err := snapshot.New(snapshot.Config{CacheSize: 16}, srcdb, srctdb, root)
+				if err != nil {
+					t.Fatalf("Failed to generate snapshot: %v", err)
+				}
+				snaptree.Release()
+			}
+			fn := filepath.Join(t.TempDir(), name)
+			if err := ExportSnapshot(srcdb, srctdb, root, nil, fn); err != nil {
+				t.Fatalf("Failed to export state in %s scheme: %v", scheme, err)
+			}
+			db := rawdb.NewMemoryDatabase()
+			tdb := triedb.NewDatabase(db, &triedb.Config{PathDB: pathdb.Defaults})
+			if err := ImportSnapshot(db, tdb, fn); err != nil {
+				t.Fatalf("Failed to import state exported in %s scheme: %v", scheme, err)
+			}
+			statedb, err := state.New(root, state.NewDatabase(tdb, nil))
+			if err != nil {
+				t.Fatalf("Failed to open imported state: %v", err)
+			}
+			for i := 0; i < 100; i++ {
+				addr := snapshotTestAccount(i)
+				if balance := statedb.GetBalance(addr); balance.Uint64() != uint64(i+1) {
+					t.Fatalf("Unexpected balance of account %d, want %d, got %d", i, i+1, balance)
+				}
+				if i%10 != 0 {
+					continue
+				}
+				if code := statedb.GetCode(addr); !bytes.Equal(code, []byte{0x60, byte(i % 20), 0xf3}) {
+					t.Fatalf("Unexpected code of account %d: %x", i, code)
+				}
+				for j := 0; j < i+1; j++ {
+					key := common.BigToHash(uint256.NewInt(uint64(j)).ToBig())
+					if value := statedb.GetState(addr, key); value != common.BigToHash(uint256.NewInt(uint64(j+1)).ToBig()) {
+						t.Fatalf("Unexpected slot %d of account %d: %x", j, i, value)
+					}
+				}
+			}
+			// Wait for the verification of the flat state scheduled on import
+			for start := time.Now(); ; time.Sleep(10 * time.Millisecond) {
+				err := tdb.VerifyState(root)
+				if err == nil {
+					break
+				}
+				if time.Since(start) > 5*time.Second {
+					t.Fatalf("Failed to verify imported flat state: %v", err)
+				}
+			}
+			tdb.Close()
+			srctdb.Close()
+		}
+	}
+}
+
+func TestSnapshotImportCorrupted(t *testing.T) {
+	defer func(accounts, slots int) {
+		snapshotAccountChunkSize, snapshotStorageChunkSize = accounts, slots
+	}(snapshotAccountChunkSize, snapshotStorageChunkSize)
+	snapshotAccountChunkSize, snapshotStorageChunkSize = 16, 8
+
+	srcdb, srctdb, root := makeSnapshotTestState(t, rawdb.PathScheme)
+	defer srctdb.Close()
+
+	fn := filepath.Join(t.TempDir(), "state.rlp")
+	if err := ExportSnapshot(srcdb, srctdb, root, nil, fn); err != nil {
+		t.Fatalf("Failed to export state: %v", err)
+	}
+	blob, err := os.ReadFile(fn)
+	if err != nil {
+		t.Fatalf("Failed to read exported state: %v", err)
+	}
+	// Modify an account in the second account chunk and drop the tail of the
+	// file, both of them must be rejected.
+	var (
+		stream   = rlp.NewStream(bytes.NewReader(blob), 0)
+		head     snapshotFileHeader
+		tampered []byte
+		accounts int
+	)
+	if err := stream.Decode(&head); err != nil {
+		t.Fatalf("Failed to decode header: %v", err)
+	}
+	tampered, _ = rlp.EncodeToBytes(&head)
+	for {
+		chunk := new(snapshotChunk)
+		if err := stream.Decode(chunk); err != nil {
+			break
+		}
+		if chunk.Kind == snapshotChunkAccounts {
+			if accounts++; accounts == 2 {
+				var account types.StateAccount
+				rlp.DecodeBytes(chunk.Values[0], &account)
+				account.Balance = new(uint256.Int).AddUint64(account.Balance, 1)
+				chunk.Values[0], _ = rlp.EncodeToBytes(&account)
+			}
+		}
+		enc, _ := rlp.EncodeToBytes(chunk)
+		tampered = append(tampered, enc...)
+	}
+	if accounts < 2 {
+		t.Fatalf("Too few account chunks: %d", accounts)
+	}
+	for name, data := range map[string][]byte{
+		"tampered":  tampered,
+		"truncated": blob[:len(blob)/2],
+	} {
+		fn := filepath.Join(t.TempDir(), "state.rlp")
+		if err := os.WriteFile(fn, data, 0644); err != nil {
+			t.Fatalf("Failed to write state: %v", err)
+		}
+		// The rejected file must leave the existing state intact
+		db, tdb, root := makeSnapshotTestState(t, rawdb.PathScheme)
+		if err := tdb.Journal(root); err != nil {
+			t.Fatalf("Failed to persist existing state: %v", err)
+		}
+		content := hashDatabase(db)
+		if err := ImportSnapshot(db, tdb, fn); err == nil {
+			t.Fatalf("Imported %s state without error", name)
+		}
+		if hashDatabase(db) != content {
+			t.Fatalf("Database modified by %s import", name)
+		}
+		statedb, err := state.New(root, state.NewDatabase(tdb, nil))
+		if err != nil {
+			t.Fatalf("Failed to open existing state after %s import: %v", name, err)
+		}
+		for i := 0; i < 100; i++ {
+			if balance := statedb.GetBalance(snapshotTestAccount(i)); balance.Uint64() != uint64(i+1) {
+				t.Fatalf("Unexpected balance of account %d after %s import, want %d, got %d", i, name, i+1, balance)
+			}
+		}
+		tdb.Close()
+	}
+}
+
+// hashDatabase returns the hash of the entire content of the database.
+func hashDatabase(db ethdb.Database) common.Hash {
+	var (
+		it     = db.NewIterator(nil, nil)
+		hasher = crypto.NewKeccakState()
+	)
+	defer it.Release()
+
+	for it.Next() {
+		hasher.Write(it.Key())
+		hasher.Write(it.Value())
+	}
+	return common.BytesToHash(hasher.Sum(nil))
+}
+
+func TestInspectStorage(t *testing.T) {
+	for _, scheme := range []string{rawdb.HashScheme, rawdb.PathScheme} {
+		db, tdb, root := makeSnapshotTestState(t, scheme)
+		if scheme == rawdb.HashScheme {
+			snaptree, err := snapshot.New(snapshot.Config{CacheSize: 16}, db, tdb, root)
+			if err != nil {
+				t.Fatalf("Failed to generate snapshot: %v", err)
+			}
+			snaptree.Release()
+		}
+		for _, order := range []string{StorageOrderSlots, StorageOrderBytes} {
+			report, err := InspectStorage(db, tdb, root, 3, order, nil)
+			if err != nil {
+				t.Fatalf("Failed to inspect storage in %s scheme: %v", scheme, err)
+			}
+			// Contract i holds i+1 slots for every tenth account
+			if report.Contracts != 10 || report.Slots != 460 {
+				t.Fatalf("Unexpected totals in %s scheme: contracts %d, slots %d", scheme, report.Contracts, report.Slots)
+			}
+			if len(report.Top) != 3 {
+				t.Fatalf("Unexpected number of top contracts: %d", len(report.Top))
+			}
+			for i, stats := range report.Top {
+				if stats.Slots != uint64(91-10*i) {
+					t.Fatalf("Unexpected slots of contract ranked %d by %s: %d", i+1, order, stats.Slots)
+				}
+				if i > 0 && report.Top[i-1].Bytes < stats.Bytes {
+					t.Fatalf("Contracts are not sorted by %s", order)
+				}
+			}
+		}
+		if _, err := InspectStorage(db, tdb, root, 3, StorageOrderGrowth, nil); err == nil {
+			t.Fatal("Growth order without growth range is accepted")
+		}
+		tdb.Close()
+	}
+}
@@ -40,7 +40,9 @@ import (
 	"github.com/ethereum/go-ethereum/core/history"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/state/pruner"
 	"github.com/ethereum/go-ethereum/core/state/snapshot"
+	"github.com/ethereum/go-ethereum/core/statediff"
 	"github.com/ethereum/go-ethereum/core/stateless"
 	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -112,6 +114,7 @@ var (
 	errChainStopped         = errors.New("blockchain is stopped")
 	errInvalidOldChain      = errors.New("invalid old chain")
 	errInvalidNewChain      = errors.New("invalid new chain")
+	errSecondaryChain       = errors.New("blockchain is opened as secondary")
 )
 
 var (
@@ -173,6 +176,10 @@ type BlockChainConfig struct {
 	StateScheme  string // Scheme used to store ethereum states and merkle tree nodes on top
 	ArchiveMode  bool   // Whether to enable the archive mode
 
+	// StatePruning enables the background deletion of the stale states, only
+	// supported by the non-archive node in hash scheme. Nil means disabled.
+	StatePruning *pruner.OnlineConfig
+
 	// State snapshot related options
 	SnapshotLimit   int  // Memory allowance (MB) to use for caching snapshot entries in memory
 	SnapshotNoBuild bool // Whether the background generation is allowed
@@ -182,6 +189,16 @@ type BlockChainConfig struct {
 	// Blocks before this number may be unavailable in the chain database.
 	ChainHistoryMode history.HistoryMode
 
+	// Retention window of the chain history in history.KeepRecent mode. Bodies
+	// and receipts are kept for blocks within either of the configured limits,
+	// a zero value disables the respective limit.
+	ChainHistoryBlocks uint64        // Number of recent blocks to retain
+	ChainHistoryPeriod time.Duration // Time span of recent blocks to retain
+
+	// StateDiff enables the export of the state changes made by every imported
+	// block. Nil means disabled.
+	StateDiff *statediff.Config
+
 	// Misc options
 	NoPrefetch bool            // Whether to disable heuristic state prefetching when processing blocks
 	Overrides  *ChainOverrides // Optional chain config overrides
@@ -193,6 +210,13 @@ type BlockChainConfig struct {
 	// If the value is zero, all transactions of the entire chain will be indexed.
 	// If the value is -1, indexing is disabled.
 	TxLookupLimit int64
+
+	// Secondary opens the chain read-only on top of a database owned and written
+	// by another process, which is followed through ReloadHead instead of blocks
+	// being imported. Both the primary and the secondary are required to use the
+	// hash state scheme in archive mode, as otherwise the recent states are held
+	// in the memory of the primary and only flushed to disk occasionally.
+	Secondary bool
 }
 
 // DefaultConfig returns the default config.
@@ -259,6 +283,14 @@ func (cfg *BlockChainConfig) triedbConfig(isVerkle bool) *triedb.Config {
 	return config
 }
 
+// openTransitionDatabase opens the verkle tree database in which the merkle state
+// of the base database is converted during the verkle transition, along with the
+// state database reading through both.
+func openTransitionDatabase(db ethdb.Database, cfg *BlockChainConfig, base *triedb.Database) (*triedb.Database, *state.CachingDB) {
+	verkledb := triedb.NewDatabase(db, cfg.triedbConfig(true))
+	return verkledb, state.NewTransitionDatabase(verkledb, base)
+}
+
 // txLookup is wrapper over transaction lookup along with the corresponding
 // transaction object.
 type txLookup struct {
@@ -292,7 +324,12 @@ type BlockChain struct {
 	flushInterval atomic.Int64                     // Time interval (processing time) after which to flush a state
 	triedb        *triedb.Database                 // The database handler for maintaining trie nodes.
 	statedb       *state.CachingDB                 // State database to reuse between imports (contains state cache)
+	verkledb      *triedb.Database                 // The database handler of the verkle tree during the verkle transition
+	verkleStatedb *state.CachingDB                 // State database of the verkle transition, nil if not configured
 	txIndexer     *txIndexer                       // Transaction indexer, might be nil if not enabled
+	historyPruner *historyPruner                   // Rolling history pruner, might be nil if not enabled
+	statePruner   *pruner.OnlinePruner             // Online state pruner, might be nil if not enabled
+	stateDiffs    *statediff.Exporter              // State diff exporter, might be nil if not enabled
 
 	hc               *HeaderChain
 	rmLogsFeed       event.Feed
@@ -341,6 +378,12 @@ func NewBlockChain(db ethdb.Database, genesis *Genesis, engine consensus.Engine,
 	if cfg == nil {
 		cfg = DefaultConfig()
 	}
+	if cfg.Secondary && cfg.StateScheme != rawdb.HashScheme {
+		return nil, fmt.Errorf("secondary chain is not supported with the %s state scheme", cfg.StateScheme)
+	}
+	if cfg.Secondary && !cfg.ArchiveMode {
+		return nil, errors.New("secondary chain requires archive mode")
+	}
 
 	// Open trie database with provided config
 	enableVerkle, err := EnableVerkleAtGenesis(db, genesis)
@@ -352,8 +395,21 @@ func NewBlockChain(db ethdb.Database, genesis *Genesis, engine consensus.Engine,
 	// Write the supplied genesis to the database if it has not been initialized
 	// yet. The corresponding chain config will be returned, either from the
 	// provided genesis or from the locally stored configuration if the genesis
-	// has already been initialized.
-	chainConfig, genesisHash, compatErr, err := SetupGenesisBlockWithOverride(db, triedb, genesis, cfg.Overrides)
+	// has already been initialized. The secondary chain relies on the primary
+	// having done so.
+	var (
+		chainConfig *params.ChainConfig
+		genesisHash common.Hash
+		compatErr   *params.ConfigCompatError
+	)
+	if cfg.Secondary {
+		if rawdb.ReadCanonicalHash(db, 0) == (common.Hash{}) {
+			return nil, ErrNoGenesis
+		}
+		chainConfig, genesisHash, err = LoadChainConfig(db, genesis)
+	} else {
+		chainConfig, genesisHash, compatErr, err = SetupGenesisBlockWithOverride(db, triedb, genesis, cfg.Overrides)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -386,6 +442,15 @@ func NewBlockChain(db ethdb.Database, genesis *Genesis, engine consensus.Engine,
 	}
 	bc.flushInterval.Store(int64(cfg.TrieTimeLimit))
 	bc.statedb = state.NewDatabase(bc.triedb, nil)
+
+	// Open the verkle tree database for converting the merkle state during
+	// the verkle transition, which requires the preimages of the state keys.
+	if chainConfig.VerkleTransitionLeaves > 0 && chainConfig.VerkleTime != nil && !chainConfig.IsVerkleGenesis() {
+		if cfg.StateScheme != rawdb.PathScheme || !cfg.Preimages {
+			return nil, errors.New("verkle transition requires the path state scheme with preimages")
+		}
+		bc.verkledb, bc.verkleStatedb = openTransitionDatabase(db, cfg, bc.triedb)
+	}
 	bc.validator = NewBlockValidator(chainConfig, bc)
 	bc.prefetcher = newStatePrefetcher(chainConfig, bc.hc)
 	bc.processor = NewStateProcessor(chainConfig, bc.hc)
@@ -407,7 +472,7 @@ func NewBlockChain(db ethdb.Database, genesis *Genesis, engine consensus.Engine,
 	// If Geth is initialized with an external ancient store, re-initialize the
 	// missing chain indexes and chain flags. This procedure can survive crash
 	// and can be resumed in next restart since chain flags are updated in last step.
-	if bc.empty() {
+	if bc.empty() && !cfg.Secondary {
 		rawdb.InitDatabaseFromFreezer(bc.db)
 	}
 	// Load blockchain states from disk
@@ -417,7 +482,11 @@ func NewBlockChain(db ethdb.Database, genesis *Genesis, engine consensus.Engine,
 	// Make sure the state associated with the block is available, or log out
 	// if there is no available state, waiting for state sync.
 	head := bc.CurrentBlock()
-	if !bc.HasState(head.Root) {
+	if !bc.HasState(head.Root) && cfg.Secondary {
+		// The secondary chain can't be repaired, the head state might not be
+		// flushed to disk by the primary yet.
+		log.Warn("Head state not available", "number", head.Number, "hash", head.Hash())
+	} else if !bc.HasState(head.Root) {
 		if head.Number.Uint64() == 0 {
 			// The genesis state is missing, which is only possible in the path-based
 			// scheme. This situation occurs when the initial state sync is not finished
@@ -456,7 +525,7 @@ func NewBlockChain(db ethdb.Database, genesis *Genesis, engine consensus.Engine,
 		}
 	}
 	// Ensure that a previous crash in SetHead doesn't leave extra ancients
-	if frozen, err := bc.db.Ancients(); err == nil && frozen > 0 {
+	if frozen, err := bc.db.Ancients(); err == nil && frozen > 0 && !cfg.Secondary {
 		var (
 			needRewind bool
 			low        uint64
@@ -506,8 +575,16 @@ func NewBlockChain(db ethdb.Database, genesis *Genesis, engine consensus.Engine,
 			bc.logger.OnGenesisBlock(bc.genesisBlock, alloc)
 		}
 	}
-	bc.setupSnapshot()
-
+	if !cfg.Secondary {
+		bc.setupSnapshot()
+	}
+	// Start the state diff export before importing any block.
+	if bc.cfg.StateDiff != nil && !cfg.Secondary {
+		bc.stateDiffs, err = statediff.New(*bc.cfg.StateDiff)
+		if err != nil {
+			return nil, err
+		}
+	}
 	// Rewind the chain in case of an incompatible config upgrade.
 	if compatErr != nil {
 		log.Warn("Rewinding chain to upgrade configuration", "err", compatErr)
@@ -519,10 +596,26 @@ func NewBlockChain(db ethdb.Database, genesis *Genesis, engine consensus.Engine,
 		rawdb.WriteChainConfig(db, genesisHash, chainConfig)
 	}
 
-	// Start tx indexer if it's enabled.
-	if bc.cfg.TxLookupLimit >= 0 {
+	// Start tx indexer if it's enabled. The secondary chain relies on the
+	// indexes maintained by the primary.
+	if bc.cfg.TxLookupLimit >= 0 && !cfg.Secondary {
 		bc.txIndexer = newTxIndexer(uint64(bc.cfg.TxLookupLimit), bc)
 	}
+	// Start the history pruner if the rolling retention is configured.
+	if bc.cfg.ChainHistoryMode == history.KeepRecent && !cfg.Secondary {
+		bc.historyPruner = newHistoryPruner(bc.cfg.ChainHistoryBlocks, bc.cfg.ChainHistoryPeriod, bc)
+	}
+	// Start the online state pruner if it's enabled.
+	if bc.cfg.StatePruning != nil && !cfg.Secondary {
+		if bc.cfg.ArchiveMode || bc.triedb.Scheme() != rawdb.HashScheme {
+			log.Warn("Online state pruning is only supported by non-archive node in hash scheme")
+		} else {
+			bc.statePruner, err = pruner.NewOnlinePruner(bc.db, bc.triedb, bc.snaps, bc, *bc.cfg.StatePruning)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
 	return bc, nil
 }
 
@@ -668,6 +761,87 @@ func (bc *BlockChain) loadLastState() error {
 	return nil
 }
 
+// ReloadHead reloads the chain markers of the secondary chain from the database,
+// which are advanced by the primary, and announces the new head block. The view
+// of the database is expected to be caught up beforehand. Nothing is written,
+// the in-memory markers are updated only.
+func (bc *BlockChain) ReloadHead() error {
+	if !bc.cfg.Secondary {
+		return errors.New("blockchain is not opened as secondary")
+	}
+	head, err := bc.reloadHead()
+	if err != nil {
+		return err
+	}
+	if head != nil {
+		bc.chainHeadFeed.Send(ChainHeadEvent{Header: head})
+	}
+	return nil
+}
+
+// reloadHead reloads the chain markers of the secondary chain, returning the new
+// head block if it has changed.
+func (bc *BlockChain) reloadHead() (*types.Header, error) {
+	if !bc.chainmu.TryLock() {
+		return nil, errChainStopped
+	}
+	defer bc.chainmu.Unlock()
+
+	hash := rawdb.ReadHeadBlockHash(bc.db)
+	if hash == (common.Hash{}) {
+		return nil, errors.New("head block marker missing")
+	}
+	var updated *types.Header
+	head := bc.CurrentBlock()
+	if hash != head.Hash() {
+		header := bc.GetHeaderByHash(hash)
+		if header == nil {
+			return nil, fmt.Errorf("head header missing, hash %x", hash)
+		}
+		// The transaction lookups of the blocks reorged out are not valid anymore
+		if header.ParentHash != head.Hash() {
+			bc.txLookupCache.Purge()
+		}
+		bc.currentBlock.Store(header)
+		headBlockGauge.Update(header.Number.Int64())
+
+		snap := header
+		if hash := rawdb.ReadHeadFastBlockHash(bc.db); hash != (common.Hash{}) {
+			if header := bc.GetHeaderByHash(hash); header != nil {
+				snap = header
+			}
+		}
+		bc.currentSnapBlock.Store(snap)
+		headFastBlockGauge.Update(snap.Number.Int64())
+
+		latest := header
+		if hash := rawdb.ReadHeadHeaderHash(bc.db); hash != (common.Hash{}) {
+			if header := bc.GetHeaderByHash(hash); header != nil {
+				latest = header
+			}
+		}
+		bc.hc.SetCurrentHeader(latest)
+
+		if err := bc.initializeHistoryPruning(max(header.Number.Uint64(), latest.Number.Uint64())); err != nil {
+			return nil, err
+		}
+		updated = header
+	}
+	// The safe block is not persisted, it follows the finalized block like on
+	// startup.
+	if hash := rawdb.ReadFinalizedBlockHash(bc.db); hash != (common.Hash{}) {
+		if final := bc.CurrentFinalBlock(); final == nil || final.Hash() != hash {
+			if header := bc.GetHeaderByHash(hash); header != nil {
+				bc.currentFinalBlock.Store(header)
+				headFinalizedBlockGauge.Update(header.Number.Int64())
+				bc.currentSafeBlock.Store(header)
+				headSafeBlockGauge.Update(header.Number.Int64())
+			}
+		}
+	}
+	return updated, nil
+}
+
 // initializeHistoryPruning sets bc.historyPrunePoint.
 func (bc *BlockChain) initializeHistoryPruning(latest uint64) error {
 	freezerTail, _ := bc.db.Tail()
@@ -708,6 +882,20 @@ func (bc *BlockChain) initializeHistoryPruning(latest uint64) error {
 		bc.historyPrunePoint.Store(predefinedPoint)
 		return nil
 
+	case history.KeepRecent:
+		if bc.cfg.ChainHistoryBlocks == 0 && bc.cfg.ChainHistoryPeriod == 0 {
+			return errors.New("history retention window not configured")
+		}
+		// The history is pruned in a rolling fashion, the prune point is wherever
+		// the pruner advanced the freezer tail to.
+		if freezerTail > 0 {
+			bc.historyPrunePoint.Store(&history.PrunePoint{
+				BlockNumber: freezerTail,
+				BlockHash:   rawdb.ReadCanonicalHash(bc.db, freezerTail),
+			})
+		}
+		return nil
+
 	default:
 		return fmt.Errorf("invalid history mode: %d", bc.cfg.ChainHistoryMode)
 	}
@@ -963,6 +1151,9 @@ func (bc *BlockChain) rewindHead(head *types.Header, root common.Hash) (*types.H
 //
 // The method returns the block number where the requested root cap was found.
 func (bc *BlockChain) setHeadBeyondRoot(head uint64, time uint64, root common.Hash, repair bool) (uint64, error) {
+	if bc.cfg.Secondary {
+		return 0, errSecondaryChain
+	}
 	if !bc.chainmu.TryLock() {
 		return 0, errChainStopped
 	}
@@ -1243,6 +1434,14 @@ func (bc *BlockChain) stopWithoutSaving() {
 	if bc.txIndexer != nil {
 		bc.txIndexer.close()
 	}
+	// Signal shutdown history pruner.
+	if bc.historyPruner != nil {
+		bc.historyPruner.close()
+	}
+	// Signal shutdown online state pruner.
+	if bc.statePruner != nil {
+		bc.statePruner.Stop()
+	}
 	// Unsubscribe all subscriptions registered from blockchain.
 	bc.scope.Close()
 
@@ -1256,6 +1455,13 @@ func (bc *BlockChain) stopWithoutSaving() {
 	// the mutex should become available quickly. It cannot be taken again after Close has
 	// returned.
 	bc.chainmu.Close()
+
+	// Close the state diff export after the last block is imported.
+	if bc.stateDiffs != nil {
+		if err := bc.stateDiffs.Close(); err != nil {
+			log.Error("Failed to close state diff exporter", "err", err)
+		}
+	}
 }
 
 // Stop stops the blockchain service. If any imports are currently in progress
@@ -1274,7 +1480,18 @@ func (bc *BlockChain) Stop() {
 	}
 	if bc.triedb.Scheme() == rawdb.PathScheme {
 		// Ensure that the in-memory trie nodes are journaled to disk properly.
-		if err := bc.triedb.Journal(bc.CurrentBlock().Root); err != nil {
+		// During the verkle transition, the head state is the verkle tree on
+		// top of the frozen merkle state, journal both of them.
+		root := bc.CurrentBlock().Root
+		if bc.verkledb != nil {
+			if ts, _ := state.ReadTransitionState(bc.db, root); ts != nil {
+				if err := bc.verkledb.Journal(root); err != nil {
+					log.Info("Failed to journal in-memory verkle nodes", "err", err)
+				}
+				root = ts.BaseRoot
+			}
+		}
+		if err := bc.triedb.Journal(root); err != nil {
 			log.Info("Failed to journal in-memory trie nodes", "err", err)
 		}
 	} else {
@@ -1283,7 +1500,9 @@ func (bc *BlockChain) Stop() {
 		//  - HEAD:     So we don't need to reprocess any blocks in the general case
 		//  - HEAD-1:   So we don't do large reorgs if our HEAD becomes an uncle
 		//  - HEAD-127: So we have a hard limit on the number of blocks reexecuted
-		if !bc.cfg.ArchiveMode {
+		//
+		// The secondary chain holds no state of its own, it's all in the database.
+		if !bc.cfg.ArchiveMode && !bc.cfg.Secondary {
 			triedb := bc.triedb
 
 			for _, offset := range []uint64{0, 1, state.TriesInMemory - 1} {
@@ -1318,6 +1537,11 @@ func (bc *BlockChain) Stop() {
 	if err := bc.triedb.Close(); err != nil {
 		log.Error("Failed to close trie database", "err", err)
 	}
+	if bc.verkledb != nil {
+		if err := bc.verkledb.Close(); err != nil {
+			log.Error("Failed to close verkle database", "err", err)
+		}
+	}
 	log.Info("Blockchain stopped")
 }
 
@@ -1565,6 +1789,20 @@ func (bc *BlockChain) writeKnownBlock(block *types.Block) error {
 	return nil
 }
 
+// exportStateDiff emits the state changes made by the given block. The diff is
+// assembled in place, as the state set is handed over to the trie database, but
+// it's encoded and written in the background. The failure is not fatal, as the
+// export has no impact on the chain.
+func (bc *BlockChain) exportStateDiff(block *types.Block, states *triedb.StateSet, codes map[common.Address][]byte) {
+	diff, err := statediff.NewDiff(block.Header(), states, codes)
+	if err == nil {
+		err = bc.stateDiffs.Export(diff)
+	}
+	if err != nil {
+		log.Error("Failed to export state diff", "number", block.Number(), "hash", block.Hash(), "err", err)
+	}
+}
+
 // writeBlockWithState writes block, metadata and corresponding state data to the
 // database.
 func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.Receipt, statedb *state.StateDB) error {
@@ -1583,10 +1821,23 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 		log.Crit("Failed to write block into disk", "err", err)
 	}
 	// Commit all cached state changes into underlying memory database.
-	root, err := statedb.Commit(block.NumberU64(), bc.chainConfig.IsEIP158(block.Number()), bc.chainConfig.IsCancun(block.Number(), block.Time()))
+	var (
+		root   common.Hash
+		err    error
+		states *triedb.StateSet
+		codes  map[common.Address][]byte
+	)
+	if bc.stateDiffs == nil {
+		root, err = statedb.Commit(block.NumberU64(), bc.chainConfig.IsEIP158(block.Number()), bc.chainConfig.IsCancun(block.Number(), block.Time()))
+	} else {
+		root, states, codes, err = statedb.CommitWithStateSet(block.NumberU64(), bc.chainConfig.IsEIP158(block.Number()), bc.chainConfig.IsCancun(block.Number(), block.Time()))
+	}
 	if err != nil {
 		return err
 	}
+	if states != nil {
+		bc.exportStateDiff(block, states, codes)
+	}
 	// If node is running in path mode, skip explicit gc operation
 	// which is unnecessary in this mode.
 	if bc.triedb.Scheme() == rawdb.PathScheme {
@@ -1958,8 +2209,9 @@ func (bc *BlockChain) processBlock(parentRoot common.Hash, block *types.Block, s
 	)
 	defer interrupt.Store(true) // terminate the prefetch at the end
 
+	sdb := bc.blockStateDatabase(block.Header())
 	if bc.cfg.NoPrefetch {
-		statedb, err = state.New(parentRoot, bc.statedb)
+		statedb, err = state.New(parentRoot, sdb)
 		if err != nil {
 			return nil, err
 		}
@@ -1969,15 +2221,15 @@ func (bc *BlockChain) processBlock(parentRoot common.Hash, block *types.Block, s
 		//
 		// Note: the main processor and prefetcher share the same reader with a local
 		// cache for mitigating the overhead of state access.
-		prefetch, process, err := bc.statedb.ReadersWithCacheStats(parentRoot)
+		prefetch, process, err := sdb.ReadersWithCacheStats(parentRoot)
 		if err != nil {
 			return nil, err
 		}
-		throwaway, err := state.NewWithReader(parentRoot, bc.statedb, prefetch)
+		throwaway, err := state.NewWithReader(parentRoot, sdb, prefetch)
 		if err != nil {
 			return nil, err
 		}
-		statedb, err = state.NewWithReader(parentRoot, bc.statedb, process)
+		statedb, err = state.NewWithReader(parentRoot, sdb, process)
 		if err != nil {
 			return nil, err
 		}
@@ -2757,6 +3009,13 @@ func (bc *BlockChain) InsertHeadersBeforeCutoff(headers []*types.Header) (int, e
 	return 0, nil
 }
 
+// ImportEraHistory adds the given era archive to the era store backing the
+// pruned chain segment, making its bodies and receipts retrievable again. The
+// archive must already be verified against the local chain by the caller.
+func (bc *BlockChain) ImportEraHistory(path string) error {
+	return rawdb.ImportEraHistory(bc.db, path)
+}
+
 // SetBlockValidatorAndProcessorForTesting sets the current validator and processor.
 // This method can be used to force an invalid blockchain to be verified for tests.
 // This method is unsafe and should only be used before block import starts.
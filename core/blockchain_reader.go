@@ -371,10 +371,31 @@ func (bc *BlockChain) TxIndexDone() bool {
 
 // HasState checks if state trie is fully present in the database or not.
 func (bc *BlockChain) HasState(hash common.Hash) bool {
-	_, err := bc.statedb.OpenTrie(hash)
+	_, err := bc.stateDatabase(hash).OpenTrie(hash)
 	return err == nil
 }
 
+// stateDatabase returns the state database holding the state with the given
+// root, which is the verkle transition one for the states produced during the
+// verkle transition.
+func (bc *BlockChain) stateDatabase(root common.Hash) *state.CachingDB {
+	if bc.verkleStatedb != nil {
+		if ts, _ := state.ReadTransitionState(bc.db, root); ts != nil {
+			return bc.verkleStatedb
+		}
+	}
+	return bc.statedb
+}
+
+// blockStateDatabase returns the state database for executing the given block,
+// which is the verkle transition one for the blocks within the transition.
+func (bc *BlockChain) blockStateDatabase(header *types.Header) *state.CachingDB {
+	if bc.verkleStatedb != nil && bc.chainConfig.IsVerkleTransition(header.Number, header.Time) {
+		return bc.verkleStatedb
+	}
+	return bc.statedb
+}
+
 // HasBlockAndState checks if a block and associated state trie is fully present
 // in the database or not, caching it if present.
 func (bc *BlockChain) HasBlockAndState(hash common.Hash, number uint64) bool {
@@ -413,7 +434,14 @@ func (bc *BlockChain) State() (*state.StateDB, error) {
 
 // StateAt returns a new mutable state based on a particular point in time.
 func (bc *BlockChain) StateAt(root common.Hash) (*state.StateDB, error) {
-	return state.New(root, bc.statedb)
+	return state.New(root, bc.stateDatabase(root))
+}
+
+// PreState returns a new mutable state based on the parent state for executing
+// the given block on top of it. Unlike StateAt, the state of the first block of
+// the verkle transition is opened for the conversion into the verkle tree.
+func (bc *BlockChain) PreState(parentRoot common.Hash, header *types.Header) (*state.StateDB, error) {
+	return state.New(parentRoot, bc.blockStateDatabase(header))
 }
 
 // HistoricState returns a historic state specified by the given root.
@@ -487,6 +515,13 @@ func (bc *BlockChain) HistoryPruningCutoff() (uint64, common.Hash) {
 	return pt.BlockNumber, pt.BlockHash
 }
 
+// HistoryFloor returns the first block whose history is available, either from
+// the database itself or from the era files backing the pruned chain segment.
+func (bc *BlockChain) HistoryFloor() uint64 {
+	cutoff, _ := bc.HistoryPruningCutoff()
+	return rawdb.EraHistoryFloor(bc.db, cutoff)
+}
+
 // TrieDB retrieves the low level trie database used for data storage.
 func (bc *BlockChain) TrieDB() *triedb.Database {
 	return bc.triedb
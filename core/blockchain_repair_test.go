@@ -2033,3 +2033,72 @@ func testIssue23496(t *testing.T, scheme string) {
 		}
 	}
 }
+
+// Tests that a checkpoint of a path-based database, including the state history
+// freezer, can be restored and extended with new blocks.
+func TestCheckpointRestorePathScheme(t *testing.T) {
+	datadir := t.TempDir()
+
+	pdb, err := pebble.New(datadir, 0, 0, "", false)
+	if err != nil {
+		t.Fatalf("Failed to create persistent key-value database: %v", err)
+	}
+	db, err := rawdb.Open(pdb, rawdb.OpenOptions{Ancient: filepath.Join(datadir, "ancient")})
+	if err != nil {
+		t.Fatalf("Failed to create persistent freezer database: %v", err)
+	}
+	defer db.Close() // Might double close, should be fine
+
+	var (
+		gspec = &Genesis{
+			Config:  params.TestChainConfig,
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		engine = ethash.NewFullFaker()
+	)
+	chain, err := NewBlockChain(db, gspec, engine, DefaultConfig().WithStateScheme(rawdb.PathScheme))
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+	_, blocks, _ := GenerateChainWithGenesis(gspec, engine, 4, func(i int, b *BlockGen) {
+		b.SetCoinbase(common.Address{0x02})
+		b.SetDifficulty(big.NewInt(1000000))
+	})
+	if _, err := chain.InsertChain(blocks[:3]); err != nil {
+		t.Fatalf("Failed to import canonical chain start: %v", err)
+	}
+	// Persist the state of the head, advancing the persisted state id
+	if err := chain.triedb.Commit(blocks[2].Root(), false); err != nil {
+		t.Fatalf("Failed to commit state: %v", err)
+	}
+	backup := filepath.Join(t.TempDir(), "backup")
+	if err := chain.triedb.Checkpoint(backup); err != nil {
+		t.Fatalf("Failed to create checkpoint: %v", err)
+	}
+	chain.Stop()
+	db.Close()
+
+	// Open the checkpoint and import the remaining block on top
+	pdb, err = pebble.New(backup, 0, 0, "", false)
+	if err != nil {
+		t.Fatalf("Failed to open checkpoint key-value database: %v", err)
+	}
+	db, err = rawdb.Open(pdb, rawdb.OpenOptions{Ancient: filepath.Join(backup, "ancient")})
+	if err != nil {
+		t.Fatalf("Failed to open checkpoint freezer database: %v", err)
+	}
+	defer db.Close()
+
+	chain, err = NewBlockChain(db, gspec, engine, DefaultConfig().WithStateScheme(rawdb.PathScheme))
+	if err != nil {
+		t.Fatalf("Failed to recreate chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if head := chain.CurrentBlock(); head.Hash() != blocks[2].Hash() {
+		t.Fatalf("Head block mismatch: have %d, want %d", head.Number, blocks[2].Number())
+	}
+	if _, err := chain.InsertChain(blocks[3:]); err != nil {
+		t.Fatalf("Failed to import block on top of checkpoint: %v", err)
+	}
+}
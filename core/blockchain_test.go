@@ -4515,3 +4515,73 @@ func TestGetCanonicalReceipt(t *testing.T) {
 		}
 	}
 }
+
+// Tests that a secondary chain opened on top of the database of another chain
+// follows its head without writing anything.
+func TestSecondaryChain(t *testing.T) {
+	var (
+		gspec = &Genesis{
+			Config:  params.TestChainConfig,
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		engine = ethash.NewFaker()
+	)
+	_, blocks, _ := GenerateChainWithGenesis(gspec, engine, 10, nil)
+
+	db, _ := rawdb.Open(rawdb.NewMemoryDatabase(), rawdb.OpenOptions{})
+	defer db.Close()
+
+	options := DefaultConfig().WithArchive(true)
+	primary, err := NewBlockChain(db, gspec, engine, options)
+	if err != nil {
+		t.Fatalf("failed to create primary chain: %v", err)
+	}
+	defer primary.Stop()
+
+	if _, err := primary.InsertChain(blocks[:5]); err != nil {
+		t.Fatalf("failed to insert blocks: %v", err)
+	}
+	secondaryOptions := *options
+	secondaryOptions.Secondary = true
+	secondaryOptions.ArchiveMode = false
+	if _, err := NewBlockChain(db, nil, engine, &secondaryOptions); err == nil {
+		t.Fatal("secondary chain opened without archive mode")
+	}
+	secondaryOptions.ArchiveMode = true
+	secondary, err := NewBlockChain(db, nil, engine, &secondaryOptions)
+	if err != nil {
+		t.Fatalf("failed to create secondary chain: %v", err)
+	}
+	defer secondary.Stop()
+
+	if head := secondary.CurrentBlock(); head.Hash() != blocks[4].Hash() {
+		t.Fatalf("unexpected secondary head, want %d, have %d", blocks[4].Number(), head.Number)
+	}
+	if err := secondary.SetHead(0); err == nil {
+		t.Fatal("secondary chain rewound")
+	}
+	heads := make(chan ChainHeadEvent, 1)
+	sub := secondary.SubscribeChainHeadEvent(heads)
+	defer sub.Unsubscribe()
+
+	if _, err := primary.InsertChain(blocks[5:]); err != nil {
+		t.Fatalf("failed to insert blocks: %v", err)
+	}
+	if err := secondary.ReloadHead(); err != nil {
+		t.Fatalf("failed to reload head: %v", err)
+	}
+	if head := secondary.CurrentHeader(); head.Hash() != blocks[9].Hash() {
+		t.Fatalf("unexpected secondary header, want %d, have %d", blocks[9].Number(), head.Number)
+	}
+	select {
+	case ev := <-heads:
+		if ev.Header.Hash() != blocks[9].Hash() {
+			t.Fatalf("unexpected head event, want %d, have %d", blocks[9].Number(), ev.Header.Number)
+		}
+	default:
+		t.Fatal("head event not sent")
+	}
+	if _, err := secondary.StateAt(blocks[9].Root()); err != nil {
+		t.Fatalf("head state not available: %v", err)
+	}
+}
@@ -377,6 +377,10 @@ func GenerateChain(config *params.ChainConfig, parent *types.Block, engine conse
 			}
 		}
 
+		// Convert the merkle state into the verkle tree ahead of any state change
+		if err := ProcessVerkleTransition(config, b.header, statedb); err != nil {
+			panic(fmt.Sprintf("verkle transition error: %v", err))
+		}
 		// Mutate the state and block according to any hard-fork specs
 		if daoBlock := config.DAOForkBlock; daoBlock != nil {
 			limit := new(big.Int).Add(daoBlock, params.DAOForkExtraRange)
@@ -426,16 +430,32 @@ func GenerateChain(config *params.ChainConfig, parent *types.Block, engine conse
 		return block, b.receipts
 	}
 
+	// The verkle tree built during the verkle transition is only supported by
+	// the path-based scheme, which retains the nodes in disk on commit as well.
+	// The conversion needs the preimages of the merkle state keys.
+	var verkledb *triedb.Database
+	if config.VerkleTransitionLeaves > 0 && !config.IsVerkleGenesis() {
+		verkledb = triedb.NewDatabase(db, triedb.VerkleDefaults)
+		defer verkledb.Close()
+	}
 	// Forcibly use hash-based state scheme for retaining all nodes in disk.
-	triedb := triedb.NewDatabase(db, triedb.HashDefaults)
+	triedb := triedb.NewDatabase(db, generatorTrieConfig(config))
 	defer triedb.Close()
 
 	for i := 0; i < n; i++ {
-		statedb, err := state.New(parent.Root(), state.NewDatabase(triedb, nil))
+		// The number and time of the block are derived as in makeHeader.
+		var (
+			tdb      = triedb
+			database = state.NewDatabase(triedb, nil)
+		)
+		if config.IsVerkleTransition(new(big.Int).Add(parent.Number(), common.Big1), parent.Time()+10) {
+			tdb, database = verkledb, state.NewTransitionDatabase(verkledb, triedb)
+		}
+		statedb, err := state.New(parent.Root(), database)
 		if err != nil {
 			panic(err)
 		}
-		block, receipts := genblock(i, parent, triedb, statedb)
+		block, receipts := genblock(i, parent, tdb, statedb)
 
 		// Post-process the receipts.
 		// Here we assign the final block hash and other info into the receipt.
@@ -467,12 +487,22 @@ func GenerateChain(config *params.ChainConfig, parent *types.Block, engine conse
 	return cm.chain, cm.receipts
 }
 
+// generatorTrieConfig returns the config of the merkle trie database used for
+// generating the chain, which records the preimages of the state keys if they
+// are needed by the verkle transition.
+func generatorTrieConfig(config *params.ChainConfig) *triedb.Config {
+	if config != nil && config.VerkleTransitionLeaves > 0 && !config.IsVerkleGenesis() {
+		return &triedb.Config{Preimages: true, HashDB: triedb.HashDefaults.HashDB}
+	}
+	return triedb.HashDefaults
+}
+
 // GenerateChainWithGenesis is a wrapper of GenerateChain which will initialize
 // genesis block to database first according to the provided genesis specification
 // then generate chain on top.
 func GenerateChainWithGenesis(genesis *Genesis, engine consensus.Engine, n int, gen func(int, *BlockGen)) (ethdb.Database, []*types.Block, []types.Receipts) {
 	db := rawdb.NewMemoryDatabase()
-	triedb := triedb.NewDatabase(db, triedb.HashDefaults)
+	triedb := triedb.NewDatabase(db, generatorTrieConfig(genesis.Config))
 	defer triedb.Close()
 	_, err := genesis.Commit(db, triedb)
 	if err != nil {
@@ -0,0 +1,93 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package filtermaps
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// VerifyIndex checks the consistency of the log index stored in the database
+// with the canonical chain of the given head. Every map of the indexed range
+// must point to its last block, which must be canonical, and every block of the
+// indexed range must point to its first log value, both in ascending order. An
+// index of an outdated version is reported as invalid, as it's reset on startup.
+//
+// The range of fully indexed blocks is returned, empty if the log index is not
+// initialized.
+func VerifyIndex(db ethdb.Reader, head uint64) (common.Range[uint64], error) {
+	rs, initialized, err := rawdb.ReadFilterMapsRange(db)
+	if err != nil {
+		return common.Range[uint64]{}, fmt.Errorf("invalid log index range: %v", err)
+	}
+	if !initialized {
+		return common.Range[uint64]{}, nil
+	}
+	if rs.Version != databaseVersion {
+		return common.Range[uint64]{}, fmt.Errorf("log index version mismatch, have %d, want %d", rs.Version, databaseVersion)
+	}
+	if rs.BlocksAfterLast < rs.BlocksFirst || rs.MapsAfterLast < rs.MapsFirst {
+		return common.Range[uint64]{}, fmt.Errorf("invalid log index range, blocks %d-%d, maps %d-%d", rs.BlocksFirst, rs.BlocksAfterLast, rs.MapsFirst, rs.MapsAfterLast)
+	}
+	blocks := common.NewRange(rs.BlocksFirst, rs.BlocksAfterLast-rs.BlocksFirst)
+	if !blocks.IsEmpty() && blocks.Last() > head {
+		return blocks, fmt.Errorf("log index is ahead of the chain, last indexed block %d, head %d", blocks.Last(), head)
+	}
+	var (
+		start   = time.Now()
+		logged  = time.Now()
+		prevMap uint64
+	)
+	for mapIndex := rs.MapsFirst; mapIndex < rs.MapsAfterLast; mapIndex++ {
+		number, id, err := rawdb.ReadFilterMapLastBlock(db, mapIndex)
+		if err != nil {
+			return blocks, fmt.Errorf("last block of map %d is missing: %v", mapIndex, err)
+		}
+		if mapIndex > rs.MapsFirst && number < prevMap {
+			return blocks, fmt.Errorf("last block of map %d is out of order, block %d, previous %d", mapIndex, number, prevMap)
+		}
+		if number > head {
+			return blocks, fmt.Errorf("last block of map %d is ahead of the chain, block %d, head %d", mapIndex, number, head)
+		}
+		if hash := rawdb.ReadCanonicalHash(db, number); hash != id {
+			return blocks, fmt.Errorf("last block of map %d is not canonical, block %d, have %x, want %x", mapIndex, number, id, hash)
+		}
+		prevMap = number
+	}
+	var prevPtr uint64
+	for number := range blocks.Iter() {
+		ptr, err := rawdb.ReadBlockLvPointer(db, number)
+		if err != nil {
+			return blocks, fmt.Errorf("log value pointer of block %d is missing: %v", number, err)
+		}
+		if number > blocks.First() && ptr < prevPtr {
+			return blocks, fmt.Errorf("log value pointer of block %d is out of order, pointer %d, previous %d", number, ptr, prevPtr)
+		}
+		prevPtr = ptr
+
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Verifying log index", "block", number, "last", blocks.Last(), "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	return blocks, nil
+}
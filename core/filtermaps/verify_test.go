@@ -0,0 +1,73 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package filtermaps
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+)
+
+func TestVerifyIndex(t *testing.T) {
+	ts := newTestSetup(t)
+	defer ts.close()
+
+	// An uninitialized index is valid but covers nothing.
+	if blocks, err := VerifyIndex(ts.db, 0); err != nil || !blocks.IsEmpty() {
+		t.Fatalf("uninitialized index: blocks %v, err %v", blocks, err)
+	}
+	ts.chain.addBlocks(100, 5, 2, 4, true)
+	ts.setHistory(0, false)
+	ts.fm.WaitIdle()
+	ts.fm.Stop()
+	ts.fm = nil
+
+	canonical := ts.chain.getCanonicalChain()
+	for number, hash := range canonical {
+		rawdb.WriteCanonicalHash(ts.db, hash, uint64(number))
+	}
+	head := uint64(len(canonical) - 1)
+	blocks, err := VerifyIndex(ts.db, head)
+	if err != nil {
+		t.Fatalf("valid index reported as invalid: %v", err)
+	}
+	if blocks.First() != 0 || blocks.Last() != head {
+		t.Fatalf("indexed range mismatch: have %d-%d, want 0-%d", blocks.First(), blocks.Last(), head)
+	}
+	// The index must not be ahead of the chain.
+	if _, err := VerifyIndex(ts.db, head-1); err == nil {
+		t.Fatal("index ahead of the chain reported as valid")
+	}
+	// The last blocks of the maps must be canonical.
+	rs, _, _ := rawdb.ReadFilterMapsRange(ts.db)
+	number, _, err := rawdb.ReadFilterMapLastBlock(ts.db, rs.MapsFirst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rawdb.WriteCanonicalHash(ts.db, common.Hash{0x01}, number)
+	if _, err := VerifyIndex(ts.db, head); err == nil {
+		t.Fatal("non-canonical map reported as valid")
+	}
+	rawdb.WriteCanonicalHash(ts.db, canonical[number], number)
+
+	// Every indexed block must have a log value pointer.
+	rawdb.DeleteBlockLvPointer(ts.db, head/2)
+	if _, err := VerifyIndex(ts.db, head); err == nil {
+		t.Fatal("missing log value pointer reported as valid")
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/history"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// historyPruner is the module responsible for expiring the bodies and receipts
+// of the blocks falling out of the configured rolling retention window. Only the
+// blocks already moved into the freezer are pruned, by advancing its tail.
+type historyPruner struct {
+	blocks uint64        // Number of recent blocks to retain, 0 if unlimited
+	period time.Duration // Time span of recent blocks to retain, 0 if unlimited
+	chain  *BlockChain
+	term   chan chan struct{}
+	closed chan struct{}
+}
+
+// newHistoryPruner initializes the history pruner and starts its background
+// loop.
+func newHistoryPruner(blocks uint64, period time.Duration, chain *BlockChain) *historyPruner {
+	pruner := &historyPruner{
+		blocks: blocks,
+		period: period,
+		chain:  chain,
+		term:   make(chan chan struct{}),
+		closed: make(chan struct{}),
+	}
+	go pruner.loop()
+
+	var window []any
+	if blocks != 0 {
+		window = append(window, "blocks", blocks)
+	}
+	if period != 0 {
+		window = append(window, "period", common.PrettyDuration(period))
+	}
+	log.Info("Initialized rolling history pruner", window...)
+
+	return pruner
+}
+
+// target returns the first block whose history is to be retained, given the
+// current chain head. All history below it can be pruned.
+func (p *historyPruner) target(head *types.Header) uint64 {
+	var (
+		number = head.Number.Uint64()
+		target uint64
+	)
+	if p.blocks != 0 && number >= p.blocks {
+		target = number - p.blocks + 1
+	}
+	if p.period != 0 {
+		// Headers are never pruned, so binary search for the first block which
+		// is still within the retention period of the chain head.
+		var limit uint64
+		if period := uint64(p.period / time.Second); head.Time > period {
+			limit = head.Time - period
+		}
+		first := uint64(sort.Search(int(number+1), func(n int) bool {
+			header := p.chain.GetHeaderByNumber(uint64(n))
+			return header == nil || header.Time >= limit
+		}))
+		// History is retained if either of the limits covers it.
+		if p.blocks == 0 || first < target {
+			target = first
+		}
+	}
+	return target
+}
+
+// prune expires the history below the retention window of the given chain head.
+// The transaction indexes of the affected blocks are removed first, while the
+// bodies are still available, followed by advancing the freezer tail. Blocks
+// archived in era files keep their transaction indexes.
+func (p *historyPruner) prune(head *types.Header, stop chan struct{}) error {
+	db := p.chain.db
+	tail, err := db.Tail()
+	if err != nil {
+		return err
+	}
+	frozen, err := db.Ancients()
+	if err != nil {
+		return err
+	}
+	// Only the frozen blocks can be pruned, the rest of the history within the
+	// key-value store will be pruned once migrated into the freezer.
+	target := min(p.target(head), frozen)
+	if target <= tail {
+		return nil
+	}
+	// The transactions of the blocks archived in era files remain indexed, as
+	// their history can still be served after the truncation. The unindexing is
+	// done by the transaction indexer if it's running, to not race with it.
+	floor := rawdb.EraHistoryFloor(db, target)
+	if indexer := p.chain.txIndexer; indexer != nil {
+		// Bail out if the unindexing was interrupted, the history must not be
+		// removed while the transaction indexes still point to it.
+		if !indexer.unindex(floor, stop) {
+			return nil
+		}
+	} else if indexTail := rawdb.ReadTxIndexTail(db); indexTail != nil && *indexTail < floor {
+		rawdb.UnindexTransactions(db, *indexTail, floor, stop, false)
+		if indexTail = rawdb.ReadTxIndexTail(db); indexTail != nil && *indexTail < floor {
+			return nil
+		}
+	}
+	start := time.Now()
+	if _, err := db.TruncateTail(target); err != nil {
+		return fmt.Errorf("failed to truncate ancient data: %v", err)
+	}
+	p.chain.historyPrunePoint.Store(&history.PrunePoint{
+		BlockNumber: target,
+		BlockHash:   rawdb.ReadCanonicalHash(db, target),
+	})
+	log.Debug("Pruned chain history", "from", tail, "to", target, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// loop is the scheduler of the pruner, starting a pruning run in the background
+// upon every new chain head unless one is already active.
+func (p *historyPruner) loop() {
+	defer close(p.closed)
+
+	var (
+		stop   chan struct{} // Non-nil if background routine is active
+		done   chan struct{} // Non-nil if background routine is active
+		headCh = make(chan ChainHeadEvent)
+		sub    = p.chain.SubscribeChainHeadEvent(headCh)
+	)
+	defer sub.Unsubscribe()
+
+	run := func(head *types.Header) {
+		stop = make(chan struct{})
+		done = make(chan struct{})
+		go func(stop, done chan struct{}) {
+			defer close(done)
+			if err := p.prune(head, stop); err != nil {
+				log.Error("Failed to prune chain history", "err", err)
+			}
+		}(stop, done)
+	}
+	if head := p.chain.CurrentBlock(); head != nil && head.Number.Uint64() != 0 {
+		run(head)
+	}
+	for {
+		select {
+		case h := <-headCh:
+			if done == nil {
+				run(h.Header)
+			}
+
+		case <-done:
+			stop = nil
+			done = nil
+
+		case ch := <-p.term:
+			if stop != nil {
+				close(stop)
+			}
+			if done != nil {
+				log.Info("Waiting background history pruner to exit")
+				<-done
+			}
+			close(ch)
+			return
+		}
+	}
+}
+
+// close shutdown the pruner. Safe to be called for multiple times.
+func (p *historyPruner) close() {
+	ch := make(chan struct{})
+	select {
+	case p.term <- ch:
+		<-ch
+	case <-p.closed:
+	}
+}
@@ -0,0 +1,309 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/internal/era/execdb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// TestHistoryPruner tests that the rolling history pruner advances the freezer
+// tail according to the configured retention window.
+func TestHistoryPruner(t *testing.T) {
+	var (
+		testBankKey, _  = crypto.GenerateKey()
+		testBankAddress = crypto.PubkeyToAddress(testBankKey.PublicKey)
+		testBankFunds   = big.NewInt(1000000000000000000)
+
+		gspec = &Genesis{
+			Config:  params.TestChainConfig,
+			Alloc:   types.GenesisAlloc{testBankAddress: {Balance: testBankFunds}},
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		engine    = ethash.NewFaker()
+		nonce     = uint64(0)
+		chainHead = uint64(64)
+		frozen    = uint64(48)
+	)
+	_, blocks, receipts := GenerateChainWithGenesis(gspec, engine, int(chainHead), func(i int, gen *BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, common.HexToAddress("0xdeadbeef"), big.NewInt(1000), params.TxGas, big.NewInt(10*params.InitialBaseFee), nil), types.HomesteadSigner{}, testBankKey)
+		gen.AddTx(tx)
+		nonce += 1
+	})
+	db, err := rawdb.Open(rawdb.NewMemoryDatabase(), rawdb.OpenOptions{})
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	chain, err := NewBlockChain(db, gspec, engine, DefaultConfig())
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if n, err := chain.InsertReceiptChain(blocks, types.EncodeBlockReceiptLists(receipts), frozen); err != nil {
+		t.Fatalf("Failed to insert receipt %d: %v", n, err)
+	}
+	if ancients, _ := db.Ancients(); ancients != frozen {
+		t.Fatalf("Unexpected number of frozen blocks, want %d, got %d", frozen, ancients)
+	}
+	rawdb.IndexTransactions(db, 0, chainHead+1, nil, false)
+
+	var (
+		head  = blocks[len(blocks)-1].Header()
+		cases = []struct {
+			blocks uint64
+			period time.Duration
+			tail   uint64
+		}{
+			{blocks: 100, tail: 0},                           // retention window beyond genesis
+			{blocks: 30, tail: 35},                           // block limit
+			{period: 200 * time.Second, tail: 44},            // time limit
+			{blocks: 30, period: 50 * time.Second, tail: 44}, // tail never moves backwards
+			{blocks: 5, tail: frozen},                        // only frozen blocks are pruned
+		}
+	)
+	for i, c := range cases {
+		pruner := &historyPruner{blocks: c.blocks, period: c.period, chain: chain}
+		if err := pruner.prune(head, nil); err != nil {
+			t.Fatalf("case %d: failed to prune history: %v", i, err)
+		}
+		if tail, _ := db.Tail(); tail != c.tail {
+			t.Fatalf("case %d: unexpected freezer tail, want %d, got %d", i, c.tail, tail)
+		}
+		if c.tail == 0 {
+			continue
+		}
+		if cutoff, hash := chain.HistoryPruningCutoff(); cutoff != c.tail || hash != blocks[c.tail-1].Hash() {
+			t.Fatalf("case %d: unexpected prune point %d [%x]", i, cutoff, hash)
+		}
+		if tail := rawdb.ReadTxIndexTail(db); tail == nil || *tail != c.tail {
+			t.Fatalf("case %d: unexpected tx index tail %v", i, tail)
+		}
+		for _, block := range blocks {
+			var (
+				number = block.NumberU64()
+				pruned = number < c.tail
+			)
+			if have := rawdb.ReadBody(db, block.Hash(), number) == nil; have != pruned {
+				t.Fatalf("case %d: block %d pruned %v, want %v", i, block.NumberU64(), have, pruned)
+			}
+			if have := rawdb.ReadRawReceipts(db, block.Hash(), number) == nil; have != pruned {
+				t.Fatalf("case %d: receipts %d pruned %v, want %v", i, block.NumberU64(), have, pruned)
+			}
+			verifyIndexes(t, db, block, !pruned)
+		}
+	}
+}
+
+// writeEraArchive archives the genesis block and the given blocks into a single
+// era file within the given directory.
+func writeEraArchive(t *testing.T, dir string, genesis *types.Block, blocks []*types.Block, receipts []types.Receipts) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create era directory: %v", err)
+	}
+	f, err := os.CreateTemp(dir, "")
+	if err != nil {
+		t.Fatalf("Failed to create era file: %v", err)
+	}
+	defer f.Close()
+
+	builder := execdb.NewBuilder(f)
+	if err := builder.Add(genesis, nil); err != nil {
+		t.Fatalf("Failed to archive genesis: %v", err)
+	}
+	for i, block := range blocks {
+		if err := builder.Add(block, receipts[i]); err != nil {
+			t.Fatalf("Failed to archive block %d: %v", block.NumberU64(), err)
+		}
+	}
+	root, err := builder.Finalize()
+	if err != nil {
+		t.Fatalf("Failed to finalize era file: %v", err)
+	}
+	if err := os.Rename(f.Name(), filepath.Join(dir, execdb.Filename("test", 0, root))); err != nil {
+		t.Fatalf("Failed to rename era file: %v", err)
+	}
+}
+
+// TestHistoryPrunerEraFallback tests that the pruned chain history is served
+// from the era files configured for the database, including the transaction
+// lookups of the archived blocks.
+func TestHistoryPrunerEraFallback(t *testing.T) {
+	var (
+		testBankKey, _  = crypto.GenerateKey()
+		testBankAddress = crypto.PubkeyToAddress(testBankKey.PublicKey)
+		testBankFunds   = big.NewInt(1000000000000000000)
+
+		gspec = &Genesis{
+			Config:  params.TestChainConfig,
+			Alloc:   types.GenesisAlloc{testBankAddress: {Balance: testBankFunds}},
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		engine    = ethash.NewFaker()
+		nonce     = uint64(0)
+		chainHead = uint64(64)
+		frozen    = uint64(48)
+		archived  = uint64(40)
+		datadir   = t.TempDir()
+		eradir    = filepath.Join(datadir, "era")
+	)
+	_, blocks, receipts := GenerateChainWithGenesis(gspec, engine, int(chainHead), func(i int, gen *BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, common.HexToAddress("0xdeadbeef"), big.NewInt(1000), params.TxGas, big.NewInt(10*params.InitialBaseFee), nil), types.HomesteadSigner{}, testBankKey)
+		gen.AddTx(tx)
+		nonce += 1
+	})
+	// Archive the blocks below the retention window into an era file
+	writeEraArchive(t, eradir, gspec.ToBlock(), blocks[:archived-1], receipts[:archived-1])
+
+	db, err := rawdb.Open(rawdb.NewMemoryDatabase(), rawdb.OpenOptions{Ancient: datadir, Era: eradir})
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	chain, err := NewBlockChain(db, gspec, engine, DefaultConfig())
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if n, err := chain.InsertReceiptChain(blocks, types.EncodeBlockReceiptLists(receipts), frozen); err != nil {
+		t.Fatalf("Failed to insert receipt %d: %v", n, err)
+	}
+	rawdb.IndexTransactions(db, 0, chainHead+1, nil, false)
+
+	var (
+		head  = blocks[len(blocks)-1].Header()
+		cases = []struct {
+			blocks uint64
+			tail   uint64
+			floor  uint64
+		}{
+			{blocks: 30, tail: 35, floor: 0},  // pruned range is archived
+			{blocks: 20, tail: 45, floor: 45}, // pruned range exceeds the archive
+		}
+	)
+	for i, c := range cases {
+		pruner := &historyPruner{blocks: c.blocks, chain: chain}
+		if err := pruner.prune(head, nil); err != nil {
+			t.Fatalf("case %d: failed to prune history: %v", i, err)
+		}
+		if tail, _ := db.Tail(); tail != c.tail {
+			t.Fatalf("case %d: unexpected freezer tail, want %d, got %d", i, c.tail, tail)
+		}
+		if floor := chain.HistoryFloor(); floor != c.floor {
+			t.Fatalf("case %d: unexpected history floor, want %d, got %d", i, c.floor, floor)
+		}
+		for j, block := range blocks {
+			var (
+				number    = block.NumberU64()
+				available = number < archived || number >= c.tail
+			)
+			body := rawdb.ReadBody(db, block.Hash(), number)
+			if have := body != nil; have != available {
+				t.Fatalf("case %d: block %d available %v, want %v", i, number, have, available)
+			}
+			if body != nil && types.DeriveSha(types.Transactions(body.Transactions), trie.NewStackTrie(nil)) != block.TxHash() {
+				t.Fatalf("case %d: block %d body mismatch", i, number)
+			}
+			have := rawdb.ReadReceipts(db, block.Hash(), number, block.Time(), gspec.Config)
+			if (have != nil) != available {
+				t.Fatalf("case %d: receipts %d available %v, want %v", i, number, have != nil, available)
+			}
+			if have != nil && types.DeriveSha(have, trie.NewStackTrie(nil)) != types.DeriveSha(receipts[j], trie.NewStackTrie(nil)) {
+				t.Fatalf("case %d: receipts %d mismatch", i, number)
+			}
+			verifyIndexes(t, db, block, number >= c.floor)
+			if number >= c.floor {
+				for _, tx := range block.Transactions() {
+					lookup, have := chain.GetCanonicalTransaction(tx.Hash())
+					if lookup == nil || have.Hash() != tx.Hash() || lookup.BlockIndex != number {
+						t.Fatalf("case %d: transaction %x of block %d not found", i, tx.Hash(), number)
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestTxIndexerEraHistory tests that the transactions of the pruned blocks are
+// indexed from the era files, if the history was pruned before the era files
+// became available.
+func TestTxIndexerEraHistory(t *testing.T) {
+	var (
+		testBankKey, _  = crypto.GenerateKey()
+		testBankAddress = crypto.PubkeyToAddress(testBankKey.PublicKey)
+		testBankFunds   = big.NewInt(1000000000000000000)
+
+		gspec = &Genesis{
+			Config:  params.TestChainConfig,
+			Alloc:   types.GenesisAlloc{testBankAddress: {Balance: testBankFunds}},
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		engine    = ethash.NewFaker()
+		nonce     = uint64(0)
+		chainHead = uint64(64)
+		frozen    = uint64(48)
+		pruned    = uint64(35)
+		datadir   = t.TempDir()
+		eradir    = filepath.Join(datadir, "era")
+	)
+	_, blocks, receipts := GenerateChainWithGenesis(gspec, engine, int(chainHead), func(i int, gen *BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, common.HexToAddress("0xdeadbeef"), big.NewInt(1000), params.TxGas, big.NewInt(10*params.InitialBaseFee), nil), types.HomesteadSigner{}, testBankKey)
+		gen.AddTx(tx)
+		nonce += 1
+	})
+	writeEraArchive(t, eradir, gspec.ToBlock(), blocks[:pruned-1], receipts[:pruned-1])
+
+	db, err := rawdb.Open(rawdb.NewMemoryDatabase(), rawdb.OpenOptions{Ancient: datadir, Era: eradir})
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	rawdb.WriteAncientBlocks(db, append([]*types.Block{gspec.ToBlock()}, blocks[:frozen-1]...), types.EncodeBlockReceiptLists(append([]types.Receipts{{}}, receipts[:frozen-1]...)))
+
+	// Prune the history along with the transaction indexes, as if it happened
+	// without any era files around.
+	rawdb.IndexTransactions(db, pruned, frozen, nil, false)
+	if _, err := db.TruncateTail(pruned); err != nil {
+		t.Fatalf("Failed to truncate history: %v", err)
+	}
+	floor := rawdb.EraHistoryFloor(db, pruned)
+	if floor != 0 {
+		t.Fatalf("Unexpected era history floor, want %d, got %d", 0, floor)
+	}
+	indexer := &txIndexer{db: db}
+	indexer.cutoff.Store(floor)
+	indexer.run(frozen-1, make(chan struct{}), make(chan struct{}))
+
+	verify(t, db, blocks[:frozen-1], 0)
+}
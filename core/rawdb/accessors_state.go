@@ -126,6 +126,21 @@ func DeleteStateID(db ethdb.KeyValueWriter, root common.Hash) {
 	}
 }
 
+// ReadVerkleTransition retrieves the encoded progress of the verkle transition
+// at the state with the provided root.
+func ReadVerkleTransition(db ethdb.KeyValueReader, root common.Hash) []byte {
+	data, _ := db.Get(verkleTransitionKey(root))
+	return data
+}
+
+// WriteVerkleTransition writes the encoded progress of the verkle transition
+// at the state with the provided root.
+func WriteVerkleTransition(db ethdb.KeyValueWriter, root common.Hash, blob []byte) {
+	if err := db.Put(verkleTransitionKey(root), blob); err != nil {
+		log.Crit("Failed to store verkle transition", "err", err)
+	}
+}
+
 // ReadPersistentStateID retrieves the id of the persistent state from the database.
 func ReadPersistentStateID(db ethdb.KeyValueReader) uint64 {
 	data, _ := db.Get(persistentStateIDKey)
@@ -50,8 +50,9 @@ var chainFreezerTableConfigs = map[string]freezerTableConfig{
 
 // freezerTableConfig contains the settings for a freezer table.
 type freezerTableConfig struct {
-	noSnappy bool // disables item compression
-	prunable bool // true for tables that can be pruned by TruncateTail
+	noSnappy    bool               // disables item compression
+	prunable    bool               // true for tables that can be pruned by TruncateTail
+	compression freezerCompression // compression algorithm of the newly created table
 }
 
 const (
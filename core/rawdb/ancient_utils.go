@@ -18,6 +18,7 @@ package rawdb
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -91,7 +92,7 @@ func inspectFreezers(db ethdb.Database) ([]freezerInfo, error) {
 		case MerkleStateFreezerName, VerkleStateFreezerName:
 			datadir, err := db.AncientDatadir()
 			if err != nil {
-				return nil, err
+				continue // the ancient directory might not be accessible, e.g. remote database
 			}
 			f, err := NewStateFreezer(datadir, freezer == VerkleStateFreezerName, true)
 			if err != nil {
@@ -117,6 +118,53 @@ func inspectFreezers(db ethdb.Database) ([]freezerInfo, error) {
 // be opened. Start and end specify the range for dumping out indexes.
 // Note this function can only be used for debugging purposes.
 func InspectFreezerTable(ancient string, freezerName string, tableName string, start, end int64) error {
+	path, config, err := resolveFreezerTable(ancient, freezerName, tableName)
+	if err != nil {
+		return err
+	}
+	table, err := newFreezerTable(path, tableName, config, true)
+	if err != nil {
+		return err
+	}
+	table.dumpIndexStdout(start, end)
+	return nil
+}
+
+// RecompressFreezerTable rewrites the items of a specific freezer table with the
+// given compression algorithm (snappy or zstd), which is also applied to the
+// items appended afterwards. The passed ancient indicates the path of root
+// ancient directory, the freezer must not be in use.
+func RecompressFreezerTable(ancient string, freezerName string, tableName string, compression string) error {
+	path, config, err := resolveFreezerTable(ancient, freezerName, tableName)
+	if err != nil {
+		return err
+	}
+	kind, err := parseCompression(compression)
+	if err != nil {
+		return err
+	}
+	if config.noSnappy {
+		return fmt.Errorf("table %s is not compressed", tableName)
+	}
+	// The offloaded data files are only accessible with the object store
+	// configured, refuse to touch such tables.
+	if _, err := os.Stat(filepath.Join(path, fmt.Sprintf("%s.remote", tableName))); err == nil {
+		return fmt.Errorf("table %s has data files offloaded to the object store", tableName)
+	}
+	table, err := newFreezerTable(path, tableName, config, false)
+	if err != nil {
+		return err
+	}
+	if err := table.recompress(kind); err != nil {
+		table.Close()
+		return err
+	}
+	return table.Close()
+}
+
+// resolveFreezerTable returns the directory and the settings of a specific
+// freezer table.
+func resolveFreezerTable(ancient string, freezerName string, tableName string) (string, freezerTableConfig, error) {
 	var (
 		path   string
 		tables map[string]freezerTableConfig
@@ -127,20 +175,15 @@ func InspectFreezerTable(ancient string, freezerName string, tableName string, s
 	case MerkleStateFreezerName, VerkleStateFreezerName:
 		path, tables = filepath.Join(ancient, freezerName), stateFreezerTableConfigs
 	default:
-		return fmt.Errorf("unknown freezer, supported ones: %v", freezers)
+		return "", freezerTableConfig{}, fmt.Errorf("unknown freezer, supported ones: %v", freezers)
 	}
-	noSnappy, exist := tables[tableName]
+	config, exist := tables[tableName]
 	if !exist {
 		var names []string
 		for name := range tables {
 			names = append(names, name)
 		}
-		return fmt.Errorf("unknown table, supported ones: %v", names)
+		return "", freezerTableConfig{}, fmt.Errorf("unknown table, supported ones: %v", names)
 	}
-	table, err := newFreezerTable(path, tableName, noSnappy, true)
-	if err != nil {
-		return err
-	}
-	table.dumpIndexStdout(start, end)
-	return nil
+	return path, config, nil
 }
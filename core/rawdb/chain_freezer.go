@@ -59,8 +59,10 @@ type chainFreezer struct {
 //   - if the empty directory is given, initializes the pure in-memory
 //     state freezer (e.g. dev mode).
 //   - if non-empty directory is given, initializes the regular file-based
-//     state freezer.
-func newChainFreezer(datadir string, eraDir string, namespace string, readonly bool) (*chainFreezer, error) {
+//     state freezer, with the sealed segments optionally offloaded to the
+//     given object store. The file-based freezer can be opened as the
+//     secondary of another process.
+func newChainFreezer(datadir string, eraDir string, namespace string, readonly bool, secondary bool, remote *remoteStore) (*chainFreezer, error) {
 	if datadir == "" {
 		return &chainFreezer{
 			ancients: NewMemoryFreezer(readonly, chainFreezerTableConfigs),
@@ -68,7 +70,7 @@ func newChainFreezer(datadir string, eraDir string, namespace string, readonly b
 			trigger:  make(chan chan struct{}),
 		}, nil
 	}
-	freezer, err := NewFreezer(datadir, namespace, readonly, freezerTableSize, chainFreezerTableConfigs)
+	freezer, err := newFreezer(datadir, namespace, readonly, secondary, freezerTableSize, chainFreezerTableConfigs, remote)
 	if err != nil {
 		return nil, err
 	}
@@ -375,6 +377,47 @@ func (f *chainFreezer) Ancient(kind string, number uint64) ([]byte, error) {
 	return nil, errUnknownTable
 }
 
+// eraFloor returns the first block from which on the chain history below the
+// given limit is continuously available in the optional era backend.
+func (f *chainFreezer) eraFloor(limit uint64) uint64 {
+	if f.eradb == nil {
+		return limit
+	}
+	return f.eradb.Floor(limit)
+}
+
+// EraHistoryFloor returns the first block from which on the pruned chain history
+// below the given limit (usually the freezer tail) can be served from the era
+// files configured for the database. The limit is returned if the database has
+// no era backend or the block right below the limit is not archived.
+func EraHistoryFloor(db ethdb.AncientReader, limit uint64) uint64 {
+	floor := limit
+	db.ReadAncients(func(reader ethdb.AncientReaderOp) error {
+		if f, ok := reader.(*chainFreezer); ok {
+			floor = f.eraFloor(limit)
+		}
+		return nil
+	})
+	return floor
+}
+
+// ImportEraHistory copies the given era1 or erae file into the era directory of
+// the database, allowing the archived blocks to be served once the history they
+// cover is pruned. The file is expected to be verified by the caller.
+func ImportEraHistory(db ethdb.AncientReader, path string) error {
+	var store *eradb.Store
+	db.ReadAncients(func(reader ethdb.AncientReaderOp) error {
+		if f, ok := reader.(*chainFreezer); ok {
+			store = f.eradb
+		}
+		return nil
+	})
+	if store == nil {
+		return errors.New("database has no era directory")
+	}
+	return store.Import(path)
+}
+
 // ReadAncients executes an operation while preventing mutations to the freezer,
 // i.e. if fn performs multiple reads, they will be consistent with each other.
 func (f *chainFreezer) ReadAncients(fn func(ethdb.AncientReaderOp) error) (err error) {
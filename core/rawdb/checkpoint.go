@@ -0,0 +1,329 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// errCheckpointUnsupported is returned if the database, either the key-value
+// store or the ancient store, doesn't support taking checkpoints.
+var errCheckpointUnsupported = errors.New("database does not support checkpoints")
+
+// capturedFile is a file of a freezer table, opened at the time the checkpoint
+// was captured. The file is kept open, so that it's still accessible even if it
+// gets deleted by a tail truncation in the meantime. Offloaded data files are
+// read from the object store instead.
+type capturedFile struct {
+	name string
+	file interface {
+		io.ReaderAt
+		io.Closer
+	}
+	size int64 // Size of the file at the time of capture
+}
+
+// tableCheckpoint is a point-in-time view of a freezer table.
+type tableCheckpoint struct {
+	name  string
+	meta  []byte // Content of the metadata file
+	files []*capturedFile
+}
+
+// checkpoint flushes the table and captures its current content. The caller is
+// required to block all mutations of the freezer while capturing, the captured
+// files can be written out after the freezer is unblocked again.
+func (t *freezerTable) checkpoint() (*tableCheckpoint, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if err := t.doSync(); err != nil {
+		return nil, err
+	}
+	cp := &tableCheckpoint{name: t.name}
+
+	// Read the metadata, which is small enough to be captured in memory
+	stat, err := t.metadata.file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	cp.meta = make([]byte, stat.Size())
+	if _, err := t.metadata.file.ReadAt(cp.meta, 0); err != nil {
+		return nil, err
+	}
+	// Open the index and data files of the table. Both are only ever appended
+	// to, so recording their current sizes is sufficient to capture them.
+	capture := func(name string) error {
+		f, err := os.Open(filepath.Join(t.path, name))
+		if err != nil {
+			return err
+		}
+		stat, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return err
+		}
+		cp.files = append(cp.files, &capturedFile{name: name, file: f, size: stat.Size()})
+		return nil
+	}
+	if err := capture(filepath.Base(t.index.Name())); err != nil {
+		cp.release()
+		return nil, err
+	}
+	if t.codec != nil && t.codec.dict != nil {
+		if err := capture(filepath.Base(t.dictionaryName())); err != nil {
+			cp.release()
+			return nil, err
+		}
+	}
+	for num := t.tailId; num <= t.headId; num++ {
+		name := t.fileName(num)
+		if t.isRemote(num) {
+			size, err := t.remote.store.Size(name)
+			if err != nil {
+				cp.release()
+				return nil, err
+			}
+			cp.files = append(cp.files, &capturedFile{name: name, file: t.remoteFiles[num], size: size})
+			continue
+		}
+		if err := capture(name); err != nil {
+			cp.release()
+			return nil, err
+		}
+	}
+	return cp, nil
+}
+
+// write writes the captured table into the given directory.
+func (cp *tableCheckpoint) write(dir string) error {
+	if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("%s.meta", cp.name)), cp.meta, 0644); err != nil {
+		return err
+	}
+	for _, captured := range cp.files {
+		if err := captured.write(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// write copies the captured content of the file into the given directory.
+func (c *capturedFile) write(dir string) error {
+	out, err := os.OpenFile(filepath.Join(dir, c.name), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	n, err := io.Copy(out, io.NewSectionReader(c.file, 0, c.size))
+	if err != nil {
+		return err
+	}
+	// The file may have been truncated by a chain rewind in the meantime, in
+	// which case the captured content is lost.
+	if n != c.size {
+		return fmt.Errorf("file %s truncated during checkpoint, have %d bytes, want %d", c.name, n, c.size)
+	}
+	return out.Sync()
+}
+
+// release closes all the files held open by the checkpoint.
+func (cp *tableCheckpoint) release() {
+	for _, captured := range cp.files {
+		captured.file.Close()
+	}
+}
+
+// freezerCheckpoint is a point-in-time view of all tables of a freezer.
+type freezerCheckpoint struct {
+	tables []*tableCheckpoint
+	frozen uint64 // Number of items at the time of capture
+	tail   uint64 // Number of the first item at the time of capture
+}
+
+// checkpoint captures the current content of all tables of the freezer. The
+// caller is required to block all mutations of the freezer while capturing.
+func (f *Freezer) checkpoint() (*freezerCheckpoint, error) {
+	cp := &freezerCheckpoint{frozen: f.frozen.Load(), tail: f.tail.Load()}
+	for _, table := range f.tables {
+		tcp, err := table.checkpoint()
+		if err != nil {
+			cp.release()
+			return nil, err
+		}
+		cp.tables = append(cp.tables, tcp)
+	}
+	return cp, nil
+}
+
+// write writes the captured tables into the given directory.
+func (cp *freezerCheckpoint) write(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, table := range cp.tables {
+		if err := table.write(dir); err != nil {
+			return err
+		}
+	}
+	log.Info("Created freezer checkpoint", "dir", dir, "tail", cp.tail, "items", cp.frozen)
+	return nil
+}
+
+// release closes all the files held open by the checkpoint.
+func (cp *freezerCheckpoint) release() {
+	for _, table := range cp.tables {
+		table.release()
+	}
+}
+
+// Checkpoint creates a consistent point-in-time copy of the freezer in the given
+// directory, which must not exist or be empty. All mutations of the freezer are
+// blocked until the tables are captured, the actual copying happens afterwards.
+//
+// The optional callback is invoked while the mutations are still blocked, which
+// allows taking the checkpoint of associated data consistent with the freezer.
+func (f *Freezer) Checkpoint(dir string, fn func() error) error {
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+		return fmt.Errorf("checkpoint directory %s is not empty", dir)
+	}
+	f.writeLock.RLock()
+	cp, err := f.checkpoint()
+	if err == nil && fn != nil {
+		err = fn()
+	}
+	f.writeLock.RUnlock()
+
+	if cp != nil {
+		defer cp.release()
+	}
+	if err != nil {
+		return err
+	}
+	return cp.write(dir)
+}
+
+// FreezerCheckpoint is a point-in-time view of an ancient store. The mutations
+// of the store are blocked from the capture until Unblock is called, allowing
+// associated data to be captured consistently with it in the meantime.
+type FreezerCheckpoint struct {
+	cp      *freezerCheckpoint
+	unblock func()
+	once    sync.Once
+}
+
+// CaptureFreezer captures the current content of the given ancient store and
+// blocks all of its mutations until the returned checkpoint is unblocked. The
+// captured content is written out via Write, after unblocking the store.
+func CaptureFreezer(store ethdb.AncientStore) (*FreezerCheckpoint, error) {
+	var (
+		freezer *Freezer
+		unlock  func()
+	)
+	switch store := store.(type) {
+	case *Freezer:
+		freezer, unlock = store, func() {}
+	case *resettableFreezer:
+		// Prevent the freezer from being reset while it's captured
+		store.lock.RLock()
+		freezer, unlock = store.freezer, store.lock.RUnlock
+	default:
+		return nil, errCheckpointUnsupported
+	}
+	freezer.writeLock.RLock()
+	cp, err := freezer.checkpoint()
+	if err != nil {
+		freezer.writeLock.RUnlock()
+		unlock()
+		return nil, err
+	}
+	return &FreezerCheckpoint{
+		cp: cp,
+		unblock: func() {
+			freezer.writeLock.RUnlock()
+			unlock()
+		},
+	}, nil
+}
+
+// Unblock resumes the mutations of the captured ancient store. It's safe to be
+// called multiple times.
+func (cp *FreezerCheckpoint) Unblock() {
+	cp.once.Do(cp.unblock)
+}
+
+// Write unblocks the captured ancient store and writes the captured content into
+// the given directory.
+func (cp *FreezerCheckpoint) Write(dir string) error {
+	cp.Unblock()
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+		return fmt.Errorf("checkpoint directory %s is not empty", dir)
+	}
+	return cp.cp.write(dir)
+}
+
+// Release unblocks the captured ancient store and closes all the files held
+// open by the checkpoint.
+func (cp *FreezerCheckpoint) Release() {
+	cp.Unblock()
+	cp.cp.release()
+}
+
+// Checkpoint implements ethdb.Checkpointer, creating a consistent point-in-time
+// copy of both the key-value store and the chain freezer. The key-value store
+// checkpoint is taken while the freezer mutations are blocked, so that no chain
+// segment can be migrated between the two in the meantime.
+//
+// The checkpoint is laid out as a regular database directory, with the chain
+// freezer placed in the "ancient/chain" sub folder.
+func (frdb *freezerdb) Checkpoint(dir string, fn func() error) error {
+	kvdb, ok := frdb.KeyValueStore.(ethdb.Checkpointer)
+	if !ok {
+		return errCheckpointUnsupported
+	}
+	freezer, ok := frdb.chainFreezer.ancients.(*Freezer)
+	if !ok {
+		return errCheckpointUnsupported
+	}
+	return freezer.Checkpoint(filepath.Join(dir, "ancient", ChainFreezerName), func() error {
+		return kvdb.Checkpoint(dir, fn)
+	})
+}
+
+// Checkpoint creates a consistent point-in-time copy of the given database in
+// the given directory, which must not exist yet. The optional callback is
+// invoked once the point-in-time view of the database is captured, before the
+// data is copied.
+func Checkpoint(db ethdb.Database, dir string, fn func() error) error {
+	cp, ok := db.(ethdb.Checkpointer)
+	if !ok {
+		return errCheckpointUnsupported
+	}
+	if _, err := os.Stat(dir); err == nil {
+		return fmt.Errorf("checkpoint directory %s already exists", dir)
+	}
+	return cp.Checkpoint(dir, fn)
+}
@@ -0,0 +1,122 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb/pebble"
+)
+
+// makeCheckpointChain creates a chain of empty blocks for checkpoint testing.
+func makeCheckpointChain(n int) []*types.Block {
+	var (
+		blocks []*types.Block
+		parent common.Hash
+	)
+	for i := 0; i < n; i++ {
+		block := types.NewBlockWithHeader(&types.Header{
+			ParentHash: parent,
+			Number:     big.NewInt(int64(i)),
+			Difficulty: big.NewInt(1),
+			Extra:      []byte("checkpoint"),
+		})
+		blocks = append(blocks, block)
+		parent = block.Hash()
+	}
+	return blocks
+}
+
+func TestDatabaseCheckpoint(t *testing.T) {
+	var (
+		dir    = t.TempDir()
+		blocks = makeCheckpointChain(20)
+		frozen = uint64(10)
+	)
+	kvdb, err := pebble.New(filepath.Join(dir, "chaindata"), 16, 16, "", false)
+	if err != nil {
+		t.Fatalf("Failed to open key-value store: %v", err)
+	}
+	db, err := Open(kvdb, OpenOptions{Ancient: filepath.Join(dir, "chaindata", "ancient")})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	receipts := types.EncodeBlockReceiptLists(make([]types.Receipts, len(blocks)))
+	if _, err := WriteAncientBlocks(db, blocks[:frozen], receipts[:frozen]); err != nil {
+		t.Fatalf("Failed to freeze blocks: %v", err)
+	}
+	for _, block := range blocks {
+		WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		if block.NumberU64() >= frozen {
+			WriteBlock(db, block)
+		}
+	}
+	head := blocks[len(blocks)-1].Hash()
+	WriteHeadHeaderHash(db, head)
+	WriteHeadBlockHash(db, head)
+
+	checkpoint := filepath.Join(dir, "checkpoint")
+	if err := Checkpoint(db, checkpoint, nil); err != nil {
+		t.Fatalf("Failed to create checkpoint: %v", err)
+	}
+	if err := Checkpoint(db, checkpoint, nil); err == nil {
+		t.Fatal("Checkpoint overwrote existing directory")
+	}
+	// Mutate the live database, none of which may leak into the checkpoint
+	if _, err := WriteAncientBlocks(db, blocks[frozen:], receipts[frozen:]); err != nil {
+		t.Fatalf("Failed to freeze blocks: %v", err)
+	}
+	if _, err := db.TruncateTail(5); err != nil {
+		t.Fatalf("Failed to truncate tail: %v", err)
+	}
+	WriteHeadBlockHash(db, blocks[15].Hash())
+
+	// Open the checkpoint and ensure it's a consistent copy of the database
+	cpkv, err := pebble.New(checkpoint, 16, 16, "", true)
+	if err != nil {
+		t.Fatalf("Failed to open checkpoint key-value store: %v", err)
+	}
+	cpdb, err := Open(cpkv, OpenOptions{Ancient: filepath.Join(checkpoint, "ancient"), ReadOnly: true})
+	if err != nil {
+		t.Fatalf("Failed to open checkpoint: %v", err)
+	}
+	defer cpdb.Close()
+
+	if items, _ := cpdb.Ancients(); items != frozen {
+		t.Fatalf("Unexpected number of frozen items, want %d, got %d", frozen, items)
+	}
+	if tail, _ := cpdb.Tail(); tail != 0 {
+		t.Fatalf("Unexpected freezer tail, want 0, got %d", tail)
+	}
+	if hash := ReadHeadBlockHash(cpdb); hash != head {
+		t.Fatalf("Unexpected head block, want %x, got %x", head, hash)
+	}
+	for _, block := range blocks {
+		if hash := ReadCanonicalHash(cpdb, block.NumberU64()); hash != block.Hash() {
+			t.Fatalf("Canonical hash %d mismatch, want %x, got %x", block.NumberU64(), block.Hash(), hash)
+		}
+		if ReadBlock(cpdb, block.Hash(), block.NumberU64()) == nil {
+			t.Fatalf("Block %d missing from checkpoint", block.NumberU64())
+		}
+	}
+}
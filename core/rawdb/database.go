@@ -31,6 +31,7 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/ethdb/objectstore"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/olekukonko/tablewriter"
 )
@@ -51,6 +52,22 @@ func (frdb *freezerdb) AncientDatadir() (string, error) {
 	return frdb.ancientRoot, nil
 }
 
+// NewSnapshot implements ethdb.Snapshotter, creating a point-in-time view of the
+// key-value store if it supports snapshots. The chain freezer is not part of the
+// snapshot.
+func (frdb *freezerdb) NewSnapshot() (ethdb.Snapshot, error) {
+	return newSnapshot(frdb.KeyValueStore)
+}
+
+// newSnapshot creates a snapshot of the given key-value store, if supported.
+func newSnapshot(db ethdb.KeyValueStore) (ethdb.Snapshot, error) {
+	snapshotter, ok := db.(ethdb.Snapshotter)
+	if !ok {
+		return nil, errNotSupported
+	}
+	return snapshotter.NewSnapshot()
+}
+
 // Close implements io.Closer, closing both the fast key-value store as well as
 // the slow ancient tables.
 func (frdb *freezerdb) Close() error {
@@ -152,6 +169,12 @@ func (db *nofreezedb) AncientDatadir() (string, error) {
 	return "", errNotSupported
 }
 
+// NewSnapshot implements ethdb.Snapshotter, creating a point-in-time view of the
+// key-value store if it supports snapshots.
+func (db *nofreezedb) NewSnapshot() (ethdb.Snapshot, error) {
+	return newSnapshot(db.KeyValueStore)
+}
+
 // NewDatabase creates a high level database on top of a given key-value data
 // store without a freezer moving immutable chain segments into cold storage.
 func NewDatabase(db ethdb.KeyValueStore) ethdb.Database {
@@ -212,6 +235,16 @@ type OpenOptions struct {
 	Era              string // era files directory
 	MetricsNamespace string // prefix added to freezer metric names
 	ReadOnly         bool
+
+	// Secondary opens the database read-only next to the primary process owning
+	// it, the key-value store must support following the primary. The view of
+	// the database is refreshed through ethdb.Follower.
+	Secondary bool
+
+	// AncientRemote is the optional location of the object store to offload the
+	// sealed chain segments to, see objectstore.Open for the format.
+	AncientRemote      string
+	AncientRemoteCache int // cache allowance of the offloaded segments in megabytes
 }
 
 // Open creates a high-level database wrapper for the given key-value store.
@@ -223,7 +256,26 @@ func Open(db ethdb.KeyValueStore, opts OpenOptions) (ethdb.Database, error) {
 	if chainFreezerDir != "" {
 		chainFreezerDir = resolveChainFreezerDir(chainFreezerDir)
 	}
-	frdb, err := newChainFreezer(chainFreezerDir, opts.Era, opts.MetricsNamespace, opts.ReadOnly)
+	var remote *remoteStore
+	if opts.AncientRemote != "" {
+		if chainFreezerDir == "" {
+			return nil, errors.New("object store requires a file-based ancient store")
+		}
+		store, err := objectstore.Open(opts.AncientRemote)
+		if err != nil {
+			return nil, err
+		}
+		remote = newRemoteStore(store, opts.AncientRemoteCache*1024*1024)
+	}
+	if opts.Secondary {
+		if _, ok := db.(ethdb.Follower); !ok {
+			return nil, errors.New("key-value store doesn't support the secondary mode")
+		}
+		if chainFreezerDir == "" {
+			return nil, errors.New("secondary mode requires a file-based ancient store")
+		}
+	}
+	frdb, err := newChainFreezer(chainFreezerDir, opts.Era, opts.MetricsNamespace, opts.ReadOnly, opts.Secondary, remote)
 	if err != nil {
 		printChainMetadata(db)
 		return nil, err
@@ -312,7 +364,7 @@ func Open(db ethdb.KeyValueStore, opts OpenOptions) (ethdb.Database, error) {
 		}
 	}
 	// Freezer is consistent with the key-value database, permit combining the two
-	if !opts.ReadOnly {
+	if !opts.ReadOnly && !opts.Secondary {
 		frdb.wg.Add(1)
 		go func() {
 			frdb.freeze(db)
@@ -478,6 +530,8 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 			metadata.Add(size)
 		case bytes.HasPrefix(key, genesisPrefix) && len(key) == (len(genesisPrefix)+common.HashLength):
 			metadata.Add(size)
+		case bytes.HasPrefix(key, verkleTransitionPrefix) && len(key) == (len(verkleTransitionPrefix)+common.HashLength):
+			metadata.Add(size)
 		case bytes.HasPrefix(key, skeletonHeaderPrefix) && len(key) == (len(skeletonHeaderPrefix)+8):
 			beaconHeaders.Add(size)
 		case bytes.HasPrefix(key, CliqueSnapshotPrefix) && len(key) == 7+common.HashLength:
@@ -604,7 +658,7 @@ var knownMetadataKeys = [][]byte{
 	snapshotGeneratorKey, snapshotRecoveryKey, txIndexTailKey, fastTxLookupLimitKey,
 	uncleanShutdownKey, badBlockKey, transitionStatusKey, skeletonSyncStatusKey,
 	persistentStateIDKey, trieJournalKey, snapshotSyncStatusKey, snapSyncStatusFlagKey,
-	filterMapsRangeKey, headStateHistoryIndexKey,
+	filterMapsRangeKey, headStateHistoryIndexKey, migrationProgressKey,
 }
 
 // printChainMetadata prints out chain metadata to stderr.
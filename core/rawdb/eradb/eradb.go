@@ -14,19 +14,23 @@
 // You should have received a copy of the GNU Lesser General Public License
 // along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
 
-// Package eradb implements a history backend using era1 files.
+// Package eradb implements a history backend using era1 and erae files.
 package eradb
 
 import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
+	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 
 	"github.com/ethereum/go-ethereum/common/lru"
 	"github.com/ethereum/go-ethereum/internal/era"
+	"github.com/ethereum/go-ethereum/internal/era/execdb"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rlp"
 )
@@ -35,7 +39,18 @@ const openFileLimit = 64
 
 var errClosed = errors.New("era store is closed")
 
-// Store manages read access to a directory of era1 files.
+// archive is the common interface of the era1 and erae file readers.
+type archive interface {
+	GetRawBodyByNumber(num uint64) ([]byte, error)
+	GetRawReceiptsByNumber(num uint64) ([]byte, error)
+	Start() uint64
+	Count() uint64
+	Close() error
+}
+
+// Store manages read access to a directory of era1 and erae files. Pre-merge
+// epochs may be archived in either format, post-merge epochs only as erae.
+//
 // The getter methods are thread-safe.
 type Store struct {
 	datadir string
@@ -46,12 +61,21 @@ type Store struct {
 	lru     lru.BasicLRU[uint64, *fileCacheEntry]
 	opening map[uint64]*fileCacheEntry
 	closing bool
+
+	// The floor of the archived history is cached for the last requested limit,
+	// as resolving it needs to open all files of the chained epochs. The cache
+	// is keyed by the limit, so it's implicitly dropped when the history is
+	// pruned further, and it's invalidated explicitly when files are imported.
+	floorLimit uint64
+	floor      uint64
+	floorOK    bool
+	floorGen   uint64 // bumped upon every invalidation of the cached floor
 }
 
 type fileCacheEntry struct {
 	refcount int           // reference count. This is protected by Store.mu!
 	opened   chan struct{} // signals opening of file has completed
-	file     *era.Era      // the file
+	file     archive       // the file
 	err      error         // error from opening the file
 }
 
@@ -76,7 +100,7 @@ func New(datadir string) (*Store, error) {
 	return db, nil
 }
 
-// Close closes all open era1 files in the cache.
+// Close closes all open era files in the cache.
 func (db *Store) Close() {
 	db.mu.Lock()
 	defer db.mu.Unlock()
@@ -135,7 +159,7 @@ func (db *Store) GetRawReceipts(number uint64) ([]byte, error) {
 }
 
 // convertReceipts transforms an encoded block receipts list from the format
-// used by era1 into the 'storage' format used by the go-ethereum ancients database.
+// used by era1 and erae into the 'storage' format used by the go-ethereum ancients database.
 func convertReceipts(input []byte) ([]byte, error) {
 	var (
 		out bytes.Buffer
@@ -249,7 +273,7 @@ func (db *Store) getCacheEntry(epoch uint64) (stat fileCacheStatus, entry *fileC
 }
 
 // fileOpened is called after an era file has been successfully opened.
-func (db *Store) fileOpened(epoch uint64, entry *fileCacheEntry, file *era.Era) {
+func (db *Store) fileOpened(epoch uint64, entry *fileCacheEntry, file archive) {
 	db.mu.Lock()
 	defer db.mu.Unlock()
 
@@ -282,31 +306,166 @@ func (db *Store) fileFailedToOpen(epoch uint64, entry *fileCacheEntry, err error
 	entry.err = err
 }
 
-func (db *Store) openEraFile(epoch uint64) (*era.Era, error) {
-	// File name scheme is <network>-<epoch>-<root>.
-	glob := fmt.Sprintf("*-%05d-*.era1", epoch)
-	matches, err := filepath.Glob(filepath.Join(db.datadir, glob))
+func (db *Store) openEraFile(epoch uint64) (archive, error) {
+	// File name scheme is <network>-<epoch>-<root>, era1 files take precedence
+	// over erae ones if both are present for the same epoch.
+	for _, ext := range []string{"era1", "erae"} {
+		glob := fmt.Sprintf("*-%05d-*.%s", epoch, ext)
+		matches, err := filepath.Glob(filepath.Join(db.datadir, glob))
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) > 1 {
+			return nil, fmt.Errorf("multiple %s files found for epoch %d", ext, epoch)
+		}
+		if len(matches) == 0 {
+			continue
+		}
+		file, err := openArchive(matches[0])
+		if err != nil {
+			return nil, err
+		}
+		log.Debug("Opened era file", "epoch", epoch, "format", ext)
+		return file, nil
+	}
+	return nil, fs.ErrNotExist
+}
+
+// openArchive opens the era1 or erae file at the given path, depending on its
+// extension, and checks that it starts at an epoch boundary.
+func openArchive(path string) (archive, error) {
+	var (
+		file archive
+		err  error
+		ext  = strings.TrimPrefix(filepath.Ext(path), ".")
+		size = uint64(era.MaxEra1Size)
+	)
+	switch ext {
+	case "era1":
+		file, err = era.Open(path)
+	case "erae":
+		file, err = execdb.Open(path)
+		size = uint64(execdb.MaxEraESize)
+	default:
+		return nil, fmt.Errorf("unknown era file extension %q", ext)
+	}
 	if err != nil {
 		return nil, err
 	}
-	if len(matches) > 1 {
-		return nil, fmt.Errorf("multiple era1 files found for epoch %d", epoch)
+	// Sanity-check start block.
+	if file.Start()%size != 0 {
+		file.Close()
+		return nil, fmt.Errorf("%s file has invalid boundary. %d %% %d != 0", ext, file.Start(), size)
 	}
-	if len(matches) == 0 {
-		return nil, fs.ErrNotExist
+	return file, nil
+}
+
+// Import copies the given era1 or erae file into the store directory, making
+// the archived blocks available. The file is expected to be verified by the
+// caller. Importing a file of an epoch which is already archived is an error,
+// unless it's the very same file.
+func (db *Store) Import(path string) error {
+	file, err := openArchive(path)
+	if err != nil {
+		return err
 	}
-	filename := matches[0]
+	epoch := file.Start() / uint64(era.MaxEra1Size)
+	file.Close()
 
-	e, err := era.Open(filename)
+	name := filepath.Base(path)
+	for _, ext := range []string{"era1", "erae"} {
+		matches, err := filepath.Glob(filepath.Join(db.datadir, fmt.Sprintf("*-%05d-*.%s", epoch, ext)))
+		if err != nil {
+			return err
+		}
+		for _, match := range matches {
+			if filepath.Base(match) == name {
+				return nil
+			}
+			return fmt.Errorf("epoch %d is already archived in %s", epoch, filepath.Base(match))
+		}
+	}
+	src, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	// Sanity-check start block.
-	if e.Start()%uint64(era.MaxEra1Size) != 0 {
-		return nil, fmt.Errorf("pre-merge era1 file has invalid boundary. %d %% %d != 0", e.Start(), era.MaxEra1Size)
+	defer src.Close()
+
+	if err := os.MkdirAll(db.datadir, 0755); err != nil {
+		return err
+	}
+	dst, err := os.CreateTemp(db.datadir, name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(dst.Name())
+
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := dst.Sync(); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(dst.Name(), filepath.Join(db.datadir, name)); err != nil {
+		return err
+	}
+	db.mu.Lock()
+	db.floorOK = false
+	db.floorGen++
+	db.mu.Unlock()
+
+	log.Info("Imported era file", "epoch", epoch, "file", name)
+	return nil
+}
+
+// Floor returns the first block number from which on all blocks until the
+// given limit (exclusive) are available in the store. The limit itself is
+// returned if the block right below it is not archived. The result is cached
+// until a different limit is requested or a file is added through Import.
+func (db *Store) Floor(limit uint64) uint64 {
+	db.mu.Lock()
+	if db.floorOK && db.floorLimit == limit {
+		db.mu.Unlock()
+		return db.floor
+	}
+	gen := db.floorGen
+	db.mu.Unlock()
+
+	floor := db.resolveFloor(limit)
+
+	db.mu.Lock()
+	if db.floorGen == gen {
+		db.floorLimit, db.floor, db.floorOK = limit, floor, true
+	}
+	db.mu.Unlock()
+	return floor
+}
+
+// resolveFloor walks the archived epochs backwards from the given limit to find
+// the first block of the continuously archived history below it.
+func (db *Store) resolveFloor(limit uint64) uint64 {
+	floor := limit
+	for floor > 0 {
+		epoch := (floor - 1) / uint64(era.MaxEra1Size)
+		entry := db.getEraByEpoch(epoch)
+		if entry.err != nil {
+			break
+		}
+		start, end := entry.file.Start(), entry.file.Start()+entry.file.Count()
+		db.doneWithFile(epoch, entry)
+
+		// Epochs are only chained if the archive reaches up to the floor.
+		if end < floor || start >= floor {
+			break
+		}
+		floor = start
 	}
-	log.Debug("Opened era1 file", "epoch", epoch)
-	return e, nil
+	return floor
 }
 
 // doneWithFile signals that the caller has finished using a file.
@@ -337,9 +496,9 @@ func (entry *fileCacheEntry) derefAndClose(epoch uint64) (closed bool) {
 
 	closeErr := entry.file.Close()
 	if closeErr == nil {
-		log.Debug("Closed era1 file", "epoch", epoch)
+		log.Debug("Closed era file", "epoch", epoch)
 	} else {
-		log.Warn("Error closing era1 file", "epoch", epoch, "err", closeErr)
+		log.Warn("Error closing era file", "epoch", epoch, "err", closeErr)
 	}
 	return true
 }
@@ -101,3 +101,45 @@ func TestEraDatabaseConcurrentOpenClose(t *testing.T) {
 	}()
 	wg.Wait()
 }
+
+func TestEraDatabaseFloor(t *testing.T) {
+	db, err := New("testdata")
+	require.NoError(t, err)
+	defer db.Close()
+
+	for _, c := range []struct {
+		limit uint64
+		floor uint64
+	}{
+		{limit: 0, floor: 0},
+		{limit: 100, floor: 0},
+		{limit: 8192, floor: 0},
+		{limit: 8193, floor: 8193},             // epoch 1 is not archived
+		{limit: 21*8192 + 5, floor: 21 * 8192}, // epoch 20 is not archived
+		{limit: 22 * 8192, floor: 21 * 8192},
+	} {
+		assert.Equal(t, c.floor, db.Floor(c.limit), "limit %d", c.limit)
+	}
+}
+
+func TestEraDatabaseImport(t *testing.T) {
+	db, err := New(t.TempDir())
+	require.NoError(t, err)
+	defer db.Close()
+
+	// The floor of the empty store is cached and must be refreshed on import.
+	assert.Equal(t, uint64(22*8192), db.Floor(22*8192))
+	require.NoError(t, db.Import("testdata/sepolia-00021-b8814b14.era1"))
+	assert.Equal(t, uint64(21*8192), db.Floor(22*8192))
+
+	// Importing the same file again is a noop.
+	require.NoError(t, db.Import("testdata/sepolia-00021-b8814b14.era1"))
+	assert.Equal(t, uint64(21*8192), db.Floor(22*8192))
+
+	require.NoError(t, db.Import("testdata/sepolia-00000-643a00f7.era1"))
+	assert.Equal(t, uint64(0), db.Floor(8192))
+
+	r, err := db.GetRawBody(175881)
+	require.NoError(t, err)
+	assert.NotEmpty(t, r)
+}
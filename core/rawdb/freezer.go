@@ -68,9 +68,14 @@ type Freezer struct {
 	writeBatch *freezerBatch
 
 	readonly     bool
+	secondary    bool                     // Whether the freezer is written concurrently by another process
 	tables       map[string]*freezerTable // Data tables for storing everything
 	instanceLock *flock.Flock             // File-system lock to prevent double opens
 	closeOnce    sync.Once
+
+	remote      *remoteStore   // Object store of the offloaded data files, nil if disabled
+	offloadQuit chan struct{}  // Channel to terminate the offloading loop
+	offloadWg   sync.WaitGroup // Tracks the offloading loop
 }
 
 // NewFreezer creates a freezer instance for maintaining immutable ordered
@@ -79,6 +84,17 @@ type Freezer struct {
 // The 'tables' argument defines the data tables. If the value of a map
 // entry is true, snappy compression is disabled for the table.
 func NewFreezer(datadir string, namespace string, readonly bool, maxTableSize uint32, tables map[string]freezerTableConfig) (*Freezer, error) {
+	return newFreezer(datadir, namespace, readonly, false, maxTableSize, tables, nil)
+}
+
+// newFreezer creates a freezer instance like NewFreezer, with the sealed data
+// files of the tables optionally offloaded to the given object store.
+//
+// If secondary is set, the freezer is opened read-only next to the primary
+// process owning it, which keeps appending items concurrently. The instance
+// lock held by the primary is skipped, and the view of the tables has to be
+// refreshed explicitly to follow the primary.
+func newFreezer(datadir string, namespace string, readonly bool, secondary bool, maxTableSize uint32, tables map[string]freezerTableConfig, remote *remoteStore) (*Freezer, error) {
 	// Create the initial freezer object
 	var (
 		readMeter  = metrics.NewRegisteredMeter(namespace+"ancient/read", nil)
@@ -101,28 +117,33 @@ func NewFreezer(datadir string, namespace string, readonly bool, maxTableSize ui
 		return nil, err
 	}
 	// Leveldb uses LOCK as the filelock filename. To prevent the
-	// name collision, we use FLOCK as the lock name.
+	// name collision, we use FLOCK as the lock name. The secondary
+	// freezer doesn't take the lock, as it's held by the primary.
 	lock := flock.New(flockFile)
-	tryLock := lock.TryLock
-	if readonly {
-		tryLock = lock.TryRLock
-	}
-	if locked, err := tryLock(); err != nil {
-		return nil, err
-	} else if !locked {
-		return nil, errors.New("locking failed")
+	if !secondary {
+		tryLock := lock.TryLock
+		if readonly {
+			tryLock = lock.TryRLock
+		}
+		if locked, err := tryLock(); err != nil {
+			return nil, err
+		} else if !locked {
+			return nil, errors.New("locking failed")
+		}
 	}
 	// Open all the supported data tables
 	freezer := &Freezer{
 		datadir:      datadir,
-		readonly:     readonly,
+		readonly:     readonly || secondary,
+		secondary:    secondary,
 		tables:       make(map[string]*freezerTable),
 		instanceLock: lock,
+		remote:       remote,
 	}
 
 	// Create the tables.
 	for name, config := range tables {
-		table, err := newTable(datadir, name, readMeter, writeMeter, sizeGauge, maxTableSize, config, readonly)
+		table, err := openTable(datadir, name, readMeter, writeMeter, sizeGauge, maxTableSize, config, readonly, secondary, remote)
 		if err != nil {
 			for _, table := range freezer.tables {
 				table.Close()
@@ -133,7 +154,11 @@ func NewFreezer(datadir string, namespace string, readonly bool, maxTableSize ui
 		freezer.tables[name] = table
 	}
 	var err error
-	if freezer.readonly {
+	if freezer.secondary {
+		// The tables of the secondary might be in the middle of being
+		// appended, only expose the items present in all of them.
+		err = freezer.refreshHead()
+	} else if freezer.readonly {
 		// In readonly mode only validate, don't truncate.
 		// validate also sets `freezer.frozen`.
 		err = freezer.validate()
@@ -152,12 +177,27 @@ func NewFreezer(datadir string, namespace string, readonly bool, maxTableSize ui
 	// Create the write batch.
 	freezer.writeBatch = newFreezerBatch(freezer)
 
-	log.Info("Opened ancient database", "database", datadir, "readonly", readonly)
+	// Start offloading the sealed data files if the object store is configured
+	if remote != nil && !freezer.readonly {
+		freezer.offloadQuit = make(chan struct{})
+		freezer.offloadWg.Add(1)
+		go freezer.offloadLoop()
+	}
+
+	log.Info("Opened ancient database", "database", datadir, "readonly", freezer.readonly, "secondary", secondary)
 	return freezer, nil
 }
 
 // Close terminates the chain freezer, closing all the data files.
 func (f *Freezer) Close() error {
+	if f.offloadQuit != nil {
+		select {
+		case <-f.offloadQuit:
+		default:
+			close(f.offloadQuit)
+		}
+		f.offloadWg.Wait()
+	}
 	f.writeLock.Lock()
 	defer f.writeLock.Unlock()
 
@@ -92,7 +92,7 @@ func (batch *freezerBatch) commit() (item uint64, writeSize int64, err error) {
 type freezerTableBatch struct {
 	t *freezerTable
 
-	sb          *snappyBuffer
+	compressor  itemCompressor
 	encBuffer   writeBuffer
 	dataBuffer  []byte
 	indexBuffer []byte
@@ -104,7 +104,7 @@ type freezerTableBatch struct {
 func (t *freezerTable) newBatch() *freezerTableBatch {
 	batch := &freezerTableBatch{t: t}
 	if !t.config.noSnappy {
-		batch.sb = new(snappyBuffer)
+		batch.compressor = t.codec.compressor(t.metadata.compression)
 	}
 	batch.reset()
 	return batch
@@ -132,8 +132,8 @@ func (batch *freezerTableBatch) Append(item uint64, data interface{}) error {
 		return err
 	}
 	encItem := batch.encBuffer.data
-	if batch.sb != nil {
-		encItem = batch.sb.compress(encItem)
+	if batch.compressor != nil {
+		encItem = batch.compressor.compress(encItem)
 	}
 	return batch.appendItem(encItem)
 }
@@ -147,8 +147,8 @@ func (batch *freezerTableBatch) AppendRaw(item uint64, blob []byte) error {
 	}
 
 	encItem := blob
-	if batch.sb != nil {
-		encItem = batch.sb.compress(blob)
+	if batch.compressor != nil {
+		encItem = batch.compressor.compress(blob)
 	}
 	return batch.appendItem(encItem)
 }
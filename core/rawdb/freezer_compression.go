@@ -0,0 +1,293 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// freezerCompression is the algorithm used for compressing the items of a
+// freezer table. It's recorded in the table metadata and applies to the newly
+// appended items. The format of the stored items is detected upon reading, so
+// that a table can hold the items of both formats while being recompressed.
+type freezerCompression uint8
+
+const (
+	compressionSnappy freezerCompression = iota // Snappy block format, the legacy default
+	compressionZstd                             // Zstd frames, using the table dictionary if trained
+)
+
+// String implements fmt.Stringer.
+func (c freezerCompression) String() string {
+	switch c {
+	case compressionSnappy:
+		return "snappy"
+	case compressionZstd:
+		return "zstd"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(c))
+	}
+}
+
+// parseCompression resolves the compression algorithm from its name.
+func parseCompression(name string) (freezerCompression, error) {
+	switch name {
+	case "snappy":
+		return compressionSnappy, nil
+	case "zstd":
+		return compressionZstd, nil
+	default:
+		return 0, fmt.Errorf("unknown compression %q, supported ones: snappy, zstd", name)
+	}
+}
+
+const (
+	// dictionarySize is the maximum size of the zstd dictionaries trained for
+	// the freezer tables.
+	dictionarySize = 64 * 1024
+
+	// dictionarySamples is the maximum number of items sampled from a table for
+	// training its dictionary.
+	dictionarySamples = 8192
+
+	// dictionarySampleBytes caps the total size of the samples used for training.
+	dictionarySampleBytes = 8 * 1024 * 1024
+)
+
+// zstdMagic is the magic number starting every zstd frame. A snappy block can
+// never start with it: the single byte length prefix 0x28 would be followed by
+// a copy tag, which is invalid at the beginning of the block.
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+// itemCompressor compresses the items of a table, reusing its output buffer.
+type itemCompressor interface {
+	compress(data []byte) []byte
+}
+
+// zstdBuffer writes zstd frames, and can be reused like snappyBuffer.
+type zstdBuffer struct {
+	encoder *zstd.Encoder
+	dst     []byte
+}
+
+// compress zstd-compresses the data.
+func (z *zstdBuffer) compress(data []byte) []byte {
+	z.dst = z.encoder.EncodeAll(data, z.dst[:0])
+	return z.dst
+}
+
+// itemCodec compresses and decompresses the items of a compressed freezer
+// table, with the optional zstd dictionary of the table.
+type itemCodec struct {
+	dict    []byte        // Zstd dictionary of the table, nil if not trained
+	dictId  uint32        // Identifier of the dictionary, derived from its content
+	encoder *zstd.Encoder // Zstd encoder, only created if zstd is the table compression
+
+	decoder    *zstd.Decoder // Zstd decoder, created upon reading the first zstd item
+	decoderErr error
+	decodeOnce sync.Once
+}
+
+// newItemCodec creates the codec of a table using the given compression for the
+// newly appended items.
+func newItemCodec(dict []byte, compression freezerCompression) (*itemCodec, error) {
+	c := &itemCodec{dict: dict}
+	if dict != nil {
+		c.dictId = dictionaryId(dict)
+	}
+	if compression == compressionZstd {
+		opts := []zstd.EOption{zstd.WithEncoderConcurrency(1), zstd.WithZeroFrames(true)}
+		if dict != nil {
+			opts = append(opts, zstd.WithEncoderDictRaw(c.dictId, dict))
+		}
+		encoder, err := zstd.NewWriter(nil, opts...)
+		if err != nil {
+			return nil, err
+		}
+		c.encoder = encoder
+	}
+	return c, nil
+}
+
+// dictionaryId derives the identifier of the dictionary from its content. The
+// identifiers below 32768 and above 2^31 are reserved by the zstd format.
+func dictionaryId(dict []byte) uint32 {
+	const low, high = 1 << 15, 1 << 31
+	return low + binary.BigEndian.Uint32(crypto.Keccak256(dict)[:4])%(high-low)
+}
+
+// compressor returns a compressor of the items with the given algorithm.
+func (c *itemCodec) compressor(compression freezerCompression) itemCompressor {
+	if compression == compressionZstd {
+		return &zstdBuffer{encoder: c.encoder}
+	}
+	return new(snappyBuffer)
+}
+
+// zstdDecoder returns the zstd decoder, creating it if not yet done.
+func (c *itemCodec) zstdDecoder() (*zstd.Decoder, error) {
+	c.decodeOnce.Do(func() {
+		opts := []zstd.DOption{zstd.WithDecoderLowmem(true)}
+		if c.dict != nil {
+			opts = append(opts, zstd.WithDecoderDictRaw(c.dictId, c.dict))
+		}
+		c.decoder, c.decoderErr = zstd.NewReader(nil, opts...)
+	})
+	return c.decoder, c.decoderErr
+}
+
+// isZstd reports whether the stored item is a zstd frame.
+func isZstd(item []byte) bool {
+	return bytes.HasPrefix(item, zstdMagic)
+}
+
+// decode decompresses the stored item, in whichever format it's stored.
+func (c *itemCodec) decode(item []byte) ([]byte, error) {
+	if !isZstd(item) {
+		return snappy.Decode(nil, item)
+	}
+	decoder, err := c.zstdDecoder()
+	if err != nil {
+		return nil, err
+	}
+	return decoder.DecodeAll(item, nil)
+}
+
+// decodedLen returns the length of the decompressed item, or zero if it's not
+// available.
+func (c *itemCodec) decodedLen(item []byte) int {
+	if !isZstd(item) {
+		n, _ := snappy.DecodedLen(item)
+		return n
+	}
+	var header zstd.Header
+	if err := header.Decode(item); err != nil || !header.HasFCS {
+		return 0
+	}
+	return int(header.FrameContentSize)
+}
+
+// close releases the resources held by the zstd encoder and decoder.
+func (c *itemCodec) close() {
+	if c.encoder != nil {
+		c.encoder.Close()
+	}
+	if c.decoder != nil {
+		c.decoder.Close()
+	}
+}
+
+// dictionaryName returns the path of the zstd dictionary of the table.
+func (t *freezerTable) dictionaryName() string {
+	return filepath.Join(t.path, fmt.Sprintf("%s.dict", t.name))
+}
+
+// loadDictionary reads the zstd dictionary of the table, nil is returned if
+// the dictionary is not trained.
+func (t *freezerTable) loadDictionary() ([]byte, error) {
+	dict, err := os.ReadFile(t.dictionaryName())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return dict, err
+}
+
+// trainDictionary builds a raw zstd dictionary of at most the given size from
+// the samples. It's a simplified version of the COVER algorithm of the reference
+// implementation: the samples are split into epochs, and from each epoch the
+// segment containing the most frequent, not yet covered, substrings is selected.
+// The most valuable segments are placed at the end of the dictionary, as they
+// are cheaper to reference from there.
+func trainDictionary(samples [][]byte, size int) []byte {
+	const (
+		dmerSize    = 8   // Length of the substrings whose frequencies are counted
+		segmentSize = 256 // Length of the segments selected into the dictionary
+		tableBits   = 20  // Log2 size of the frequency table
+	)
+	var content []byte
+	for _, sample := range samples {
+		content = append(content, sample...)
+	}
+	if len(content) <= size {
+		return content
+	}
+	// Count the frequencies of all substrings, hashed into a fixed size table
+	hash := func(pos int) uint64 {
+		return (binary.LittleEndian.Uint64(content[pos:]) * 0x9e3779b97f4a7c15) >> (64 - tableBits)
+	}
+	freqs := make([]uint64, 1<<tableBits)
+	for pos := 0; pos+dmerSize <= len(content); pos++ {
+		freqs[hash(pos)]++
+	}
+	// Select the best segment of each epoch
+	type segment struct {
+		start int
+		score uint64
+	}
+	var (
+		epochs   = max(size/segmentSize, 1)
+		epochLen = len(content) / epochs
+		selected []segment
+	)
+	for epoch := 0; epoch < epochs; epoch++ {
+		begin, end := epoch*epochLen, (epoch+1)*epochLen
+		if end-begin < segmentSize {
+			continue
+		}
+		var score uint64
+		for pos := begin; pos <= begin+segmentSize-dmerSize; pos++ {
+			score += freqs[hash(pos)]
+		}
+		best := segment{start: begin, score: score}
+		for start := begin + 1; start+segmentSize <= end; start++ {
+			score -= freqs[hash(start-1)]
+			score += freqs[hash(start+segmentSize-dmerSize)]
+			if score > best.score {
+				best = segment{start: start, score: score}
+			}
+		}
+		if best.score == 0 {
+			continue
+		}
+		selected = append(selected, best)
+
+		// Reset the frequencies of the covered substrings, so that they are not
+		// selected again in the subsequent epochs
+		for pos := best.start; pos <= best.start+segmentSize-dmerSize; pos++ {
+			freqs[hash(pos)] = 0
+		}
+	}
+	sort.SliceStable(selected, func(i, j int) bool {
+		return selected[i].score < selected[j].score
+	})
+	dict := make([]byte, 0, len(selected)*segmentSize)
+	for _, s := range selected {
+		dict = append(dict, content[s.start:s.start+segmentSize]...)
+	}
+	return dict
+}
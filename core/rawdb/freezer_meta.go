@@ -29,7 +29,8 @@ import (
 const (
 	freezerTableV1 = 1              // Initial version of metadata struct
 	freezerTableV2 = 2              // Add field: 'flushOffset'
-	freezerVersion = freezerTableV2 // The current used version
+	freezerTableV3 = 3              // Add field: 'compression'
+	freezerVersion = freezerTableV2 // The current used version, unless zstd compression is selected
 )
 
 // freezerTableMeta is a collection of additional properties that describe the
@@ -65,6 +66,12 @@ type freezerTableMeta struct {
 	// The offset could be moved forward by applying sync operation, or be moved
 	// backward in cases of head/tail truncation, etc.
 	flushOffset int64
+
+	// compression is the algorithm used for compressing the newly appended
+	// items, it's meaningless for the tables with compression disabled. The
+	// items already stored are decoded regardless of it, allowing the table
+	// to be recompressed gradually.
+	compression freezerCompression
 }
 
 // decodeV1 attempts to decode the metadata structure in v1 format. If fails or
@@ -123,6 +130,43 @@ func decodeV2(file *os.File) *freezerTableMeta {
 	}
 }
 
+// decodeV3 attempts to decode the metadata structure in v3 format. If fails or
+// the result is incompatible, nil is returned.
+func decodeV3(file *os.File) *freezerTableMeta {
+	_, err := file.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil
+	}
+	type obj struct {
+		Version     uint16
+		Tail        uint64
+		Offset      uint64
+		Compression uint8
+	}
+	var o obj
+	if err := rlp.Decode(file, &o); err != nil {
+		return nil
+	}
+	if o.Version != freezerTableV3 {
+		return nil
+	}
+	if o.Offset > math.MaxInt64 {
+		log.Error("Invalid flushOffset in freezer metadata", "offset", o.Offset, "file", file.Name())
+		return nil
+	}
+	if o.Compression > uint8(compressionZstd) {
+		log.Error("Invalid compression in freezer metadata", "compression", o.Compression, "file", file.Name())
+		return nil
+	}
+	return &freezerTableMeta{
+		file:        file,
+		version:     freezerTableV3,
+		virtualTail: o.Tail,
+		flushOffset: int64(o.Offset),
+		compression: freezerCompression(o.Compression),
+	}
+}
+
 // newMetadata initializes the metadata object, either by loading it from the file
 // or by constructing a new one from scratch.
 func newMetadata(file *os.File) (*freezerTableMeta, error) {
@@ -133,7 +177,7 @@ func newMetadata(file *os.File) (*freezerTableMeta, error) {
 	if stat.Size() == 0 {
 		m := &freezerTableMeta{
 			file:        file,
-			version:     freezerTableV2,
+			version:     freezerVersion,
 			virtualTail: 0,
 			flushOffset: 0,
 		}
@@ -142,6 +186,9 @@ func newMetadata(file *os.File) (*freezerTableMeta, error) {
 		}
 		return m, nil
 	}
+	if m := decodeV3(file); m != nil {
+		return m, nil
+	}
 	if m := decodeV2(file); m != nil {
 		return m, nil
 	}
@@ -163,23 +210,49 @@ func (m *freezerTableMeta) setFlushOffset(offset int64, sync bool) error {
 	return m.write(sync)
 }
 
+// setCompression sets the compression algorithm and flushes the metadata if
+// sync is true.
+func (m *freezerTableMeta) setCompression(compression freezerCompression, sync bool) error {
+	m.compression = compression
+	return m.write(sync)
+}
+
 // write flushes the content of metadata into file and performs a fsync if required.
+//
+// The v3 format is only used by the tables with zstd compression selected, the
+// others are kept readable by the releases predating the compression field.
 func (m *freezerTableMeta) write(sync bool) error {
-	type obj struct {
+	type objV2 struct {
 		Version uint16
 		Tail    uint64
 		Offset  uint64
 	}
-	var o obj
-	o.Version = freezerVersion // forcibly use the current version
-	o.Tail = m.virtualTail
-	o.Offset = uint64(m.flushOffset)
-
+	type objV3 struct {
+		Version     uint16
+		Tail        uint64
+		Offset      uint64
+		Compression uint8
+	}
+	var o any
+	if m.compression == compressionZstd {
+		o = &objV3{
+			Version:     freezerTableV3,
+			Tail:        m.virtualTail,
+			Offset:      uint64(m.flushOffset),
+			Compression: uint8(m.compression),
+		}
+	} else {
+		o = &objV2{
+			Version: freezerVersion, // forcibly use the current version
+			Tail:    m.virtualTail,
+			Offset:  uint64(m.flushOffset),
+		}
+	}
 	_, err := m.file.Seek(0, io.SeekStart)
 	if err != nil {
 		return err
 	}
-	if err := rlp.Encode(m.file, &o); err != nil {
+	if err := rlp.Encode(m.file, o); err != nil {
 		return err
 	}
 	if !sync {
@@ -46,6 +46,31 @@ func TestReadWriteFreezerTableMeta(t *testing.T) {
 	if meta.virtualTail != uint64(100) {
 		t.Fatalf("Unexpected virtual tail field")
 	}
+	meta.setCompression(compressionZstd, false)
+
+	meta, err = newMetadata(f)
+	if err != nil {
+		t.Fatalf("Failed to reload metadata %v", err)
+	}
+	if meta.version != freezerTableV3 {
+		t.Fatalf("Unexpected version field")
+	}
+	if meta.compression != compressionZstd {
+		t.Fatalf("Unexpected compression field")
+	}
+	// Switching back to snappy restores the format readable by older releases
+	meta.setCompression(compressionSnappy, false)
+
+	meta, err = newMetadata(f)
+	if err != nil {
+		t.Fatalf("Failed to reload metadata %v", err)
+	}
+	if meta.version != freezerTableV2 {
+		t.Fatalf("Unexpected version field")
+	}
+	if meta.virtualTail != uint64(100) || meta.compression != compressionSnappy {
+		t.Fatalf("Unexpected metadata fields")
+	}
 }
 
 func TestUpgradeMetadata(t *testing.T) {
@@ -98,6 +123,9 @@ func TestUpgradeMetadata(t *testing.T) {
 	if meta.flushOffset != 100 {
 		t.Fatal("Unexpected flush offset field")
 	}
+	if meta.compression != compressionSnappy {
+		t.Fatal("Unexpected compression field")
+	}
 }
 
 func TestInvalidMetadata(t *testing.T) {
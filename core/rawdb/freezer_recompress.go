@@ -0,0 +1,368 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// recompressJournal records the rewrite of a data file in progress. The file is
+// rewritten into a temporary file first, and the journal is persisted before the
+// temporary file replaces the original one and the index entries of its items
+// are updated, so that an interrupted rewrite can be completed upon the next
+// opening of the table.
+type recompressJournal struct {
+	File    uint32   // Number of the rewritten data file
+	Start   uint64   // Position of the first index entry pointing into the file
+	Offsets []uint32 // End offsets of the items in the rewritten file
+}
+
+// journalName returns the path of the recompression journal of the table.
+func (t *freezerTable) journalName() string {
+	return filepath.Join(t.path, fmt.Sprintf("%s.recompress", t.name))
+}
+
+// rewrittenName returns the path of the temporary file the data file with the
+// given number is rewritten into.
+func (t *freezerTable) rewrittenName(num uint32) string {
+	return filepath.Join(t.path, t.fileName(num)+".tmp")
+}
+
+// recoverRecompression completes the interrupted rewrite of a data file, if
+// there is any. It's meant to be called before the table is repaired.
+func (t *freezerTable) recoverRecompression() error {
+	blob, err := os.ReadFile(t.journalName())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if t.readonly {
+		return fmt.Errorf("interrupted recompression of table %s, open it in read-write mode to recover", t.name)
+	}
+	var journal recompressJournal
+	if err := rlp.DecodeBytes(blob, &journal); err != nil {
+		return fmt.Errorf("invalid recompression journal of table %s: %v", t.name, err)
+	}
+	t.logger.Warn("Completing interrupted recompression", "file", t.fileName(journal.File))
+	return t.applyRecompression(&journal)
+}
+
+// applyRecompression replaces the data file with the rewritten one and points
+// the index entries of its items to their new location. It can be applied
+// repeatedly, as the replacement is skipped if already done.
+func (t *freezerTable) applyRecompression(journal *recompressJournal) error {
+	var (
+		tmp  = t.rewrittenName(journal.File)
+		path = filepath.Join(t.path, t.fileName(journal.File))
+	)
+	if _, err := os.Stat(tmp); err == nil {
+		t.releaseFile(journal.File)
+		if err := os.Rename(tmp, path); err != nil {
+			return err
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	buf := make([]byte, 0, len(journal.Offsets)*indexEntrySize)
+	for _, offset := range journal.Offsets {
+		entry := indexEntry{filenum: journal.File, offset: offset}
+		buf = entry.append(buf)
+	}
+	if _, err := t.index.WriteAt(buf, int64(journal.Start*indexEntrySize)); err != nil {
+		return err
+	}
+	if err := t.index.Sync(); err != nil {
+		return err
+	}
+	if err := os.Remove(t.journalName()); err != nil {
+		return err
+	}
+	// Reopen the rewritten file if the table is already in use
+	if t.head == nil {
+		return nil
+	}
+	if journal.File != t.headId {
+		_, err := t.openFile(journal.File, openFreezerFileForReadOnly)
+		return err
+	}
+	head, err := t.openFile(journal.File, openFreezerFileForAppend)
+	if err != nil {
+		return err
+	}
+	stat, err := head.Stat()
+	if err != nil {
+		return err
+	}
+	t.head, t.headBytes = head, stat.Size()
+	return nil
+}
+
+// fileEntries returns the range of the index entries pointing into the data file
+// with the given number. The first entry, holding the tail, is never included.
+func (t *freezerTable) fileEntries(num uint32) (uint64, uint64, error) {
+	stat, err := t.index.Stat()
+	if err != nil {
+		return 0, 0, err
+	}
+	var (
+		count = int(stat.Size()/indexEntrySize) - 1
+		buf   = make([]byte, indexEntrySize)
+	)
+	search := func(match func(filenum uint32) bool) uint64 {
+		return 1 + uint64(sort.Search(count, func(i int) bool {
+			if err != nil {
+				return true
+			}
+			if _, err = t.index.ReadAt(buf, int64(i+1)*indexEntrySize); err != nil {
+				return true
+			}
+			var entry indexEntry
+			entry.unmarshalBinary(buf)
+			return match(entry.filenum)
+		}))
+	}
+	first := search(func(filenum uint32) bool { return filenum >= num })
+	last := search(func(filenum uint32) bool { return filenum > num })
+	return first, last, err
+}
+
+// rewriteFile rewrites the items of the data file with the given number into a
+// temporary file, converting them into the given compression, and persists the
+// journal of the rewrite. Nil is returned if all the items are already stored
+// in the requested format.
+func (t *freezerTable) rewriteFile(num uint32, compression freezerCompression, compressor itemCompressor) (*recompressJournal, error) {
+	first, last, err := t.fileEntries(num)
+	if err != nil {
+		return nil, err
+	}
+	if first == last {
+		return nil, nil
+	}
+	src, exist := t.files[num]
+	if !exist {
+		return nil, fmt.Errorf("missing data file %d", num)
+	}
+	// Read the index entries of the items, the items are stored contiguously
+	// from the beginning of the file.
+	entries := make([]byte, (last-first)*indexEntrySize)
+	if _, err := t.index.ReadAt(entries, int64(first*indexEntrySize)); err != nil {
+		return nil, err
+	}
+	out, err := os.Create(t.rewrittenName(num))
+	if err != nil {
+		return nil, err
+	}
+	var done bool
+	defer func() {
+		if !done {
+			out.Close()
+			os.Remove(out.Name())
+		}
+	}()
+
+	var (
+		reader  = bufio.NewReaderSize(io.NewSectionReader(src, 0, math.MaxInt64), 1024*1024)
+		writer  = bufio.NewWriterSize(out, 1024*1024)
+		journal = &recompressJournal{File: num, Start: first}
+		item    []byte
+		start   uint32
+		written uint64
+		changed bool
+	)
+	for pos := 0; pos < len(entries); pos += indexEntrySize {
+		var entry indexEntry
+		entry.unmarshalBinary(entries[pos:])
+		if entry.offset < start {
+			return nil, fmt.Errorf("invalid index entry %d of file %d", first+uint64(pos/indexEntrySize), num)
+		}
+		item = grow(item[:0], int(entry.offset-start))
+		if _, err := io.ReadFull(reader, item); err != nil {
+			return nil, err
+		}
+		start = entry.offset
+
+		blob := item
+		if isZstd(item) != (compression == compressionZstd) {
+			data, err := t.codec.decode(item)
+			if err != nil {
+				return nil, err
+			}
+			blob, changed = compressor.compress(data), true
+		}
+		if _, err := writer.Write(blob); err != nil {
+			return nil, err
+		}
+		written += uint64(len(blob))
+		if written > math.MaxUint32 {
+			return nil, fmt.Errorf("rewritten data file %d exceeds the maximum size", num)
+		}
+		journal.Offsets = append(journal.Offsets, uint32(written))
+	}
+	if !changed {
+		return nil, nil
+	}
+	if err := writer.Flush(); err != nil {
+		return nil, err
+	}
+	if err := out.Sync(); err != nil {
+		return nil, err
+	}
+	if err := out.Close(); err != nil {
+		return nil, err
+	}
+	blob, err := rlp.EncodeToBytes(journal)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeFileAtomic(t.journalName(), bytes.NewReader(blob)); err != nil {
+		return nil, err
+	}
+	done = true
+	return journal, nil
+}
+
+// sampleItems retrieves the items evenly distributed across the table for the
+// dictionary training, truncating them to stay within the allowance.
+func (t *freezerTable) sampleItems() ([][]byte, error) {
+	var (
+		tail    = t.itemHidden.Load()
+		items   = t.items.Load()
+		count   = min(items-tail, dictionarySamples)
+		samples [][]byte
+	)
+	if count == 0 {
+		return nil, nil
+	}
+	step, limit := (items-tail)/count, dictionarySampleBytes/int(count)
+	for item := tail; item < items; item += step {
+		blob, err := t.Retrieve(item)
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, blob[:min(len(blob), limit)])
+	}
+	return samples, nil
+}
+
+// recompress rewrites the data files of the table, converting the stored items
+// into the given compression, which is also applied to the newly appended ones.
+// A zstd dictionary is trained from the table content when switching to zstd
+// for the first time. The table must not be used concurrently.
+func (t *freezerTable) recompress(compression freezerCompression) error {
+	if t.config.noSnappy {
+		return fmt.Errorf("table %s is not compressed", t.name)
+	}
+	if t.remote != nil {
+		return fmt.Errorf("table %s has data files offloaded to the object store", t.name)
+	}
+	dict := t.codec.dict
+	if compression == compressionZstd && dict == nil {
+		samples, err := t.sampleItems()
+		if err != nil {
+			return err
+		}
+		if dict = trainDictionary(samples, dictionarySize); len(dict) == 0 {
+			dict = nil
+		} else {
+			if err := writeFileAtomic(t.dictionaryName(), bytes.NewReader(dict)); err != nil {
+				return err
+			}
+			t.logger.Info("Trained compression dictionary", "samples", len(samples), "size", common.StorageSize(len(dict)))
+		}
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	codec, err := newItemCodec(dict, compression)
+	if err != nil {
+		return err
+	}
+	t.codec.close()
+	t.codec = codec
+
+	// Switch the compression of the newly appended items to zstd before storing
+	// any zstd item, the table becomes unreadable for the releases without zstd
+	// support from this point on. The switch to snappy is delayed until all the
+	// items are converted, the items stored in either format are readable during
+	// the conversion.
+	if compression == compressionZstd {
+		if err := t.metadata.setCompression(compression, true); err != nil {
+			return err
+		}
+	}
+	var (
+		start      = time.Now()
+		compressor = codec.compressor(compression)
+		before     int64
+		after      int64
+	)
+	for num := t.tailId; num <= t.headId; num++ {
+		stat, err := t.files[num].Stat()
+		if err != nil {
+			return err
+		}
+		journal, err := t.rewriteFile(num, compression, compressor)
+		if err != nil {
+			return err
+		}
+		before += stat.Size()
+		if journal == nil {
+			after += stat.Size()
+			continue
+		}
+		if err := t.applyRecompression(journal); err != nil {
+			return err
+		}
+		size := int64(journal.Offsets[len(journal.Offsets)-1])
+		after += size
+		t.logger.Info("Recompressed data file", "file", t.fileName(num), "items", len(journal.Offsets),
+			"size", common.StorageSize(stat.Size()), "recompressed", common.StorageSize(size))
+	}
+	if compression == compressionSnappy {
+		if err := t.metadata.setCompression(compression, true); err != nil {
+			return err
+		}
+	}
+	// The dictionary is not needed anymore once all the items are converted
+	// into snappy.
+	if compression == compressionSnappy && dict != nil {
+		if err := os.Remove(t.dictionaryName()); err != nil {
+			return err
+		}
+		if t.codec, err = newItemCodec(nil, compression); err != nil {
+			return err
+		}
+		codec.close()
+	}
+	t.logger.Info("Recompressed freezer table", "compression", compression,
+		"size", common.StorageSize(before), "recompressed", common.StorageSize(after), "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
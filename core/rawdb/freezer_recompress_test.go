@@ -0,0 +1,192 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// getRecord returns a compressible item, assembled from a few recurring
+// fragments like the receipts of the popular contracts.
+func getRecord(i int) []byte {
+	rng := rand.New(rand.NewSource(int64(i)))
+	fragments := rand.New(rand.NewSource(0))
+
+	var record []byte
+	for j := 0; j < 4; j++ {
+		fragment := make([]byte, 48)
+		fragments.Seed(int64(rng.Intn(16)))
+		fragments.Read(fragment)
+		record = append(record, fragment...)
+
+		noise := make([]byte, 8)
+		rng.Read(noise)
+		record = append(record, noise...)
+	}
+	return record
+}
+
+// openRecordTable opens the test table with small data files.
+func openRecordTable(t *testing.T, dir string, readonly bool) (*freezerTable, error) {
+	t.Helper()
+	return newTable(dir, "test", metrics.NewMeter(), metrics.NewMeter(), metrics.NewGauge(), 4096, freezerTableConfig{prunable: true}, readonly)
+}
+
+// writeRecords appends the records in the range to the table.
+func writeRecords(t *testing.T, f *freezerTable, from, to int) {
+	t.Helper()
+
+	batch := f.newBatch()
+	for i := from; i < to; i++ {
+		if err := batch.AppendRaw(uint64(i), getRecord(i)); err != nil {
+			t.Fatalf("Failed to append record %d: %v", i, err)
+		}
+	}
+	if err := batch.commit(); err != nil {
+		t.Fatalf("Failed to commit records: %v", err)
+	}
+}
+
+// checkRecords ensures the table contains the expected records in the range.
+func checkRecords(t *testing.T, f *freezerTable, from, to int) {
+	t.Helper()
+
+	items, err := f.RetrieveItems(uint64(from), uint64(to-from), 0)
+	if err != nil {
+		t.Fatalf("Failed to retrieve records: %v", err)
+	}
+	if len(items) != to-from {
+		t.Fatalf("Unexpected number of records, want %d, got %d", to-from, len(items))
+	}
+	for i, item := range items {
+		if !bytes.Equal(item, getRecord(from+i)) {
+			t.Fatalf("Record %d mismatch", from+i)
+		}
+	}
+}
+
+func TestFreezerRecompress(t *testing.T) {
+	dir := t.TempDir()
+	f, err := openRecordTable(t, dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeRecords(t, f, 0, 200)
+	if err := f.truncateTail(50); err != nil {
+		t.Fatal(err)
+	}
+	before, _ := f.size()
+
+	if err := f.recompress(compressionZstd); err != nil {
+		t.Fatalf("Failed to recompress table: %v", err)
+	}
+	if f.codec.dict == nil || len(f.codec.dict) > dictionarySize {
+		t.Fatalf("Unexpected dictionary size %d", len(f.codec.dict))
+	}
+	if after, _ := f.size(); after >= before {
+		t.Fatalf("Table not shrunk by recompression, before %d, after %d", before, after)
+	}
+	checkRecords(t, f, 50, 200)
+
+	// Reopen the table and extend it with zstd compressed records
+	f.Close()
+	if f, err = openRecordTable(t, dir, false); err != nil {
+		t.Fatal(err)
+	}
+	if f.metadata.compression != compressionZstd {
+		t.Fatalf("Unexpected compression %v", f.metadata.compression)
+	}
+	writeRecords(t, f, 200, 250)
+	item, _, err := f.retrieveItems(249, 1, 0)
+	if err != nil || !isZstd(item) {
+		t.Fatalf("Appended record not compressed with zstd: %v", err)
+	}
+	f.Close()
+	if f, err = openRecordTable(t, dir, false); err != nil {
+		t.Fatal(err)
+	}
+	checkRecords(t, f, 50, 250)
+
+	// Convert the records back to snappy, the dictionary should be dropped
+	if err := f.recompress(compressionSnappy); err != nil {
+		t.Fatalf("Failed to recompress table: %v", err)
+	}
+	if _, err := os.Stat(f.dictionaryName()); !os.IsNotExist(err) {
+		t.Fatalf("Dictionary not removed: %v", err)
+	}
+	f.Close()
+	if f, err = openRecordTable(t, dir, true); err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	checkRecords(t, f, 50, 250)
+	for i := 50; i < 250; i++ {
+		if item, _, _ := f.retrieveItems(uint64(i), 1, 0); isZstd(item) {
+			t.Fatalf("Record %d not converted to snappy", i)
+		}
+	}
+}
+
+func TestFreezerRecompressRecovery(t *testing.T) {
+	dir := t.TempDir()
+	f, err := openRecordTable(t, dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeRecords(t, f, 0, 100)
+
+	// Rewrite a data file, but crash before swapping it in
+	codec, err := newItemCodec(nil, compressionZstd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.codec = codec
+	journal, err := f.rewriteFile(1, compressionZstd, codec.compressor(compressionZstd))
+	if err != nil || journal == nil {
+		t.Fatalf("Failed to rewrite data file: %v", err)
+	}
+	f.Close()
+
+	if _, err := openRecordTable(t, dir, true); err == nil {
+		t.Fatal("Interrupted recompression ignored in read-only mode")
+	}
+	if f, err = openRecordTable(t, dir, false); err != nil {
+		t.Fatalf("Failed to recover table: %v", err)
+	}
+	defer f.Close()
+
+	if _, err := os.Stat(f.journalName()); !os.IsNotExist(err) {
+		t.Fatalf("Journal not removed: %v", err)
+	}
+	checkRecords(t, f, 0, 100)
+
+	first, last, err := f.fileEntries(1)
+	if err != nil || first == last {
+		t.Fatalf("Failed to locate the records of the data file: %v", err)
+	}
+	for i := first - 1; i < last-1; i++ {
+		if item, _, _ := f.retrieveItems(i, 1, 0); !isZstd(item) {
+			t.Fatalf("Record %d not converted to zstd", i)
+		}
+	}
+}
@@ -0,0 +1,309 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/ethdb/objectstore"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// remoteBlockSize is the granularity at which the offloaded data files are
+	// fetched from the object store and cached locally.
+	remoteBlockSize = 256 * 1024
+
+	// offloadInterval is the time interval between the attempts to offload the
+	// sealed data files of the freezer.
+	offloadInterval = time.Minute
+)
+
+// remoteStore is the object store holding the offloaded data files of a freezer,
+// along with the local cache of the recently accessed file blocks.
+type remoteStore struct {
+	store  objectstore.Store
+	cache  *lru.SizeConstrainedCache[remoteBlock, []byte]
+	fileId atomic.Uint64 // Unique identifier of the remote files, used as the cache key
+}
+
+// remoteBlock identifies a block of an offloaded data file.
+type remoteBlock struct {
+	file  uint64
+	index int64
+}
+
+// newRemoteStore creates the remote store with the given cache allowance in bytes.
+func newRemoteStore(store objectstore.Store, cache int) *remoteStore {
+	return &remoteStore{
+		store: store,
+		cache: lru.NewSizeConstrainedCache[remoteBlock, []byte](uint64(cache)),
+	}
+}
+
+// remoteFile is a data file of a freezer table stored in the object store.
+type remoteFile struct {
+	remote *remoteStore
+	key    string
+	id     uint64
+}
+
+// newFile creates a reader of the offloaded file with the given key.
+func (r *remoteStore) newFile(key string) *remoteFile {
+	return &remoteFile{remote: r, key: key, id: r.fileId.Add(1)}
+}
+
+// block retrieves the block with the given index, either from the cache or from
+// the object store. The last block of the file is shorter than remoteBlockSize.
+func (f *remoteFile) block(index int64) ([]byte, error) {
+	key := remoteBlock{file: f.id, index: index}
+	if blob, ok := f.remote.cache.Get(key); ok {
+		return blob, nil
+	}
+	blob := make([]byte, remoteBlockSize)
+	n, err := f.remote.store.ReadAt(f.key, blob, index*remoteBlockSize)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	blob = blob[:n]
+	f.remote.cache.Add(key, blob)
+	return blob, nil
+}
+
+// ReadAt implements io.ReaderAt.
+func (f *remoteFile) ReadAt(p []byte, off int64) (int, error) {
+	var n int
+	for n < len(p) {
+		pos := off + int64(n)
+		index := pos / remoteBlockSize
+		blob, err := f.block(index)
+		if err != nil {
+			return n, err
+		}
+		start := pos - index*remoteBlockSize
+		if start >= int64(len(blob)) {
+			return n, io.EOF
+		}
+		n += copy(p[n:], blob[start:])
+	}
+	return n, nil
+}
+
+// Close implements io.Closer, it's a noop as no resources are held.
+func (f *remoteFile) Close() error {
+	return nil
+}
+
+// remoteMarkerName returns the name of the file tracking the first data file
+// of the table which is still stored locally.
+func (t *freezerTable) remoteMarkerName() string {
+	return filepath.Join(t.path, fmt.Sprintf("%s.remote", t.name))
+}
+
+// loadLocalId reads the number of the first locally stored data file. All the
+// data files before it are offloaded to the object store.
+func (t *freezerTable) loadLocalId() error {
+	blob, err := os.ReadFile(t.remoteMarkerName())
+	if errors.Is(err, os.ErrNotExist) {
+		t.localId = 0
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(blob) != 4 {
+		return fmt.Errorf("invalid remote marker of table %s", t.name)
+	}
+	t.localId = binary.BigEndian.Uint32(blob)
+	return nil
+}
+
+// setLocalId persists the number of the first locally stored data file. This
+// function assumes the write lock is held.
+func (t *freezerTable) setLocalId(num uint32) error {
+	var blob [4]byte
+	binary.BigEndian.PutUint32(blob[:], num)
+
+	tmp := t.remoteMarkerName() + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(blob[:]); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, t.remoteMarkerName()); err != nil {
+		return err
+	}
+	t.localId = num
+	return nil
+}
+
+// isRemote reports whether the data file with the given number is offloaded.
+func (t *freezerTable) isRemote(num uint32) bool {
+	return t.remote != nil && num < t.localId
+}
+
+// deleteRemote removes the offloaded data file from the object store. Failures
+// are only logged, leaving an unreferenced object behind at worst.
+func (t *freezerTable) deleteRemote(num uint32) {
+	if err := t.remote.store.Delete(t.fileName(num)); err != nil {
+		t.logger.Warn("Failed to delete offloaded data file", "file", t.fileName(num), "err", err)
+	}
+}
+
+// ensureLocal downloads the data file with the given number from the object
+// store if it's offloaded, as it's about to be modified by a head truncation.
+// All the subsequent offloaded files are dropped. This function assumes the
+// write lock is held.
+func (t *freezerTable) ensureLocal(num uint32) error {
+	if !t.isRemote(num) {
+		return nil
+	}
+	var (
+		name = t.fileName(num)
+		path = filepath.Join(t.path, name)
+	)
+	size, err := t.remote.store.Size(name)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, io.NewSectionReader(t.remote.newFile(name), 0, size)); err != nil {
+		return err
+	}
+	if err := t.setLocalId(num); err != nil {
+		return err
+	}
+	for id := range t.remoteFiles {
+		if id >= num {
+			delete(t.remoteFiles, id)
+			t.deleteRemote(id)
+		}
+	}
+	t.rewinds++
+	t.logger.Info("Restored offloaded data file", "file", name, "size", size)
+	return nil
+}
+
+// offload uploads the sealed data files of the table into the object store and
+// removes the local copies, keeping the head file local. The upload is done
+// without holding the lock, the result is discarded if the file was modified
+// by a head truncation in the meantime.
+func (t *freezerTable) offload() error {
+	for {
+		t.lock.RLock()
+		var (
+			num     = max(t.localId, t.tailId)
+			head    = t.headId
+			rewinds = t.rewinds
+			closed  = t.index == nil
+		)
+		t.lock.RUnlock()
+
+		if closed {
+			return errClosed
+		}
+		if num >= head {
+			return nil
+		}
+		var (
+			start = time.Now()
+			name  = t.fileName(num)
+			path  = filepath.Join(t.path, name)
+		)
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		stat, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return err
+		}
+		err = t.remote.store.Put(name, f, stat.Size())
+		f.Close()
+		if err != nil {
+			return err
+		}
+		// Swap the local file with the uploaded one, unless it was modified
+		t.lock.Lock()
+		if t.index == nil || t.rewinds != rewinds || num >= t.headId || num < t.localId {
+			t.lock.Unlock()
+			continue
+		}
+		if num < t.tailId {
+			t.deleteRemote(num) // deleted by tail truncation in the meantime
+		} else {
+			if err := t.setLocalId(num + 1); err != nil {
+				t.lock.Unlock()
+				return err
+			}
+			t.releaseFile(num)
+			os.Remove(path)
+			t.remoteFiles[num] = t.remote.newFile(name)
+		}
+		t.lock.Unlock()
+
+		t.logger.Info("Offloaded data file", "file", name, "size", stat.Size(), "elapsed", time.Since(start))
+	}
+}
+
+// offload uploads the sealed data files of all the tables into the object store.
+func (f *Freezer) offload() error {
+	for _, table := range f.tables {
+		if err := table.offload(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// offloadLoop periodically offloads the sealed data files of the freezer until
+// the freezer is closed.
+func (f *Freezer) offloadLoop() {
+	defer f.offloadWg.Done()
+
+	ticker := time.NewTicker(offloadInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := f.offload(); err != nil && !errors.Is(err, errClosed) {
+			log.Warn("Failed to offload ancient data files", "err", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-f.offloadQuit:
+			return
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/objectstore"
+)
+
+// checkFreezerItems ensures the freezer contains the expected items in the range.
+func checkFreezerItems(t *testing.T, f *Freezer, from, to uint64) {
+	t.Helper()
+
+	for i := from; i < to; i++ {
+		blob, err := f.Ancient("test", i)
+		if err != nil {
+			t.Fatalf("Failed to retrieve item %d: %v", i, err)
+		}
+		if !bytes.Equal(blob, getChunk(256, int(i))) {
+			t.Fatalf("Item %d mismatch", i)
+		}
+	}
+	blobs, err := f.AncientRange("test", from, to-from, 0)
+	if err != nil {
+		t.Fatalf("Failed to retrieve items %d-%d: %v", from, to, err)
+	}
+	for i, blob := range blobs {
+		if !bytes.Equal(blob, getChunk(256, int(from)+i)) {
+			t.Fatalf("Ranged item %d mismatch", int(from)+i)
+		}
+	}
+}
+
+func TestFreezerOffload(t *testing.T) {
+	var (
+		dir    = t.TempDir()
+		store  = objectstore.NewMemory()
+		remote = newRemoteStore(store, 1024*1024)
+		tables = map[string]freezerTableConfig{"test": {noSnappy: true, prunable: true}}
+	)
+	f, err := newFreezer(dir, "", false, false, 2049, tables, remote)
+	if err != nil {
+		t.Fatalf("Failed to open freezer: %v", err)
+	}
+	// Fill 4 data files of 8 items each and the head file partially
+	_, err = f.ModifyAncients(func(op ethdb.AncientWriteOp) error {
+		for i := 0; i < 36; i++ {
+			if err := op.AppendRaw("test", uint64(i), getChunk(256, i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to write items: %v", err)
+	}
+	if err := f.offload(); err != nil {
+		t.Fatalf("Failed to offload data files: %v", err)
+	}
+	want := []string{"test.0000.rdat", "test.0001.rdat", "test.0002.rdat", "test.0003.rdat"}
+	if keys := store.Keys(); !slices.Equal(keys, want) {
+		t.Fatalf("Unexpected offloaded files, want %v, got %v", want, keys)
+	}
+	for _, name := range want {
+		if common.FileExist(filepath.Join(dir, name)) {
+			t.Fatalf("Offloaded file %s still stored locally", name)
+		}
+	}
+	if !common.FileExist(filepath.Join(dir, "test.0004.rdat")) {
+		t.Fatal("Head file is not stored locally")
+	}
+	checkFreezerItems(t, f, 0, 36)
+
+	// Truncate the tail, the offloaded file must be deleted
+	if _, err := f.TruncateTail(10); err != nil {
+		t.Fatalf("Failed to truncate tail: %v", err)
+	}
+	if keys := store.Keys(); !slices.Equal(keys, want[1:]) {
+		t.Fatalf("Unexpected offloaded files after tail truncation, want %v, got %v", want[1:], keys)
+	}
+	// Truncate the head into an offloaded file, it must be restored
+	if _, err := f.TruncateHead(20); err != nil {
+		t.Fatalf("Failed to truncate head: %v", err)
+	}
+	if keys := store.Keys(); !slices.Equal(keys, want[1:2]) {
+		t.Fatalf("Unexpected offloaded files after head truncation, want %v, got %v", want[1:2], keys)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "test.0002.rdat")); err != nil {
+		t.Fatalf("Truncated data file is not restored: %v", err)
+	}
+	checkFreezerItems(t, f, 10, 20)
+	f.Close()
+
+	// Reopen the freezer and ensure the offloaded files are still accessible
+	f, err = newFreezer(dir, "", false, false, 2049, tables, newRemoteStore(store, 1024*1024))
+	if err != nil {
+		t.Fatalf("Failed to reopen freezer: %v", err)
+	}
+	defer f.Close()
+	checkFreezerItems(t, f, 10, 20)
+}
@@ -0,0 +1,215 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// errNotSecondary is returned when attempting to refresh a database which is
+// not opened as a secondary.
+var errNotSecondary = errors.New("database is not opened as secondary")
+
+// dataSize returns the size of the data file with the given number. The files
+// offloaded to the object store are sealed, they're reported as unbounded.
+// This function assumes the write lock is held.
+func (t *freezerTable) dataSize(num uint32) (int64, error) {
+	if t.isRemote(num) {
+		return math.MaxInt64, nil
+	}
+	if f, exist := t.files[num]; exist {
+		stat, err := f.Stat()
+		if err != nil {
+			return 0, err
+		}
+		return stat.Size(), nil
+	}
+	stat, err := os.Stat(filepath.Join(t.path, t.fileName(num)))
+	if err != nil {
+		return 0, err
+	}
+	return stat.Size(), nil
+}
+
+// refresh reloads the state of the secondary table from its files, which are
+// written concurrently by the primary. Unlike repair, nothing is modified: the
+// items being appended by the primary are exposed once both their data and
+// index entries are written. The index and metadata files are reopened, as they
+// are replaced by the tail truncations.
+func (t *freezerTable) refresh() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.index == nil {
+		return errClosed
+	}
+	index, err := openFreezerFileForReadOnly(t.index.Name())
+	if err != nil {
+		return err
+	}
+	meta, err := openFreezerFileForReadOnly(t.metadata.file.Name())
+	if err != nil {
+		index.Close()
+		return err
+	}
+	metadata, err := newMetadata(meta)
+	if err != nil {
+		index.Close()
+		meta.Close()
+		return err
+	}
+	t.index.Close()
+	t.metadata.file.Close()
+	t.index, t.metadata = index, metadata
+
+	if t.remote != nil {
+		if err := t.loadLocalId(); err != nil {
+			return err
+		}
+	}
+	// Read the tail entry, and find the last entry whose data is fully written.
+	// An index entry being appended or a missing tail entry of a table not yet
+	// initialized are ignored.
+	stat, err := t.index.Stat()
+	if err != nil {
+		return err
+	}
+	var (
+		size   = stat.Size() - stat.Size()%indexEntrySize
+		buffer = make([]byte, indexEntrySize)
+		first  indexEntry
+	)
+	if size == 0 {
+		size = indexEntrySize
+	} else {
+		if _, err := t.index.ReadAt(buffer, 0); err != nil {
+			return err
+		}
+		first.unmarshalBinary(buffer)
+	}
+	last := indexEntry{filenum: first.filenum}
+	for ; size > indexEntrySize; size -= indexEntrySize {
+		if _, err := t.index.ReadAt(buffer, size-indexEntrySize); err != nil {
+			return err
+		}
+		var entry indexEntry
+		entry.unmarshalBinary(buffer)
+
+		stored, err := t.dataSize(entry.filenum)
+		if err != nil {
+			return err
+		}
+		if stored >= int64(entry.offset) {
+			last = entry
+			break
+		}
+	}
+	t.tailId = first.filenum
+	t.headId = last.filenum
+	t.headBytes = int64(last.offset)
+	t.itemOffset.Store(uint64(first.offset))
+	t.itemHidden.Store(max(uint64(first.offset), t.metadata.virtualTail))
+	t.items.Store(uint64(first.offset) + uint64(size/indexEntrySize-1))
+
+	// Release the files dropped by the primary, and open the new ones
+	t.releaseFilesBefore(t.tailId, false)
+	t.releaseFilesAfter(t.headId, false)
+	for num := t.tailId; num <= t.headId; num++ {
+		if _, exist := t.files[num]; exist {
+			continue
+		}
+		if t.isRemote(num) {
+			if _, exist := t.remoteFiles[num]; !exist {
+				t.remoteFiles[num] = t.remote.newFile(t.fileName(num))
+			}
+			continue
+		}
+		if _, err := t.openFile(num, openFreezerFileForReadOnly); err != nil {
+			return err
+		}
+	}
+	t.head = t.files[t.headId]
+	return nil
+}
+
+// refreshHead sets the number of frozen items and the tail of the secondary
+// freezer from its tables. The tables might differ in length while the primary
+// is appending items, only the items present in all of them are exposed.
+func (f *Freezer) refreshHead() error {
+	var (
+		frozen uint64 = math.MaxUint64
+		tail   uint64
+	)
+	for _, table := range f.tables {
+		frozen = min(frozen, table.items.Load())
+		if table.config.prunable {
+			tail = max(tail, table.itemHidden.Load())
+		}
+	}
+	if frozen == math.MaxUint64 {
+		frozen = 0
+	}
+	f.frozen.Store(frozen)
+	f.tail.Store(min(tail, frozen))
+	return nil
+}
+
+// refresh reloads the tables of the secondary freezer, following the items
+// appended and removed by the primary.
+func (f *Freezer) refresh() error {
+	if !f.secondary {
+		return errNotSecondary
+	}
+	f.writeLock.Lock()
+	defer f.writeLock.Unlock()
+
+	for _, table := range f.tables {
+		if err := table.refresh(); err != nil {
+			return err
+		}
+	}
+	return f.refreshHead()
+}
+
+// refresh reloads the secondary chain freezer.
+func (f *chainFreezer) refresh() error {
+	freezer, ok := f.ancients.(*Freezer)
+	if !ok {
+		return errNotSecondary
+	}
+	return freezer.refresh()
+}
+
+// CatchUp implements ethdb.Follower, refreshing the key-value store and then the
+// freezer. The chain segments are moved into the freezer before being deleted
+// from the key-value store, so refreshing the freezer last ensures no segment is
+// missing from the view in between.
+func (frdb *freezerdb) CatchUp() error {
+	follower, ok := frdb.KeyValueStore.(ethdb.Follower)
+	if !ok {
+		return errNotSecondary
+	}
+	if err := follower.CatchUp(); err != nil {
+		return err
+	}
+	return frdb.chainFreezer.refresh()
+}
@@ -32,7 +32,6 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
-	"github.com/golang/snappy"
 )
 
 var (
@@ -87,7 +86,7 @@ func (i *indexEntry) bounds(end *indexEntry) (startOffset, endOffset, fileId uin
 }
 
 // freezerTable represents a single chained data table within the freezer (e.g. blocks).
-// It consists of a data file (snappy encoded arbitrary data blobs) and an indexEntry
+// It consists of a data file (compressed arbitrary data blobs) and an indexEntry
 // file (uncompressed 64 bit indices into the data file).
 type freezerTable struct {
 	items      atomic.Uint64 // Number of items stored in the table (including items removed from tail)
@@ -100,8 +99,10 @@ type freezerTable struct {
 	// should never be lower than itemOffset.
 	itemHidden atomic.Uint64
 
-	config      freezerTableConfig // if true, disables snappy compression. Note: does not work retroactively
+	config      freezerTableConfig // if noSnappy is set, disables compression. Note: does not work retroactively
+	codec       *itemCodec         // codec of the compressed items, nil if compression is disabled
 	readonly    bool
+	secondary   bool   // if set, the table is written concurrently by another process
 	maxFileSize uint32 // Max file size for data-files
 	name        string
 	path        string
@@ -112,6 +113,11 @@ type freezerTable struct {
 	headId uint32              // number of the currently active head file
 	tailId uint32              // number of the earliest file
 
+	remote      *remoteStore           // Object store of the offloaded data files, nil if disabled
+	remoteFiles map[uint32]*remoteFile // Data files offloaded to the object store
+	localId     uint32                 // number of the earliest file stored locally
+	rewinds     uint64                 // Counter of the head truncations into sealed files
+
 	metadata    *freezerTableMeta // metadata of the table
 	uncommitted uint64            // Count of items written without flushing to file
 	lastSync    time.Time         // Timestamp when the last sync was performed
@@ -134,6 +140,12 @@ func newFreezerTable(path, name string, config freezerTableConfig, readonly bool
 // non-existent. Both files are truncated to the shortest common length to ensure
 // they don't go out of sync.
 func newTable(path string, name string, readMeter, writeMeter *metrics.Meter, sizeGauge *metrics.Gauge, maxFilesize uint32, config freezerTableConfig, readonly bool) (*freezerTable, error) {
+	return openTable(path, name, readMeter, writeMeter, sizeGauge, maxFilesize, config, readonly, false, nil)
+}
+
+// openTable opens a freezer table like newTable, with the sealed data files
+// optionally offloaded to the given object store.
+func openTable(path string, name string, readMeter, writeMeter *metrics.Meter, sizeGauge *metrics.Gauge, maxFilesize uint32, config freezerTableConfig, readonly bool, secondary bool, remote *remoteStore) (*freezerTable, error) {
 	// Ensure the containing directory exists and open the indexEntry file
 	if err := os.MkdirAll(path, 0755); err != nil {
 		return nil, err
@@ -149,7 +161,7 @@ func newTable(path string, name string, readMeter, writeMeter *metrics.Meter, si
 		index *os.File
 		meta  *os.File
 	)
-	if readonly {
+	if readonly || secondary {
 		// Will fail if table index file or meta file is not existent
 		index, err = openFreezerFileForReadOnly(filepath.Join(path, idxName))
 		if err != nil {
@@ -171,10 +183,20 @@ func newTable(path string, name string, readMeter, writeMeter *metrics.Meter, si
 	}
 	// Load metadata from the file. The tag will be true if legacy metadata
 	// is detected.
+	stat, err := meta.Stat()
+	if err != nil {
+		return nil, err
+	}
 	metadata, err := newMetadata(meta)
 	if err != nil {
 		return nil, err
 	}
+	// Apply the configured compression to the newly created table
+	if stat.Size() == 0 && !config.noSnappy && config.compression != metadata.compression {
+		if err := metadata.setCompression(config.compression, true); err != nil {
+			return nil, err
+		}
+	}
 	// Create the table and repair any past inconsistency
 	tab := &freezerTable{
 		index:       index,
@@ -188,10 +210,44 @@ func newTable(path string, name string, readMeter, writeMeter *metrics.Meter, si
 		path:        path,
 		logger:      log.New("database", path, "table", name),
 		config:      config,
-		readonly:    readonly,
+		readonly:    readonly || secondary,
+		secondary:   secondary,
 		maxFileSize: maxFilesize,
 	}
-	if err := tab.repair(); err != nil {
+	if remote != nil {
+		tab.remote = remote
+		tab.remoteFiles = make(map[uint32]*remoteFile)
+		if err := tab.loadLocalId(); err != nil {
+			tab.Close()
+			return nil, err
+		}
+	}
+	if !config.noSnappy {
+		dict, err := tab.loadDictionary()
+		if err != nil {
+			tab.Close()
+			return nil, err
+		}
+		if tab.codec, err = newItemCodec(dict, metadata.compression); err != nil {
+			tab.Close()
+			return nil, err
+		}
+		if !secondary {
+			if err := tab.recoverRecompression(); err != nil {
+				tab.Close()
+				return nil, err
+			}
+		}
+	}
+	// The secondary table is in use by the primary, which might be in the middle
+	// of appending items. Rather than being repaired, the table is loaded in its
+	// current state, excluding the partially written items.
+	if secondary {
+		err = tab.refresh()
+	} else {
+		err = tab.repair()
+	}
+	if err != nil {
 		tab.Close()
 		return nil, err
 	}
@@ -339,6 +395,9 @@ func (t *freezerTable) repair() error {
 			if newLastIndex.filenum != lastIndex.filenum {
 				// Release earlier opened file
 				t.releaseFile(lastIndex.filenum)
+				if err := t.ensureLocal(newLastIndex.filenum); err != nil {
+					return err
+				}
 				if t.head, err = t.openFile(newLastIndex.filenum, openFreezerFileForAppend); err != nil {
 					return err
 				}
@@ -582,8 +641,13 @@ func (t *freezerTable) preopen() (err error) {
 	// The repair might have already opened (some) files
 	t.releaseFilesAfter(0, false)
 
-	// Open all except head in RDONLY
+	// Open all except head in RDONLY, the offloaded files are read remotely
 	for i := t.tailId; i < t.headId; i++ {
+		if t.isRemote(i) {
+			os.Remove(filepath.Join(t.path, t.fileName(i))) // leftover of an interrupted offload
+			t.remoteFiles[i] = t.remote.newFile(t.fileName(i))
+			continue
+		}
 		if _, err = t.openFile(i, openFreezerFileForReadOnly); err != nil {
 			return err
 		}
@@ -657,6 +721,10 @@ func (t *freezerTable) truncateHead(items uint64) error {
 	if expected.filenum != t.headId {
 		// If already open for reading, force-reopen for writing
 		t.releaseFile(expected.filenum)
+		if err := t.ensureLocal(expected.filenum); err != nil {
+			return err
+		}
+		t.rewinds++
 		newHead, err := t.openFile(expected.filenum, openFreezerFileForAppend)
 		if err != nil {
 			return err
@@ -860,6 +928,9 @@ func (t *freezerTable) Close() error {
 	t.index = nil
 	t.head = nil
 	t.metadata.file = nil
+	if t.codec != nil {
+		t.codec.close()
+	}
 
 	if errs != nil {
 		return fmt.Errorf("%v", errs)
@@ -871,13 +942,7 @@ func (t *freezerTable) Close() error {
 func (t *freezerTable) openFile(num uint32, opener func(string) (*os.File, error)) (f *os.File, err error) {
 	var exist bool
 	if f, exist = t.files[num]; !exist {
-		var name string
-		if t.config.noSnappy {
-			name = fmt.Sprintf("%s.%04d.rdat", t.name, num)
-		} else {
-			name = fmt.Sprintf("%s.%04d.cdat", t.name, num)
-		}
-		f, err = opener(filepath.Join(t.path, name))
+		f, err = opener(filepath.Join(t.path, t.fileName(num)))
 		if err != nil {
 			return nil, err
 		}
@@ -886,6 +951,14 @@ func (t *freezerTable) openFile(num uint32, opener func(string) (*os.File, error
 	return f, err
 }
 
+// fileName returns the name of the data file with the given number.
+func (t *freezerTable) fileName(num uint32) string {
+	if t.config.noSnappy {
+		return fmt.Sprintf("%s.%04d.rdat", t.name, num)
+	}
+	return fmt.Sprintf("%s.%04d.cdat", t.name, num)
+}
+
 // releaseFile closes a file, and removes it from the open file cache.
 // Assumes that the caller holds the write lock
 func (t *freezerTable) releaseFile(num uint32) {
@@ -906,6 +979,14 @@ func (t *freezerTable) releaseFilesAfter(num uint32, remove bool) {
 			}
 		}
 	}
+	for fnum := range t.remoteFiles {
+		if fnum > num {
+			delete(t.remoteFiles, fnum)
+			if remove {
+				t.deleteRemote(fnum)
+			}
+		}
+	}
 }
 
 // releaseFilesBefore closes all open files with a lower number, and optionally also deletes the files
@@ -919,6 +1000,14 @@ func (t *freezerTable) releaseFilesBefore(num uint32, remove bool) {
 			}
 		}
 	}
+	for fnum := range t.remoteFiles {
+		if fnum < num {
+			delete(t.remoteFiles, fnum)
+			if remove {
+				t.deleteRemote(fnum)
+			}
+		}
+	}
 }
 
 // getIndices returns the index entries for the given from-item, covering 'count' items.
@@ -989,13 +1078,13 @@ func (t *freezerTable) RetrieveItems(start, count, maxBytes uint64) ([][]byte, e
 		offset += diskSize
 		decompressedSize := diskSize
 		if !t.config.noSnappy {
-			decompressedSize, _ = snappy.DecodedLen(item)
+			decompressedSize = t.codec.decodedLen(item)
 		}
 		if i > 0 && maxBytes != 0 && uint64(outputSize+decompressedSize) > maxBytes {
 			break
 		}
 		if !t.config.noSnappy {
-			data, err := snappy.Decode(nil, item)
+			data, err := t.codec.decode(item)
 			if err != nil {
 				return nil, err
 			}
@@ -1042,8 +1131,12 @@ func (t *freezerTable) retrieveItems(start, count, maxBytes uint64) ([]byte, []i
 	// readData is a helper method to read a single data item from disk.
 	readData := func(fileId, start uint32, length int) error {
 		output = grow(output, length)
-		dataFile, exist := t.files[fileId]
-		if !exist {
+		var dataFile io.ReaderAt
+		if f, exist := t.files[fileId]; exist {
+			dataFile = f
+		} else if f, exist := t.remoteFiles[fileId]; exist {
+			dataFile = f
+		} else {
 			return fmt.Errorf("missing data file %d", fileId)
 		}
 		if _, err := dataFile.ReadAt(output[len(output)-length:], int64(start)); err != nil {
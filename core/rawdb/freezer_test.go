@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"math/big"
 	"math/rand"
+	"os"
 	"sync"
 	"testing"
 
@@ -417,3 +418,69 @@ func TestFreezerSuite(t *testing.T) {
 		return f
 	})
 }
+
+func TestFreezerSecondary(t *testing.T) {
+	t.Parallel()
+
+	tables := map[string]freezerTableConfig{"a": {noSnappy: true, prunable: true}, "b": {noSnappy: true, prunable: true}}
+	f, dir := newFreezerForTesting(t, tables)
+	defer f.Close()
+
+	appendItems := func(kind string, from, to uint64) {
+		batch := f.tables[kind].newBatch()
+		for i := from; i < to; i++ {
+			require.NoError(t, batch.AppendRaw(i, getChunk(1024, int(i))))
+		}
+		require.NoError(t, batch.commit())
+	}
+	appendItems("a", 0, 10)
+	appendItems("b", 0, 10)
+
+	// The secondary must be opened despite the lock held by the primary
+	secondary, err := newFreezer(dir, "", false, true, 2049, tables, nil)
+	if err != nil {
+		t.Fatal("can't open secondary freezer", err)
+	}
+	defer secondary.Close()
+
+	checkAncientCount(t, secondary, "a", 10)
+	if _, err := secondary.ModifyAncients(func(op ethdb.AncientWriteOp) error { return nil }); err == nil {
+		t.Fatal("secondary freezer accepted write")
+	}
+	// Items being appended are only exposed once present in all tables
+	appendItems("a", 10, 15)
+	require.NoError(t, secondary.refresh())
+	checkAncientCount(t, secondary, "b", 10)
+
+	appendItems("b", 10, 15)
+	require.NoError(t, secondary.refresh())
+	checkAncientCount(t, secondary, "b", 15)
+
+	// Index entries pointing beyond the written data must be ignored
+	index, err := os.OpenFile(f.tables["a"].index.Name(), os.O_WRONLY|os.O_APPEND, 0644)
+	require.NoError(t, err)
+	entry := indexEntry{filenum: f.tables["a"].headId, offset: 1 << 30}
+	_, err = index.Write(entry.append(nil))
+	require.NoError(t, err)
+	require.NoError(t, index.Close())
+
+	require.NoError(t, secondary.refresh())
+	checkAncientCount(t, secondary, "a", 15)
+
+	// Tail truncations of the primary must be followed
+	_, err = f.TruncateTail(6)
+	require.NoError(t, err)
+	require.NoError(t, secondary.refresh())
+
+	if tail, _ := secondary.Tail(); tail != 6 {
+		t.Fatalf("unexpected secondary tail, want 6, have %d", tail)
+	}
+	if _, err := secondary.Ancient("a", 5); err == nil {
+		t.Fatal("truncated item retrieved from secondary")
+	}
+	for i := uint64(6); i < 15; i++ {
+		blob, err := secondary.Ancient("b", i)
+		require.NoError(t, err)
+		require.Equal(t, getChunk(1024, int(i)), blob)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// ErrMigrationInterrupted is returned if a key-value store migration is stopped
+// before completion. The progress is retained, the migration can be resumed.
+var ErrMigrationInterrupted = errors.New("migration interrupted")
+
+// ReadMigrationProgress retrieves the last key copied by an interrupted key-value
+// store migration, nil is returned if no migration is in progress.
+func ReadMigrationProgress(db ethdb.KeyValueReader) []byte {
+	blob, _ := db.Get(migrationProgressKey)
+	return blob
+}
+
+// MigrateKeyValueStore copies all the entries of the source key-value store into
+// the destination one, typically backed by a different database engine.
+//
+// The progress is tracked in the destination store, so an interrupted migration
+// is resumed from the last committed batch if invoked again with the same stores.
+// The progress marker is removed once the migration has completed.
+func MigrateKeyValueStore(src ethdb.KeyValueStore, dst ethdb.KeyValueStore, stop chan struct{}) error {
+	var start []byte
+	if last := ReadMigrationProgress(dst); len(last) > 0 {
+		start = append(common.CopyBytes(last), 0x00) // smallest key after the last copied one
+		log.Info("Resuming key-value store migration", "last", hexutil.Encode(last))
+	}
+	var (
+		it      = src.NewIterator(nil, start)
+		batch   = dst.NewBatch()
+		count   int
+		size    common.StorageSize
+		started = time.Now()
+		logged  = time.Now()
+	)
+	defer it.Release()
+
+	for it.Next() {
+		key, value := it.Key(), it.Value()
+
+		// Skip the progress marker of a migration the source itself was created by
+		if bytes.Equal(key, migrationProgressKey) {
+			continue
+		}
+		if err := batch.Put(key, value); err != nil {
+			return err
+		}
+		count += 1
+		size += common.StorageSize(len(key) + len(value))
+
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Put(migrationProgressKey, key); err != nil {
+				return err
+			}
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+
+			select {
+			case <-stop:
+				log.Info("Key-value store migration interrupted", "entries", count, "size", size, "elapsed", common.PrettyDuration(time.Since(started)))
+				return ErrMigrationInterrupted
+			default:
+			}
+			if time.Since(logged) > 8*time.Second {
+				log.Info("Migrating key-value store", "entries", count, "size", size, "last", hexutil.Encode(key), "elapsed", common.PrettyDuration(time.Since(started)))
+				logged = time.Now()
+			}
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if err := batch.Delete(migrationProgressKey); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	if err := dst.SyncKeyValue(); err != nil {
+		return err
+	}
+	log.Info("Migrated key-value store", "entries", count, "size", size, "elapsed", common.PrettyDuration(time.Since(started)))
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+)
+
+func TestMigrateKeyValueStore(t *testing.T) {
+	src := memorydb.New()
+	for i := 0; i < 5000; i++ {
+		key, value := make([]byte, 32), make([]byte, 64)
+		rand.Read(key)
+		rand.Read(value)
+		src.Put(key, value)
+	}
+	// Interrupt the migration right after the first batch
+	var (
+		dst  = memorydb.New()
+		stop = make(chan struct{})
+	)
+	close(stop)
+	if err := MigrateKeyValueStore(src, dst, stop); !errors.Is(err, ErrMigrationInterrupted) {
+		t.Fatalf("Unexpected error, want %v, got %v", ErrMigrationInterrupted, err)
+	}
+	if ReadMigrationProgress(dst) == nil {
+		t.Fatal("Migration progress not tracked")
+	}
+	if dst.Len() >= src.Len() {
+		t.Fatalf("Migration not interrupted, %d of %d entries copied", dst.Len(), src.Len())
+	}
+	// Resume the migration and ensure all entries are copied
+	if err := MigrateKeyValueStore(src, dst, nil); err != nil {
+		t.Fatalf("Failed to resume migration: %v", err)
+	}
+	if ReadMigrationProgress(dst) != nil {
+		t.Fatal("Migration progress not cleaned up")
+	}
+	if dst.Len() != src.Len() {
+		t.Fatalf("Entry count mismatch, want %d, got %d", src.Len(), dst.Len())
+	}
+	it := src.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		value, err := dst.Get(it.Key())
+		if err != nil {
+			t.Fatalf("Entry %x missing: %v", it.Key(), err)
+		}
+		if !bytes.Equal(value, it.Value()) {
+			t.Fatalf("Entry %x mismatch, want %x, got %x", it.Key(), it.Value(), value)
+		}
+	}
+}
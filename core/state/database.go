@@ -27,6 +27,7 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/trie/bintrie"
 	"github.com/ethereum/go-ethereum/trie/trienode"
 	"github.com/ethereum/go-ethereum/trie/utils"
 	"github.com/ethereum/go-ethereum/triedb"
@@ -151,6 +152,10 @@ type CachingDB struct {
 	codeCache     *lru.SizeConstrainedCache[common.Hash, []byte]
 	codeSizeCache *lru.Cache[common.Hash, int]
 	pointCache    *utils.PointCache
+
+	// base is the merkle database of the state being converted into the verkle
+	// tree in triedb, nil if the database isn't used for the verkle transition.
+	base *triedb.Database
 }
 
 // NewDatabase creates a state database with the provided data sources.
@@ -165,6 +170,16 @@ func NewDatabase(triedb *triedb.Database, snap *snapshot.Tree) *CachingDB {
 	}
 }
 
+// NewTransitionDatabase creates a state database for the conversion of the
+// merkle state in base into the verkle tree in triedb. The verkle tree is built
+// as an overlay on top of the merkle state, which is opened at the states with
+// no conversion progress recorded, namely at the start of the conversion.
+func NewTransitionDatabase(triedb *triedb.Database, base *triedb.Database) *CachingDB {
+	db := NewDatabase(triedb, nil)
+	db.base = base
+	return db
+}
+
 // NewDatabaseForTesting is similar to NewDatabase, but it initializes the caching
 // db by using an ephemeral memory db with default config for testing.
 func NewDatabaseForTesting() *CachingDB {
@@ -173,6 +188,16 @@ func NewDatabaseForTesting() *CachingDB {
 
 // Reader returns a state reader associated with the specified state root.
 func (db *CachingDB) Reader(stateRoot common.Hash) (Reader, error) {
+	// The flat states are not available during the verkle transition, serve
+	// all the reads with the overlaid trees.
+	if db.base != nil {
+		tr, err := db.OpenTrie(stateRoot)
+		if err != nil {
+			return nil, err
+		}
+		reader := newTrieReaderWithTrie(stateRoot, db.triedb, tr)
+		return newReader(newCachingCodeReader(db.disk, db.codeCache, db.codeSizeCache), reader), nil
+	}
 	var readers []StateReader
 
 	// Configure the state reader using the standalone snapshot in hash mode.
@@ -223,6 +248,12 @@ func (db *CachingDB) ReadersWithCacheStats(stateRoot common.Hash) (ReaderWithSta
 
 // OpenTrie opens the main account trie at a specific root hash.
 func (db *CachingDB) OpenTrie(root common.Hash) (Trie, error) {
+	if db.base != nil {
+		return db.openTransitionTrie(root)
+	}
+	if db.triedb.IsBinary() {
+		return bintrie.NewBinaryTrie(root, db.triedb)
+	}
 	if db.triedb.IsVerkle() {
 		return trie.NewVerkleTrie(root, db.triedb, db.pointCache)
 	}
@@ -233,6 +264,31 @@ func (db *CachingDB) OpenTrie(root common.Hash) (Trie, error) {
 	return tr, nil
 }
 
+// openTransitionTrie opens the state tree at a specific root hash during the
+// verkle transition. The state without the recorded conversion progress is the
+// merkle state at which the conversion starts, and the one with the completed
+// conversion is a plain verkle tree.
+func (db *CachingDB) openTransitionTrie(root common.Hash) (Trie, error) {
+	ts, err := ReadTransitionState(db.disk, root)
+	if err != nil {
+		return nil, err
+	}
+	if ts != nil && ts.Ended {
+		return trie.NewVerkleTrie(root, db.triedb, db.pointCache)
+	}
+	overlayRoot, baseRoot := root, root
+	if ts == nil {
+		overlayRoot = types.EmptyVerkleHash
+	} else {
+		baseRoot = ts.BaseRoot
+	}
+	overlay, err := trie.NewVerkleTrie(overlayRoot, db.triedb, db.pointCache)
+	if err != nil {
+		return nil, err
+	}
+	return trie.NewTransitionTrie(overlay, baseRoot, db.base)
+}
+
 // OpenStorageTrie opens the storage trie of an account.
 func (db *CachingDB) OpenStorageTrie(stateRoot common.Hash, address common.Address, root common.Hash, self Trie) (Trie, error) {
 	// In the verkle case, there is only one tree. But the two-tree structure
@@ -286,6 +342,10 @@ func mustCopyTrie(t Trie) Trie {
 		return t.Copy()
 	case *trie.VerkleTrie:
 		return t.Copy()
+	case *bintrie.BinaryTrie:
+		return t.Copy()
+	case *trie.TransitionTrie:
+		return t.Copy()
 	default:
 		panic(fmt.Errorf("unknown trie type %T", t))
 	}
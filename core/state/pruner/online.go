@@ -0,0 +1,375 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package pruner
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state/snapshot"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/triedb"
+)
+
+const (
+	// onlinePruneBatch is the number of stale trie nodes deleted at once.
+	onlinePruneBatch = 1024
+
+	// onlinePruneRecheck is the interval of checking whether the chain has
+	// progressed enough for selecting the pruning target.
+	onlinePruneRecheck = 3 * time.Second
+)
+
+// errOnlinePrunerStopped is returned if the pruning cycle is interrupted by the
+// termination of the pruner.
+var errOnlinePrunerStopped = errors.New("online pruner stopped")
+
+// OnlineConfig includes all the configurations for online pruning.
+type OnlineConfig struct {
+	BloomSize uint64        // The Megabytes of memory allocated to bloom-filter
+	Rate      int           // Maximum number of trie nodes deleted per second
+	Depth     uint64        // Number of blocks between the chain head and the pruning target
+	Interval  time.Duration // Time to wait between two pruning cycles
+}
+
+// DefaultOnlineConfig contains the default settings for online pruning.
+var DefaultOnlineConfig = OnlineConfig{
+	BloomSize: 2048,
+	Rate:      50000,
+	Depth:     128,
+	Interval:  time.Hour,
+}
+
+// ChainReader defines a small collection of methods needed to select the state
+// to be retained by the online pruner.
+type ChainReader interface {
+	// CurrentBlock retrieves the head block of the canonical chain.
+	CurrentBlock() *types.Header
+
+	// GetHeaderByNumber retrieves a canonical header by number.
+	GetHeaderByNumber(number uint64) *types.Header
+}
+
+// OnlinePruner is a background service deleting the stale state of the legacy
+// hash based scheme, while the chain keeps importing blocks. It works in cycles:
+//
+//   - install a journal in the trie database, protecting all the trie nodes
+//     persisted from now on
+//   - wait for a recent state which is deep enough to be safe from reorgs,
+//     and created after the journal is installed, commit it into the disk
+//   - mark the trie nodes of this state in the bloom filter, regenerating the
+//     tries from the snapshot, or traversing them if the snapshot is unusable
+//   - iterate the database, deleting the trie nodes which are neither marked
+//     nor journaled, with a bounded rate
+//
+// All the states built on top of the target state are retained, as they only
+// reference the nodes of the target state or the ones persisted afterwards. The
+// older states, as well as the ones of the sidechains forking below the target,
+// are left incomplete, similarly to the offline pruning.
+//
+// Contract codes are never deleted, as they are not tracked by the journal.
+type OnlinePruner struct {
+	config   OnlineConfig
+	db       ethdb.Database
+	triedb   *triedb.Database
+	snaptree *snapshot.Tree // Snapshot for marking the target state, nil if not available
+	chain    ChainReader
+
+	keep *stateBloom // Trie nodes to retain in the current pruning cycle
+	lock sync.Mutex  // Lock protecting the bloom filter against concurrent deletion
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewOnlinePruner creates the online pruner on top of a hash based trie database
+// and starts its background loop.
+func NewOnlinePruner(db ethdb.Database, triedb *triedb.Database, snaptree *snapshot.Tree, chain ChainReader, config OnlineConfig) (*OnlinePruner, error) {
+	if triedb.Scheme() != rawdb.HashScheme {
+		return nil, errors.New("online pruning is only supported in hash scheme")
+	}
+	// Sanitize the bloom filter size if it's too small.
+	if config.BloomSize < 256 {
+		log.Warn("Sanitizing bloomfilter size", "provided(MB)", config.BloomSize, "updated(MB)", 256)
+		config.BloomSize = 256
+	}
+	if config.Rate <= 0 {
+		log.Warn("Sanitizing pruning rate", "provided", config.Rate, "updated", DefaultOnlineConfig.Rate)
+		config.Rate = DefaultOnlineConfig.Rate
+	}
+	p := &OnlinePruner{
+		config:   config,
+		db:       db,
+		triedb:   triedb,
+		snaptree: snaptree,
+		chain:    chain,
+		quit:     make(chan struct{}),
+	}
+	p.wg.Add(1)
+	go p.loop()
+
+	log.Info("Initialized online state pruner", "bloom", common.StorageSize(config.BloomSize*1024*1024), "rate", config.Rate, "depth", config.Depth, "interval", config.Interval)
+	return p, nil
+}
+
+// Stop terminates the background pruning and waits for it to exit.
+func (p *OnlinePruner) Stop() {
+	close(p.quit)
+	p.wg.Wait()
+}
+
+// loop runs the pruning cycles until the pruner is stopped.
+func (p *OnlinePruner) loop() {
+	defer p.wg.Done()
+
+	for {
+		err := p.prune()
+		if errors.Is(err, errOnlinePrunerStopped) {
+			return
+		}
+		if err != nil {
+			log.Error("Failed to prune state", "err", err)
+		}
+		if !p.wait(p.config.Interval) {
+			return
+		}
+	}
+}
+
+// wait blocks for the given duration, returning false if the pruner is stopped
+// in the meantime.
+func (p *OnlinePruner) wait(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-p.quit:
+		return false
+	}
+}
+
+// Put implements ethdb.KeyValueWriter, marking the trie node or contract code
+// with the given key to be retained.
+func (p *OnlinePruner) Put(key []byte, value []byte) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.keep.Put(key, value)
+}
+
+// Delete implements ethdb.KeyValueWriter.
+func (p *OnlinePruner) Delete(key []byte) error { panic("not supported") }
+
+// journal is the write hook of the trie database, retaining the trie node that
+// is being persisted. The deletion happens with the lock held, so the node is
+// either written after the deletion of a stale copy, or isn't deleted at all.
+func (p *OnlinePruner) journal(hash common.Hash) {
+	p.Put(hash.Bytes(), nil)
+}
+
+// prune runs a single pruning cycle.
+func (p *OnlinePruner) prune() error {
+	// The state must be fully available, which isn't the case during snap sync.
+	if rawdb.ReadSnapSyncStatusFlag(p.db) == rawdb.StateSyncRunning {
+		log.Debug("Skipping online pruning during state sync")
+		return nil
+	}
+	keep, err := newStateBloomWithSize(p.config.BloomSize)
+	if err != nil {
+		return err
+	}
+	p.lock.Lock()
+	p.keep = keep
+	p.lock.Unlock()
+
+	if err := p.triedb.SetWriteHook(p.journal); err != nil {
+		return err
+	}
+	defer func() {
+		p.triedb.SetWriteHook(nil)
+
+		p.lock.Lock()
+		p.keep = nil
+		p.lock.Unlock()
+	}()
+	// Wait for the target state, which must be created after the journal is
+	// installed. Any node of the newer states persisted before that, e.g. by
+	// capping the dirty cache of the trie database, would not be retained.
+	// The block following the current head might be under import already,
+	// skip it too.
+	first := p.chain.CurrentBlock().Number.Uint64() + 2
+	var target *types.Header
+	for target == nil {
+		if head := p.chain.CurrentBlock().Number.Uint64(); head >= first+p.config.Depth {
+			target = p.chain.GetHeaderByNumber(head - p.config.Depth)
+		}
+		if target == nil && !p.wait(onlinePruneRecheck) {
+			return errOnlinePrunerStopped
+		}
+	}
+	root := target.Root
+
+	// Persist the target state, it's the state to start from in case of crash,
+	// as none of its nodes will be deleted.
+	if err := p.triedb.Commit(root, false); err != nil {
+		return err
+	}
+	if !rawdb.HasLegacyTrieNode(p.db, root) {
+		return fmt.Errorf("state %x of block %d is not available", root, target.Number)
+	}
+	start := time.Now()
+	if err := p.mark(root); err != nil {
+		return err
+	}
+	// Traverse the genesis, put all genesis state entries into the
+	// bloom filter too.
+	if err := extractGenesis(p.db, p); err != nil {
+		return err
+	}
+	log.Info("Marked state for online pruning", "number", target.Number, "root", root, "elapsed", common.PrettyDuration(time.Since(start)))
+
+	return p.sweep(start)
+}
+
+// mark records all the trie nodes of the given state in the bloom filter. The
+// tries are regenerated from the snapshot if possible, or traversed otherwise.
+// The latter is necessary if the snapshot layer of the state is flattened in
+// the meantime.
+func (p *OnlinePruner) mark(root common.Hash) error {
+	if p.snaptree != nil {
+		err := snapshot.GenerateTrie(p.snaptree, root, p.db, p)
+		if err == nil {
+			return nil
+		}
+		log.Info("Falling back to traversing the state", "root", root, "err", err)
+	}
+	return extractState(p.db, root, p)
+}
+
+// sweep iterates the database, deleting the trie nodes which are not retained.
+func (p *OnlinePruner) sweep(start time.Time) error {
+	var (
+		count, skipped int
+		size           common.StorageSize
+		pstart         = time.Now()
+		logged         = time.Now()
+		pending        [][]byte
+		iter           = p.db.NewIterator(nil, nil)
+	)
+	defer func() { iter.Release() }()
+
+	for iter.Next() {
+		key := iter.Key()
+
+		// Only the trie nodes are deleted, the contract codes are retained
+		if len(key) != common.HashLength {
+			continue
+		}
+		if p.contain(key) {
+			skipped++
+			continue
+		}
+		key = common.CopyBytes(key)
+		pending = append(pending, key)
+		size += common.StorageSize(len(key) + len(iter.Value()))
+
+		if len(pending) < onlinePruneBatch {
+			continue
+		}
+		// Release the iterator during the deletion and the throttling, allowing
+		// the underlying compactor to delete the entries.
+		iter.Release()
+
+		deleted, err := p.delete(pending)
+		if err != nil {
+			return err
+		}
+		count, skipped = count+deleted, skipped+len(pending)-deleted
+		pending = nil
+
+		if !p.throttle(pstart, count) {
+			return errOnlinePrunerStopped
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Pruning state data online", "nodes", count, "skipped", skipped, "size", size, "elapsed", common.PrettyDuration(time.Since(pstart)))
+			logged = time.Now()
+		}
+		iter = p.db.NewIterator(nil, key)
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	deleted, err := p.delete(pending)
+	if err != nil {
+		return err
+	}
+	count += deleted
+	log.Info("Pruned state data online", "nodes", count, "size", size, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// contain reports whether the trie node with the given key is to be retained.
+func (p *OnlinePruner) contain(key []byte) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.keep.Contain(key)
+}
+
+// delete removes the given trie nodes from the database, unless they have been
+// persisted again since they were found stale. It returns the number of nodes
+// deleted.
+func (p *OnlinePruner) delete(keys [][]byte) (int, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	var (
+		deleted int
+		batch   = p.db.NewBatch()
+	)
+	for _, key := range keys {
+		if p.keep.Contain(key) {
+			continue
+		}
+		batch.Delete(key)
+		deleted++
+	}
+	return deleted, batch.Write()
+}
+
+// throttle blocks until the number of the deleted nodes is within the allowed
+// rate, returning false if the pruner is stopped in the meantime.
+func (p *OnlinePruner) throttle(start time.Time, deleted int) bool {
+	allowed := time.Duration(deleted) * time.Second / time.Duration(p.config.Rate)
+	if elapsed := time.Since(start); elapsed < allowed {
+		return p.wait(allowed - elapsed)
+	}
+	select {
+	case <-p.quit:
+		return false
+	default:
+		return true
+	}
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/trie/bintrie"
 	"github.com/ethereum/go-ethereum/trie/utils"
 	"github.com/ethereum/go-ethereum/triedb"
 	"github.com/ethereum/go-ethereum/triedb/database"
@@ -238,21 +239,30 @@ func newTrieReader(root common.Hash, db *triedb.Database, cache *utils.PointCach
 		tr  Trie
 		err error
 	)
-	if !db.IsVerkle() {
-		tr, err = trie.NewStateTrie(trie.StateTrieID(root), db)
-	} else {
+	switch {
+	case db.IsBinary():
+		tr, err = bintrie.NewBinaryTrie(root, db)
+	case db.IsVerkle():
 		tr, err = trie.NewVerkleTrie(root, db, cache)
+	default:
+		tr, err = trie.NewStateTrie(trie.StateTrieID(root), db)
 	}
 	if err != nil {
 		return nil, err
 	}
+	return newTrieReaderWithTrie(root, db, tr), nil
+}
+
+// newTrieReaderWithTrie constructs a trie reader of the specific state on top
+// of the already opened main trie.
+func newTrieReaderWithTrie(root common.Hash, db *triedb.Database, tr Trie) *trieReader {
 	return &trieReader{
 		root:     root,
 		db:       db,
 		mainTrie: tr,
 		subRoots: make(map[common.Address]common.Hash),
 		subTries: make(map[common.Address]Trie),
-	}, nil
+	}
 }
 
 // account is the inner version of Account and assumes the r.lock is already held.
@@ -523,6 +533,15 @@ func newReaderWithCacheStats(reader *readerWithCache) *readerWithCacheStats {
 	}
 }
 
+// NewReaderWithCache wraps the given reader with a concurrency-safe cache of the
+// resolved accounts and storage slots. The returned reader is meant to be shared
+// by multiple state instances of the same root, e.g. the workers executing the
+// transactions of a block concurrently, so that the state entries are resolved
+// from the underlying reader only once.
+func NewReaderWithCache(reader Reader) ReaderWithStats {
+	return newReaderWithCacheStats(newReaderWithCache(reader))
+}
+
 // Account implements StateReader, retrieving the account specified by the address.
 // The returned account might be nil if it's not existent.
 //
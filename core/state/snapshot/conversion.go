@@ -54,6 +54,22 @@ type (
 // accounts as well as the corresponding storages and regenerate the whole state
 // (account trie + all storage tries).
 func GenerateTrie(snaptree *Tree, root common.Hash, src ethdb.Database, dst ethdb.KeyValueWriter) error {
+	return generateTrie(snaptree, root, src, dst, snaptree.triedb.Scheme())
+}
+
+// GeneratePathTrie is the equivalent of GenerateTrie, but the regenerated trie
+// nodes are always stored in the path-based scheme, irrespective of the scheme
+// of the trie database the snapshot is backed by. It's used to convert a state
+// stored in the hash-based scheme into the path-based one.
+//
+// The given writer must be safe for concurrent use.
+func GeneratePathTrie(snaptree *Tree, root common.Hash, src ethdb.Database, dst ethdb.KeyValueWriter) error {
+	return generateTrie(snaptree, root, src, dst, rawdb.PathScheme)
+}
+
+// generateTrie regenerates the whole state from the snapshot, storing the trie
+// nodes in the specified scheme.
+func generateTrie(snaptree *Tree, root common.Hash, src ethdb.Database, dst ethdb.KeyValueWriter, scheme string) error {
 	// Traverse all state by snapshot, re-generate the whole state trie
 	acctIt, err := snaptree.AccountIterator(root, common.Hash{})
 	if err != nil {
@@ -61,7 +77,6 @@ func GenerateTrie(snaptree *Tree, root common.Hash, src ethdb.Database, dst ethd
 	}
 	defer acctIt.Release()
 
-	scheme := snaptree.triedb.Scheme()
 	got, err := generateTrieRoot(dst, scheme, acctIt, common.Hash{}, stackTrieGenerate, func(dst ethdb.KeyValueWriter, accountHash, codeHash common.Hash, stat *generateStats) (common.Hash, error) {
 		// Migrate the code first, commit the contract code into the tmp db.
 		if codeHash != types.EmptyCodeHash {
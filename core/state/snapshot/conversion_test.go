@@ -0,0 +1,82 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshot
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/ethereum/go-ethereum/triedb/pathdb"
+	"github.com/holiman/uint256"
+)
+
+// Tests that a state stored in the hash-based scheme can be converted into the
+// path-based one by regenerating the trie nodes from the snapshot.
+func TestGeneratePathTrie(t *testing.T) {
+	helper := newHelper(rawdb.HashScheme)
+	stRoot := helper.makeStorageTrie("acc-1", []string{"key-1", "key-2", "key-3"}, []string{"val-1", "val-2", "val-3"}, true)
+	helper.addAccount("acc-1", &types.StateAccount{Balance: uint256.NewInt(1), Root: stRoot, CodeHash: types.EmptyCodeHash.Bytes()})
+	helper.addAccount("acc-2", &types.StateAccount{Balance: uint256.NewInt(2), Root: types.EmptyRootHash, CodeHash: types.EmptyCodeHash.Bytes()})
+	helper.addAccount("acc-3", &types.StateAccount{Balance: uint256.NewInt(3), Root: types.EmptyRootHash, CodeHash: types.EmptyCodeHash.Bytes()})
+
+	root, snap := helper.CommitAndGenerate()
+	select {
+	case <-snap.genPending:
+	case <-time.After(3 * time.Second):
+		t.Fatal("Snapshot generation failed")
+	}
+	snaps := &Tree{
+		triedb: helper.triedb,
+		layers: map[common.Hash]snapshot{root: snap},
+	}
+	if rawdb.ReadAccountTrieNode(helper.diskdb, nil) != nil {
+		t.Fatal("Unexpected path-based state")
+	}
+	if err := GeneratePathTrie(snaps, root, helper.diskdb, helper.diskdb); err != nil {
+		t.Fatalf("Failed to generate path-based state: %v", err)
+	}
+	blob := rawdb.ReadAccountTrieNode(helper.diskdb, nil)
+	if hash := crypto.Keccak256Hash(blob); hash != root {
+		t.Fatalf("Root node mismatch, want %x, got %x", root, hash)
+	}
+	// Open the converted state with the path-based trie database
+	db := triedb.NewDatabase(helper.diskdb, &triedb.Config{PathDB: &pathdb.Config{SnapshotNoBuild: true}})
+	defer db.Close()
+
+	tr, err := trie.NewStateTrie(trie.StorageTrieID(root, hashData([]byte("acc-1")), stRoot), db)
+	if err != nil {
+		t.Fatalf("Failed to open storage trie: %v", err)
+	}
+	for i, key := range []string{"key-1", "key-2", "key-3"} {
+		want := []byte(fmt.Sprintf("val-%d", i+1))
+		if val := tr.MustGet([]byte(key)); !bytes.Equal(val, want) {
+			t.Fatalf("Storage %s mismatch, want %s, got %s", key, want, val)
+		}
+	}
+	// Signal abortion to the generator and wait for it to tear down
+	stop := make(chan *generatorStats)
+	snap.genAbort <- stop
+	<-stop
+}
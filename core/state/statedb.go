@@ -38,6 +38,7 @@ import (
 	"github.com/ethereum/go-ethereum/trie"
 	"github.com/ethereum/go-ethereum/trie/trienode"
 	"github.com/ethereum/go-ethereum/trie/utils"
+	"github.com/ethereum/go-ethereum/triedb"
 	"github.com/holiman/uint256"
 	"golang.org/x/sync/errgroup"
 )
@@ -138,6 +139,9 @@ type StateDB struct {
 	// State witness if cross validation is needed
 	witness *stateless.Witness
 
+	// Progress of the verkle transition, nil if the state isn't converted
+	transition *TransitionState
+
 	// Measurements gathered during execution for debugging purposes
 	AccountReads    time.Duration
 	AccountHashes   time.Duration
@@ -697,6 +701,10 @@ func (s *StateDB) Copy() *StateDB {
 	if s.witness != nil {
 		state.witness = s.witness.Copy()
 	}
+	if s.transition != nil {
+		transition := *s.transition
+		state.transition = &transition
+	}
 	if s.accessEvents != nil {
 		state.accessEvents = s.accessEvents.Copy()
 	}
@@ -1324,12 +1332,25 @@ func (s *StateDB) commitAndFlush(block uint64, deleteEmptyObjects bool, noStorag
 		// If trie database is enabled, commit the state update as a new layer
 		if db := s.db.TrieDB(); db != nil {
 			start := time.Now()
-			if err := db.Update(ret.root, ret.originRoot, block, ret.nodes, ret.stateSet()); err != nil {
+
+			// The verkle tree of the first converted state is built on top
+			// of the empty tree, rather than the merkle state.
+			parent := ret.originRoot
+			if s.transition != nil && parent == s.transition.BaseRoot {
+				parent = types.EmptyVerkleHash
+			}
+			if err := db.Update(ret.root, parent, block, ret.nodes, ret.stateSet()); err != nil {
 				return nil, err
 			}
 			s.TrieDBCommits += time.Since(start)
 		}
 	}
+	// Record the progress of the verkle transition with the new state
+	if s.transition != nil {
+		if db := s.db.TrieDB().Disk(); db != nil {
+			WriteTransitionState(db, ret.root, s.transition)
+		}
+	}
 	s.reader, _ = s.db.Reader(s.originalRoot)
 	return ret, err
 }
@@ -1356,6 +1377,22 @@ func (s *StateDB) Commit(block uint64, deleteEmptyObjects bool, noStorageWiping
 	return ret.root, nil
 }
 
+// CommitWithStateSet is the equivalent of Commit, but additionally returns the
+// state mutations in the form consumed by the trie database, along with the
+// contract codes deployed in the state transition. The returned objects are
+// shared with the trie database and must not be modified.
+func (s *StateDB) CommitWithStateSet(block uint64, deleteEmptyObjects bool, noStorageWiping bool) (common.Hash, *triedb.StateSet, map[common.Address][]byte, error) {
+	ret, err := s.commitAndFlush(block, deleteEmptyObjects, noStorageWiping)
+	if err != nil {
+		return common.Hash{}, nil, nil, err
+	}
+	codes := make(map[common.Address][]byte, len(ret.codes))
+	for addr, code := range ret.codes {
+		codes[addr] = code.blob
+	}
+	return ret.root, ret.stateSet(), codes, nil
+}
+
 // Prepare handles the preparatory steps for executing a state transition with.
 // This method must be invoked before state transition.
 //
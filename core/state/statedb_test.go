@@ -1368,3 +1368,45 @@ func TestStorageDirtiness(t *testing.T) {
 	state.RevertToSnapshot(snap)
 	checkDirty(common.Hash{0x1}, common.Hash{0x1}, true)
 }
+
+// Tests that the state can be committed into and loaded from a binary tree.
+func TestBinaryState(t *testing.T) {
+	var (
+		disk = rawdb.NewMemoryDatabase()
+		tdb  = triedb.NewDatabase(disk, triedb.BinaryDefaults)
+		sdb  = NewDatabase(tdb, nil)
+		addr = common.Address{1}
+		code = []byte{0x60, 0x00, 0x60, 0x00}
+	)
+	state, _ := New(types.EmptyBinaryHash, sdb)
+	state.SetBalance(addr, uint256.NewInt(42), tracing.BalanceChangeUnspecified)
+	state.SetNonce(addr, 1, tracing.NonceChangeUnspecified)
+	state.SetCode(addr, code)
+	state.SetState(addr, common.Hash{1}, common.Hash{2})
+	root, err := state.Commit(1, true, false)
+	if err != nil {
+		t.Fatalf("Failed to commit state, %v", err)
+	}
+	if err := tdb.Commit(root, false); err != nil {
+		t.Fatalf("Failed to commit trie database, %v", err)
+	}
+	// Reload the state from the database
+	tdb.Close()
+	tdb = triedb.NewDatabase(disk, triedb.BinaryDefaults)
+	state, err = New(root, NewDatabase(tdb, nil))
+	if err != nil {
+		t.Fatalf("Failed to reopen state, %v", err)
+	}
+	if balance := state.GetBalance(addr); balance.Uint64() != 42 {
+		t.Fatalf("Balance mismatch, have %v, want 42", balance)
+	}
+	if nonce := state.GetNonce(addr); nonce != 1 {
+		t.Fatalf("Nonce mismatch, have %d, want 1", nonce)
+	}
+	if hash := state.GetCodeHash(addr); hash != crypto.Keccak256Hash(code) {
+		t.Fatalf("Code hash mismatch, have %x", hash)
+	}
+	if value := state.GetState(addr, common.Hash{1}); value != (common.Hash{2}) {
+		t.Fatalf("Storage mismatch, have %x", value)
+	}
+}
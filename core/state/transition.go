@@ -0,0 +1,216 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/holiman/uint256"
+)
+
+// TransitionState is the progress of the conversion of the merkle state into
+// the verkle tree, recorded for each state root produced during the conversion.
+//
+// The merkle state is converted in the order of the hashed keys. The storage
+// slots of an account are converted before the account header, after which the
+// conversion moves on to the next account.
+type TransitionState struct {
+	BaseRoot       common.Hash // Root of the merkle state being converted
+	CurrentAccount common.Hash // Hash of the account being converted, or the position to resume from
+	CurrentSlot    common.Hash // Hash of the storage slot to resume the current account from
+	Ended          bool        // Flag whether the whole merkle state is converted
+}
+
+// ReadTransitionState retrieves the progress of the verkle transition at the
+// state with the given root, nil if the state isn't produced by the transition.
+func ReadTransitionState(db ethdb.KeyValueReader, root common.Hash) (*TransitionState, error) {
+	blob := rawdb.ReadVerkleTransition(db, root)
+	if len(blob) == 0 {
+		return nil, nil
+	}
+	var ts TransitionState
+	if err := rlp.DecodeBytes(blob, &ts); err != nil {
+		return nil, fmt.Errorf("invalid verkle transition state of %x: %v", root, err)
+	}
+	return &ts, nil
+}
+
+// WriteTransitionState stores the progress of the verkle transition at the state
+// with the given root.
+func WriteTransitionState(db ethdb.KeyValueWriter, root common.Hash, ts *TransitionState) {
+	blob, err := rlp.EncodeToBytes(ts)
+	if err != nil {
+		panic(err) // can't happen, the struct is always encodable
+	}
+	rawdb.WriteVerkleTransition(db, root, blob)
+}
+
+// nextHash returns the hash following the given one, or false if the given hash
+// is the largest one.
+func nextHash(hash common.Hash) (common.Hash, bool) {
+	next, overflow := new(uint256.Int).AddOverflow(new(uint256.Int).SetBytes(hash[:]), uint256.NewInt(1))
+	if overflow {
+		return common.Hash{}, false
+	}
+	return next.Bytes32(), true
+}
+
+// ConvertToVerkle copies at most the given number of leaves of the merkle state
+// into the verkle overlay, resuming from the progress recorded at the original
+// state. The leaves already present in the overlay, because of the conversion
+// or of state modifications, are skipped without being counted. The progress
+// is recorded with the state root at commit.
+//
+// It must be invoked before any state modification of the block is applied.
+func (s *StateDB) ConvertToVerkle(leaves int) error {
+	if s.trie == nil {
+		tr, err := s.db.OpenTrie(s.originalRoot)
+		if err != nil {
+			return err
+		}
+		s.trie = tr
+	}
+	ts, err := ReadTransitionState(s.db.TrieDB().Disk(), s.originalRoot)
+	if err != nil {
+		return err
+	}
+	if ts == nil {
+		ts = &TransitionState{BaseRoot: s.originalRoot}
+	}
+	s.transition = ts
+	if ts.Ended {
+		return nil
+	}
+	tr, ok := s.trie.(*trie.TransitionTrie)
+	if !ok {
+		return fmt.Errorf("state %x is not in the verkle transition", s.originalRoot)
+	}
+	// The tries loaded by the prefetcher don't contain the converted leaves and
+	// would replace the live tree at hashing, drop them.
+	s.StopPrefetcher()
+
+	nodeIt, err := tr.Base().NodeIterator(ts.CurrentAccount[:])
+	if err != nil {
+		return err
+	}
+	it := trie.NewIterator(nodeIt)
+	for leaves > 0 && it.Next() {
+		hash := common.BytesToHash(it.Key)
+		if hash != ts.CurrentAccount {
+			ts.CurrentAccount, ts.CurrentSlot = hash, common.Hash{}
+		}
+		preimage := tr.GetKey(it.Key)
+		if len(preimage) != common.AddressLength {
+			return fmt.Errorf("missing preimage of account %x", hash)
+		}
+		addr := common.BytesToAddress(preimage)
+
+		var acc types.StateAccount
+		if err := rlp.DecodeBytes(it.Value, &acc); err != nil {
+			return fmt.Errorf("invalid account %x: %v", addr, err)
+		}
+		done, err := s.convertStorage(tr, ts, addr, &leaves)
+		if err != nil {
+			return err
+		}
+		if !done {
+			return nil
+		}
+		var code []byte
+		if codeHash := common.BytesToHash(acc.CodeHash); codeHash != types.EmptyCodeHash {
+			code, err = s.reader.Code(addr, codeHash)
+			if err != nil {
+				return err
+			}
+			if len(code) == 0 {
+				return fmt.Errorf("missing code %x of account %x", codeHash, addr)
+			}
+		}
+		written, err := tr.ConvertAccount(addr, &acc, code)
+		if err != nil {
+			return err
+		}
+		if written {
+			leaves--
+		}
+		next, ok := nextHash(hash)
+		if !ok {
+			ts.Ended = true
+			return nil
+		}
+		ts.CurrentAccount, ts.CurrentSlot = next, common.Hash{}
+	}
+	if it.Err != nil {
+		return it.Err
+	}
+	if leaves > 0 {
+		ts.Ended = true
+	}
+	return nil
+}
+
+// convertStorage copies the storage slots of the account from the recorded
+// position into the overlay, as long as the leaf budget allows. It returns
+// whether all the slots of the account are converted.
+func (s *StateDB) convertStorage(tr *trie.TransitionTrie, ts *TransitionState, addr common.Address, leaves *int) (bool, error) {
+	storage, err := tr.BaseStorage(addr)
+	if err != nil || storage == nil {
+		return err == nil, err
+	}
+	nodeIt, err := storage.NodeIterator(ts.CurrentSlot[:])
+	if err != nil {
+		return false, err
+	}
+	it := trie.NewIterator(nodeIt)
+	for it.Next() {
+		key := tr.GetKey(it.Key)
+		if len(key) != common.HashLength {
+			return false, fmt.Errorf("missing preimage of slot %x of account %x", it.Key, addr)
+		}
+		_, value, _, err := rlp.Split(it.Value)
+		if err != nil {
+			return false, fmt.Errorf("invalid slot %x of account %x: %v", key, addr, err)
+		}
+		written, err := tr.ConvertStorage(addr, key, value)
+		if err != nil {
+			return false, err
+		}
+		if !written {
+			continue
+		}
+		*leaves--
+
+		// Stop after the last slot allowed by the budget, unless it's the
+		// last possible one which would leave nothing to resume from.
+		if *leaves == 0 {
+			if next, ok := nextHash(common.BytesToHash(it.Key)); ok {
+				ts.CurrentSlot = next
+				return false, nil
+			}
+		}
+	}
+	if it.Err != nil {
+		return false, it.Err
+	}
+	return true, nil
+}
@@ -65,6 +65,10 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 		gp          = new(GasPool).AddGas(block.GasLimit())
 	)
 
+	// Convert the merkle state into the verkle tree ahead of any state change
+	if err := ProcessVerkleTransition(p.config, header, statedb); err != nil {
+		return nil, err
+	}
 	// Mutate the block and state according to any hard-fork specs
 	if p.config.DAOForkSupport && p.config.DAOForkBlock != nil && p.config.DAOForkBlock.Cmp(block.Number()) == 0 {
 		misc.ApplyDAOHardFork(statedb)
@@ -266,6 +270,16 @@ func ProcessParentBlockHash(prevHash common.Hash, evm *vm.EVM) {
 	evm.StateDB.Finalise(true)
 }
 
+// ProcessVerkleTransition converts the configured number of leaves of the merkle
+// state into the verkle tree if the block is within the verkle transition. It
+// must be invoked before any state modification of the block.
+func ProcessVerkleTransition(config *params.ChainConfig, header *types.Header, statedb *state.StateDB) error {
+	if !config.IsVerkleTransition(header.Number, header.Time) {
+		return nil
+	}
+	return statedb.ConvertToVerkle(int(config.VerkleTransitionLeaves))
+}
+
 // ProcessWithdrawalQueue calls the EIP-7002 withdrawal queue contract.
 // It returns the opaque request data returned by the contract.
 func ProcessWithdrawalQueue(requests *[][]byte, evm *vm.EVM) error {
@@ -0,0 +1,135 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/state/pruner"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// TestOnlineStatePruning tests that the online state pruner deletes the stale
+// states persisted on disk, while keeping the recent ones intact.
+func TestOnlineStatePruning(t *testing.T) {
+	t.Run("snapshot", func(t *testing.T) { testOnlineStatePruning(t, true) })
+	t.Run("trie", func(t *testing.T) { testOnlineStatePruning(t, false) })
+}
+
+func testOnlineStatePruning(t *testing.T, snapshot bool) {
+	var (
+		testBankKey, _  = crypto.GenerateKey()
+		testBankAddress = crypto.PubkeyToAddress(testBankKey.PublicKey)
+		testBankFunds   = big.NewInt(1000000000000000000)
+
+		gspec = &Genesis{
+			Config:  params.TestChainConfig,
+			Alloc:   types.GenesisAlloc{testBankAddress: {Balance: testBankFunds}},
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		engine = ethash.NewFaker()
+		signer = types.LatestSigner(gspec.Config)
+	)
+	_, blocks, _ := GenerateChainWithGenesis(gspec, engine, state.TriesInMemory+32, func(i int, gen *BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(gen.TxNonce(testBankAddress), common.BigToAddress(big.NewInt(int64(0x1000+i))), big.NewInt(1000), params.TxGas, gen.BaseFee(), nil), signer, testBankKey)
+		gen.AddTx(tx)
+	})
+	db := rawdb.NewMemoryDatabase()
+	defer db.Close()
+
+	// Flush the state of every block beyond the in-memory window into the
+	// disk, to have stale states.
+	config := DefaultConfig()
+	config.TrieDirtyLimit = 0
+	if !snapshot {
+		config.SnapshotLimit = 0
+	}
+	chain, err := NewBlockChain(db, gspec, engine, config)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if n, err := chain.InsertChain(blocks[:state.TriesInMemory+8]); err != nil {
+		t.Fatalf("Failed to insert block %d: %v", n, err)
+	}
+	stale := blocks[state.TriesInMemory+4].Root()
+	if !rawdb.HasLegacyTrieNode(db, stale) {
+		t.Fatal("Stale state is not persisted")
+	}
+	// Start the pruner after the stale state is persisted, and keep importing
+	// blocks until it selects the target and deletes the stale state.
+	chain.statePruner, err = pruner.NewOnlinePruner(db, chain.triedb, chain.snaps, chain, pruner.OnlineConfig{BloomSize: 256, Rate: 1000000, Interval: time.Hour})
+	if err != nil {
+		t.Fatalf("Failed to create pruner: %v", err)
+	}
+	var (
+		next     = state.TriesInMemory + 8
+		deadline = time.Now().Add(30 * time.Second)
+	)
+	for rawdb.HasLegacyTrieNode(db, stale) {
+		if time.Now().After(deadline) {
+			t.Fatal("Stale state is not pruned")
+		}
+		if next < len(blocks) {
+			if n, err := chain.InsertChain(blocks[next : next+1]); err != nil {
+				t.Fatalf("Failed to insert block %d: %v", n, err)
+			}
+			next++
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	// Wait for the termination of the pruning cycle, the pruner exits after the
+	// sweeping is either completed or interrupted.
+	chain.statePruner.Stop()
+	chain.statePruner = nil
+
+	// Ensure the genesis and the head states are complete
+	for _, root := range []common.Hash{chain.Genesis().Root(), chain.CurrentBlock().Root} {
+		tr, err := trie.NewStateTrie(trie.StateTrieID(root), chain.triedb)
+		if err != nil {
+			t.Fatalf("Failed to open state %x: %v", root, err)
+		}
+		it, err := tr.NodeIterator(nil)
+		if err != nil {
+			t.Fatalf("Failed to iterate state %x: %v", root, err)
+		}
+		for it.Next(true) {
+		}
+		if it.Error() != nil {
+			t.Fatalf("State %x is incomplete: %v", root, it.Error())
+		}
+	}
+	statedb, err := chain.State()
+	if err != nil {
+		t.Fatalf("Failed to open head state: %v", err)
+	}
+	for i := 0; i < next; i++ {
+		if balance := statedb.GetBalance(common.BigToAddress(big.NewInt(int64(0x1000 + i)))); balance.Uint64() != 1000 {
+			t.Fatalf("Unexpected balance of account %d, want %d, got %d", i, 1000, balance)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package statediff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const (
+	// FormatJSON is the format of newline delimited JSON objects.
+	FormatJSON = "json"
+
+	// FormatRLP is the format of concatenated RLP lists.
+	FormatRLP = "rlp"
+)
+
+// clientQueue is the number of diffs buffered for a socket client, which is
+// disconnected if it falls behind further.
+const clientQueue = 1024
+
+// exportQueue is the number of diffs buffered for the export, the block import
+// is only blocked by the export if it falls behind further.
+const exportQueue = 256
+
+// errClosed is returned if a diff is exported after the exporter is closed.
+var errClosed = errors.New("state diff exporter closed")
+
+// Config contains the settings of the state diff export.
+type Config struct {
+	Format  string // Encoding of the diffs, either FormatJSON or FormatRLP
+	Path    string // Directory of the rotating diff files, empty to disable
+	MaxSize int    // Maximum size in megabytes of a diff file before it gets rotated
+	Socket  string // Path of the unix socket streaming the diffs, empty to disable
+}
+
+// Exporter writes the diffs into the rotating files and streams them to the
+// clients connected to the local socket. The diffs are encoded and written in
+// the background, in the order of their submission.
+type Exporter struct {
+	format   string
+	file     *lumberjack.Logger // Rotating file of the diffs, nil if not enabled
+	listener net.Listener       // Socket listener, nil if not enabled
+
+	queue       chan *Diff    // Diffs submitted for the export
+	queueClosed bool          // Whether the queue is closed, protected by queueLock
+	queueLock   sync.RWMutex  // Lock preventing the closure of the queue while submitting
+	done        chan struct{} // Closed when all the queued diffs are exported
+
+	clients map[net.Conn]chan []byte
+	closed  bool
+	lock    sync.Mutex
+	wg      sync.WaitGroup
+}
+
+// New creates the state diff exporter with the given configuration.
+func New(config Config) (*Exporter, error) {
+	if config.Format != FormatJSON && config.Format != FormatRLP {
+		return nil, fmt.Errorf("unknown state diff format %q", config.Format)
+	}
+	if config.Path == "" && config.Socket == "" {
+		return nil, errors.New("neither state diff path nor socket is specified")
+	}
+	e := &Exporter{
+		format:  config.Format,
+		queue:   make(chan *Diff, exportQueue),
+		done:    make(chan struct{}),
+		clients: make(map[net.Conn]chan []byte),
+	}
+	if config.Path != "" {
+		e.file = &lumberjack.Logger{
+			Filename: filepath.Join(config.Path, "statediff."+config.Format),
+		}
+		if config.MaxSize > 0 {
+			e.file.MaxSize = config.MaxSize
+		}
+	}
+	if config.Socket != "" {
+		// Ensure the socket path exists and remove any previous leftover
+		if err := os.MkdirAll(filepath.Dir(config.Socket), 0751); err != nil {
+			return nil, err
+		}
+		os.Remove(config.Socket)
+		listener, err := net.Listen("unix", config.Socket)
+		if err != nil {
+			return nil, err
+		}
+		os.Chmod(config.Socket, 0600)
+		e.listener = listener
+
+		e.wg.Add(1)
+		go e.accept()
+	}
+	go e.loop()
+
+	log.Info("Enabled state diff export", "format", config.Format, "path", config.Path, "socket", config.Socket)
+	return e, nil
+}
+
+// Export queues the diff for the export, blocking only if the queue is full. The
+// failures of the export itself are logged, as they can't be attributed to the
+// caller anymore.
+func (e *Exporter) Export(diff *Diff) error {
+	e.queueLock.RLock()
+	defer e.queueLock.RUnlock()
+
+	if e.queueClosed {
+		return errClosed
+	}
+	e.queue <- diff
+	return nil
+}
+
+// loop exports the queued diffs until the queue is closed.
+func (e *Exporter) loop() {
+	defer close(e.done)
+
+	for diff := range e.queue {
+		if err := e.export(diff); err != nil {
+			log.Error("Failed to export state diff", "number", diff.Number, "hash", diff.Hash, "err", err)
+		}
+	}
+}
+
+// export writes the diff into the file and queues it to all the socket clients.
+func (e *Exporter) export(diff *Diff) error {
+	blob, err := e.encode(diff)
+	if err != nil {
+		return err
+	}
+	if e.file != nil {
+		if _, err := e.file.Write(blob); err != nil {
+			return err
+		}
+	}
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	for conn, queue := range e.clients {
+		select {
+		case queue <- blob:
+		default:
+			log.Warn("Dropping lagging state diff client", "addr", conn.RemoteAddr())
+			e.drop(conn)
+		}
+	}
+	return nil
+}
+
+// Close exports the queued diffs, stops the socket listener, disconnects all the
+// clients and closes the diff file.
+func (e *Exporter) Close() error {
+	e.queueLock.Lock()
+	if !e.queueClosed {
+		e.queueClosed = true
+		close(e.queue)
+	}
+	e.queueLock.Unlock()
+	<-e.done
+
+	if e.listener != nil {
+		e.listener.Close()
+	}
+	e.lock.Lock()
+	e.closed = true
+	for conn := range e.clients {
+		e.drop(conn)
+	}
+	e.lock.Unlock()
+	e.wg.Wait()
+
+	if e.file != nil {
+		return e.file.Close()
+	}
+	return nil
+}
+
+// encode serializes the diff in the configured format.
+func (e *Exporter) encode(diff *Diff) ([]byte, error) {
+	if e.format == FormatRLP {
+		return rlp.EncodeToBytes(diff)
+	}
+	blob, err := json.Marshal(diff)
+	if err != nil {
+		return nil, err
+	}
+	return append(blob, '\n'), nil
+}
+
+// accept registers the incoming socket connections until the listener is closed.
+func (e *Exporter) accept() {
+	defer e.wg.Done()
+
+	for {
+		conn, err := e.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				log.Warn("State diff socket failed", "err", err)
+			}
+			return
+		}
+		queue := make(chan []byte, clientQueue)
+
+		e.lock.Lock()
+		if e.closed {
+			e.lock.Unlock()
+			conn.Close()
+			return
+		}
+		e.clients[conn] = queue
+		e.lock.Unlock()
+
+		e.wg.Add(1)
+		go e.serve(conn, queue)
+	}
+}
+
+// serve streams the queued diffs to the client until the queue is closed or the
+// connection fails.
+func (e *Exporter) serve(conn net.Conn, queue chan []byte) {
+	defer e.wg.Done()
+
+	for blob := range queue {
+		if _, err := conn.Write(blob); err != nil {
+			log.Debug("State diff client disconnected", "err", err)
+
+			e.lock.Lock()
+			e.drop(conn)
+			e.lock.Unlock()
+			return
+		}
+	}
+}
+
+// drop disconnects the client, the lock is assumed to be held.
+func (e *Exporter) drop(conn net.Conn) {
+	queue, ok := e.clients[conn]
+	if !ok {
+		return
+	}
+	delete(e.clients, conn)
+	close(queue)
+	conn.Close()
+}
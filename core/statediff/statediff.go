@@ -0,0 +1,173 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package statediff implements the export of the state changes made by every
+// imported block, derived from the state set committed into the trie database.
+//
+// A diff is emitted for each block whose state is written, in the order of the
+// import. The blocks of the side chains are exported too, consumers are expected
+// to follow the canonical chain through the block hash and the parent hash.
+//
+// Two encodings of the diffs are supported:
+//
+//   - "json": every diff is a JSON object terminated by a newline character.
+//     Hashes, addresses and byte arrays are hex encoded with 0x prefix, the
+//     balances are decimal strings.
+//   - "rlp": every diff is an RLP list, concatenated without any framing as the
+//     RLP items are self-delimiting.
+//
+// A diff has the following structure, the RLP encoding follows the field order:
+//
+//	Diff        = [number, hash, parentHash, stateRoot, accounts: [AccountDiff...]]
+//	AccountDiff = [address, prev: Account or [], post: Account or [], code, storage: [SlotDiff...]]
+//	Account     = [nonce, balance, storageRoot, codeHash]
+//	SlotDiff    = [hash, key or "", prev, post]
+//
+// The accounts are sorted by address and the slots by the hash of the slot key.
+// The prev and post fields of an account are empty, i.e. null in JSON and an empty
+// list in RLP, if the account doesn't exist before or after the block. The code is the deployed contract code, empty if the
+// code isn't changed. The slot values are 32 bytes long, zero meaning the slot is
+// empty. The raw slot key is only available since the Cancun fork, before which
+// only the hash is exported.
+package statediff
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/holiman/uint256"
+)
+
+// Diff contains the state changes made by a block.
+type Diff struct {
+	Number     uint64         `json:"number"`
+	Hash       common.Hash    `json:"hash"`
+	ParentHash common.Hash    `json:"parentHash"`
+	Root       common.Hash    `json:"stateRoot"`
+	Accounts   []*AccountDiff `json:"accounts"`
+}
+
+// AccountDiff contains the changes of a single account.
+type AccountDiff struct {
+	Address common.Address `json:"address"`
+	Prev    *Account       `json:"prev"        rlp:"nil"`
+	Post    *Account       `json:"post"        rlp:"nil"`
+	Code    hexutil.Bytes  `json:"code,omitempty"`
+	Storage []*SlotDiff    `json:"storage,omitempty"`
+}
+
+// Account is the content of an account.
+type Account struct {
+	Nonce       uint64       `json:"nonce"`
+	Balance     *uint256.Int `json:"balance"`
+	StorageRoot common.Hash  `json:"storageRoot"`
+	CodeHash    common.Hash  `json:"codeHash"`
+}
+
+// SlotDiff contains the change of a single storage slot.
+type SlotDiff struct {
+	Hash common.Hash  `json:"hash"`
+	Key  *common.Hash `json:"key,omitempty" rlp:"nil"`
+	Prev common.Hash  `json:"prev"`
+	Post common.Hash  `json:"post"`
+}
+
+// NewDiff assembles the diff of the given block from the state set committed
+// into the trie database, along with the contract codes deployed in the block.
+func NewDiff(header *types.Header, states *triedb.StateSet, codes map[common.Address][]byte) (*Diff, error) {
+	diff := &Diff{
+		Number:     header.Number.Uint64(),
+		Hash:       header.Hash(),
+		ParentHash: header.ParentHash,
+		Root:       header.Root,
+		Accounts:   []*AccountDiff{},
+	}
+	// All the mutated accounts are tracked in the origin set, keyed by address,
+	// while the mutated values are keyed by the hash of the address.
+	for addr, origin := range states.AccountsOrigin {
+		var (
+			err      error
+			addrHash = crypto.Keccak256Hash(addr.Bytes())
+			account  = &AccountDiff{Address: addr, Code: codes[addr]}
+		)
+		if account.Prev, err = decodeAccount(origin); err != nil {
+			return nil, fmt.Errorf("account %x: %w", addr, err)
+		}
+		if account.Post, err = decodeAccount(states.Accounts[addrHash]); err != nil {
+			return nil, fmt.Errorf("account %x: %w", addr, err)
+		}
+		for key, origin := range states.StoragesOrigin[addr] {
+			slot := &SlotDiff{Hash: key}
+			if states.RawStorageKey {
+				slot.Hash = crypto.Keccak256Hash(key.Bytes())
+				slot.Key = &key
+			}
+			if slot.Prev, err = decodeSlot(origin); err != nil {
+				return nil, fmt.Errorf("slot %x of account %x: %w", key, addr, err)
+			}
+			if slot.Post, err = decodeSlot(states.Storages[addrHash][slot.Hash]); err != nil {
+				return nil, fmt.Errorf("slot %x of account %x: %w", key, addr, err)
+			}
+			account.Storage = append(account.Storage, slot)
+		}
+		slices.SortFunc(account.Storage, func(a, b *SlotDiff) int {
+			return bytes.Compare(a.Hash.Bytes(), b.Hash.Bytes())
+		})
+		diff.Accounts = append(diff.Accounts, account)
+	}
+	slices.SortFunc(diff.Accounts, func(a, b *AccountDiff) int {
+		return bytes.Compare(a.Address.Bytes(), b.Address.Bytes())
+	})
+	return diff, nil
+}
+
+// decodeAccount decodes the account in the slim RLP encoding, nil is returned
+// if the account doesn't exist.
+func decodeAccount(blob []byte) (*Account, error) {
+	if len(blob) == 0 {
+		return nil, nil
+	}
+	account, err := types.FullAccount(blob)
+	if err != nil {
+		return nil, err
+	}
+	return &Account{
+		Nonce:       account.Nonce,
+		Balance:     account.Balance,
+		StorageRoot: account.Root,
+		CodeHash:    common.BytesToHash(account.CodeHash),
+	}, nil
+}
+
+// decodeSlot decodes the slot value in the prefix-zero-trimmed RLP encoding,
+// zero is returned if the slot is empty.
+func decodeSlot(blob []byte) (common.Hash, error) {
+	if len(blob) == 0 {
+		return common.Hash{}, nil
+	}
+	_, content, _, err := rlp.Split(blob)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(content), nil
+}
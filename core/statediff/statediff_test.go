@@ -0,0 +1,249 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package statediff
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/holiman/uint256"
+)
+
+var (
+	testAddrA = common.HexToAddress("0xaaaa")
+	testAddrB = common.HexToAddress("0xbbbb")
+	testAddrC = common.HexToAddress("0xcccc")
+	testSlot1 = common.HexToHash("0x01")
+	testSlot2 = common.HexToHash("0x02")
+	testCode  = []byte{0x60, 0x00, 0x60, 0x00, 0xf3}
+)
+
+// makeTestDiff commits a state transition with account, storage and code
+// changes and assembles the diff from the resulting state set.
+func makeTestDiff(t *testing.T, rawStorageKey bool) *Diff {
+	t.Helper()
+
+	sdb := state.NewDatabaseForTesting()
+	statedb, _ := state.New(types.EmptyRootHash, sdb)
+	statedb.SetBalance(testAddrA, uint256.NewInt(100), tracing.BalanceChangeUnspecified)
+	statedb.SetBalance(testAddrB, uint256.NewInt(200), tracing.BalanceChangeUnspecified)
+	statedb.SetState(testAddrB, testSlot1, common.HexToHash("0x11"))
+	statedb.SetState(testAddrB, testSlot2, common.HexToHash("0x22"))
+	parent, err := statedb.Commit(0, true, rawStorageKey)
+	if err != nil {
+		t.Fatalf("Failed to commit state: %v", err)
+	}
+	statedb, _ = state.New(parent, sdb)
+	statedb.AddBalance(testAddrA, uint256.NewInt(1), tracing.BalanceChangeUnspecified)
+	statedb.SetState(testAddrB, testSlot1, common.HexToHash("0x33"))
+	statedb.SetState(testAddrB, testSlot2, common.Hash{})
+	statedb.SetNonce(testAddrC, 1, tracing.NonceChangeUnspecified)
+	statedb.SetCode(testAddrC, testCode)
+	root, states, codes, err := statedb.CommitWithStateSet(1, true, rawStorageKey)
+	if err != nil {
+		t.Fatalf("Failed to commit state: %v", err)
+	}
+	header := &types.Header{Number: big.NewInt(1), ParentHash: common.HexToHash("0x1234"), Root: root}
+	diff, err := NewDiff(header, states, codes)
+	if err != nil {
+		t.Fatalf("Failed to assemble diff: %v", err)
+	}
+	if diff.Number != 1 || diff.Hash != header.Hash() || diff.ParentHash != header.ParentHash || diff.Root != root {
+		t.Fatalf("Unexpected block metadata: %+v", diff)
+	}
+	return diff
+}
+
+func TestNewDiff(t *testing.T) {
+	for _, raw := range []bool{false, true} {
+		diff := makeTestDiff(t, raw)
+		if len(diff.Accounts) != 3 {
+			t.Fatalf("Unexpected number of accounts, want 3, got %d", len(diff.Accounts))
+		}
+		a, b, c := diff.Accounts[0], diff.Accounts[1], diff.Accounts[2]
+
+		// Balance change
+		if a.Address != testAddrA || a.Prev.Balance.Uint64() != 100 || a.Post.Balance.Uint64() != 101 || len(a.Code) != 0 || len(a.Storage) != 0 {
+			t.Fatalf("Unexpected diff of account A: %+v", a)
+		}
+		// Storage changes, sorted by slot hash
+		if b.Address != testAddrB || b.Prev.StorageRoot == b.Post.StorageRoot || len(b.Storage) != 2 {
+			t.Fatalf("Unexpected diff of account B: %+v", b)
+		}
+		want := map[common.Hash][2]common.Hash{
+			testSlot1: {common.HexToHash("0x11"), common.HexToHash("0x33")},
+			testSlot2: {common.HexToHash("0x22"), {}},
+		}
+		for i, slot := range b.Storage {
+			if i > 0 && bytes.Compare(b.Storage[i-1].Hash.Bytes(), slot.Hash.Bytes()) >= 0 {
+				t.Fatal("Storage slots are not sorted")
+			}
+			var key common.Hash
+			for k := range want {
+				if crypto.Keccak256Hash(k.Bytes()) == slot.Hash {
+					key = k
+				}
+			}
+			if raw && (slot.Key == nil || *slot.Key != key) {
+				t.Fatalf("Unexpected raw key of slot %x: %v", slot.Hash, slot.Key)
+			}
+			if !raw && slot.Key != nil {
+				t.Fatalf("Unexpected raw key of slot %x: %v", slot.Hash, slot.Key)
+			}
+			if values := want[key]; slot.Prev != values[0] || slot.Post != values[1] {
+				t.Fatalf("Unexpected values of slot %x, want %x, got %x %x", key, values, slot.Prev, slot.Post)
+			}
+		}
+		// Account creation with code
+		if c.Address != testAddrC || c.Prev != nil || c.Post.Nonce != 1 || c.Post.CodeHash != crypto.Keccak256Hash(testCode) || !bytes.Equal(c.Code, testCode) {
+			t.Fatalf("Unexpected diff of account C: %+v", c)
+		}
+	}
+}
+
+func TestExportFile(t *testing.T) {
+	diff := makeTestDiff(t, true)
+
+	for _, format := range []string{FormatJSON, FormatRLP} {
+		dir := t.TempDir()
+		exporter, err := New(Config{Format: format, Path: dir})
+		if err != nil {
+			t.Fatalf("Failed to create exporter: %v", err)
+		}
+		for i := 0; i < 3; i++ {
+			if err := exporter.Export(diff); err != nil {
+				t.Fatalf("Failed to export diff: %v", err)
+			}
+		}
+		if err := exporter.Close(); err != nil {
+			t.Fatalf("Failed to close exporter: %v", err)
+		}
+		if err := exporter.Export(diff); err == nil {
+			t.Fatal("Exported diff after closing the exporter")
+		}
+		blob, err := os.ReadFile(filepath.Join(dir, "statediff."+format))
+		if err != nil {
+			t.Fatalf("Failed to read diff file: %v", err)
+		}
+		got := decodeDiffs(t, format, bytes.NewReader(blob), 3)
+		for _, d := range got {
+			if !equalDiff(d, diff) {
+				t.Fatalf("Diff mismatch in %s format, want %+v, got %+v", format, diff, d)
+			}
+		}
+	}
+}
+
+func TestExportSocket(t *testing.T) {
+	diff := makeTestDiff(t, false)
+
+	path := filepath.Join(t.TempDir(), "statediff.ipc")
+	exporter, err := New(Config{Format: FormatRLP, Socket: path})
+	if err != nil {
+		t.Fatalf("Failed to create exporter: %v", err)
+	}
+	defer exporter.Close()
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer conn.Close()
+
+	// Wait for the registration of the client
+	for {
+		exporter.lock.Lock()
+		n := len(exporter.clients)
+		exporter.lock.Unlock()
+		if n == 1 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := exporter.Export(diff); err != nil {
+		t.Fatalf("Failed to export diff: %v", err)
+	}
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if got := decodeDiffs(t, FormatRLP, conn, 1); !equalDiff(got[0], diff) {
+		t.Fatalf("Diff mismatch, want %+v, got %+v", diff, got[0])
+	}
+}
+
+// decodeDiffs decodes the given number of diffs from the stream.
+func decodeDiffs(t *testing.T, format string, r io.Reader, n int) []*Diff {
+	t.Helper()
+
+	var (
+		diffs  []*Diff
+		stream = rlp.NewStream(r, 0)
+		reader = bufio.NewReader(r)
+	)
+	for i := 0; i < n; i++ {
+		diff := new(Diff)
+		if format == FormatRLP {
+			if err := stream.Decode(diff); err != nil {
+				t.Fatalf("Failed to decode diff %d: %v", i, err)
+			}
+		} else {
+			line, err := reader.ReadBytes('\n')
+			if err != nil {
+				t.Fatalf("Failed to read diff %d: %v", i, err)
+			}
+			if err := json.Unmarshal(line, diff); err != nil {
+				t.Fatalf("Failed to decode diff %d: %v", i, err)
+			}
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs
+}
+
+// equalDiff reports whether the two diffs are identical, ignoring the difference
+// between the nil and empty slices.
+func equalDiff(a, b *Diff) bool {
+	blobA, _ := json.Marshal(a)
+	blobB, _ := json.Marshal(b)
+	return bytes.Equal(blobA, blobB)
+}
+
+// TestEncodeAbsentAccount tests that the absent accounts are encoded as empty
+// lists in RLP, as documented.
+func TestEncodeAbsentAccount(t *testing.T) {
+	blob, err := rlp.EncodeToBytes(&AccountDiff{Address: testAddrC})
+	if err != nil {
+		t.Fatalf("Failed to encode account diff: %v", err)
+	}
+	// [address, [], [], "", []]
+	want := append(append([]byte{0xd9, 0x94}, testAddrC.Bytes()...), 0xc0, 0xc0, 0x80, 0xc0)
+	if !bytes.Equal(blob, want) {
+		t.Fatalf("Unexpected encoding, want %x, got %x", want, blob)
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package stateless
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ContractUsage is the part of a witness required by a single contract.
+type ContractUsage struct {
+	StorageNodes int `json:"storageNodes"` // Number of storage trie nodes
+	StorageBytes int `json:"storageBytes"` // Total size of the storage trie nodes
+	CodeBytes    int `json:"codeBytes"`    // Size of the contract code
+}
+
+// Usage is the part of a witness attributed to a transaction, or to the block
+// processing outside of the transactions (system calls, withdrawals, rewards).
+type Usage struct {
+	AccountNodes int `json:"accountNodes"` // Number of account trie nodes
+	AccountBytes int `json:"accountBytes"` // Total size of the account trie nodes
+	StorageNodes int `json:"storageNodes"` // Number of storage trie nodes
+	StorageBytes int `json:"storageBytes"` // Total size of the storage trie nodes
+	Codes        int `json:"codes"`        // Number of contract codes
+	CodeBytes    int `json:"codeBytes"`    // Total size of the contract codes
+
+	Contracts map[common.Address]*ContractUsage `json:"contracts,omitempty"` // Breakdown by contract
+}
+
+// contract returns the usage entry of the given contract, creating it if needed.
+func (u *Usage) contract(addr common.Address) *ContractUsage {
+	if u.Contracts == nil {
+		u.Contracts = make(map[common.Address]*ContractUsage)
+	}
+	usage := u.Contracts[addr]
+	if usage == nil {
+		usage = new(ContractUsage)
+		u.Contracts[addr] = usage
+	}
+	return usage
+}
+
+// Remainder is the part of a witness which could not be attributed, e.g. the
+// sibling nodes resolved when a trie node is collapsed by a deletion, or code
+// deployed within the block itself.
+type Remainder struct {
+	Nodes     int `json:"nodes"`     // Number of trie nodes
+	Bytes     int `json:"bytes"`     // Total size of the trie nodes
+	Codes     int `json:"codes"`     // Number of contract codes
+	CodeBytes int `json:"codeBytes"` // Total size of the contract codes
+}
+
+// Attribution splits the contents of a witness among the transactions of the
+// block it was collected for. Every trie node and contract code is attributed
+// to the first transaction which required it, so the sum of the parts always
+// equals the size of the whole witness.
+//
+// Attribution is not safe for concurrent use.
+type Attribution struct {
+	Txs          []*Usage  // Witness parts attributed to the individual transactions
+	Block        *Usage    // Witness part attributed to the block level processing
+	Unattributed Remainder // Witness part not attributed to anything
+
+	witness *Witness            // Witness to split up
+	nodes   map[string]struct{} // Set of trie nodes already attributed
+	codes   map[string]struct{} // Set of contract codes already attributed
+}
+
+// NewAttribution creates an empty attribution of the given witness to a block
+// with the given number of transactions.
+func NewAttribution(witness *Witness, txs int) *Attribution {
+	a := &Attribution{
+		Txs:     make([]*Usage, txs),
+		Block:   new(Usage),
+		witness: witness,
+		nodes:   make(map[string]struct{}),
+		codes:   make(map[string]struct{}),
+	}
+	for i := range a.Txs {
+		a.Txs[i] = new(Usage)
+	}
+	return a
+}
+
+// usage returns the usage entry of the given transaction, or the block level
+// entry if the index is negative.
+func (a *Attribution) usage(tx int) *Usage {
+	if tx < 0 {
+		return a.Block
+	}
+	return a.Txs[tx]
+}
+
+// claimNode marks the node as attributed, returning false if it's not part of
+// the witness or has already been attributed.
+func (a *Attribution) claimNode(node []byte) bool {
+	if _, ok := a.witness.State[string(node)]; !ok {
+		return false
+	}
+	if _, ok := a.nodes[string(node)]; ok {
+		return false
+	}
+	a.nodes[string(node)] = struct{}{}
+	return true
+}
+
+// AddAccountNodes attributes the account trie nodes to the given transaction,
+// or to the block if the index is negative. Nodes which are not part of the
+// witness, or attributed earlier, are ignored.
+func (a *Attribution) AddAccountNodes(tx int, nodes [][]byte) {
+	usage := a.usage(tx)
+	for _, node := range nodes {
+		if a.claimNode(node) {
+			usage.AccountNodes++
+			usage.AccountBytes += len(node)
+		}
+	}
+}
+
+// AddStorageNodes attributes the storage trie nodes of the given contract to the
+// transaction, or to the block if the index is negative. Nodes which are not
+// part of the witness, or attributed earlier, are ignored.
+func (a *Attribution) AddStorageNodes(tx int, addr common.Address, nodes [][]byte) {
+	usage := a.usage(tx)
+	for _, node := range nodes {
+		if a.claimNode(node) {
+			usage.StorageNodes++
+			usage.StorageBytes += len(node)
+
+			contract := usage.contract(addr)
+			contract.StorageNodes++
+			contract.StorageBytes += len(node)
+		}
+	}
+}
+
+// AddCode attributes the code of the given contract to the transaction, or to
+// the block if the index is negative. Code which is not part of the witness,
+// or attributed earlier, is ignored.
+func (a *Attribution) AddCode(tx int, addr common.Address, code []byte) {
+	if _, ok := a.witness.Codes[string(code)]; !ok {
+		return
+	}
+	if _, ok := a.codes[string(code)]; ok {
+		return
+	}
+	a.codes[string(code)] = struct{}{}
+
+	usage := a.usage(tx)
+	usage.Codes++
+	usage.CodeBytes += len(code)
+	usage.contract(addr).CodeBytes += len(code)
+}
+
+// Finalize accounts the trie nodes and codes of the witness not attributed so
+// far as the unattributed remainder. No more items should be attributed after.
+func (a *Attribution) Finalize() {
+	a.Unattributed = Remainder{}
+	for node := range a.witness.State {
+		if _, ok := a.nodes[node]; !ok {
+			a.Unattributed.Nodes++
+			a.Unattributed.Bytes += len(node)
+		}
+	}
+	for code := range a.witness.Codes {
+		if _, ok := a.codes[code]; !ok {
+			a.Unattributed.Codes++
+			a.Unattributed.CodeBytes += len(code)
+		}
+	}
+}
@@ -59,23 +59,36 @@ type txIndexer struct {
 	tail atomic.Pointer[uint64]
 
 	// cutoff denotes the block number before which the chain segment should
-	// be pruned and not available locally.
-	cutoff uint64
+	// be pruned and not available locally, nor from the era files backing the
+	// pruned history. The transactions of the archived blocks are indexed from
+	// the era files. It advances if the chain history is pruned in a rolling
+	// fashion.
+	cutoff atomic.Uint64
 	db     ethdb.Database
-	term   chan chan struct{}
-	closed chan struct{}
+
+	unindexCh chan *unindexRequest
+	term      chan chan struct{}
+	closed    chan struct{}
+}
+
+// unindexRequest is a request of the history pruner to remove the transaction
+// indexes below the given block, whose history is about to be pruned.
+type unindexRequest struct {
+	floor uint64
+	done  chan struct{} // Closed once the indexer processed the request
 }
 
 // newTxIndexer initializes the transaction indexer.
 func newTxIndexer(limit uint64, chain *BlockChain) *txIndexer {
-	cutoff, _ := chain.HistoryPruningCutoff()
+	cutoff := chain.HistoryFloor()
 	indexer := &txIndexer{
-		limit:  limit,
-		cutoff: cutoff,
-		db:     chain.db,
-		term:   make(chan chan struct{}),
-		closed: make(chan struct{}),
+		limit:     limit,
+		db:        chain.db,
+		unindexCh: make(chan *unindexRequest),
+		term:      make(chan chan struct{}),
+		closed:    make(chan struct{}),
 	}
+	indexer.cutoff.Store(cutoff)
 	indexer.head.Store(indexer.resolveHead())
 	indexer.tail.Store(rawdb.ReadTxIndexTail(chain.db))
 
@@ -83,10 +96,10 @@ func newTxIndexer(limit uint64, chain *BlockChain) *txIndexer {
 
 	var msg string
 	if limit == 0 {
-		if indexer.cutoff == 0 {
+		if cutoff == 0 {
 			msg = "entire chain"
 		} else {
-			msg = fmt.Sprintf("blocks since #%d", indexer.cutoff)
+			msg = fmt.Sprintf("blocks since #%d", cutoff)
 		}
 	} else {
 		msg = fmt.Sprintf("last %d blocks", limit)
@@ -100,20 +113,30 @@ func newTxIndexer(limit uint64, chain *BlockChain) *txIndexer {
 // If the stop channel is closed, the task should terminate as soon as possible.
 // The done channel will be closed once the task is complete.
 //
-// Existing transaction indexes are assumed to be valid, with both the head
-// and tail above the configured cutoff.
+// Existing transaction indexes are assumed to be valid, with the head above
+// the configured cutoff. The indexes below the cutoff are removed first.
 func (indexer *txIndexer) run(head uint64, stop chan struct{}, done chan struct{}) {
 	defer func() { close(done) }()
 
 	// Short circuit if the chain is either empty, or entirely below the
 	// cutoff point.
-	if head == 0 || head < indexer.cutoff {
+	cutoff := indexer.cutoff.Load()
+	if head == 0 || head < cutoff {
 		return
 	}
 	// The tail flag is not existent, it means the node is just initialized
 	// and all blocks in the chain (part of them may from ancient store) are
 	// not indexed yet, index the chain according to the configured limit.
 	tail := rawdb.ReadTxIndexTail(indexer.db)
+
+	// The cutoff was advanced by the history pruner, remove the indexes of the
+	// blocks below it while they are still available.
+	if tail != nil && *tail < cutoff {
+		rawdb.UnindexTransactions(indexer.db, *tail, cutoff, stop, false)
+		if tail = rawdb.ReadTxIndexTail(indexer.db); tail == nil || *tail < cutoff {
+			return
+		}
+	}
 	if tail == nil {
 		// Determine the first block for transaction indexing, taking the
 		// configured cutoff point into account.
@@ -121,7 +144,7 @@ func (indexer *txIndexer) run(head uint64, stop chan struct{}, done chan struct{
 		if indexer.limit != 0 && head >= indexer.limit {
 			from = head - indexer.limit + 1
 		}
-		from = max(from, indexer.cutoff)
+		from = max(from, cutoff)
 		rawdb.IndexTransactions(indexer.db, from, head+1, stop, true)
 		return
 	}
@@ -129,7 +152,7 @@ func (indexer *txIndexer) run(head uint64, stop chan struct{}, done chan struct{
 	// present), while the whole chain are requested for indexing.
 	if indexer.limit == 0 || head < indexer.limit {
 		if *tail > 0 {
-			from := max(uint64(0), indexer.cutoff)
+			from := max(uint64(0), cutoff)
 			rawdb.IndexTransactions(indexer.db, from, *tail, stop, true)
 		}
 		return
@@ -137,7 +160,7 @@ func (indexer *txIndexer) run(head uint64, stop chan struct{}, done chan struct{
 	// The tail flag is existent, adjust the index range according to configured
 	// limit and the latest chain head.
 	from := head - indexer.limit + 1
-	from = max(from, indexer.cutoff)
+	from = max(from, cutoff)
 	if from < *tail {
 		// Reindex a part of missing indices and rewind index tail to HEAD-limit
 		rawdb.IndexTransactions(indexer.db, from, *tail, stop, true)
@@ -154,6 +177,7 @@ func (indexer *txIndexer) run(head uint64, stop chan struct{}, done chan struct{
 // * The index tail is below the configured cutoff, but it is not empty.
 func (indexer *txIndexer) repair(head uint64) {
 	// If the transactions haven't been indexed yet, nothing to repair
+	cutoff := indexer.cutoff.Load()
 	tail := rawdb.ReadTxIndexTail(indexer.db)
 	if tail == nil {
 		return
@@ -177,7 +201,7 @@ func (indexer *txIndexer) repair(head uint64) {
 	// removing the tail of transaction indexing and purges the
 	// transaction indexes. **It's not a common case, as the cutoff
 	// is usually defined below the chain head**.
-	if head < indexer.cutoff {
+	if head < cutoff {
 		// A crash may occur between the two delete operations,
 		// potentially leaving dangling indexes in the database.
 		// However, this is considered acceptable.
@@ -190,24 +214,24 @@ func (indexer *txIndexer) repair(head uint64) {
 		indexer.tail.Store(nil)
 		rawdb.DeleteTxIndexTail(indexer.db)
 		rawdb.DeleteAllTxLookupEntries(indexer.db, nil)
-		log.Warn("Purge transaction indexes", "head", head, "cutoff", indexer.cutoff)
+		log.Warn("Purge transaction indexes", "head", head, "cutoff", cutoff)
 		return
 	}
 
 	// The chain head is above the cutoff while the tail is below the
 	// cutoff. Shift the tail to the cutoff point and remove the indexes
 	// below.
-	if *tail < indexer.cutoff {
+	if *tail < cutoff {
 		// A crash may occur between the two delete operations,
 		// potentially leaving dangling indexes in the database.
 		// However, this is considered acceptable.
-		indexer.tail.Store(&indexer.cutoff)
-		rawdb.WriteTxIndexTail(indexer.db, indexer.cutoff)
+		indexer.tail.Store(&cutoff)
+		rawdb.WriteTxIndexTail(indexer.db, cutoff)
 		rawdb.DeleteAllTxLookupEntries(indexer.db, func(txhash common.Hash, blob []byte) bool {
 			n := rawdb.DecodeTxLookupEntry(blob, indexer.db)
-			return n != nil && *n < indexer.cutoff
+			return n != nil && *n < cutoff
 		})
-		log.Warn("Purge transaction indexes below cutoff", "tail", *tail, "cutoff", indexer.cutoff)
+		log.Warn("Purge transaction indexes below cutoff", "tail", *tail, "cutoff", cutoff)
 	}
 }
 
@@ -235,6 +259,10 @@ func (indexer *txIndexer) loop(chain *BlockChain) {
 		done   chan struct{} // Non-nil if background routine is active
 		headCh = make(chan ChainHeadEvent)
 		sub    = chain.SubscribeChainHeadEvent(headCh)
+
+		floor   uint64          // Highest block below which the history pruner requested unindexing
+		serving *unindexRequest // Unindexing request handled by the active routine
+		queued  *unindexRequest // Unindexing request waiting for the active routine to finish
 	)
 	defer sub.Unsubscribe()
 
@@ -242,6 +270,15 @@ func (indexer *txIndexer) loop(chain *BlockChain) {
 	head := indexer.head.Load()
 	indexer.repair(head)
 
+	run := func(head uint64) {
+		// Pick up the advanced cutoff if the chain history is pruned in a
+		// rolling fashion. The history about to be pruned is already excluded.
+		indexer.cutoff.Store(max(chain.HistoryFloor(), floor))
+
+		stop = make(chan struct{})
+		done = make(chan struct{})
+		go indexer.run(head, stop, done)
+	}
 	// Launch the initial processing if chain is not empty (head != genesis).
 	// This step is useful in these scenarios that chain has no progress.
 	if head != 0 {
@@ -254,9 +291,19 @@ func (indexer *txIndexer) loop(chain *BlockChain) {
 		case h := <-headCh:
 			indexer.head.Store(h.Header.Number.Uint64())
 			if done == nil {
-				stop = make(chan struct{})
-				done = make(chan struct{})
-				go indexer.run(h.Header.Number.Uint64(), stop, done)
+				run(h.Header.Number.Uint64())
+			}
+
+		case req := <-indexer.unindexCh:
+			floor = max(floor, req.floor)
+			if done == nil {
+				serving = req
+				run(indexer.head.Load())
+			} else {
+				if queued != nil {
+					close(queued.done)
+				}
+				queued = req
 			}
 
 		case <-done:
@@ -264,6 +311,15 @@ func (indexer *txIndexer) loop(chain *BlockChain) {
 			done = nil
 			indexer.tail.Store(rawdb.ReadTxIndexTail(indexer.db))
 
+			if serving != nil {
+				close(serving.done)
+				serving = nil
+			}
+			if queued != nil {
+				serving, queued = queued, nil
+				run(indexer.head.Load())
+			}
+
 		case ch := <-indexer.term:
 			if stop != nil {
 				close(stop)
@@ -278,11 +334,36 @@ func (indexer *txIndexer) loop(chain *BlockChain) {
 	}
 }
 
+// unindex requests the removal of the transaction indexes below the given block,
+// whose history is about to be pruned, and waits until the indexer processed
+// the request. It returns false if the indexes are still present, either since
+// the request was aborted or the unindexing was interrupted.
+func (indexer *txIndexer) unindex(floor uint64, stop chan struct{}) bool {
+	req := &unindexRequest{floor: floor, done: make(chan struct{})}
+	select {
+	case indexer.unindexCh <- req:
+	case <-stop:
+		return false
+	case <-indexer.closed:
+		return false
+	}
+	select {
+	case <-req.done:
+	case <-stop:
+		return false
+	case <-indexer.closed:
+		return false
+	}
+	tail := rawdb.ReadTxIndexTail(indexer.db)
+	return tail == nil || *tail >= floor
+}
+
 // report returns the tx indexing progress.
 func (indexer *txIndexer) report(head uint64, tail *uint64) TxIndexProgress {
 	// Special case if the head is even below the cutoff,
 	// nothing to index.
-	if head < indexer.cutoff {
+	cutoff := indexer.cutoff.Load()
+	if head < cutoff {
 		return TxIndexProgress{
 			Indexed:   0,
 			Remaining: 0,
@@ -293,7 +374,7 @@ func (indexer *txIndexer) report(head uint64, tail *uint64) TxIndexProgress {
 	if indexer.limit == 0 || total > head {
 		total = head + 1 // genesis included
 	}
-	length := head - indexer.cutoff + 1 // all available chain for indexing
+	length := head - cutoff + 1 // all available chain for indexing
 	if total > length {
 		total = length
 	}
@@ -133,6 +133,53 @@ func TestTxIndexer(t *testing.T) {
 	}
 }
 
+// TestTxIndexerCutoff tests that the transaction indexes below an advanced
+// cutoff are removed and never indexed again.
+func TestTxIndexerCutoff(t *testing.T) {
+	var (
+		testBankKey, _  = crypto.GenerateKey()
+		testBankAddress = crypto.PubkeyToAddress(testBankKey.PublicKey)
+		testBankFunds   = big.NewInt(1000000000000000000)
+
+		gspec = &Genesis{
+			Config:  params.TestChainConfig,
+			Alloc:   types.GenesisAlloc{testBankAddress: {Balance: testBankFunds}},
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		engine    = ethash.NewFaker()
+		nonce     = uint64(0)
+		chainHead = uint64(128)
+	)
+	_, blocks, receipts := GenerateChainWithGenesis(gspec, engine, int(chainHead), func(i int, gen *BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, common.HexToAddress("0xdeadbeef"), big.NewInt(1000), params.TxGas, big.NewInt(10*params.InitialBaseFee), nil), types.HomesteadSigner{}, testBankKey)
+		gen.AddTx(tx)
+		nonce += 1
+	})
+	var cases = []struct {
+		limit   uint64
+		cutoffs []uint64
+		tails   []uint64
+	}{
+		{limit: 0, cutoffs: []uint64{0, 32, 32, 96}, tails: []uint64{0, 32, 32, 96}},
+		{limit: 64, cutoffs: []uint64{0, 32, 96}, tails: []uint64{65, 65, 96}},
+	}
+	for _, c := range cases {
+		db, _ := rawdb.Open(rawdb.NewMemoryDatabase(), rawdb.OpenOptions{})
+		rawdb.WriteAncientBlocks(db, append([]*types.Block{gspec.ToBlock()}, blocks...), types.EncodeBlockReceiptLists(append([]types.Receipts{{}}, receipts...)))
+
+		indexer := &txIndexer{
+			limit: c.limit,
+			db:    db,
+		}
+		for i, cutoff := range c.cutoffs {
+			indexer.cutoff.Store(cutoff)
+			indexer.run(chainHead, make(chan struct{}), make(chan struct{}))
+			verify(t, db, blocks, c.tails[i])
+		}
+		db.Close()
+	}
+}
+
 func TestTxIndexerRepair(t *testing.T) {
 	var (
 		testBankKey, _  = crypto.GenerateKey()
@@ -246,7 +293,7 @@ func TestTxIndexerRepair(t *testing.T) {
 		}
 		indexer.run(chainHead, make(chan struct{}), make(chan struct{}))
 
-		indexer.cutoff = c.cutoff
+		indexer.cutoff.Store(c.cutoff)
 		indexer.repair(c.head)
 
 		if c.expTail == nil {
@@ -432,10 +479,10 @@ func TestTxIndexerReport(t *testing.T) {
 
 		// Index the initial blocks from ancient store
 		indexer := &txIndexer{
-			limit:  c.limit,
-			cutoff: c.cutoff,
-			db:     db,
+			limit: c.limit,
+			db:    db,
 		}
+		indexer.cutoff.Store(c.cutoff)
 		p := indexer.report(c.head, c.tail)
 		if p.Indexed != c.expIndexed {
 			t.Fatalf("Unexpected indexed: %d, expected: %d", p.Indexed, c.expIndexed)
@@ -1614,24 +1614,28 @@ func (p *BlobPool) Pending(filter txpool.PendingFilter) map[common.Address][]*tx
 	pending := make(map[common.Address][]*txpool.LazyTransaction, len(p.index))
 	for addr, txs := range p.index {
 		lazies := make([]*txpool.LazyTransaction, 0, len(txs))
-		for _, tx := range txs {
+		for i, tx := range txs {
 			// If transaction filtering was requested, discard badly priced ones
+			underpriced := false
 			if filter.MinTip != nil && filter.BaseFee != nil {
 				if tx.execFeeCap.Lt(filter.BaseFee) {
-					break // basefee too low, cannot be included, discard rest of txs from the account
-				}
-				tip := new(uint256.Int).Sub(tx.execFeeCap, filter.BaseFee)
-				if tip.Gt(tx.execTipCap) {
-					tip = tx.execTipCap
-				}
-				if tip.Lt(filter.MinTip) {
-					break // allowed or remaining tip too low, cannot be included, discard rest of txs from the account
+					underpriced = true // basefee too low, cannot be included
+				} else {
+					tip := new(uint256.Int).Sub(tx.execFeeCap, filter.BaseFee)
+					if tip.Gt(tx.execTipCap) {
+						tip = tx.execTipCap
+					}
+					underpriced = tip.Lt(filter.MinTip) // allowed or remaining tip too low, cannot be included
 				}
 			}
-			if filter.BlobFee != nil {
-				if tx.blobFeeCap.Lt(filter.BlobFee) {
-					break // blobfee too low, cannot be included, discard rest of txs from the account
+			if filter.BlobFee != nil && tx.blobFeeCap.Lt(filter.BlobFee) {
+				underpriced = true // blobfee too low, cannot be included
+			}
+			if underpriced {
+				if filter.Underpriced != nil {
+					filter.Underpriced(len(txs) - i)
 				}
+				break // discard rest of txs from the account
 			}
 			if filter.GasLimitCap != 0 {
 				if tx.execGas > filter.GasLimitCap {
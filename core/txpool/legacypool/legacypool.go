@@ -534,6 +534,9 @@ func (pool *LegacyPool) Pending(filter txpool.PendingFilter) map[common.Address]
 			for i, tx := range txs {
 				if minTipBig != nil {
 					if tx.EffectiveGasTipIntCmp(minTipBig, baseFeeBig) < 0 {
+						if filter.Underpriced != nil {
+							filter.Underpriced(len(txs) - i)
+						}
 						txs = txs[:i]
 						break
 					}
@@ -113,6 +113,27 @@ func (tracker *TxTracker) TrackAll(txs []*types.Transaction) {
 	localGauge.Update(int64(len(tracker.all)))
 }
 
+// Get returns the tracked transaction with the given hash, or nil if the
+// transaction is not tracked.
+func (tracker *TxTracker) Get(hash common.Hash) *types.Transaction {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	return tracker.all[hash]
+}
+
+// Tracked returns all tracked transactions, grouped by sender and sorted by nonce.
+func (tracker *TxTracker) Tracked() []*types.Transaction {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	txs := make([]*types.Transaction, 0, len(tracker.all))
+	for _, list := range tracker.byAddr {
+		txs = append(txs, list.Flatten()...)
+	}
+	return txs
+}
+
 // recheck checks and returns any transactions that needs to be resubmitted.
 func (tracker *TxTracker) recheck(journalCheck bool) (resubmits []*types.Transaction, rejournal map[common.Address]types.Transactions) {
 	tracker.mu.Lock()
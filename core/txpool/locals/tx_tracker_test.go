@@ -163,3 +163,27 @@ func TestResubmit(t *testing.T) {
 		t.Fatalf("Unexpected transactions being tracked, got: %d, want: %d", len(all[address]), len(txs))
 	}
 }
+
+func TestTracked(t *testing.T) {
+	env := newTestEnv(t, 1, 0, "")
+	defer env.close()
+
+	txs := env.makeTxs(5)
+	env.tracker.TrackAll([]*types.Transaction{txs[3], txs[1], txs[4], txs[0], txs[2]})
+
+	tracked := env.tracker.Tracked()
+	if len(tracked) != len(txs) {
+		t.Fatalf("Unexpected number of tracked transactions, got: %d, want: %d", len(tracked), len(txs))
+	}
+	for i, tx := range tracked {
+		if tx.Hash() != txs[i].Hash() {
+			t.Fatalf("Tracked transaction %d mismatch, got nonce %d, want %d", i, tx.Nonce(), txs[i].Nonce())
+		}
+	}
+	if tx := env.tracker.Get(txs[2].Hash()); tx == nil || tx.Hash() != txs[2].Hash() {
+		t.Fatalf("Failed to retrieve tracked transaction")
+	}
+	if tx := env.tracker.Get(common.Hash{0x01}); tx != nil {
+		t.Fatalf("Retrieved untracked transaction")
+	}
+}
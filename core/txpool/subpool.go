@@ -80,6 +80,11 @@ type PendingFilter struct {
 
 	OnlyPlainTxs bool // Return only plain EVM transactions (peer-join announces, block space filling)
 	OnlyBlobTxs  bool // Return only blob transactions (block blob-space filling)
+
+	// Underpriced, if set, is called with the number of executable transactions
+	// of an account discarded for not paying the required fees: the first badly
+	// priced one and all the ones following it.
+	Underpriced func(count int)
 }
 
 // TxMetadata denotes the metadata of a transaction.
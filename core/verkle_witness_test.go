@@ -34,6 +34,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/trie"
 	"github.com/ethereum/go-ethereum/trie/utils"
 	"github.com/ethereum/go-ethereum/triedb"
 	"github.com/ethereum/go-verkle"
@@ -1101,3 +1102,107 @@ func TestProcessVerkleSelfDestructInSameTxWithSelfBeneficiaryAndPrefundedAccount
 		}
 	}
 }
+
+func TestProcessVerkleTransition(t *testing.T) {
+	config := *testVerkleChainConfig
+	config.VerkleTime = u64(20) // Second block, the block time is 10 seconds
+	config.EnableVerkleAtGenesis = false
+	config.VerkleTransitionLeaves = 3
+
+	var (
+		signer     = types.LatestSigner(&config)
+		testKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		sender     = crypto.PubkeyToAddress(testKey.PublicKey)
+		contract   = common.HexToAddress("0xc0de")
+		code       = bytes.Repeat([]byte{byte(vm.JUMPDEST)}, 100)
+		storage    = map[common.Hash]common.Hash{
+			{0x01}:        {0x01},
+			{0x02}:        {0x02},
+			{0x03}:        {0x03},
+			common.Hash{}: {0x04},
+		}
+		gspec = &Genesis{
+			Config: &config,
+			Alloc: GenesisAlloc{
+				sender:                       {Balance: big.NewInt(1000000000000000000)},
+				contract:                     {Balance: big.NewInt(1), Code: code, Storage: storage},
+				params.HistoryStorageAddress: {Nonce: 1, Code: params.HistoryStorageCode, Balance: common.Big0},
+			},
+		}
+		bcdb    = rawdb.NewMemoryDatabase()
+		options = DefaultConfig().WithStateScheme(rawdb.PathScheme)
+	)
+	options.Preimages = true
+	options.SnapshotLimit = 0
+	blockchain, err := NewBlockChain(bcdb, gspec, beacon.New(ethash.NewFaker()), options)
+	if err != nil {
+		t.Fatalf("Failed to create blockchain, %v", err)
+	}
+	defer blockchain.Stop()
+
+	_, chain, _ := GenerateChainWithGenesis(gspec, beacon.New(ethash.NewFaker()), 8, func(i int, gen *BlockGen) {
+		gen.SetPoS()
+		tx, _ := types.SignTx(types.NewTransaction(uint64(i), common.Address{byte(i + 1)}, big.NewInt(999), 100000, gen.BaseFee(), nil), signer, testKey)
+		gen.AddTx(tx)
+	})
+	if n, err := blockchain.InsertChain(chain); err != nil {
+		t.Fatalf("Failed to import block %d, %v", n, err)
+	}
+	// The merkle state is kept until the fork, the conversion starts from it
+	if ts, _ := state.ReadTransitionState(bcdb, chain[0].Root()); ts != nil {
+		t.Fatal("Transition state is recorded before the fork")
+	}
+	base := chain[0].Root()
+	checkState := func(number int, sdb *state.StateDB) {
+		for key, val := range storage {
+			if have := sdb.GetState(contract, key); have != val {
+				t.Fatalf("Block %d: slot %x mismatch, have %x, want %x", number, key, have, val)
+			}
+		}
+		if have := sdb.GetCode(contract); !bytes.Equal(have, code) {
+			t.Fatalf("Block %d: code mismatch, have %x", number, have)
+		}
+		if have := sdb.GetNonce(sender); have != uint64(number) {
+			t.Fatalf("Block %d: nonce mismatch, have %d, want %d", number, have, number)
+		}
+	}
+	var ended bool
+	for i := 1; i < len(chain); i++ {
+		ts, err := state.ReadTransitionState(bcdb, chain[i].Root())
+		if err != nil || ts == nil {
+			t.Fatalf("Block %d: missing transition state, %v", i+1, err)
+		}
+		if ts.BaseRoot != base {
+			t.Fatalf("Block %d: base root mismatch, have %x, want %x", i+1, ts.BaseRoot, base)
+		}
+		if ended && !ts.Ended {
+			t.Fatalf("Block %d: transition is resumed", i+1)
+		}
+		ended = ts.Ended
+
+		sdb, err := blockchain.StateAt(chain[i].Root())
+		if err != nil {
+			t.Fatalf("Block %d: failed to open state, %v", i+1, err)
+		}
+		checkState(i+1, sdb)
+	}
+	if !ended {
+		t.Fatal("Transition is not completed")
+	}
+	// The converted state is served by the verkle tree alone
+	root := chain[len(chain)-1].Root()
+	tr, err := trie.NewVerkleTrie(root, blockchain.verkledb, utils.NewPointCache(1024))
+	if err != nil {
+		t.Fatalf("Failed to open verkle tree, %v", err)
+	}
+	for key, val := range storage {
+		have, err := tr.GetStorage(contract, key.Bytes())
+		if err != nil || common.BytesToHash(have) != val {
+			t.Fatalf("Slot %x mismatch in verkle tree, have %x, want %x, %v", key, have, val, err)
+		}
+	}
+	acc, err := tr.GetAccount(contract)
+	if err != nil || acc == nil || acc.Balance.Uint64() != 1 || !bytes.Equal(acc.CodeHash, crypto.Keccak256(code)) {
+		t.Fatalf("Contract mismatch in verkle tree, have %v, %v", acc, err)
+	}
+}
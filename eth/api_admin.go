@@ -79,6 +79,22 @@ func (api *AdminAPI) ExportChain(file string, first *uint64, last *uint64) (bool
 	return true, nil
 }
 
+// Backup creates a consistent point-in-time copy of the chain database, along
+// with the chain freezer and the state histories, in the given directory. The
+// node keeps operating while the backup is taken. The backup can be restored
+// with `geth db restore`.
+func (api *AdminAPI) Backup(path string) (bool, error) {
+	if _, err := os.Stat(path); err == nil {
+		// Directory already exists. Allowing overwrite could be a DoS vector,
+		// since the 'path' may point to arbitrary paths on the drive.
+		return false, errors.New("location would overwrite an existing directory")
+	}
+	if err := api.eth.BlockChain().TrieDB().Checkpoint(path); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 func hasAllBlocks(chain *core.BlockChain, bs []*types.Block) bool {
 	for _, b := range bs {
 		if !chain.HasBlock(b.Hash(), b.NumberU64()) {
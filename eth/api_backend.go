@@ -278,8 +278,16 @@ func (b *EthAPIBackend) HistoryPruningCutoff() uint64 {
 	return bn
 }
 
+func (b *EthAPIBackend) HistoryFloor() uint64 {
+	return b.eth.blockchain.HistoryFloor()
+}
+
 func (b *EthAPIBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
-	return b.eth.blockchain.GetReceiptsByHash(hash), nil
+	receipts := b.eth.blockchain.GetReceiptsByHash(hash)
+	if receipts == nil && b.isPruned(hash) {
+		return nil, &history.PrunedHistoryError{}
+	}
+	return receipts, nil
 }
 
 func (b *EthAPIBackend) GetCanonicalReceipt(tx *types.Transaction, blockHash common.Hash, blockNumber, blockIndex uint64) (*types.Receipt, error) {
@@ -287,7 +295,19 @@ func (b *EthAPIBackend) GetCanonicalReceipt(tx *types.Transaction, blockHash com
 }
 
 func (b *EthAPIBackend) GetLogs(ctx context.Context, hash common.Hash, number uint64) ([][]*types.Log, error) {
-	return rawdb.ReadLogs(b.eth.chainDb, hash, number), nil
+	logs := rawdb.ReadLogs(b.eth.chainDb, hash, number)
+	if logs == nil && number < b.HistoryPruningCutoff() {
+		return nil, &history.PrunedHistoryError{}
+	}
+	return logs, nil
+}
+
+// isPruned reports whether the history of the block with the given hash was
+// pruned. The chain history may be pruned in the background, so this is meant
+// to be checked after failing to retrieve the data.
+func (b *EthAPIBackend) isPruned(hash common.Hash) bool {
+	number := b.eth.blockchain.GetBlockNumber(hash)
+	return number != nil && *number < b.HistoryPruningCutoff()
 }
 
 func (b *EthAPIBackend) GetEVM(ctx context.Context, state *state.StateDB, header *types.Header, vmConfig *vm.Config, blockCtx *vm.BlockContext) *vm.EVM {
@@ -424,6 +444,14 @@ func (b *EthAPIBackend) FeeHistory(ctx context.Context, blockCount uint64, lastB
 	return b.gpo.FeeHistory(ctx, blockCount, lastBlock, rewardPercentiles)
 }
 
+func (b *EthAPIBackend) FeeHistoryByType(ctx context.Context, blockCount uint64, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*gasprice.TypedFeeHistory, error) {
+	return b.gpo.FeeHistoryByType(ctx, blockCount, lastBlock, rewardPercentiles)
+}
+
+func (b *EthAPIBackend) FeeEstimates(ctx context.Context) (*gasprice.FeeEstimates, error) {
+	return b.gpo.FeeEstimates(ctx)
+}
+
 func (b *EthAPIBackend) BlobBaseFee(ctx context.Context) *big.Int {
 	if excess := b.CurrentHeader().ExcessBlobGas; excess != nil {
 		return eip4844.CalcBlobFee(b.ChainConfig(), b.CurrentHeader())
@@ -459,6 +487,10 @@ func (b *EthAPIBackend) RPCTxFeeCap() float64 {
 	return b.eth.config.RPCTxFeeCap
 }
 
+func (b *EthAPIBackend) RPCDatabaseWrite() bool {
+	return b.eth.config.RPCDatabaseWrite
+}
+
 func (b *EthAPIBackend) CurrentView() *filtermaps.ChainView {
 	head := b.eth.blockchain.CurrentBlock()
 	if head == nil {
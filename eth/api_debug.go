@@ -420,6 +420,190 @@ func (api *DebugAPI) GetAccessibleState(from, to rpc.BlockNumber) (uint64, error
 	return 0, errors.New("no state found")
 }
 
+// HistoryMaxResults is the maximum number of state changes to be returned per
+// call of the account and storage history queries.
+const HistoryMaxResults = 1024
+
+// AccountValue is the content of an account in the account history.
+type AccountValue struct {
+	Nonce       hexutil.Uint64 `json:"nonce"`
+	Balance     *hexutil.Big   `json:"balance"`
+	CodeHash    common.Hash    `json:"codeHash"`
+	StorageRoot common.Hash    `json:"storageRoot"`
+}
+
+// AccountChange is a mutation of an account in the given block, the values are
+// nil if the account is not existent.
+type AccountChange struct {
+	Block hexutil.Uint64 `json:"block"`
+	Prev  *AccountValue  `json:"prev"`
+	Post  *AccountValue  `json:"post"`
+}
+
+// AccountHistoryResult is the result of a debug_getAccountHistory API call.
+type AccountHistoryResult struct {
+	Changes []AccountChange `json:"changes"`
+	Next    *hexutil.Uint64 `json:"next"` // Block to resume the query from, nil if complete
+}
+
+// StorageChange is a mutation of a storage slot in the given block.
+type StorageChange struct {
+	Block hexutil.Uint64 `json:"block"`
+	Prev  common.Hash    `json:"prev"`
+	Post  common.Hash    `json:"post"`
+}
+
+// StorageHistoryResult is the result of a debug_getStorageHistory API call.
+type StorageHistoryResult struct {
+	Changes []StorageChange `json:"changes"`
+	Next    *hexutil.Uint64 `json:"next"` // Block to resume the query from, nil if complete
+}
+
+// GetAccountHistory returns the mutations of the account within the block range
+// [from, to], along with the account before and after each of them. At most
+// maxResults mutations are returned per call, the query is resumed from the
+// returned next block.
+//
+// The history is served from the state history index of the path-based scheme,
+// maintained in archive mode. The mutations in the recent blocks, not persisted
+// into the disk layer yet, are not covered.
+func (api *DebugAPI) GetAccountHistory(address common.Address, from, to rpc.BlockNumber, maxResults *int) (*AccountHistoryResult, error) {
+	start, end, limit, err := api.historyQuery(from, to, maxResults)
+	if err != nil {
+		return nil, err
+	}
+	changes, more, err := api.eth.blockchain.TrieDB().AccountChanges(address, start, end, limit)
+	if err != nil {
+		return nil, err
+	}
+	result := &AccountHistoryResult{Changes: make([]AccountChange, 0, len(changes))}
+	for _, change := range changes {
+		prev, err := decodeAccountValue(change.Prev)
+		if err != nil {
+			return nil, err
+		}
+		post, err := decodeAccountValue(change.Post)
+		if err != nil {
+			return nil, err
+		}
+		result.Changes = append(result.Changes, AccountChange{Block: hexutil.Uint64(change.Block), Prev: prev, Post: post})
+	}
+	if more {
+		next := hexutil.Uint64(changes[len(changes)-1].Block + 1)
+		result.Next = &next
+	}
+	return result, nil
+}
+
+// GetStorageHistory returns the mutations of the storage slot within the block
+// range [from, to], along with the value before and after each of them. At most
+// maxResults mutations are returned per call, the query is resumed from the
+// returned next block.
+//
+// The history is served from the state history index of the path-based scheme,
+// maintained in archive mode. The mutations in the recent blocks, not persisted
+// into the disk layer yet, are not covered.
+func (api *DebugAPI) GetStorageHistory(address common.Address, slot common.Hash, from, to rpc.BlockNumber, maxResults *int) (*StorageHistoryResult, error) {
+	start, end, limit, err := api.historyQuery(from, to, maxResults)
+	if err != nil {
+		return nil, err
+	}
+	changes, more, err := api.eth.blockchain.TrieDB().StorageChanges(address, slot, start, end, limit)
+	if err != nil {
+		return nil, err
+	}
+	result := &StorageHistoryResult{Changes: make([]StorageChange, 0, len(changes))}
+	for _, change := range changes {
+		prev, err := decodeStorageValue(change.Prev)
+		if err != nil {
+			return nil, err
+		}
+		post, err := decodeStorageValue(change.Post)
+		if err != nil {
+			return nil, err
+		}
+		result.Changes = append(result.Changes, StorageChange{Block: hexutil.Uint64(change.Block), Prev: prev, Post: post})
+	}
+	if more {
+		next := hexutil.Uint64(changes[len(changes)-1].Block + 1)
+		result.Next = &next
+	}
+	return result, nil
+}
+
+// historyQuery resolves the block range and the result limit of a state history
+// query.
+func (api *DebugAPI) historyQuery(from, to rpc.BlockNumber, maxResults *int) (uint64, uint64, int, error) {
+	if api.eth.blockchain.TrieDB().Scheme() != rawdb.PathScheme {
+		return 0, 0, 0, errors.New("state history is only available in path-based scheme")
+	}
+	resolve := func(number rpc.BlockNumber) (uint64, error) {
+		var header *types.Header
+		switch number {
+		case rpc.EarliestBlockNumber:
+			return 0, nil
+		case rpc.LatestBlockNumber, rpc.PendingBlockNumber:
+			header = api.eth.blockchain.CurrentBlock()
+		case rpc.FinalizedBlockNumber:
+			header = api.eth.blockchain.CurrentFinalBlock()
+		case rpc.SafeBlockNumber:
+			header = api.eth.blockchain.CurrentSafeBlock()
+		default:
+			return uint64(number), nil
+		}
+		if header == nil {
+			return 0, fmt.Errorf("block %s not found", number)
+		}
+		return header.Number.Uint64(), nil
+	}
+	start, err := resolve(from)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	end, err := resolve(to)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	if start > end {
+		return 0, 0, 0, fmt.Errorf("start block (%d) is greater than end block (%d)", start, end)
+	}
+	limit := HistoryMaxResults
+	if maxResults != nil && *maxResults > 0 && *maxResults < limit {
+		limit = *maxResults
+	}
+	return start, end, limit, nil
+}
+
+// decodeAccountValue decodes the slim RLP-encoded account in the state history,
+// returning nil if the account is not existent.
+func decodeAccountValue(blob []byte) (*AccountValue, error) {
+	if len(blob) == 0 {
+		return nil, nil
+	}
+	account, err := types.FullAccount(blob)
+	if err != nil {
+		return nil, err
+	}
+	return &AccountValue{
+		Nonce:       hexutil.Uint64(account.Nonce),
+		Balance:     (*hexutil.Big)(account.Balance.ToBig()),
+		CodeHash:    common.BytesToHash(account.CodeHash),
+		StorageRoot: account.Root,
+	}, nil
+}
+
+// decodeStorageValue decodes the RLP-encoded storage slot in the state history.
+func decodeStorageValue(blob []byte) (common.Hash, error) {
+	if len(blob) == 0 {
+		return common.Hash{}, nil
+	}
+	_, content, _, err := rlp.Split(blob)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(content), nil
+}
+
 // SetTrieFlushInterval configures how often in-memory tries are persisted
 // to disk. The value is in terms of block processing time, not wall clock.
 // If the value is shorter than the block generation time, or even 0 or negative,
@@ -37,6 +37,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/ethereum/go-ethereum/triedb"
 	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/assert"
@@ -335,3 +336,135 @@ func TestGetModifiedAccounts(t *testing.T) {
 		}
 	})
 }
+
+func TestGetStateHistory(t *testing.T) {
+	t.Parallel()
+
+	var (
+		key, _    = crypto.GenerateKey()
+		sender    = crypto.PubkeyToAddress(key.PublicKey)
+		recipient = common.Address{0xaa}
+		contract  = common.Address{0xbb}
+		signer    = types.HomesteadSigner{}
+		genesis   = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: types.GenesisAlloc{
+				sender: {Balance: big.NewInt(params.Ether)},
+				// Stores the first word of the calldata in slot zero
+				contract: {Balance: common.Big0, Code: common.FromHex("0x600035600055")},
+			},
+		}
+		transfers []uint64 // Blocks transferring to the recipient
+		stores    []uint64 // Blocks storing into the contract
+		nonce     uint64
+	)
+	_, blocks, _ := core.GenerateChainWithGenesis(genesis, ethash.NewFaker(), 200, func(i int, b *core.BlockGen) {
+		number := b.Number().Uint64()
+		if number%7 == 0 {
+			tx, _ := types.SignTx(types.NewTransaction(nonce, recipient, big.NewInt(1000), params.TxGas, b.BaseFee(), nil), signer, key)
+			b.AddTx(tx)
+			transfers = append(transfers, number)
+			nonce++
+		}
+		if number%10 == 0 {
+			data := common.BigToHash(new(big.Int).SetUint64(number)).Bytes()
+			tx, _ := types.SignTx(types.NewTransaction(nonce, contract, common.Big0, 100000, b.BaseFee(), data), signer, key)
+			b.AddTx(tx)
+			stores = append(stores, number)
+			nonce++
+		}
+	})
+	options := &core.BlockChainConfig{
+		TrieCleanLimit: 256,
+		TrieDirtyLimit: 256,
+		TrieTimeLimit:  5 * time.Minute,
+		StateScheme:    rawdb.PathScheme,
+		ArchiveMode:    true,
+	}
+	db, err := rawdb.NewDatabaseWithFreezer(rawdb.NewMemoryDatabase(), "", "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, err := core.NewBlockChain(db, genesis, ethash.NewFaker(), options)
+	if err != nil {
+		t.Fatalf("failed to create tester chain: %v", err)
+	}
+	defer chain.Stop()
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("block %d: failed to insert into chain: %v", n, err)
+	}
+	api := NewDebugAPI(&Ethereum{blockchain: chain})
+
+	// Wait for the state histories to be indexed
+	for start := time.Now(); ; time.Sleep(10 * time.Millisecond) {
+		if _, err := api.GetAccountHistory(recipient, rpc.EarliestBlockNumber, rpc.LatestBlockNumber, nil); err == nil {
+			break
+		} else if time.Since(start) > 10*time.Second {
+			t.Fatalf("state histories are not indexed: %v", err)
+		}
+	}
+	// Only the blocks below the in-memory diff layers are covered
+	covered := func(numbers []uint64) []uint64 {
+		var res []uint64
+		for _, n := range numbers {
+			if n <= 200-128 {
+				res = append(res, n)
+			}
+		}
+		return res
+	}
+	// Page through the recipient history, one change per call
+	var (
+		have []uint64
+		from = rpc.EarliestBlockNumber
+		max  = 1
+	)
+	for {
+		result, err := api.GetAccountHistory(recipient, from, rpc.LatestBlockNumber, &max)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, change := range result.Changes {
+			balance := new(big.Int).Mul(big.NewInt(1000), big.NewInt(int64(len(have)+1)))
+			if change.Post == nil || change.Post.Balance.ToInt().Cmp(balance) != 0 {
+				t.Fatalf("block %d: balance mismatch, have %v, want %v", change.Block, change.Post, balance)
+			}
+			if (len(have) == 0) != (change.Prev == nil) {
+				t.Fatalf("block %d: unexpected prior account %v", change.Block, change.Prev)
+			}
+			have = append(have, uint64(change.Block))
+		}
+		if result.Next == nil {
+			break
+		}
+		from = rpc.BlockNumber(*result.Next)
+	}
+	if want := covered(transfers); !slices.Equal(have, want) {
+		t.Fatalf("recipient history mismatch, have %v, want %v", have, want)
+	}
+	// Query the storage history within a sub-range
+	result, err := api.GetStorageHistory(contract, common.Hash{}, 15, 65, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Next != nil {
+		t.Fatalf("unexpected pagination, next %d", *result.Next)
+	}
+	var want []uint64
+	for _, n := range stores {
+		if n >= 15 && n <= 65 {
+			want = append(want, n)
+		}
+	}
+	if len(result.Changes) != len(want) {
+		t.Fatalf("storage change count mismatch, have %d, want %d", len(result.Changes), len(want))
+	}
+	for i, change := range result.Changes {
+		if uint64(change.Block) != want[i] {
+			t.Fatalf("storage change %d: block mismatch, have %d, want %d", i, change.Block, want[i])
+		}
+		if change.Prev != common.BigToHash(new(big.Int).SetUint64(want[i]-10)) || change.Post != common.BigToHash(new(big.Int).SetUint64(want[i])) {
+			t.Fatalf("storage change %d: value mismatch, prev %x, post %x", i, change.Prev, change.Post)
+		}
+	}
+}
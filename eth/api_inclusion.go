@@ -0,0 +1,162 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/miner"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// inclusionRecheckInterval is the interval at which the inclusion estimates of
+// the local transactions are refreshed for subscribers if no new head arrives.
+// The value is chosen to align with the lifetime of the miner's pending block.
+const inclusionRecheckInterval = 2 * time.Second
+
+var errUnknownTransaction = errors.New("unknown transaction")
+
+// InclusionAPI provides an API to estimate where pending transactions would land
+// if a block was built right now.
+type InclusionAPI struct {
+	eth *Ethereum
+}
+
+// NewInclusionAPI creates a new InclusionAPI instance.
+func NewInclusionAPI(eth *Ethereum) *InclusionAPI {
+	return &InclusionAPI{eth}
+}
+
+// inclusionEstimateResult is the RPC representation of an inclusion estimate.
+type inclusionEstimateResult struct {
+	Hash         common.Hash     `json:"hash"`
+	BlockNumber  hexutil.Uint64  `json:"blockNumber"`
+	Included     bool            `json:"included"`
+	Position     *hexutil.Uint64 `json:"position"`
+	Reverted     bool            `json:"reverted"`
+	GasUsed      hexutil.Uint64  `json:"gasUsed"`
+	EffectiveTip *hexutil.Big    `json:"effectiveTip"`
+	Error        string          `json:"error,omitempty"`
+}
+
+func newInclusionEstimateResult(est *miner.InclusionEstimate) *inclusionEstimateResult {
+	res := &inclusionEstimateResult{
+		Hash:         est.Hash,
+		BlockNumber:  hexutil.Uint64(est.BlockNumber),
+		Included:     est.Included,
+		Reverted:     est.Reverted,
+		GasUsed:      hexutil.Uint64(est.GasUsed),
+		EffectiveTip: (*hexutil.Big)(est.EffectiveTip),
+	}
+	if est.Position >= 0 {
+		pos := hexutil.Uint64(est.Position)
+		res.Position = &pos
+	}
+	if est.Error != nil {
+		res.Error = est.Error.Error()
+	}
+	return res
+}
+
+// InclusionEstimate returns where the transaction with the given hash would land
+// if a block was built on top of the current head right now: its position in
+// the pending block, whether it would revert and the effective tip it pays. The
+// transaction must either be tracked as a local one or be known by the pool.
+func (api *InclusionAPI) InclusionEstimate(hash common.Hash) (*inclusionEstimateResult, error) {
+	var tx *types.Transaction
+	if api.eth.localTxTracker != nil {
+		tx = api.eth.localTxTracker.Get(hash)
+	}
+	if tx == nil {
+		tx = api.eth.txPool.Get(hash)
+	}
+	if tx == nil {
+		return nil, errUnknownTransaction
+	}
+	estimates, err := api.eth.Miner().EstimateInclusion([]*types.Transaction{tx})
+	if err != nil {
+		return nil, err
+	}
+	return newInclusionEstimateResult(estimates[0]), nil
+}
+
+// InclusionEstimates creates a subscription that periodically reports the
+// inclusion estimates of all locally submitted transactions still pending in the
+// pool, whenever a new head arrives and at least every couple of seconds.
+func (api *InclusionAPI) InclusionEstimates(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		var (
+			heads  = make(chan core.ChainHeadEvent, 1)
+			sub    = api.eth.BlockChain().SubscribeChainHeadEvent(heads)
+			ticker = time.NewTicker(inclusionRecheckInterval)
+		)
+		defer sub.Unsubscribe()
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-heads:
+			case <-ticker.C:
+			case <-rpcSub.Err():
+				return
+			}
+			results := api.localEstimates()
+			if len(results) > 0 {
+				notifier.Notify(rpcSub.ID, results)
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
+// localEstimates returns the inclusion estimates of all tracked local transactions
+// which are still pending in the pool.
+func (api *InclusionAPI) localEstimates() []*inclusionEstimateResult {
+	if api.eth.localTxTracker == nil {
+		return nil
+	}
+	var txs []*types.Transaction
+	for _, tx := range api.eth.localTxTracker.Tracked() {
+		if api.eth.txPool.Has(tx.Hash()) {
+			txs = append(txs, tx)
+		}
+	}
+	if len(txs) == 0 {
+		return nil
+	}
+	estimates, err := api.eth.Miner().EstimateInclusion(txs)
+	if err != nil {
+		return nil
+	}
+	results := make([]*inclusionEstimateResult, len(estimates))
+	for i, est := range estimates {
+		results[i] = newInclusionEstimateResult(est)
+	}
+	return results
+}
@@ -17,8 +17,11 @@
 package eth
 
 import (
+	"errors"
 	"math/big"
 
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
@@ -56,3 +59,132 @@ func (api *MinerAPI) SetGasLimit(gasLimit hexutil.Uint64) bool {
 	api.e.Miner().SetGasCeil(uint64(gasLimit))
 	return true
 }
+
+// SetForcedSenders sets the list of senders whose pending transactions are included
+// into locally built payloads before any other transaction.
+func (api *MinerAPI) SetForcedSenders(senders []common.Address) bool {
+	api.e.Miner().SetForcedSenders(senders)
+	return true
+}
+
+// ForcedSenders returns the list of senders on the forced inclusion list.
+func (api *MinerAPI) ForcedSenders() []common.Address {
+	return api.e.Miner().ForcedSenders()
+}
+
+// forcedInclusionResult is the outcome of including a single transaction of the
+// forced inclusion list.
+type forcedInclusionResult struct {
+	Hash     common.Hash    `json:"hash"`
+	Sender   common.Address `json:"sender"`
+	Nonce    hexutil.Uint64 `json:"nonce"`
+	Included bool           `json:"included"`
+	Error    string         `json:"error,omitempty"`
+}
+
+// forcedInclusionReportResult is the outcome of the forced inclusion list for
+// the most recently built payload.
+type forcedInclusionReportResult struct {
+	ParentHash   common.Hash              `json:"parentHash"`
+	Number       hexutil.Uint64           `json:"number"`
+	Transactions []*forcedInclusionResult `json:"transactions"`
+}
+
+// ForcedInclusionReport returns which transactions of the forced inclusion list
+// were included into the most recently built payload and why the others were not.
+func (api *MinerAPI) ForcedInclusionReport() *forcedInclusionReportResult {
+	report := api.e.Miner().ForcedInclusionReport()
+	if report == nil {
+		return nil
+	}
+	result := &forcedInclusionReportResult{
+		ParentHash:   report.ParentHash,
+		Number:       hexutil.Uint64(report.Number),
+		Transactions: make([]*forcedInclusionResult, len(report.Transactions)),
+	}
+	for i, tx := range report.Transactions {
+		result.Transactions[i] = &forcedInclusionResult{
+			Hash:     tx.Hash,
+			Sender:   tx.Sender,
+			Nonce:    hexutil.Uint64(tx.Nonce),
+			Included: tx.Included,
+		}
+		if tx.Error != nil {
+			result.Transactions[i].Error = tx.Error.Error()
+		}
+	}
+	return result
+}
+
+var errUnknownPayload = errors.New("unknown payload")
+
+// skippedTxResult is a transaction skipped during a payload build iteration.
+type skippedTxResult struct {
+	Hash   common.Hash `json:"hash"`
+	Reason string      `json:"reason"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// buildIterationResult contains the diagnostics of a single build iteration.
+type buildIterationResult struct {
+	Start      hexutil.Uint64     `json:"start"`
+	Duration   hexutil.Uint64     `json:"duration"`
+	Empty      bool               `json:"empty"`
+	Considered hexutil.Uint64     `json:"considered"`
+	Included   hexutil.Uint64     `json:"included"`
+	Skipped    map[string]int     `json:"skipped"`
+	SkippedTxs []*skippedTxResult `json:"skippedTransactions"`
+	Fees       *hexutil.Big       `json:"fees,omitempty"`
+	Error      string             `json:"error,omitempty"`
+}
+
+// payloadDiagnosticsResult contains the diagnostics of all build iterations of
+// a payload.
+type payloadDiagnosticsResult struct {
+	ID         engine.PayloadID        `json:"payloadId"`
+	ParentHash common.Hash             `json:"parentHash"`
+	Timestamp  hexutil.Uint64          `json:"timestamp"`
+	Iterations []*buildIterationResult `json:"iterations"`
+	Value      *hexutil.Big            `json:"blockValue"`
+}
+
+// PayloadDiagnostics returns the diagnostics of building the payload with the
+// given id: the duration of every build iteration, the number of transactions
+// considered and skipped (along with the reasons), the fees collected and the
+// value of the best block built. Only the most recent payloads are retained.
+func (api *MinerAPI) PayloadDiagnostics(id engine.PayloadID) (*payloadDiagnosticsResult, error) {
+	diag := api.e.Miner().PayloadDiagnostics(id)
+	if diag == nil {
+		return nil, errUnknownPayload
+	}
+	result := &payloadDiagnosticsResult{
+		ID:         diag.ID,
+		ParentHash: diag.ParentHash,
+		Timestamp:  hexutil.Uint64(diag.Timestamp),
+		Iterations: make([]*buildIterationResult, len(diag.Iterations)),
+		Value:      (*hexutil.Big)(diag.Value),
+	}
+	for i, it := range diag.Iterations {
+		res := &buildIterationResult{
+			Start:      hexutil.Uint64(it.Start.UnixMilli()),
+			Duration:   hexutil.Uint64(it.Duration.Milliseconds()),
+			Empty:      it.Empty,
+			Considered: hexutil.Uint64(it.Considered),
+			Included:   hexutil.Uint64(it.Included),
+			Skipped:    it.Skipped,
+			SkippedTxs: make([]*skippedTxResult, len(it.SkippedTxs)),
+			Fees:       (*hexutil.Big)(it.Fees),
+		}
+		for j, tx := range it.SkippedTxs {
+			res.SkippedTxs[j] = &skippedTxResult{Hash: tx.Hash, Reason: tx.Reason}
+			if tx.Error != nil {
+				res.SkippedTxs[j].Error = tx.Error.Error()
+			}
+		}
+		if it.Error != nil {
+			res.Error = it.Error.Error()
+		}
+		result.Iterations[i] = res
+	}
+	return result, nil
+}
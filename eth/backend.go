@@ -20,6 +20,7 @@ package eth
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"math/big"
@@ -33,8 +34,10 @@ import (
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/filtermaps"
+	"github.com/ethereum/go-ethereum/core/history"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state/pruner"
+	"github.com/ethereum/go-ethereum/core/statediff"
 	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
 	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
@@ -82,6 +85,10 @@ const (
 	// maxParallelENRRequests is the maximum number of parallel ENR requests that can be
 	// performed by a disc/v4 source.
 	maxParallelENRRequests = 16
+
+	// maxFollowBackoff is the maximum delay between the retries of a secondary
+	// node failing to catch up with the database of the primary node.
+	maxFollowBackoff = time.Minute
 )
 
 // Config contains the configuration options of the ETH protocol.
@@ -124,6 +131,8 @@ type Ethereum struct {
 	lock sync.RWMutex // Protects the variadic fields (e.g. gas price and etherbase)
 
 	shutdownTracker *shutdowncheck.ShutdownTracker // Tracks if and when the node has shutdown ungracefully
+
+	closeFollow chan struct{} // Channel to stop following the primary node in secondary mode
 }
 
 // New creates a new Ethereum object (including the initialisation of the common Ethereum object),
@@ -136,6 +145,9 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	if !config.HistoryMode.IsValid() {
 		return nil, fmt.Errorf("invalid history mode %d", config.HistoryMode)
 	}
+	if config.HistoryMode == history.KeepRecent && config.HistoryRetentionBlocks == 0 && config.HistoryRetentionDays == 0 {
+		return nil, errors.New("history mode \"recent\" requires a retention window in blocks or days")
+	}
 	if config.Miner.GasPrice == nil || config.Miner.GasPrice.Sign() <= 0 {
 		log.Warn("Sanitizing invalid miner gas price", "provided", config.Miner.GasPrice, "updated", ethconfig.Defaults.Miner.GasPrice)
 		config.Miner.GasPrice = new(big.Int).Set(ethconfig.Defaults.Miner.GasPrice)
@@ -152,13 +164,26 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	log.Info("Allocated trie memory caches", "clean", common.StorageSize(config.TrieCleanCache)*1024*1024, "dirty", common.StorageSize(config.TrieDirtyCache)*1024*1024)
 
 	dbOptions := node.DatabaseOptions{
-		Cache:             config.DatabaseCache,
-		Handles:           config.DatabaseHandles,
-		AncientsDirectory: config.DatabaseFreezer,
-		EraDirectory:      config.DatabaseEra,
-		MetricsNamespace:  "eth/db/chaindata/",
+		Cache:               config.DatabaseCache,
+		Handles:             config.DatabaseHandles,
+		AncientsDirectory:   config.DatabaseFreezer,
+		EraDirectory:        config.DatabaseEra,
+		AncientsRemote:      config.DatabaseFreezerRemote,
+		AncientsRemoteCache: config.DatabaseFreezerRemoteCache,
+		MetricsNamespace:    "eth/db/chaindata/",
+	}
+	var (
+		secondary = config.DatabaseSecondary != ""
+		chainDb   ethdb.Database
+		err       error
+	)
+	if secondary {
+		// The database is owned by a primary node, follow it without writing.
+		dbOptions.Secondary = true
+		chainDb, err = stack.OpenDatabaseWithOptions(config.DatabaseSecondary, dbOptions)
+	} else {
+		chainDb, err = stack.OpenDatabaseWithOptions("chaindata", dbOptions)
 	}
-	chainDb, err := stack.OpenDatabaseWithOptions("chaindata", dbOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -167,7 +192,7 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		return nil, err
 	}
 	// Try to recover offline state pruning only in hash-based.
-	if scheme == rawdb.HashScheme {
+	if scheme == rawdb.HashScheme && !secondary {
 		if err := pruner.RecoverPruning(stack.ResolvePath(""), chainDb); err != nil {
 			log.Error("Failed to recover state", "error", err)
 		}
@@ -191,16 +216,20 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 
 	// Assemble the Ethereum object.
 	eth := &Ethereum{
-		config:          config,
-		chainDb:         chainDb,
-		eventMux:        stack.EventMux(),
-		accountManager:  stack.AccountManager(),
-		engine:          engine,
-		networkID:       networkID,
-		gasPrice:        config.Miner.GasPrice,
-		p2pServer:       stack.Server(),
-		discmix:         enode.NewFairMix(discmixTimeout),
-		shutdownTracker: shutdowncheck.NewShutdownTracker(chainDb),
+		config:         config,
+		chainDb:        chainDb,
+		eventMux:       stack.EventMux(),
+		accountManager: stack.AccountManager(),
+		engine:         engine,
+		networkID:      networkID,
+		gasPrice:       config.Miner.GasPrice,
+		p2pServer:      stack.Server(),
+		discmix:        enode.NewFairMix(discmixTimeout),
+	}
+	if secondary {
+		eth.closeFollow = make(chan struct{})
+	} else {
+		eth.shutdownTracker = shutdowncheck.NewShutdownTracker(chainDb)
 	}
 	bcVersion := rawdb.ReadDatabaseVersion(chainDb)
 	var dbVer = "<nil>"
@@ -210,7 +239,7 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	log.Info("Initialising Ethereum protocol", "network", networkID, "dbversion", dbVer)
 
 	// Create BlockChain object.
-	if !config.SkipBcVersionCheck {
+	if !config.SkipBcVersionCheck && !secondary {
 		if bcVersion != nil && *bcVersion > core.BlockChainVersion {
 			return nil, fmt.Errorf("database version is v%d, Geth %s only supports v%d", *bcVersion, version.WithMeta, core.BlockChainVersion)
 		} else if bcVersion == nil || *bcVersion < core.BlockChainVersion {
@@ -222,17 +251,19 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	}
 	var (
 		options = &core.BlockChainConfig{
-			TrieCleanLimit:   config.TrieCleanCache,
-			NoPrefetch:       config.NoPrefetch,
-			TrieDirtyLimit:   config.TrieDirtyCache,
-			ArchiveMode:      config.NoPruning,
-			TrieTimeLimit:    config.TrieTimeout,
-			SnapshotLimit:    config.SnapshotCache,
-			Preimages:        config.Preimages,
-			StateHistory:     config.StateHistory,
-			StateScheme:      scheme,
-			ChainHistoryMode: config.HistoryMode,
-			TxLookupLimit:    int64(min(config.TransactionHistory, math.MaxInt64)),
+			TrieCleanLimit:     config.TrieCleanCache,
+			NoPrefetch:         config.NoPrefetch,
+			TrieDirtyLimit:     config.TrieDirtyCache,
+			ArchiveMode:        config.NoPruning,
+			TrieTimeLimit:      config.TrieTimeout,
+			SnapshotLimit:      config.SnapshotCache,
+			Preimages:          config.Preimages,
+			StateHistory:       config.StateHistory,
+			StateScheme:        scheme,
+			ChainHistoryMode:   config.HistoryMode,
+			ChainHistoryBlocks: config.HistoryRetentionBlocks,
+			ChainHistoryPeriod: time.Duration(config.HistoryRetentionDays) * 24 * time.Hour,
+			TxLookupLimit:      int64(min(config.TransactionHistory, math.MaxInt64)),
 			VmConfig: vm.Config{
 				EnablePreimageRecording: config.EnablePreimageRecording,
 			},
@@ -241,8 +272,22 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 			// - DATADIR/triedb/merkle.journal
 			// - DATADIR/triedb/verkle.journal
 			TrieJournalDirectory: stack.ResolvePath("triedb"),
+			Secondary:            secondary,
 		}
 	)
+	if config.OnlinePruning {
+		pruneConfig := pruner.DefaultOnlineConfig
+		pruneConfig.Rate = config.OnlinePruningRate
+		options.StatePruning = &pruneConfig
+	}
+	if config.StateDiffPath != "" || config.StateDiffSocket != "" {
+		options.StateDiff = &statediff.Config{
+			Format:  config.StateDiffFormat,
+			Path:    config.StateDiffPath,
+			MaxSize: config.StateDiffMaxSize,
+			Socket:  config.StateDiffSocket,
+		}
+	}
 	if config.VMTrace != "" {
 		traceConfig := json.RawMessage("{}")
 		if config.VMTraceJsonConfig != "" {
@@ -282,7 +327,13 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	if fb := eth.blockchain.CurrentFinalBlock(); fb != nil {
 		finalBlock = fb.Number.Uint64()
 	}
-	filterMaps, err := filtermaps.NewFilterMaps(chainDb, chainView, historyCutoff, finalBlock, filtermaps.DefaultParams, fmConfig)
+	fmDb := ethdb.Database(chainDb)
+	if secondary {
+		// The log index can't be written into the database of the primary, the
+		// logs are searched without the index instead.
+		fmDb, fmConfig.Disabled = rawdb.NewMemoryDatabase(), true
+	}
+	filterMaps, err := filtermaps.NewFilterMaps(fmDb, chainView, historyCutoff, finalBlock, filtermaps.DefaultParams, fmConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -327,6 +378,7 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		BloomCache:     uint64(cacheLimit),
 		EventMux:       eth.eventMux,
 		RequiredBlocks: config.RequiredBlocks,
+		Secondary:      secondary,
 	}); err != nil {
 		return nil, err
 	}
@@ -352,7 +404,9 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	stack.RegisterLifecycle(eth)
 
 	// Successful startup; push a marker and check previous unclean shutdowns.
-	eth.shutdownTracker.MarkStartup()
+	if eth.shutdownTracker != nil {
+		eth.shutdownTracker.MarkStartup()
+	}
 
 	return eth, nil
 }
@@ -384,6 +438,9 @@ func (s *Ethereum) APIs() []rpc.API {
 		{
 			Namespace: "miner",
 			Service:   NewMinerAPI(s),
+		}, {
+			Namespace: "eth",
+			Service:   NewInclusionAPI(s),
 		}, {
 			Namespace: "eth",
 			Service:   downloader.NewDownloaderAPI(s.handler.downloader, s.blockchain, s.eventMux),
@@ -421,6 +478,10 @@ func (s *Ethereum) ArchiveMode() bool                  { return s.config.NoPruni
 // Protocols returns all the currently configured
 // network protocols to start.
 func (s *Ethereum) Protocols() []p2p.Protocol {
+	// The secondary node doesn't sync, it follows the database of the primary.
+	if s.closeFollow != nil {
+		return nil
+	}
 	protos := eth.MakeProtocols((*ethHandler)(s.handler), s.networkID, s.discmix)
 	if s.config.SnapshotCache > 0 {
 		protos = append(protos, snap.MakeProtocols((*snapHandler)(s.handler))...)
@@ -435,14 +496,19 @@ func (s *Ethereum) Start() error {
 		return err
 	}
 
-	// Regularly update shutdown marker
-	s.shutdownTracker.Start()
+	if s.closeFollow != nil {
+		// Follow the primary node instead of running the networking layer
+		go s.followPrimary()
+	} else {
+		// Regularly update shutdown marker
+		s.shutdownTracker.Start()
 
-	// Start the networking layer
-	s.handler.Start(s.p2pServer.MaxPeers)
+		// Start the networking layer
+		s.handler.Start(s.p2pServer.MaxPeers)
 
-	// Start the connection manager
-	s.dropper.Start(s.p2pServer, func() bool { return !s.Synced() })
+		// Start the connection manager
+		s.dropper.Start(s.p2pServer, func() bool { return !s.Synced() })
+	}
 
 	// start log indexer
 	s.filterMaps.Start()
@@ -508,6 +574,45 @@ func (s *Ethereum) updateFilterMapsHeads() {
 	}
 }
 
+// followPrimary periodically catches up with the database written by the primary
+// node and moves the chain head along, in secondary mode. Failed catch-ups are
+// retried with an exponentially growing delay, to not hammer the database while
+// the primary is e.g. down or restarting.
+func (s *Ethereum) followPrimary() {
+	follower, ok := s.chainDb.(ethdb.Follower)
+	if !ok {
+		log.Error("Database can't follow the primary node")
+		return
+	}
+	interval := s.config.DatabaseSecondaryInterval
+	if interval <= 0 {
+		interval = ethconfig.Defaults.DatabaseSecondaryInterval
+	}
+	var (
+		delay = interval
+		timer = time.NewTimer(delay)
+	)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			if err := follower.CatchUp(); err != nil {
+				delay = min(2*delay, max(maxFollowBackoff, interval))
+				log.Warn("Failed to catch up with the primary database", "retry", common.PrettyDuration(delay), "err", err)
+			} else {
+				delay = interval
+				if err := s.blockchain.ReloadHead(); err != nil {
+					log.Warn("Failed to reload the chain head", "err", err)
+				}
+			}
+			timer.Reset(delay)
+		case <-s.closeFollow:
+			return
+		}
+	}
+}
+
 func (s *Ethereum) setupDiscovery() error {
 	eth.StartENRUpdater(s.blockchain, s.p2pServer.LocalNode())
 
@@ -563,7 +668,11 @@ func (s *Ethereum) Stop() error {
 	// Stop all the peer-related stuff first.
 	s.discmix.Close()
 	s.dropper.Stop()
-	s.handler.Stop()
+	if s.closeFollow != nil {
+		close(s.closeFollow)
+	} else {
+		s.handler.Stop()
+	}
 
 	// Then stop everything else.
 	ch := make(chan struct{})
@@ -575,7 +684,9 @@ func (s *Ethereum) Stop() error {
 	s.engine.Close()
 
 	// Clean shutdown marker as the last thing before closing db
-	s.shutdownTracker.Stop()
+	if s.shutdownTracker != nil {
+		s.shutdownTracker.Stop()
+	}
 
 	s.chainDb.Close()
 	s.eventMux.Stop()
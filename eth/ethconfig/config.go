@@ -28,6 +28,8 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/history"
+	"github.com/ethereum/go-ethereum/core/state/pruner"
+	"github.com/ethereum/go-ethereum/core/statediff"
 	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
 	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
@@ -49,26 +51,31 @@ var FullNodeGPO = gasprice.Config{
 
 // Defaults contains default settings for use on the Ethereum main net.
 var Defaults = Config{
-	HistoryMode:        history.KeepAll,
-	SyncMode:           SnapSync,
-	NetworkId:          0, // enable auto configuration of networkID == chainID
-	TxLookupLimit:      2350000,
-	TransactionHistory: 2350000,
-	LogHistory:         2350000,
-	StateHistory:       params.FullImmutabilityThreshold,
-	DatabaseCache:      512,
-	TrieCleanCache:     154,
-	TrieDirtyCache:     256,
-	TrieTimeout:        60 * time.Minute,
-	SnapshotCache:      102,
-	FilterLogCacheSize: 32,
-	Miner:              miner.DefaultConfig,
-	TxPool:             legacypool.DefaultConfig,
-	BlobPool:           blobpool.DefaultConfig,
-	RPCGasCap:          50000000,
-	RPCEVMTimeout:      5 * time.Second,
-	GPO:                FullNodeGPO,
-	RPCTxFeeCap:        1, // 1 ether
+	HistoryMode:                history.KeepAll,
+	SyncMode:                   SnapSync,
+	NetworkId:                  0, // enable auto configuration of networkID == chainID
+	TxLookupLimit:              2350000,
+	TransactionHistory:         2350000,
+	LogHistory:                 2350000,
+	StateHistory:               params.FullImmutabilityThreshold,
+	DatabaseCache:              512,
+	DatabaseFreezerRemoteCache: 256,
+	DatabaseSecondaryInterval:  time.Second,
+	TrieCleanCache:             154,
+	TrieDirtyCache:             256,
+	TrieTimeout:                60 * time.Minute,
+	SnapshotCache:              102,
+	OnlinePruningRate:          pruner.DefaultOnlineConfig.Rate,
+	StateDiffFormat:            statediff.FormatJSON,
+	StateDiffMaxSize:           100,
+	FilterLogCacheSize:         32,
+	Miner:                      miner.DefaultConfig,
+	TxPool:                     legacypool.DefaultConfig,
+	BlobPool:                   blobpool.DefaultConfig,
+	RPCGasCap:                  50000000,
+	RPCEVMTimeout:              5 * time.Second,
+	GPO:                        FullNodeGPO,
+	RPCTxFeeCap:                1, // 1 ether
 }
 
 //go:generate go run github.com/fjl/gencodec -type Config -formats toml -out gen_config.go
@@ -87,6 +94,11 @@ type Config struct {
 	// HistoryMode configures chain history retention.
 	HistoryMode history.HistoryMode
 
+	// Rolling chain history retention window, only relevant in history mode
+	// "recent". A zero value disables the respective limit.
+	HistoryRetentionBlocks uint64 `toml:",omitempty"` // Number of recent blocks to retain bodies and receipts for
+	HistoryRetentionDays   uint64 `toml:",omitempty"` // Number of days of recent blocks to retain bodies and receipts for
+
 	// This can be set to list of enrtree:// URLs which will be queried for
 	// nodes to connect to.
 	EthDiscoveryURLs  []string
@@ -96,6 +108,11 @@ type Config struct {
 	NoPruning  bool // Whether to disable pruning and flush everything to disk
 	NoPrefetch bool // Whether to disable prefetching and only load state on demand
 
+	// Online pruning deletes the stale states in the background, only relevant
+	// in state.scheme=hash without archive mode.
+	OnlinePruning     bool `toml:",omitempty"` // Whether to enable the online state pruning
+	OnlinePruningRate int  `toml:",omitempty"` // Maximum number of trie nodes deleted per second
+
 	// Deprecated: use 'TransactionHistory' instead.
 	TxLookupLimit uint64 `toml:",omitempty"` // The maximum number of blocks from head whose tx indices are reserved.
 
@@ -122,6 +139,20 @@ type Config struct {
 	DatabaseFreezer    string
 	DatabaseEra        string
 
+	// Optional object store to offload the sealed ancient chain segments to,
+	// along with the cache allowance (in megabytes) of the offloaded segments.
+	DatabaseFreezerRemote      string `toml:",omitempty"`
+	DatabaseFreezerRemoteCache int
+
+	// Chain database directory of a primary node running alongside, which is
+	// opened read-only and followed instead of syncing the chain. Both nodes
+	// must run in archive mode with the hash state scheme.
+	DatabaseSecondary string `toml:",omitempty"`
+
+	// Time interval at which a secondary node catches up with the database of
+	// the primary node. Failed catch-ups are retried with a growing delay.
+	DatabaseSecondaryInterval time.Duration
+
 	TrieCleanCache int
 	TrieDirtyCache int
 	TrieTimeout    time.Duration
@@ -148,6 +179,13 @@ type Config struct {
 	VMTrace           string
 	VMTraceJsonConfig string
 
+	// State diff export options, the export is enabled if either the path or
+	// the socket is specified.
+	StateDiffFormat  string `toml:",omitempty"` // Encoding of the diffs, "json" or "rlp"
+	StateDiffPath    string `toml:",omitempty"` // Directory of the rotating diff files
+	StateDiffMaxSize int    `toml:",omitempty"` // Maximum size in megabytes of a diff file
+	StateDiffSocket  string `toml:",omitempty"` // Path of the unix socket streaming the diffs
+
 	// RPCGasCap is the global gas cap for eth-call variants.
 	RPCGasCap uint64
 
@@ -158,6 +196,9 @@ type Config struct {
 	// send-transaction variants. The unit is ether.
 	RPCTxFeeCap float64
 
+	// RPCDatabaseWrite allows modifying the database through the debug_db* APIs.
+	RPCDatabaseWrite bool `toml:",omitempty"`
+
 	// OverrideOsaka (TODO: remove after the fork)
 	OverrideOsaka *uint64 `toml:",omitempty"`
 
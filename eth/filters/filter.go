@@ -87,7 +87,7 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 		if header == nil {
 			return nil, errors.New("unknown block")
 		}
-		if header.Number.Uint64() < f.sys.backend.HistoryPruningCutoff() {
+		if header.Number.Uint64() < f.sys.backend.HistoryFloor() {
 			return nil, &history.PrunedHistoryError{}
 		}
 		return f.blockLogs(ctx, header)
@@ -119,7 +119,7 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 			}
 			return hdr.Number.Uint64(), nil
 		case rpc.EarliestBlockNumber.Int64():
-			earliest := f.sys.backend.HistoryPruningCutoff()
+			earliest := f.sys.backend.HistoryFloor()
 			hdr, _ := f.sys.backend.HeaderByNumber(ctx, rpc.BlockNumber(earliest))
 			if hdr == nil {
 				return 0, errors.New("earliest header not found")
@@ -65,7 +65,7 @@ type Backend interface {
 
 	CurrentHeader() *types.Header
 	ChainConfig() *params.ChainConfig
-	HistoryPruningCutoff() uint64
+	HistoryFloor() uint64 // First block whose logs are available, locally or from era files
 	SubscribeNewTxsEvent(chan<- core.NewTxsEvent) event.Subscription
 	SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription
 	SubscribeRemovedLogsEvent(ch chan<- core.RemovedLogsEvent) event.Subscription
@@ -312,10 +312,11 @@ func (es *EventSystem) SubscribeLogs(crit ethereum.FilterQuery, logs chan []*typ
 	}
 
 	if from == rpc.EarliestBlockNumber {
-		from = rpc.BlockNumber(es.backend.HistoryPruningCutoff())
+		from = rpc.BlockNumber(es.backend.HistoryFloor())
 	}
-	// Queries beyond the pruning cutoff are not supported.
-	if uint64(from) < es.backend.HistoryPruningCutoff() {
+	// Queries beyond the pruning cutoff are only supported for the history
+	// archived in era files.
+	if uint64(from) < es.backend.HistoryFloor() {
 		return nil, &history.PrunedHistoryError{}
 	}
 
@@ -47,6 +47,7 @@ type testBackend struct {
 	chainFeed       event.Feed
 	pendingBlock    *types.Block
 	pendingReceipts types.Receipts
+	historyFloor    uint64
 }
 
 func (b *testBackend) ChainConfig() *params.ChainConfig {
@@ -190,8 +191,8 @@ func (b *testBackend) setPending(block *types.Block, receipts types.Receipts) {
 	b.pendingReceipts = receipts
 }
 
-func (b *testBackend) HistoryPruningCutoff() uint64 {
-	return 0
+func (b *testBackend) HistoryFloor() uint64 {
+	return b.historyFloor
 }
 
 func newTestFilterSystem(db ethdb.Database, cfg Config) (*testBackend, *FilterSystem) {
@@ -19,6 +19,7 @@ package filters
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"math/big"
 	"strings"
 	"testing"
@@ -29,6 +30,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/filtermaps"
+	"github.com/ethereum/go-ethereum/core/history"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -419,6 +421,64 @@ func testFilters(t *testing.T, history uint64, noHistory bool) {
 	})
 }
 
+// TestFiltersPrunedHistory tests that logs below the history pruning cutoff are
+// served as long as the history is available from era files, and rejected as
+// pruned otherwise.
+func TestFiltersPrunedHistory(t *testing.T) {
+	var (
+		db           = rawdb.NewMemoryDatabase()
+		backend, sys = newTestFilterSystem(db, Config{})
+		api          = NewFilterAPI(sys)
+		addr         = common.BytesToAddress([]byte("jeff"))
+
+		gspec = &core.Genesis{
+			BaseFee: big.NewInt(params.InitialBaseFee),
+			Config:  params.TestChainConfig,
+		}
+	)
+	defer db.Close()
+	_, chain, receipts := core.GenerateChainWithGenesis(gspec, ethash.NewFaker(), 10, func(i int, gen *core.BlockGen) {
+		if i == 2 || i == 7 {
+			gen.AddUncheckedReceipt(makeReceipt(addr))
+			gen.AddUncheckedTx(types.NewTransaction(999, common.HexToAddress("0x999"), big.NewInt(999), 999, gen.BaseFee(), nil))
+		}
+	})
+	gspec.MustCommit(db, triedb.NewDatabase(db, triedb.HashDefaults))
+	for i, block := range chain {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(db, block.Hash())
+		rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), receipts[i])
+	}
+	backend.startFilterMaps(0, false, filtermaps.DefaultParams)
+	defer backend.stopFilterMaps()
+
+	crit := FilterCriteria{FromBlock: big.NewInt(0), ToBlock: big.NewInt(10), Addresses: []common.Address{addr}}
+
+	// The history below block 5 is pruned, but archived in era files.
+	logs, err := api.GetLogs(context.Background(), crit)
+	if err != nil {
+		t.Fatalf("Failed to retrieve archived logs: %v", err)
+	}
+	if len(logs) != 2 || logs[0].BlockNumber != 3 || logs[1].BlockNumber != 8 {
+		t.Fatalf("Unexpected logs: %v", logs)
+	}
+	hash := chain[2].Hash()
+	if logs, err := api.GetLogs(context.Background(), FilterCriteria{BlockHash: &hash}); err != nil || len(logs) != 1 {
+		t.Fatalf("Failed to retrieve archived block logs: %v %v", logs, err)
+	}
+	// The history below block 5 is pruned and not archived.
+	backend.historyFloor = 5
+
+	var pruned *history.PrunedHistoryError
+	if _, err := api.GetLogs(context.Background(), crit); !errors.As(err, &pruned) {
+		t.Fatalf("Expected pruned history error, got %v", err)
+	}
+	if _, err := api.GetLogs(context.Background(), FilterCriteria{BlockHash: &hash}); !errors.As(err, &pruned) {
+		t.Fatalf("Expected pruned history error for block filter, got %v", err)
+	}
+}
+
 func TestRangeLogs(t *testing.T) {
 	var (
 		db           = rawdb.NewMemoryDatabase()
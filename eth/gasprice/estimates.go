@@ -0,0 +1,290 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package gasprice
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// estimateBlocks is the number of recent blocks sampled for the fee estimates.
+const estimateBlocks = 20
+
+// feeTier describes a fee estimation tier: the percentile of the recently
+// observed inclusion thresholds it targets and the number of blocks within
+// which the transaction is expected to be included.
+type feeTier struct {
+	percentile int
+	blocks     uint64
+}
+
+var (
+	slowTier     = feeTier{percentile: 30, blocks: 10}
+	standardTier = feeTier{percentile: 60, blocks: 3}
+	fastTier     = feeTier{percentile: 90, blocks: 1}
+)
+
+// FeeEstimate is a single fee suggestion along with the estimated likelihood
+// of a transaction paying it being included in the chain.
+type FeeEstimate struct {
+	TipCap      *big.Int // Suggested max priority fee per gas
+	FeeCap      *big.Int // Suggested max fee per gas, covering base fee growth over Blocks
+	BlobFeeCap  *big.Int // Suggested max fee per blob gas, nil before Cancun
+	Blocks      uint64   // Number of blocks the suggestion is targeting
+	Probability float64  // Estimated probability of inclusion within Blocks
+}
+
+// FeeEstimates contains the slow, standard and fast fee suggestions derived
+// from the recent chain history and the current transaction pool contents.
+type FeeEstimates struct {
+	BlockNumber uint64   // Head block the estimates are based on
+	BaseFee     *big.Int // Base fee of the next block
+	BlobBaseFee *big.Int // Blob base fee of the next block, nil before Cancun
+
+	Slow     *FeeEstimate
+	Standard *FeeEstimate
+	Fast     *FeeEstimate
+}
+
+// FeeEstimates returns tiered fee suggestions together with the estimated
+// probability of inclusion for each of them.
+//
+// The probability is derived from the inclusion thresholds (lowest effective
+// tip of any non-coinbase transaction) of the recently sampled blocks, assuming
+// blocks are independent. Pending pool transactions paying a higher tip are
+// assumed to be included first, delaying the estimated inclusion accordingly.
+//
+// The inclusion thresholds are computed once per head block and cached until
+// the head changes, the pool contents are evaluated on every call.
+func (oracle *Oracle) FeeEstimates(ctx context.Context) (*FeeEstimates, error) {
+	head, err := oracle.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	thresholds, err := oracle.inclusionThresholds(ctx, head)
+	if err != nil {
+		return nil, err
+	}
+	// Compute the fees of the next block.
+	var (
+		config      = oracle.backend.ChainConfig()
+		baseFee     = new(big.Int)
+		blobBaseFee *big.Int
+		next        = new(big.Int).Add(head.Number, big.NewInt(1))
+	)
+	if config.IsLondon(next) {
+		baseFee = eip1559.CalcBaseFee(config, head)
+	}
+	if head.ExcessBlobGas != nil {
+		excess := eip4844.CalcExcessBlobGas(config, head, head.Time)
+		blobBaseFee = eip4844.CalcBlobFee(config, &types.Header{Number: next, Time: head.Time, ExcessBlobGas: &excess})
+	}
+	// Gather the tips the pending pool transactions pay on top of the next
+	// base fee, ignoring the ones which are not yet executable.
+	txs, err := oracle.backend.GetPoolTransactions()
+	if err != nil {
+		return nil, err
+	}
+	pool := make([]pooledTip, 0, len(txs))
+	for _, tx := range txs {
+		tip, err := tx.EffectiveGasTip(baseFee)
+		if err != nil {
+			continue
+		}
+		pool = append(pool, pooledTip{tip: tip, gas: tx.Gas()})
+	}
+	estimate := func(tier feeTier) *FeeEstimate {
+		tip := new(big.Int)
+		if len(thresholds) > 0 {
+			tip.Set(thresholds[(len(thresholds)-1)*tier.percentile/100])
+		}
+		if tip.Cmp(oracle.maxPrice) > 0 {
+			tip.Set(oracle.maxPrice)
+		}
+		res := &FeeEstimate{
+			TipCap:      tip,
+			FeeCap:      new(big.Int).Add(tip, projectBaseFee(config, head, baseFee, tier.blocks)),
+			Blocks:      tier.blocks,
+			Probability: inclusionProbability(thresholds, pool, head.GasLimit, tip, tier.blocks),
+		}
+		if blobBaseFee != nil {
+			res.BlobFeeCap = projectBlobBaseFee(config, head, tier.blocks)
+		}
+		return res
+	}
+	return &FeeEstimates{
+		BlockNumber: head.Number.Uint64(),
+		BaseFee:     baseFee,
+		BlobBaseFee: blobBaseFee,
+		Slow:        estimate(slowTier),
+		Standard:    estimate(standardTier),
+		Fast:        estimate(fastTier),
+	}, nil
+}
+
+// inclusionThresholds returns the sorted inclusion thresholds of the recently
+// sampled blocks up to the given head. Blocks without any transaction from a
+// sender other than the block producer don't tell anything about the accepted
+// tips, so they are skipped. The thresholds are cached until the head changes
+// and must not be modified by the caller.
+func (oracle *Oracle) inclusionThresholds(ctx context.Context, head *types.Header) ([]*big.Int, error) {
+	headHash := head.Hash()
+
+	oracle.cacheLock.RLock()
+	lastHead, lastThresholds := oracle.lastThresholdHead, oracle.lastThresholds
+	oracle.cacheLock.RUnlock()
+	if headHash == lastHead && lastThresholds != nil {
+		return lastThresholds, nil
+	}
+	oracle.fetchLock.Lock()
+	defer oracle.fetchLock.Unlock()
+
+	// Try checking the cache again, maybe the last fetch fetched what we need
+	oracle.cacheLock.RLock()
+	lastHead, lastThresholds = oracle.lastThresholdHead, oracle.lastThresholds
+	oracle.cacheLock.RUnlock()
+	if headHash == lastHead && lastThresholds != nil {
+		return lastThresholds, nil
+	}
+	thresholds := make([]*big.Int, 0, estimateBlocks)
+	for number := head.Number.Uint64(); number > 0 && head.Number.Uint64()-number < estimateBlocks; number-- {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		block, err := oracle.backend.BlockByNumber(ctx, rpc.BlockNumber(number))
+		if err != nil {
+			return nil, err
+		}
+		if block == nil {
+			break
+		}
+		if threshold := oracle.inclusionThreshold(block); threshold != nil {
+			thresholds = append(thresholds, threshold)
+		}
+	}
+	slices.SortFunc(thresholds, func(a, b *big.Int) int { return a.Cmp(b) })
+
+	oracle.cacheLock.Lock()
+	oracle.lastThresholdHead = headHash
+	oracle.lastThresholds = thresholds
+	oracle.cacheLock.Unlock()
+
+	return thresholds, nil
+}
+
+// pooledTip is the effective tip and gas limit of a pending pool transaction.
+type pooledTip struct {
+	tip *big.Int
+	gas uint64
+}
+
+// inclusionThreshold returns the lowest effective tip paid by any transaction
+// in the block which was not sent by the block producer itself, or nil if the
+// block has no such transactions.
+func (oracle *Oracle) inclusionThreshold(block *types.Block) *big.Int {
+	var (
+		signer  = types.MakeSigner(oracle.backend.ChainConfig(), block.Number(), block.Time())
+		baseFee = block.BaseFee()
+		lowest  *big.Int
+	)
+	for _, tx := range block.Transactions() {
+		tip, err := tx.EffectiveGasTip(baseFee)
+		if err != nil || tip.Cmp(oracle.ignorePrice) < 0 {
+			continue
+		}
+		if sender, err := types.Sender(signer, tx); err != nil || sender == block.Coinbase() {
+			continue
+		}
+		if lowest == nil || tip.Cmp(lowest) < 0 {
+			lowest = tip
+		}
+	}
+	return lowest
+}
+
+// inclusionProbability estimates the probability of a transaction paying the
+// given tip being included within the given number of blocks.
+func inclusionProbability(thresholds []*big.Int, pool []pooledTip, gasLimit uint64, tip *big.Int, blocks uint64) float64 {
+	if len(thresholds) == 0 {
+		return 1
+	}
+	// Calculate the chance of a single block accepting the tip.
+	var accepted int
+	for _, threshold := range thresholds {
+		if threshold.Cmp(tip) <= 0 {
+			accepted++
+		}
+	}
+	single := float64(accepted) / float64(len(thresholds))
+
+	// Pool transactions paying more are expected to be included first, filling
+	// up whole blocks ahead of ours.
+	var ahead uint64
+	for _, p := range pool {
+		if p.tip.Cmp(tip) > 0 {
+			ahead += p.gas
+		}
+	}
+	var skipped uint64
+	if gasLimit > 0 {
+		skipped = ahead / gasLimit
+	}
+	if skipped >= blocks {
+		return 0
+	}
+	return 1 - math.Pow(1-single, float64(blocks-skipped))
+}
+
+// projectBaseFee returns the base fee of the last block in a window of the
+// given size starting at the next block, assuming all blocks are full.
+func projectBaseFee(config *params.ChainConfig, head *types.Header, baseFee *big.Int, blocks uint64) *big.Int {
+	fee := new(big.Int).Set(baseFee)
+	if head.BaseFee == nil {
+		return fee
+	}
+	parent := &types.Header{Number: new(big.Int).Add(head.Number, big.NewInt(1)), GasLimit: head.GasLimit, GasUsed: head.GasLimit, BaseFee: fee}
+	for i := uint64(1); i < blocks; i++ {
+		fee = eip1559.CalcBaseFee(config, parent)
+		parent = &types.Header{Number: new(big.Int).Add(parent.Number, big.NewInt(1)), GasLimit: head.GasLimit, GasUsed: head.GasLimit, BaseFee: fee}
+	}
+	return fee
+}
+
+// projectBlobBaseFee returns the blob base fee of the last block in a window
+// of the given size starting at the next block, assuming all blocks carry the
+// maximum number of blobs.
+func projectBlobBaseFee(config *params.ChainConfig, head *types.Header, blocks uint64) *big.Int {
+	var (
+		excess = eip4844.CalcExcessBlobGas(config, head, head.Time)
+		parent = &types.Header{Number: new(big.Int).Add(head.Number, big.NewInt(1)), Time: head.Time, BaseFee: head.BaseFee, ExcessBlobGas: &excess}
+	)
+	for i := uint64(1); i < blocks; i++ {
+		used := eip4844.MaxBlobGasPerBlock(config, parent.Time)
+		parent.BlobGasUsed = &used
+		excess := eip4844.CalcExcessBlobGas(config, parent, parent.Time)
+		parent = &types.Header{Number: new(big.Int).Add(parent.Number, big.NewInt(1)), Time: parent.Time, BaseFee: parent.BaseFee, ExcessBlobGas: &excess}
+	}
+	return eip4844.CalcBlobFee(config, parent)
+}
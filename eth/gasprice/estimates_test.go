@@ -0,0 +1,126 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package gasprice
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestFeeEstimates(t *testing.T) {
+	var cases = []struct {
+		crowded bool // whether the pool has a full block of better paying txs
+
+		expTips   [3]int64 // expected slow, standard and fast tips in gwei
+		expChance [3]float64
+	}{
+		// The sampled inclusion thresholds are 13G..32G, one per block
+		{false, [3]int64{18, 24, 30}, [3]float64{1 - math.Pow(0.7, 10), 1 - math.Pow(0.4, 3), 0.9}},
+		{true, [3]int64{18, 24, 30}, [3]float64{1 - math.Pow(0.7, 9), 1 - math.Pow(0.4, 2), 0}},
+	}
+	for i, c := range cases {
+		backend := newTestBackend(t, big.NewInt(0), big.NewInt(28), false)
+		if c.crowded {
+			head := backend.CurrentHeader()
+			backend.pool = types.Transactions{types.NewTx(&types.DynamicFeeTx{
+				ChainID:   backend.ChainConfig().ChainID,
+				To:        &common.Address{},
+				Gas:       head.GasLimit,
+				GasFeeCap: big.NewInt(500 * params.GWei),
+				GasTipCap: big.NewInt(100 * params.GWei),
+			})}
+		}
+		oracle := NewOracle(backend, Config{Blocks: 1, MaxHeaderHistory: 1000, MaxBlockHistory: 1000}, nil)
+
+		res, err := oracle.FeeEstimates(context.Background())
+		backend.teardown()
+		if err != nil {
+			t.Fatalf("Test case %d: failed to estimate fees: %v", i, err)
+		}
+		if res.BlockNumber != testHead {
+			t.Fatalf("Test case %d: head mismatch, want %d, got %d", i, testHead, res.BlockNumber)
+		}
+		if res.BlobBaseFee == nil {
+			t.Fatalf("Test case %d: missing blob base fee", i)
+		}
+		for j, est := range []*FeeEstimate{res.Slow, res.Standard, res.Fast} {
+			if want := big.NewInt(c.expTips[j] * params.GWei); est.TipCap.Cmp(want) != 0 {
+				t.Errorf("Test case %d, tier %d: tip mismatch, want %d, got %d", i, j, want, est.TipCap)
+			}
+			if math.Abs(est.Probability-c.expChance[j]) > 1e-9 {
+				t.Errorf("Test case %d, tier %d: probability mismatch, want %f, got %f", i, j, c.expChance[j], est.Probability)
+			}
+			if min := new(big.Int).Add(est.TipCap, res.BaseFee); est.FeeCap.Cmp(min) < 0 {
+				t.Errorf("Test case %d, tier %d: fee cap %d below next block requirement %d", i, j, est.FeeCap, min)
+			}
+			if est.BlobFeeCap == nil || est.BlobFeeCap.Cmp(res.BlobBaseFee) < 0 {
+				t.Errorf("Test case %d, tier %d: blob fee cap %v below blob base fee %d", i, j, est.BlobFeeCap, res.BlobBaseFee)
+			}
+		}
+	}
+}
+
+func TestFeeEstimatesCache(t *testing.T) {
+	backend := newTestBackend(t, big.NewInt(0), big.NewInt(28), false)
+	defer backend.teardown()
+
+	oracle := NewOracle(backend, Config{Blocks: 1, MaxHeaderHistory: 1000, MaxBlockHistory: 1000}, nil)
+	first, err := oracle.FeeEstimates(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to estimate fees: %v", err)
+	}
+	// Modifying a result must not leak into the subsequent ones
+	tip := new(big.Int).Set(first.Fast.TipCap)
+	first.Fast.TipCap.SetUint64(0)
+
+	// Pool changes must be reflected without a new head block
+	head := backend.CurrentHeader()
+	backend.pool = types.Transactions{types.NewTx(&types.DynamicFeeTx{
+		ChainID:   backend.ChainConfig().ChainID,
+		To:        &common.Address{},
+		Gas:       head.GasLimit,
+		GasFeeCap: big.NewInt(500 * params.GWei),
+		GasTipCap: big.NewInt(100 * params.GWei),
+	})}
+	second, err := oracle.FeeEstimates(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to estimate fees: %v", err)
+	}
+	if second.Fast.TipCap.Cmp(tip) != 0 {
+		t.Fatalf("Cached estimate modified, want tip %d, got %d", tip, second.Fast.TipCap)
+	}
+	if second.Fast.Probability != 0 {
+		t.Fatalf("Pool change not reflected, fast probability %f", second.Fast.Probability)
+	}
+}
+
+func TestInclusionThresholdEmptyBlock(t *testing.T) {
+	backend := newTestBackend(t, big.NewInt(0), big.NewInt(28), false)
+	defer backend.teardown()
+
+	oracle := NewOracle(backend, Config{Blocks: 1, MaxHeaderHistory: 1000, MaxBlockHistory: 1000}, nil)
+	block := types.NewBlockWithHeader(backend.CurrentHeader())
+	if threshold := oracle.inclusionThreshold(block); threshold != nil {
+		t.Fatalf("Empty block has inclusion threshold %d", threshold)
+	}
+}
@@ -62,6 +62,7 @@ type blockFees struct {
 type cacheKey struct {
 	number      uint64
 	percentiles string
+	byType      bool
 }
 
 // processedFees contains the results of a processed block.
@@ -71,6 +72,11 @@ type processedFees struct {
 	gasUsedRatio                 float64
 	blobGasUsedRatio             float64
 	blobBaseFee, nextBlobBaseFee *big.Int
+
+	// only set if the transaction type breakdown is requested
+	typeReward map[uint8][]*big.Int
+	blobCount  uint64
+	blobsPerTx []uint64
 }
 
 // txGasAndReward is sorted in ascending order based on reward
@@ -81,8 +87,9 @@ type txGasAndReward struct {
 
 // processBlock takes a blockFees structure with the blockNumber, the header and optionally
 // the block field filled in, retrieves the block from the backend if not present yet and
-// fills in the rest of the fields.
-func (oracle *Oracle) processBlock(bf *blockFees, percentiles []float64) {
+// fills in the rest of the fields. If byType is set, the rewards are additionally split up
+// by transaction type and the blob distribution of the block is computed.
+func (oracle *Oracle) processBlock(bf *blockFees, percentiles []float64, byType bool) {
 	config := oracle.backend.ChainConfig()
 
 	// Fill in base fee and next base fee.
@@ -113,6 +120,9 @@ func (oracle *Oracle) processBlock(bf *blockFees, percentiles []float64) {
 		}
 	}
 
+	if byType && bf.block != nil {
+		bf.results.blobCount, bf.results.blobsPerTx = blobDistribution(bf.block.Transactions())
+	}
 	if len(percentiles) == 0 {
 		// rewards were not requested, return null
 		return
@@ -128,6 +138,9 @@ func (oracle *Oracle) processBlock(bf *blockFees, percentiles []float64) {
 		for i := range bf.results.reward {
 			bf.results.reward[i] = new(big.Int)
 		}
+		if byType {
+			bf.results.typeReward = make(map[uint8][]*big.Int)
+		}
 		return
 	}
 
@@ -139,18 +152,67 @@ func (oracle *Oracle) processBlock(bf *blockFees, percentiles []float64) {
 	slices.SortStableFunc(sorter, func(a, b txGasAndReward) int {
 		return a.reward.Cmp(b.reward)
 	})
+	bf.results.reward = percentileRewards(sorter, bf.block.GasUsed(), percentiles)
 
-	var txIndex int
-	sumGasUsed := sorter[0].gasUsed
+	if byType {
+		var (
+			sorters = make(map[uint8][]txGasAndReward)
+			gasUsed = make(map[uint8]uint64)
+		)
+		for i, tx := range bf.block.Transactions() {
+			reward, _ := tx.EffectiveGasTip(bf.block.BaseFee())
+			sorters[tx.Type()] = append(sorters[tx.Type()], txGasAndReward{gasUsed: bf.receipts[i].GasUsed, reward: reward})
+			gasUsed[tx.Type()] += bf.receipts[i].GasUsed
+		}
+		bf.results.typeReward = make(map[uint8][]*big.Int, len(sorters))
+		for typ, sorter := range sorters {
+			slices.SortStableFunc(sorter, func(a, b txGasAndReward) int {
+				return a.reward.Cmp(b.reward)
+			})
+			bf.results.typeReward[typ] = percentileRewards(sorter, gasUsed[typ], percentiles)
+		}
+	}
+}
 
+// percentileRewards returns the requested percentiles of the rewards in the given
+// non-empty, ascending sorted list of transactions, weighted by gas used.
+func percentileRewards(sorter []txGasAndReward, totalGasUsed uint64, percentiles []float64) []*big.Int {
+	var (
+		reward     = make([]*big.Int, len(percentiles))
+		txIndex    int
+		sumGasUsed = sorter[0].gasUsed
+	)
 	for i, p := range percentiles {
-		thresholdGasUsed := uint64(float64(bf.block.GasUsed()) * p / 100)
-		for sumGasUsed < thresholdGasUsed && txIndex < len(bf.block.Transactions())-1 {
+		thresholdGasUsed := uint64(float64(totalGasUsed) * p / 100)
+		for sumGasUsed < thresholdGasUsed && txIndex < len(sorter)-1 {
 			txIndex++
 			sumGasUsed += sorter[txIndex].gasUsed
 		}
-		bf.results.reward[i] = sorter[txIndex].reward
+		reward[i] = sorter[txIndex].reward
+	}
+	return reward
+}
+
+// blobDistribution returns the total number of blobs carried by the given
+// transactions, along with a histogram in which entry i counts the blob
+// transactions carrying i+1 blobs.
+func blobDistribution(txs types.Transactions) (uint64, []uint64) {
+	var (
+		total     uint64
+		histogram []uint64
+	)
+	for _, tx := range txs {
+		n := len(tx.BlobHashes())
+		if n == 0 {
+			continue
+		}
+		for len(histogram) < n {
+			histogram = append(histogram, 0)
+		}
+		histogram[n-1]++
+		total += uint64(n)
 	}
+	return total, histogram
 }
 
 // resolveBlockRange resolves the specified block range to absolute block numbers while also
@@ -239,15 +301,107 @@ func (oracle *Oracle) resolveBlockRange(ctx context.Context, reqEnd rpc.BlockNum
 // Note: baseFee and blobBaseFee both include the next block after the newest of the returned range,
 // because this value can be derived from the newest block.
 func (oracle *Oracle) FeeHistory(ctx context.Context, blocks uint64, unresolvedLastBlock rpc.BlockNumber, rewardPercentiles []float64) (*big.Int, [][]*big.Int, []*big.Int, []float64, []*big.Int, []float64, error) {
+	oldestBlock, fees, err := oracle.feeHistory(ctx, blocks, unresolvedLastBlock, rewardPercentiles, false)
+	if err != nil || len(fees) == 0 {
+		return common.Big0, nil, nil, nil, nil, nil, err
+	}
+	var (
+		reward           = make([][]*big.Int, len(fees))
+		baseFee          = make([]*big.Int, len(fees)+1)
+		gasUsedRatio     = make([]float64, len(fees))
+		blobGasUsedRatio = make([]float64, len(fees))
+		blobBaseFee      = make([]*big.Int, len(fees)+1)
+	)
+	for i, res := range fees {
+		reward[i], baseFee[i], baseFee[i+1], gasUsedRatio[i] = res.reward, res.baseFee, res.nextBaseFee, res.gasUsedRatio
+		blobGasUsedRatio[i], blobBaseFee[i], blobBaseFee[i+1] = res.blobGasUsedRatio, res.blobBaseFee, res.nextBlobBaseFee
+	}
+	if len(rewardPercentiles) == 0 {
+		reward = nil
+	}
+	return new(big.Int).SetUint64(oldestBlock), reward, baseFee, gasUsedRatio, blobBaseFee, blobGasUsedRatio, nil
+}
+
+// TypedFeeHistory is the extended version of the fee history, in which the reward
+// percentiles are reported separately for each transaction type and the blob
+// usage of the blocks is broken down further.
+type TypedFeeHistory struct {
+	OldestBlock      *big.Int
+	BaseFee          []*big.Int
+	GasUsedRatio     []float64
+	BlobBaseFee      []*big.Int
+	BlobGasUsedRatio []float64
+
+	// Reward contains the requested percentiles of the effective priority fees
+	// paid by all the transactions in each block, the same as in FeeHistory.
+	Reward [][]*big.Int
+
+	// RewardByType contains, for every transaction type, the requested percentiles
+	// of the effective priority fees paid by the transactions of that type in each
+	// block, weighted by gas used. Rows of blocks without any transaction of a
+	// given type are nil.
+	RewardByType map[uint8][][]*big.Int
+
+	BlobCount  []uint64   // Number of blobs carried by each block
+	BlobsPerTx [][]uint64 // Per-block histogram, entry i counts the blob transactions carrying i+1 blobs
+}
+
+// FeeHistoryByType returns the same data as FeeHistory, but with the reward
+// percentiles split up by transaction type and with the blob count distribution
+// of each block added, allowing blob transactions to be priced separately from
+// regular traffic.
+func (oracle *Oracle) FeeHistoryByType(ctx context.Context, blocks uint64, unresolvedLastBlock rpc.BlockNumber, rewardPercentiles []float64) (*TypedFeeHistory, error) {
+	oldestBlock, fees, err := oracle.feeHistory(ctx, blocks, unresolvedLastBlock, rewardPercentiles, true)
+	if err != nil {
+		return nil, err
+	}
+	history := &TypedFeeHistory{OldestBlock: new(big.Int)}
+	if len(fees) == 0 {
+		return history, nil
+	}
+	history.OldestBlock.SetUint64(oldestBlock)
+	history.BaseFee = make([]*big.Int, len(fees)+1)
+	history.GasUsedRatio = make([]float64, len(fees))
+	history.BlobBaseFee = make([]*big.Int, len(fees)+1)
+	history.BlobGasUsedRatio = make([]float64, len(fees))
+	history.BlobCount = make([]uint64, len(fees))
+	history.BlobsPerTx = make([][]uint64, len(fees))
+	if len(rewardPercentiles) != 0 {
+		history.Reward = make([][]*big.Int, len(fees))
+		history.RewardByType = make(map[uint8][][]*big.Int)
+	}
+	for i, res := range fees {
+		history.BaseFee[i], history.BaseFee[i+1], history.GasUsedRatio[i] = res.baseFee, res.nextBaseFee, res.gasUsedRatio
+		history.BlobBaseFee[i], history.BlobBaseFee[i+1], history.BlobGasUsedRatio[i] = res.blobBaseFee, res.nextBlobBaseFee, res.blobGasUsedRatio
+		history.BlobCount[i], history.BlobsPerTx[i] = res.blobCount, res.blobsPerTx
+
+		if history.Reward != nil {
+			history.Reward[i] = res.reward
+		}
+		for typ, reward := range res.typeReward {
+			if history.RewardByType[typ] == nil {
+				history.RewardByType[typ] = make([][]*big.Int, len(fees))
+			}
+			history.RewardByType[typ][i] = reward
+		}
+	}
+	return history, nil
+}
+
+// feeHistory retrieves and processes the blocks of the requested range, returning
+// the number of the oldest block and the processed fees of each block in order.
+// If the transaction type breakdown is requested, the blocks are always fetched,
+// even if no reward percentiles are requested.
+func (oracle *Oracle) feeHistory(ctx context.Context, blocks uint64, unresolvedLastBlock rpc.BlockNumber, rewardPercentiles []float64, byType bool) (uint64, []processedFees, error) {
 	if blocks < 1 {
-		return common.Big0, nil, nil, nil, nil, nil, nil // returning with no data and no error means there are no retrievable blocks
+		return 0, nil, nil // returning with no data and no error means there are no retrievable blocks
 	}
 	maxFeeHistory := oracle.maxHeaderHistory
-	if len(rewardPercentiles) != 0 {
+	if len(rewardPercentiles) != 0 || byType {
 		maxFeeHistory = oracle.maxBlockHistory
 	}
 	if len(rewardPercentiles) > maxQueryLimit {
-		return common.Big0, nil, nil, nil, nil, nil, fmt.Errorf("%w: over the query limit %d", errInvalidPercentile, maxQueryLimit)
+		return 0, nil, fmt.Errorf("%w: over the query limit %d", errInvalidPercentile, maxQueryLimit)
 	}
 	if blocks > maxFeeHistory {
 		log.Warn("Sanitizing fee history length", "requested", blocks, "truncated", maxFeeHistory)
@@ -255,10 +409,10 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks uint64, unresolvedL
 	}
 	for i, p := range rewardPercentiles {
 		if p < 0 || p > 100 {
-			return common.Big0, nil, nil, nil, nil, nil, fmt.Errorf("%w: %f", errInvalidPercentile, p)
+			return 0, nil, fmt.Errorf("%w: %f", errInvalidPercentile, p)
 		}
 		if i > 0 && p <= rewardPercentiles[i-1] {
-			return common.Big0, nil, nil, nil, nil, nil, fmt.Errorf("%w: #%d:%f >= #%d:%f", errInvalidPercentile, i-1, rewardPercentiles[i-1], i, p)
+			return 0, nil, fmt.Errorf("%w: #%d:%f >= #%d:%f", errInvalidPercentile, i-1, rewardPercentiles[i-1], i, p)
 		}
 	}
 	var (
@@ -268,7 +422,7 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks uint64, unresolvedL
 	)
 	pendingBlock, pendingReceipts, lastBlock, blocks, err := oracle.resolveBlockRange(ctx, unresolvedLastBlock, blocks)
 	if err != nil || blocks == 0 {
-		return common.Big0, nil, nil, nil, nil, nil, err
+		return 0, nil, err
 	}
 	oldestBlock := lastBlock + 1 - blocks
 
@@ -293,16 +447,16 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks uint64, unresolvedL
 				if pendingBlock != nil && blockNumber >= pendingBlock.NumberU64() {
 					fees.block, fees.receipts = pendingBlock, pendingReceipts
 					fees.header = fees.block.Header()
-					oracle.processBlock(fees, rewardPercentiles)
+					oracle.processBlock(fees, rewardPercentiles, byType)
 					results <- fees
 				} else {
-					cacheKey := cacheKey{number: blockNumber, percentiles: string(percentileKey)}
+					cacheKey := cacheKey{number: blockNumber, percentiles: string(percentileKey), byType: byType}
 
 					if p, ok := oracle.historyCache.Get(cacheKey); ok {
 						fees.results = p
 						results <- fees
 					} else {
-						if len(rewardPercentiles) != 0 {
+						if len(rewardPercentiles) != 0 || byType {
 							fees.block, fees.err = oracle.backend.BlockByNumber(ctx, rpc.BlockNumber(blockNumber))
 							if fees.block != nil && fees.err == nil {
 								fees.receipts, fees.err = oracle.backend.GetReceipts(ctx, fees.block.Hash())
@@ -312,7 +466,7 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks uint64, unresolvedL
 							fees.header, fees.err = oracle.backend.HeaderByNumber(ctx, rpc.BlockNumber(blockNumber))
 						}
 						if fees.header != nil && fees.err == nil {
-							oracle.processBlock(fees, rewardPercentiles)
+							oracle.processBlock(fees, rewardPercentiles, byType)
 							if fees.err == nil {
 								oracle.historyCache.Add(cacheKey, fees.results)
 							}
@@ -325,22 +479,17 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks uint64, unresolvedL
 		}()
 	}
 	var (
-		reward           = make([][]*big.Int, blocks)
-		baseFee          = make([]*big.Int, blocks+1)
-		gasUsedRatio     = make([]float64, blocks)
-		blobGasUsedRatio = make([]float64, blocks)
-		blobBaseFee      = make([]*big.Int, blocks+1)
-		firstMissing     = blocks
+		fees         = make([]processedFees, blocks)
+		firstMissing = blocks
 	)
 	for ; blocks > 0; blocks-- {
-		fees := <-results
-		if fees.err != nil {
-			return common.Big0, nil, nil, nil, nil, nil, fees.err
+		res := <-results
+		if res.err != nil {
+			return 0, nil, res.err
 		}
-		i := fees.blockNumber - oldestBlock
-		if fees.results.baseFee != nil {
-			reward[i], baseFee[i], baseFee[i+1], gasUsedRatio[i] = fees.results.reward, fees.results.baseFee, fees.results.nextBaseFee, fees.results.gasUsedRatio
-			blobGasUsedRatio[i], blobBaseFee[i], blobBaseFee[i+1] = fees.results.blobGasUsedRatio, fees.results.blobBaseFee, fees.results.nextBlobBaseFee
+		i := res.blockNumber - oldestBlock
+		if res.results.baseFee != nil {
+			fees[i] = res.results
 		} else {
 			// getting no block and no error means we are requesting into the future (might happen because of a reorg)
 			if i < firstMissing {
@@ -349,14 +498,7 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks uint64, unresolvedL
 		}
 	}
 	if firstMissing == 0 {
-		return common.Big0, nil, nil, nil, nil, nil, nil
-	}
-	if len(rewardPercentiles) != 0 {
-		reward = reward[:firstMissing]
-	} else {
-		reward = nil
+		return 0, nil, nil
 	}
-	baseFee, gasUsedRatio = baseFee[:firstMissing+1], gasUsedRatio[:firstMissing]
-	blobBaseFee, blobGasUsedRatio = blobBaseFee[:firstMissing+1], blobGasUsedRatio[:firstMissing]
-	return new(big.Int).SetUint64(oldestBlock), reward, baseFee, gasUsedRatio, blobBaseFee, blobGasUsedRatio, nil
+	return oldestBlock, fees[:firstMissing], nil
 }
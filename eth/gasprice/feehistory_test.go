@@ -20,8 +20,11 @@ import (
 	"context"
 	"errors"
 	"math/big"
+	"reflect"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
@@ -105,3 +108,53 @@ func TestFeeHistory(t *testing.T) {
 		}
 	}
 }
+
+func TestFeeHistoryByType(t *testing.T) {
+	backend := newTestBackend(t, big.NewInt(16), big.NewInt(28), false)
+	defer backend.teardown()
+	oracle := NewOracle(backend, Config{MaxHeaderHistory: 1000, MaxBlockHistory: 1000}, nil)
+
+	history, err := oracle.FeeHistoryByType(context.Background(), 10, 32, []float64{0, 50})
+	if err != nil {
+		t.Fatalf("Failed to retrieve fee history: %v", err)
+	}
+	if history.OldestBlock.Uint64() != 23 {
+		t.Fatalf("First block mismatch, want %d, got %d", 23, history.OldestBlock)
+	}
+	if len(history.BaseFee) != 11 || len(history.GasUsedRatio) != 10 || len(history.BlobCount) != 10 {
+		t.Fatalf("Array length mismatch: baseFee %d, gasUsedRatio %d, blobCount %d", len(history.BaseFee), len(history.GasUsedRatio), len(history.BlobCount))
+	}
+	// The combined rewards must match the ones of the plain fee history
+	_, reward, _, _, _, _, err := oracle.FeeHistory(context.Background(), 10, 32, []float64{0, 50})
+	if err != nil {
+		t.Fatalf("Failed to retrieve fee history: %v", err)
+	}
+	if !reflect.DeepEqual(history.Reward, reward) {
+		t.Fatalf("Combined reward mismatch, want %v, got %v", reward, history.Reward)
+	}
+	if _, ok := history.RewardByType[types.LegacyTxType]; ok {
+		t.Fatalf("Unexpected legacy rewards after the London fork")
+	}
+	for i := 0; i < 10; i++ {
+		number := int64(23 + i)
+		if reward := history.RewardByType[types.DynamicFeeTxType][i]; len(reward) != 2 || reward[0].Cmp(big.NewInt(number*params.GWei)) != 0 {
+			t.Fatalf("Block %d: dynamic fee reward mismatch, got %v", number, reward)
+		}
+		blobReward := history.RewardByType[types.BlobTxType][i]
+		if number < 28 {
+			if blobReward != nil || history.BlobCount[i] != 0 || len(history.BlobsPerTx[i]) != 0 {
+				t.Fatalf("Block %d: unexpected blob data before Cancun", number)
+			}
+			continue
+		}
+		if len(blobReward) != 2 || blobReward[1].Cmp(big.NewInt(number*params.GWei)) != 0 {
+			t.Fatalf("Block %d: blob reward mismatch, got %v", number, blobReward)
+		}
+		if history.BlobCount[i] != 6 {
+			t.Fatalf("Block %d: blob count mismatch, want %d, got %d", number, 6, history.BlobCount[i])
+		}
+		if len(history.BlobsPerTx[i]) != 1 || history.BlobsPerTx[i][0] != 6 {
+			t.Fatalf("Block %d: blob distribution mismatch, got %v", number, history.BlobsPerTx[i])
+		}
+	}
+}
@@ -55,6 +55,7 @@ type OracleBackend interface {
 	BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Block, error)
 	GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error)
 	Pending() (*types.Block, types.Receipts, *state.StateDB)
+	GetPoolTransactions() (types.Transactions, error)
 	ChainConfig() *params.ChainConfig
 	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
 }
@@ -74,6 +75,9 @@ type Oracle struct {
 	maxHeaderHistory, maxBlockHistory uint64
 
 	historyCache *lru.Cache[cacheKey, processedFees]
+
+	lastThresholdHead common.Hash
+	lastThresholds    []*big.Int
 }
 
 // NewOracle returns a new gasprice oracle which can recommend suitable
@@ -42,7 +42,8 @@ const testHead = 32
 
 type testBackend struct {
 	chain   *core.BlockChain
-	pending bool // pending block available
+	pending bool               // pending block available
+	pool    types.Transactions // pending pool transactions
 }
 
 func (b *testBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
@@ -110,6 +111,10 @@ func (b *testBackend) Pending() (*types.Block, types.Receipts, *state.StateDB) {
 	return nil, nil, nil
 }
 
+func (b *testBackend) GetPoolTransactions() (types.Transactions, error) {
+	return b.pool, nil
+}
+
 func (b *testBackend) ChainConfig() *params.ChainConfig {
 	return b.chain.Config()
 }
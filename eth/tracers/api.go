@@ -41,6 +41,7 @@ import (
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/internal/ethapi/override"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -71,6 +72,13 @@ const (
 
 var errTxNotFound = errors.New("transaction not found")
 
+var (
+	accountCacheHitMeter  = metrics.NewRegisteredMeter("eth/tracers/account/reads/cache/hit", nil)
+	accountCacheMissMeter = metrics.NewRegisteredMeter("eth/tracers/account/reads/cache/miss", nil)
+	storageCacheHitMeter  = metrics.NewRegisteredMeter("eth/tracers/storage/reads/cache/hit", nil)
+	storageCacheMissMeter = metrics.NewRegisteredMeter("eth/tracers/storage/reads/cache/miss", nil)
+)
+
 // StateReleaseFunc is used to deallocate resources held by constructing a
 // historical state for tracing purposes.
 type StateReleaseFunc func()
@@ -102,6 +110,28 @@ func NewAPI(backend Backend) *API {
 	return &API{backend: backend}
 }
 
+// shareStateReader recreates the freshly constructed state of the given root with
+// a reader caching the resolved accounts and storage slots. The reader is shared
+// by all the copies of the returned state, so that the concurrent tracing workers
+// don't resolve the same state entries repeatedly. The cache statistics are
+// reported once the state is released.
+func shareStateReader(root common.Hash, statedb *state.StateDB, release StateReleaseFunc) (*state.StateDB, StateReleaseFunc, error) {
+	reader := state.NewReaderWithCache(statedb.Reader())
+	shared, err := state.NewWithReader(root, statedb.Database(), reader)
+	if err != nil {
+		release()
+		return nil, nil, err
+	}
+	return shared, func() {
+		stats := reader.GetStats()
+		accountCacheHitMeter.Mark(stats.AccountHit)
+		accountCacheMissMeter.Mark(stats.AccountMiss)
+		storageCacheHitMeter.Mark(stats.StorageHit)
+		storageCacheMissMeter.Mark(stats.StorageMiss)
+		release()
+	}, nil
+}
+
 // chainContext constructs the context reader which is used by the evm for reading
 // the necessary chain context.
 func (api *API) chainContext(ctx context.Context) core.ChainContext {
@@ -378,6 +408,13 @@ func (api *API) traceChain(start, end *types.Block, config *TraceConfig, closed
 				failed = err
 				break
 			}
+			// The state is accessed by both the tracer of the next block and the
+			// state creator for the subsequent block, share the resolved states.
+			statedb, release, err = shareStateReader(block.Root(), statedb, release)
+			if err != nil {
+				failed = err
+				break
+			}
 			// Insert block's parent beacon block root in the state
 			// as per EIP-4788.
 			context := core.NewEVMBlockContext(next.Header(), api.chainContext(ctx), nil)
@@ -598,6 +635,16 @@ func (api *API) traceBlock(ctx context.Context, block *types.Block, config *Trac
 	if err != nil {
 		return nil, err
 	}
+	// JS tracers have high overhead. In this case run a parallel
+	// process that generates states in one thread and traces txes
+	// in separate worker threads, sharing the resolved states.
+	parallel := config != nil && config.Tracer != nil && *config.Tracer != "" && DefaultDirectory.IsJS(*config.Tracer)
+	if parallel {
+		statedb, release, err = shareStateReader(parent.Root(), statedb, release)
+		if err != nil {
+			return nil, err
+		}
+	}
 	defer release()
 
 	blockCtx := core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil)
@@ -608,14 +655,8 @@ func (api *API) traceBlock(ctx context.Context, block *types.Block, config *Trac
 	if api.backend.ChainConfig().IsPrague(block.Number(), block.Time()) {
 		core.ProcessParentBlockHash(block.ParentHash(), evm)
 	}
-
-	// JS tracers have high overhead. In this case run a parallel
-	// process that generates states in one thread and traces txes
-	// in separate worker threads.
-	if config != nil && config.Tracer != nil && *config.Tracer != "" {
-		if isJS := DefaultDirectory.IsJS(*config.Tracer); isJS {
-			return api.traceBlockParallel(ctx, block, statedb, config)
-		}
+	if parallel {
+		return api.traceBlockParallel(ctx, block, statedb, config)
 	}
 	// Native tracers have low overhead
 	var (
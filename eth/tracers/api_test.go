@@ -17,6 +17,7 @@
 package tracers
 
 import (
+	"bytes"
 	"context"
 	"crypto/ecdsa"
 	"encoding/json"
@@ -38,6 +39,7 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/stateless"
 	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
@@ -643,6 +645,147 @@ func TestTraceBlock(t *testing.T) {
 	}
 }
 
+func TestTraceBlockParallel(t *testing.T) {
+	t.Parallel()
+
+	// Initialize test accounts
+	accounts := newAccounts(3)
+	genesis := &core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc: types.GenesisAlloc{
+			accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+			accounts[1].addr: {Balance: big.NewInt(params.Ether)},
+			accounts[2].addr: {Balance: big.NewInt(params.Ether)},
+		},
+	}
+	genBlocks := 2
+	signer := types.HomesteadSigner{}
+	backend := newTestBackend(t, genBlocks, genesis, func(i int, b *core.BlockGen) {
+		// Transfer from account[0] to account[1] repeatedly, the transactions
+		// access the same accounts.
+		for j := 0; j < 8; j++ {
+			tx, _ := types.SignTx(types.NewTransaction(uint64(i*8+j), accounts[1].addr, big.NewInt(1000), params.TxGas, b.BaseFee(), nil), signer, accounts[0].key)
+			b.AddTx(tx)
+		}
+	})
+	defer backend.chain.Stop()
+	api := NewAPI(backend)
+
+	block, _ := api.blockByNumber(context.Background(), rpc.BlockNumber(genBlocks))
+	parent, _ := api.blockByNumber(context.Background(), rpc.BlockNumber(genBlocks-1))
+
+	want, err := api.traceBlock(context.Background(), block, nil)
+	if err != nil {
+		t.Fatalf("Failed to trace block: %v", err)
+	}
+	statedb, release, err := backend.StateAtBlock(context.Background(), parent, defaultTraceReexec, nil, true, false)
+	if err != nil {
+		t.Fatalf("Failed to retrieve state: %v", err)
+	}
+	statedb, release, err = shareStateReader(parent.Root(), statedb, release)
+	if err != nil {
+		t.Fatalf("Failed to share state reader: %v", err)
+	}
+	defer release()
+
+	have, err := api.traceBlockParallel(context.Background(), block, statedb, nil)
+	if err != nil {
+		t.Fatalf("Failed to trace block in parallel: %v", err)
+	}
+	haveBlob, _ := json.Marshal(have)
+	wantBlob, _ := json.Marshal(want)
+	if !bytes.Equal(haveBlob, wantBlob) {
+		t.Fatalf("Result mismatch, have\n%s\nwant\n%s", haveBlob, wantBlob)
+	}
+	// The accounts resolved by the state generator must be served from the
+	// shared cache to the tracing workers.
+	stats := statedb.Reader().(state.ReaderWithStats).GetStats()
+	if stats.AccountHit == 0 {
+		t.Fatalf("Shared state cache is not hit: %+v", stats)
+	}
+}
+
+func TestWitnessBreakdown(t *testing.T) {
+	t.Parallel()
+
+	// Initialize test accounts and a contract reading all of its storage slots
+	var (
+		accounts = newAccounts(2)
+		contract = common.HexToAddress("0xc0de")
+		code     []byte
+		storage  = make(map[common.Hash]common.Hash)
+	)
+	for i := 0; i < 32; i++ {
+		code = append(code, byte(vm.PUSH1), byte(i), byte(vm.SLOAD), byte(vm.POP))
+		storage[common.BigToHash(big.NewInt(int64(i)))] = common.HexToHash("0x01")
+	}
+	code = append(code, byte(vm.STOP))
+
+	genesis := &core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc: types.GenesisAlloc{
+			accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+			accounts[1].addr: {Balance: big.NewInt(params.Ether)},
+			contract:         {Code: code, Storage: storage},
+		},
+	}
+	signer := types.HomesteadSigner{}
+	backend := newTestBackend(t, 1, genesis, func(i int, b *core.BlockGen) {
+		// Call the contract twice with a plain transfer in between, only the
+		// first call should be charged with the contract storage and code.
+		for j, to := range []common.Address{contract, accounts[1].addr, contract} {
+			tx, _ := types.SignTx(types.NewTransaction(uint64(j), to, big.NewInt(0), 100000, b.BaseFee(), nil), signer, accounts[0].key)
+			b.AddTx(tx)
+		}
+	})
+	defer backend.chain.Stop()
+	api := NewAPI(backend)
+
+	block, _ := api.blockByNumber(context.Background(), rpc.BlockNumber(1))
+	result, err := api.WitnessBreakdown(context.Background(), block.Hash(), nil)
+	if err != nil {
+		t.Fatalf("Failed to break down witness: %v", err)
+	}
+	if len(result.Transactions) != 3 {
+		t.Fatalf("Unexpected number of transactions: %d", len(result.Transactions))
+	}
+	// The parts of the witness must add up to the whole
+	var (
+		nodes = result.Unattributed.Nodes
+		size  = result.Unattributed.Bytes
+		codes = result.Unattributed.Codes
+		usage = []*stateless.Usage{result.Block}
+	)
+	for _, tx := range result.Transactions {
+		usage = append(usage, tx.Usage)
+	}
+	for _, u := range usage {
+		nodes += u.AccountNodes + u.StorageNodes
+		size += u.AccountBytes + u.StorageBytes
+		codes += u.Codes
+	}
+	if nodes != result.Nodes || size != result.Bytes || codes != result.Codes {
+		t.Fatalf("Witness parts mismatch: nodes %d/%d, bytes %d/%d, codes %d/%d", nodes, result.Nodes, size, result.Bytes, codes, result.Codes)
+	}
+	// Without deletions, every trie node must be on the path of an access
+	if result.Unattributed.Nodes != 0 {
+		t.Fatalf("Unexpected unattributed witness part: %+v", result.Unattributed)
+	}
+	// The contract storage and code must be attributed to the first call
+	first := result.Transactions[0].Contracts[contract]
+	if first == nil || first.StorageNodes == 0 || first.CodeBytes != len(code) {
+		t.Fatalf("Unexpected contract usage of the first call: %+v", first)
+	}
+	if first.StorageNodes != result.Transactions[0].StorageNodes {
+		t.Fatalf("Storage nodes attributed to other contracts: %d != %d", first.StorageNodes, result.Transactions[0].StorageNodes)
+	}
+	for i := 1; i < 3; i++ {
+		if tx := result.Transactions[i]; tx.StorageNodes != 0 || tx.Codes != 0 {
+			t.Fatalf("Unexpected usage of transaction %d: %+v", i, tx.Usage)
+		}
+	}
+}
+
 func TestTracingWithOverrides(t *testing.T) {
 	t.Parallel()
 	// Initialize test accounts
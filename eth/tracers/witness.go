@@ -0,0 +1,331 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie/trienode"
+)
+
+// stateAccess is a state entry resolved by the state reader during execution.
+type stateAccess struct {
+	tx   int            // Index of the transaction resolving the entry, -1 for the block
+	addr common.Address // Address of the account
+	slot *common.Hash   // Storage slot key, nil for account and code accesses
+	code *common.Hash   // Code hash, nil for account and storage accesses
+}
+
+// accessRecorder is a state reader recording the state entries resolved through
+// it, along with the transaction resolving them. The state database resolves
+// each entry only once per block, so the first transaction requiring an entry
+// is the one recorded.
+//
+// accessRecorder is safe for concurrent access.
+type accessRecorder struct {
+	state.Reader
+
+	tx       int                                         // Index of the transaction being executed, -1 for the block
+	accesses []stateAccess                               // State entries resolved, in order of resolution
+	accounts map[common.Address]*types.StateAccount      // Accounts resolved, nil if non-existent
+	slots    map[common.Address]map[common.Hash]struct{} // Storage slots resolved
+	codes    map[common.Hash]struct{}                    // Contract codes resolved
+	lock     sync.Mutex
+}
+
+// newAccessRecorder wraps the given state reader into an access recorder.
+func newAccessRecorder(reader state.Reader) *accessRecorder {
+	return &accessRecorder{
+		Reader:   reader,
+		tx:       -1,
+		accounts: make(map[common.Address]*types.StateAccount),
+		slots:    make(map[common.Address]map[common.Hash]struct{}),
+		codes:    make(map[common.Hash]struct{}),
+	}
+}
+
+// setTx sets the index of the transaction being executed, -1 for the block.
+func (r *accessRecorder) setTx(tx int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.tx = tx
+}
+
+// Account implements state.StateReader, recording the resolved account.
+func (r *accessRecorder) Account(addr common.Address) (*types.StateAccount, error) {
+	account, err := r.Reader.Account(addr)
+	if err != nil {
+		return nil, err
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, ok := r.accounts[addr]; !ok {
+		var copied *types.StateAccount
+		if account != nil {
+			copied = account.Copy()
+		}
+		r.accounts[addr] = copied
+		r.accesses = append(r.accesses, stateAccess{tx: r.tx, addr: addr})
+	}
+	return account, nil
+}
+
+// Storage implements state.StateReader, recording the resolved storage slot.
+func (r *accessRecorder) Storage(addr common.Address, slot common.Hash) (common.Hash, error) {
+	value, err := r.Reader.Storage(addr, slot)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, ok := r.slots[addr][slot]; !ok {
+		if r.slots[addr] == nil {
+			r.slots[addr] = make(map[common.Hash]struct{})
+		}
+		r.slots[addr][slot] = struct{}{}
+		r.accesses = append(r.accesses, stateAccess{tx: r.tx, addr: addr, slot: &slot})
+	}
+	return value, nil
+}
+
+// Code implements state.ContractCodeReader, recording the resolved code.
+func (r *accessRecorder) Code(addr common.Address, codeHash common.Hash) ([]byte, error) {
+	code, err := r.Reader.Code(addr, codeHash)
+	if err != nil {
+		return nil, err
+	}
+	r.recordCode(addr, codeHash)
+	return code, nil
+}
+
+// CodeSize implements state.ContractCodeReader, recording the resolved code.
+func (r *accessRecorder) CodeSize(addr common.Address, codeHash common.Hash) (int, error) {
+	size, err := r.Reader.CodeSize(addr, codeHash)
+	if err != nil {
+		return 0, err
+	}
+	r.recordCode(addr, codeHash)
+	return size, nil
+}
+
+// recordCode records the access to the code with the given hash.
+func (r *accessRecorder) recordCode(addr common.Address, codeHash common.Hash) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, ok := r.codes[codeHash]; !ok {
+		r.codes[codeHash] = struct{}{}
+		r.accesses = append(r.accesses, stateAccess{tx: r.tx, addr: addr, code: &codeHash})
+	}
+}
+
+// txWitnessUsage is the part of the witness attributed to a transaction.
+type txWitnessUsage struct {
+	TxHash common.Hash `json:"txHash"`
+	*stateless.Usage
+}
+
+// witnessBreakdown is the result of a witness breakdown, splitting the stateless
+// witness of a block among its transactions and the contracts accessed.
+type witnessBreakdown struct {
+	Nodes     int `json:"nodes"`     // Number of trie nodes in the witness
+	Bytes     int `json:"bytes"`     // Total size of the trie nodes in the witness
+	Codes     int `json:"codes"`     // Number of contract codes in the witness
+	CodeBytes int `json:"codeBytes"` // Total size of the contract codes in the witness
+
+	Transactions []*txWitnessUsage   `json:"transactions"` // Parts attributed to the transactions
+	Block        *stateless.Usage    `json:"block"`        // Part attributed to the block level processing
+	Unattributed stateless.Remainder `json:"unattributed"` // Part not attributed to anything
+}
+
+// WitnessBreakdown executes a block (bad- or canon- or side-) while collecting
+// its stateless witness, and returns the size of the witness broken down by the
+// transactions and the contracts accessed by them. Every trie node and contract
+// code is attributed to the first transaction requiring it; the accesses made
+// by system calls, withdrawals and block rewards are attributed to the block.
+func (api *API) WitnessBreakdown(ctx context.Context, hash common.Hash, config *TraceConfig) (*witnessBreakdown, error) {
+	block, _ := api.blockByHash(ctx, hash)
+	if block == nil {
+		// Check in the bad blocks
+		block = rawdb.ReadBadBlock(api.backend.ChainDb(), hash)
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block %#x not found", hash)
+	}
+	if block.NumberU64() == 0 {
+		return nil, errors.New("genesis is not traceable")
+	}
+	parent, err := api.blockByNumberAndHash(ctx, rpc.BlockNumber(block.NumberU64()-1), block.ParentHash())
+	if err != nil {
+		return nil, err
+	}
+	reexec := defaultTraceReexec
+	if config != nil && config.Reexec != nil {
+		reexec = *config.Reexec
+	}
+	statedb, release, err := api.backend.StateAtBlock(ctx, parent, reexec, nil, true, false)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// Recreate the state on top of a reader recording the resolved entries and
+	// enable the witness collection.
+	recorder := newAccessRecorder(statedb.Reader())
+	statedb, err = state.NewWithReader(parent.Root(), statedb.Database(), recorder)
+	if err != nil {
+		return nil, err
+	}
+	witness, err := stateless.NewWitness(block.Header(), api.chainContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	statedb.StartPrefetcher("tracer", witness)
+	defer statedb.StopPrefetcher()
+
+	// Execute the block through the state processor, attributing the accesses
+	// made within the transactions to them and everything else to the block.
+	chainConfig := api.backend.ChainConfig()
+	hc, err := core.NewHeaderChain(api.backend.ChainDb(), chainConfig, api.backend.Engine(), func() bool { return ctx.Err() != nil })
+	if err != nil {
+		return nil, err
+	}
+	var (
+		txIndex int
+		hooks   = &tracing.Hooks{
+			OnTxStart: func(vm *tracing.VMContext, tx *types.Transaction, from common.Address) {
+				recorder.setTx(txIndex)
+				txIndex++
+			},
+			OnTxEnd: func(receipt *types.Receipt, err error) {
+				recorder.setTx(-1)
+			},
+		}
+	)
+	if _, err := core.NewStateProcessor(chainConfig, hc).Process(block, statedb, vm.Config{Tracer: hooks}); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	// Hash the state to gather the trie nodes into the witness
+	statedb.IntermediateRoot(chainConfig.IsEIP158(block.Number()))
+	if err := statedb.Error(); err != nil {
+		return nil, err
+	}
+	attribution, err := attributeWitness(statedb.Database(), parent.Root(), witness, recorder, len(block.Transactions()))
+	if err != nil {
+		return nil, err
+	}
+	result := &witnessBreakdown{
+		Codes:        len(witness.Codes),
+		Nodes:        len(witness.State),
+		Block:        attribution.Block,
+		Unattributed: attribution.Unattributed,
+	}
+	for node := range witness.State {
+		result.Bytes += len(node)
+	}
+	for code := range witness.Codes {
+		result.CodeBytes += len(code)
+	}
+	for i, tx := range block.Transactions() {
+		result.Transactions = append(result.Transactions, &txWitnessUsage{
+			TxHash: tx.Hash(),
+			Usage:  attribution.Txs[i],
+		})
+	}
+	return result, nil
+}
+
+// attributeWitness splits the witness among the transactions which resolved the
+// state entries recorded. The trie nodes required by each entry are those on its
+// proof path in the pre-state, keyed by the hash of the address or slot.
+func attributeWitness(db state.Database, root common.Hash, witness *stateless.Witness, recorder *accessRecorder, txs int) (*stateless.Attribution, error) {
+	attribution := stateless.NewAttribution(witness, txs)
+
+	accountTrie, err := db.OpenTrie(root)
+	if err != nil {
+		return nil, err
+	}
+	storageTries := make(map[common.Address]state.Trie)
+	for _, access := range recorder.accesses {
+		switch {
+		case access.code != nil:
+			code, err := recorder.Reader.Code(access.addr, *access.code)
+			if err != nil {
+				return nil, err
+			}
+			attribution.AddCode(access.tx, access.addr, code)
+
+		case access.slot != nil:
+			// Slots of the accounts not existing in the pre-state, or
+			// without storage, are not backed by any trie node.
+			account := recorder.accounts[access.addr]
+			if account == nil || account.Root == types.EmptyRootHash {
+				continue
+			}
+			storageTrie := storageTries[access.addr]
+			if storageTrie == nil {
+				storageTrie, err = db.OpenStorageTrie(root, access.addr, account.Root, accountTrie)
+				if err != nil {
+					return nil, err
+				}
+				storageTries[access.addr] = storageTrie
+			}
+			var proof trienode.ProofList
+			if err := storageTrie.Prove(crypto.Keccak256(access.slot.Bytes()), &proof); err != nil {
+				return nil, err
+			}
+			attribution.AddStorageNodes(access.tx, access.addr, proofNodes(proof))
+
+		default:
+			var proof trienode.ProofList
+			if err := accountTrie.Prove(crypto.Keccak256(access.addr.Bytes()), &proof); err != nil {
+				return nil, err
+			}
+			attribution.AddAccountNodes(access.tx, proofNodes(proof))
+		}
+	}
+	attribution.Finalize()
+	return attribution, nil
+}
+
+// proofNodes converts the proof list into a list of raw trie nodes.
+func proofNodes(proof trienode.ProofList) [][]byte {
+	nodes := make([][]byte, len(proof))
+	for i, node := range proof {
+		nodes[i] = node
+	}
+	return nodes
+}
@@ -94,6 +94,43 @@ type Compacter interface {
 	Compact(start []byte, limit []byte) error
 }
 
+// Checkpointer wraps the Checkpoint method of a backing data store.
+type Checkpointer interface {
+	// Checkpoint creates a consistent point-in-time copy of the data store in
+	// the given directory, which must not exist yet. The data store remains
+	// fully operational while the checkpoint is being taken.
+	//
+	// The optional callback is invoked once the point-in-time view of the data
+	// store is captured, allowing associated data to be captured consistently.
+	Checkpoint(dir string, fn func() error) error
+}
+
+// Follower wraps the CatchUp method of a read-only data store opened as the
+// secondary of a data store owned and written by another process.
+type Follower interface {
+	// CatchUp refreshes the view of the data store to reflect the changes made
+	// by the primary since the last catch-up.
+	CatchUp() error
+}
+
+// Snapshot is a consistent point-in-time read-only view of a key-value data
+// store, unaffected by the writes made after its creation.
+type Snapshot interface {
+	KeyValueReader
+	Iteratee
+
+	// Release releases the resources associated with the snapshot, it can't be
+	// used afterwards.
+	Release()
+}
+
+// Snapshotter wraps the NewSnapshot method of a backing data store.
+type Snapshotter interface {
+	// NewSnapshot creates a point-in-time view of the data store, which must be
+	// released after use.
+	NewSnapshot() (Snapshot, error)
+}
+
 // KeyValueStore contains all the methods required to allow handling different
 // key-value data stores backing the high level database.
 type KeyValueStore interface {
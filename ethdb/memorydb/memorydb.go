@@ -195,6 +195,22 @@ func (db *Database) NewIterator(prefix []byte, start []byte) ethdb.Iterator {
 	}
 }
 
+// NewSnapshot creates a point-in-time view of the database, as a deep copy of
+// its content.
+func (db *Database) NewSnapshot() (ethdb.Snapshot, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return nil, errMemorydbClosed
+	}
+	copied := make(map[string][]byte, len(db.db))
+	for key, value := range db.db {
+		copied[key] = common.CopyBytes(value)
+	}
+	return &snapshot{db: &Database{db: copied}}, nil
+}
+
 // Stat returns the statistic data of the database.
 func (db *Database) Stat() (string, error) {
 	return "", nil
@@ -338,6 +354,32 @@ func (b *batch) Replay(w ethdb.KeyValueWriter) error {
 	return nil
 }
 
+// snapshot is a point-in-time view of a memory database.
+type snapshot struct {
+	db *Database
+}
+
+// Has retrieves if a key is present in the snapshot.
+func (snap *snapshot) Has(key []byte) (bool, error) {
+	return snap.db.Has(key)
+}
+
+// Get retrieves the given key if it's present in the snapshot.
+func (snap *snapshot) Get(key []byte) ([]byte, error) {
+	return snap.db.Get(key)
+}
+
+// NewIterator creates a binary-alphabetical iterator over a subset of the
+// snapshot content.
+func (snap *snapshot) NewIterator(prefix []byte, start []byte) ethdb.Iterator {
+	return snap.db.NewIterator(prefix, start)
+}
+
+// Release releases the snapshot, any consecutive data access op fails.
+func (snap *snapshot) Release() {
+	snap.db.Close()
+}
+
 // iterator can walk over the (potentially partial) keyspace of a memory key
 // value store. Internally it is a deep copy of the entire iterated state,
 // sorted by keys.
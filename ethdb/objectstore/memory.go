@@ -0,0 +1,100 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package objectstore
+
+import (
+	"io"
+	"sort"
+	"sync"
+)
+
+// Memory is an in-memory object store, mostly useful for testing.
+type Memory struct {
+	objects map[string][]byte
+	lock    sync.RWMutex
+}
+
+// NewMemory creates an empty in-memory object store.
+func NewMemory() *Memory {
+	return &Memory{objects: make(map[string][]byte)}
+}
+
+// Put implements Store.
+func (m *Memory) Put(key string, r io.Reader, size int64) error {
+	blob := make([]byte, size)
+	if _, err := io.ReadFull(r, blob); err != nil {
+		return err
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.objects[key] = blob
+	return nil
+}
+
+// ReadAt implements Store.
+func (m *Memory) ReadAt(key string, p []byte, offset int64) (int, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	blob, ok := m.objects[key]
+	if !ok {
+		return 0, ErrNotFound
+	}
+	if offset >= int64(len(blob)) {
+		return 0, io.EOF
+	}
+	n := copy(p, blob[offset:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Size implements Store.
+func (m *Memory) Size(key string) (int64, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	blob, ok := m.objects[key]
+	if !ok {
+		return 0, ErrNotFound
+	}
+	return int64(len(blob)), nil
+}
+
+// Delete implements Store.
+func (m *Memory) Delete(key string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.objects, key)
+	return nil
+}
+
+// Keys returns the sorted keys of all the stored objects.
+func (m *Memory) Keys() []string {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	keys := make([]string, 0, len(m.objects))
+	for key := range m.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
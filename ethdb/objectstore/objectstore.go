@@ -0,0 +1,87 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package objectstore implements a minimal client for S3-compatible object
+// stores, used for offloading immutable database files.
+package objectstore
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned if the requested object doesn't exist.
+var ErrNotFound = errors.New("object not found")
+
+// Store is an object store holding immutable blobs addressed by key.
+type Store interface {
+	// Put uploads the object with the given size read from the reader,
+	// replacing any existing object with the same key.
+	Put(key string, r io.Reader, size int64) error
+
+	// ReadAt reads len(p) bytes of the object starting at the given offset.
+	// Following the io.ReaderAt semantics, io.EOF is returned alongside the
+	// number of bytes read if the object ends before p is filled.
+	ReadAt(key string, p []byte, offset int64) (int, error)
+
+	// Size returns the size of the object.
+	Size(key string) (int64, error)
+
+	// Delete removes the object, deleting a non-existent object is a noop.
+	Delete(key string) error
+}
+
+// Open creates an object store client from the given location, in the form
+// of s3://bucket/prefix?region=<region>&endpoint=<url>&timeout=<duration>.
+//
+// The region defaults to us-east-1 and the endpoint to the AWS one of the region.
+// Specifying the endpoint allows using any S3-compatible store. The timeout of
+// the requests defaults to one minute, uploads are only required to receive a
+// response within it after sending the object. The credentials are read from
+// the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables.
+func Open(location string) (Store, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "s3" {
+		return nil, fmt.Errorf("unsupported object store scheme %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, errors.New("object store bucket is not specified")
+	}
+	var timeout time.Duration
+	if v := u.Query().Get("timeout"); v != "" {
+		if timeout, err = time.ParseDuration(v); err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("invalid object store timeout %q", v)
+		}
+	}
+	config := S3Config{
+		Bucket:    u.Host,
+		Prefix:    strings.Trim(u.Path, "/"),
+		Region:    u.Query().Get("region"),
+		Endpoint:  u.Query().Get("endpoint"),
+		AccessKey: os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		Timeout:   timeout,
+	}
+	return NewS3(config)
+}
@@ -0,0 +1,225 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+// unsignedPayload is the payload hash used for requests whose body is not
+// covered by the signature, avoiding to hash the uploaded files upfront.
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// defaultTimeout is the default timeout of the object store requests.
+const defaultTimeout = time.Minute
+
+// S3Config contains the settings of an S3-compatible object store.
+type S3Config struct {
+	Bucket    string        // Name of the bucket
+	Prefix    string        // Optional key prefix, without leading or trailing slash
+	Region    string        // Region of the bucket, defaults to us-east-1
+	Endpoint  string        // Endpoint of the store, defaults to the AWS endpoint of the region
+	AccessKey string        // AWS access key ID
+	SecretKey string        // AWS secret access key
+	Timeout   time.Duration // Timeout of the requests, defaults to one minute
+}
+
+// S3 is an object store client speaking the S3 REST protocol, using path-style
+// addressing so that any S3-compatible store can be used.
+type S3 struct {
+	endpoint *url.URL
+	bucket   string
+	prefix   string
+	region   string
+	creds    aws.Credentials
+	signer   *v4.Signer
+	client   *http.Client
+	timeout  time.Duration
+}
+
+// NewS3 creates a client for the S3-compatible object store.
+func NewS3(config S3Config) (*S3, error) {
+	if config.Bucket == "" {
+		return nil, errors.New("object store bucket is not specified")
+	}
+	region := config.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	endpoint := config.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid object store endpoint: %v", err)
+	}
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	// The duration of uploads depends on the size of the object, so they are not
+	// bounded as a whole. Instead, the store has to start responding within the
+	// timeout once the object was sent.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = timeout
+
+	return &S3{
+		endpoint: u,
+		bucket:   config.Bucket,
+		prefix:   config.Prefix,
+		region:   region,
+		creds:    aws.Credentials{AccessKeyID: config.AccessKey, SecretAccessKey: config.SecretKey},
+		signer:   v4.NewSigner(),
+		client:   &http.Client{Transport: transport},
+		timeout:  timeout,
+	}, nil
+}
+
+// url returns the path-style URL of the object.
+func (s *S3) url(key string) string {
+	u := *s.endpoint
+	u.Path = "/" + path.Join(s.bucket, s.prefix, key)
+	return u.String()
+}
+
+// do signs and sends the request.
+func (s *S3) do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("x-amz-content-sha256", unsignedPayload)
+	if err := s.signer.SignHTTP(req.Context(), s.creds, req, unsignedPayload, "s3", s.region, time.Now()); err != nil {
+		return nil, err
+	}
+	return s.client.Do(req)
+}
+
+// responseError converts an unsuccessful response into an error.
+func responseError(res *http.Response) error {
+	if res.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+	return fmt.Errorf("object store request failed: %s: %s", res.Status, body)
+}
+
+// Put implements Store, uploading the object with a single request.
+func (s *S3) Put(key string, r io.Reader, size int64) error {
+	req, err := http.NewRequest(http.MethodPut, s.url(key), r)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+
+	res, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return responseError(res)
+	}
+	return nil
+}
+
+// ReadAt implements Store, fetching the requested range of the object.
+func (s *S3) ReadAt(key string, p []byte, offset int64) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url(key), nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, offset+int64(len(p))-1))
+
+	res, err := s.do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusRequestedRangeNotSatisfiable:
+		return 0, io.EOF
+	default:
+		return 0, responseError(res)
+	}
+	// The range is cut off at the end of the object, report the short read
+	n, err := io.ReadFull(res.Body, p)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// Size implements Store.
+func (s *S3) Size(key string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, s.url(key), nil)
+	if err != nil {
+		return 0, err
+	}
+	res, err := s.do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return 0, responseError(res)
+	}
+	return res.ContentLength, nil
+}
+
+// Delete implements Store.
+func (s *S3) Delete(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.url(key), nil)
+	if err != nil {
+		return err
+	}
+	res, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	default:
+		return responseError(res)
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package objectstore
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeS3 is a minimal S3-compatible server backed by an in-memory store.
+type fakeS3 struct {
+	bucket string
+	store  *Memory
+}
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=key/") {
+		http.Error(w, "unauthorized", http.StatusForbidden)
+		return
+	}
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != s.bucket {
+		http.Error(w, "no such bucket", http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case http.MethodPut:
+		if err := s.store.Put(key, r.Body, r.ContentLength); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	case http.MethodHead:
+		size, err := s.store.Size(key)
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", fmt.Sprint(size))
+	case http.MethodGet:
+		var start, end int64
+		if _, err := fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-%d", &start, &end); err != nil {
+			http.Error(w, "range required", http.StatusBadRequest)
+			return
+		}
+		size, err := s.store.Size(key)
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if start >= size {
+			w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		end = min(end, size-1)
+		blob := make([]byte, end-start+1)
+		s.store.ReadAt(key, blob, start)
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(blob)
+	case http.MethodDelete:
+		s.store.Delete(key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3(t *testing.T) {
+	backend := &fakeS3{bucket: "bucket", store: NewMemory()}
+	server := httptest.NewServer(backend)
+	defer server.Close()
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "key")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	store, err := Open(fmt.Sprintf("s3://bucket/ancient/chain?endpoint=%s", url.QueryEscape(server.URL)))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	blob := bytes.Repeat([]byte("0123456789"), 100)
+	if err := store.Put("headers.0000.cdat", bytes.NewReader(blob), int64(len(blob))); err != nil {
+		t.Fatalf("Failed to put object: %v", err)
+	}
+	if keys := backend.store.Keys(); len(keys) != 1 || keys[0] != "ancient/chain/headers.0000.cdat" {
+		t.Fatalf("Unexpected objects: %v", keys)
+	}
+	if size, err := store.Size("headers.0000.cdat"); err != nil || size != int64(len(blob)) {
+		t.Fatalf("Unexpected object size, want %d, got %d (%v)", len(blob), size, err)
+	}
+	buf := make([]byte, 15)
+	if n, err := store.ReadAt("headers.0000.cdat", buf, 42); err != nil || n != len(buf) {
+		t.Fatalf("Failed to read object: %d, %v", n, err)
+	}
+	if !bytes.Equal(buf, blob[42:57]) {
+		t.Fatalf("Read mismatch, want %s, got %s", blob[42:57], buf)
+	}
+	if n, err := store.ReadAt("headers.0000.cdat", buf, int64(len(blob))-5); n != 5 || !errors.Is(err, io.EOF) {
+		t.Fatalf("Unexpected result reading beyond the end: %d, %v", n, err)
+	}
+	if !bytes.Equal(buf[:5], blob[len(blob)-5:]) {
+		t.Fatalf("Short read mismatch, want %s, got %s", blob[len(blob)-5:], buf[:5])
+	}
+	if _, err := store.ReadAt("headers.0001.cdat", buf, 0); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Unexpected error reading missing object: %v", err)
+	}
+	if err := store.Delete("headers.0000.cdat"); err != nil {
+		t.Fatalf("Failed to delete object: %v", err)
+	}
+	if _, err := store.Size("headers.0000.cdat"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Object not deleted: %v", err)
+	}
+}
+
+func TestS3Timeout(t *testing.T) {
+	stall := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-stall
+	}))
+	defer server.Close()
+	defer close(stall)
+
+	if _, err := Open("s3://bucket?timeout=soon"); err == nil {
+		t.Fatal("Invalid timeout accepted")
+	}
+	store, err := Open(fmt.Sprintf("s3://bucket?endpoint=%s&timeout=100ms", url.QueryEscape(server.URL)))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, err := store.ReadAt("headers.0000.cdat", make([]byte, 10), 0)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("Stalled request succeeded")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stalled request not timed out")
+	}
+}
@@ -188,6 +188,13 @@ func New(file string, cache int, handles int, namespace string, readonly bool) (
 	logger := log.New("database", file)
 	logger.Info("Allocated cache and file handles", "cache", common.StorageSize(cache*1024*1024), "handles", handles)
 
+	return open(file, cache, handles, namespace, readonly, logger, nil)
+}
+
+// open opens the pebble database with the given allowances, which are expected
+// to be sanitized already. The optional configure callback can adjust the options
+// before the database is opened.
+func open(file string, cache int, handles int, namespace string, readonly bool, logger log.Logger, configure func(*pebble.Options)) (*Database, error) {
 	// The max memtable size is limited by the uint32 offsets stored in
 	// internal/arenaskl.node, DeferredBatchOp, and flushableBatchEntry.
 	//
@@ -297,6 +304,9 @@ func New(file string, cache int, handles int, namespace string, readonly bool) (
 	// for more details.
 	opt.Experimental.ReadSamplingMultiplier = -1
 
+	if configure != nil {
+		configure(opt)
+	}
 	// Open the db and recover any potential corruptions
 	innerDB, err := pebble.Open(file, opt)
 	if err != nil {
@@ -492,6 +502,27 @@ func (d *Database) Compact(start []byte, limit []byte) error {
 	return d.db.Compact(start, limit, true) // Parallelization is preferred
 }
 
+// Checkpoint creates a consistent point-in-time copy of the key-value store in
+// the given directory, which must not exist yet. The files of the store are hard
+// linked into the checkpoint if possible, falling back to copying otherwise. The
+// write-ahead-log is flushed beforehand, so that the checkpoint doesn't depend on
+// replaying it. The optional callback is invoked right after the checkpoint is
+// created.
+func (d *Database) Checkpoint(dir string, fn func() error) error {
+	d.quitLock.RLock()
+	defer d.quitLock.RUnlock()
+	if d.closed {
+		return pebble.ErrClosed
+	}
+	if err := d.db.Checkpoint(dir, pebble.WithFlushedWAL()); err != nil {
+		return err
+	}
+	if fn != nil {
+		return fn()
+	}
+	return nil
+}
+
 // Path returns the path to the database directory.
 func (d *Database) Path() string {
 	return d.fn
@@ -714,6 +745,83 @@ func (b *batch) Replay(w ethdb.KeyValueWriter) error {
 	}
 }
 
+// NewSnapshot creates a point-in-time view of the database, which must be
+// released after use.
+func (d *Database) NewSnapshot() (ethdb.Snapshot, error) {
+	d.quitLock.RLock()
+	defer d.quitLock.RUnlock()
+	if d.closed {
+		return nil, pebble.ErrClosed
+	}
+	return &snapshot{db: d, snap: d.db.NewSnapshot()}, nil
+}
+
+// snapshot is a wrapper of the underlying snapshot in storage engine.
+type snapshot struct {
+	db   *Database
+	snap *pebble.Snapshot
+}
+
+// Has retrieves if a key is present in the snapshot.
+func (snap *snapshot) Has(key []byte) (bool, error) {
+	snap.db.quitLock.RLock()
+	defer snap.db.quitLock.RUnlock()
+	if snap.db.closed {
+		return false, pebble.ErrClosed
+	}
+	_, closer, err := snap.snap.Get(key)
+	if err == pebble.ErrNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	if err = closer.Close(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Get retrieves the given key if it's present in the snapshot.
+func (snap *snapshot) Get(key []byte) ([]byte, error) {
+	snap.db.quitLock.RLock()
+	defer snap.db.quitLock.RUnlock()
+	if snap.db.closed {
+		return nil, pebble.ErrClosed
+	}
+	dat, closer, err := snap.snap.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]byte, len(dat))
+	copy(ret, dat)
+	if err = closer.Close(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// NewIterator creates a binary-alphabetical iterator over a subset of the
+// snapshot content with a particular key prefix, starting at a particular
+// initial key (or after, if it does not exist).
+func (snap *snapshot) NewIterator(prefix []byte, start []byte) ethdb.Iterator {
+	iter, _ := snap.snap.NewIter(&pebble.IterOptions{
+		LowerBound: append(prefix, start...),
+		UpperBound: upperBound(prefix),
+	})
+	iter.First()
+	return &pebbleIterator{iter: iter, moved: true, released: false}
+}
+
+// Release releases the snapshot. It's a noop if the database is already closed.
+func (snap *snapshot) Release() {
+	snap.db.quitLock.RLock()
+	defer snap.db.quitLock.RUnlock()
+	if snap.db.closed {
+		return
+	}
+	snap.snap.Close()
+}
+
 // pebbleIterator is a wrapper of underlying iterator in storage engine.
 // The purpose of this structure is to implement the missing APIs.
 //
@@ -17,7 +17,9 @@
 package pebble
 
 import (
+	"bytes"
 	"errors"
+	"path/filepath"
 	"testing"
 
 	"github.com/cockroachdb/pebble"
@@ -78,3 +80,158 @@ func TestPebbleLogData(t *testing.T) {
 		t.Fatal("Unknown database entry")
 	}
 }
+
+func TestPebbleCheckpoint(t *testing.T) {
+	dir := t.TempDir()
+	db, err := New(filepath.Join(dir, "db"), 16, 16, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := db.Put([]byte("a"), []byte("1")); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Checkpoint(filepath.Join(dir, "checkpoint"), nil); err != nil {
+		t.Fatalf("failed to create checkpoint: %v", err)
+	}
+	// Modifications after the checkpoint must not be visible in it
+	if err := db.Put([]byte("a"), []byte("2")); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Put([]byte("b"), []byte("3")); err != nil {
+		t.Fatal(err)
+	}
+	cp, err := New(filepath.Join(dir, "checkpoint"), 16, 16, "", true)
+	if err != nil {
+		t.Fatalf("failed to open checkpoint: %v", err)
+	}
+	defer cp.Close()
+
+	if val, err := cp.Get([]byte("a")); err != nil || !bytes.Equal(val, []byte("1")) {
+		t.Fatalf("unexpected checkpoint value: %x, %v", val, err)
+	}
+	if has, _ := cp.Has([]byte("b")); has {
+		t.Fatal("checkpoint contains entry written afterwards")
+	}
+	// Checkpoints must not overwrite existing directories
+	if err := db.Checkpoint(filepath.Join(dir, "checkpoint"), nil); err == nil {
+		t.Fatal("checkpoint overwrote existing directory")
+	}
+}
+
+func TestPebbleSnapshot(t *testing.T) {
+	db, err := New(filepath.Join(t.TempDir(), "db"), 16, 16, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	db.Put([]byte("a"), []byte("1"))
+	db.Put([]byte("b"), []byte("2"))
+
+	snap, err := db.NewSnapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer snap.Release()
+
+	// Modifications after the snapshot must not be visible in it
+	db.Put([]byte("a"), []byte("3"))
+	db.Delete([]byte("b"))
+	db.Put([]byte("c"), []byte("4"))
+
+	if val, err := snap.Get([]byte("a")); err != nil || !bytes.Equal(val, []byte("1")) {
+		t.Fatalf("unexpected snapshot value: %x, %v", val, err)
+	}
+	if has, _ := snap.Has([]byte("b")); !has {
+		t.Fatal("snapshot misses entry deleted afterwards")
+	}
+	var keys []string
+	it := snap.NewIterator(nil, nil)
+	for it.Next() {
+		keys = append(keys, string(it.Key()))
+	}
+	it.Release()
+	if len(keys) != 2 || keys[0] != "a" || keys[1] != "b" {
+		t.Fatalf("unexpected snapshot keys: %v", keys)
+	}
+}
+
+func TestPebbleSecondary(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "db")
+	db, err := New(dir, 16, 16, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := db.Put([]byte("a"), []byte("1")); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SyncKeyValue(); err != nil {
+		t.Fatal(err)
+	}
+	secondary, err := NewSecondary(dir, 16, 16, "")
+	if err != nil {
+		t.Fatalf("failed to open secondary: %v", err)
+	}
+	defer secondary.Close()
+
+	if val, err := secondary.Get([]byte("a")); err != nil || !bytes.Equal(val, []byte("1")) {
+		t.Fatalf("unexpected secondary value: %x, %v", val, err)
+	}
+	if err := secondary.Put([]byte("b"), []byte("2")); err == nil {
+		t.Fatal("secondary accepted write")
+	}
+	// Modifications of the primary must only be visible after catching up
+	if err := db.Put([]byte("b"), []byte("2")); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SyncKeyValue(); err != nil {
+		t.Fatal(err)
+	}
+	if has, _ := secondary.Has([]byte("b")); has {
+		t.Fatal("secondary contains entry before catching up")
+	}
+	it := secondary.NewIterator(nil, nil)
+	defer it.Release()
+
+	if err := secondary.CatchUp(); err != nil {
+		t.Fatalf("failed to catch up: %v", err)
+	}
+	if val, err := secondary.Get([]byte("b")); err != nil || !bytes.Equal(val, []byte("2")) {
+		t.Fatalf("unexpected secondary value after catch-up: %x, %v", val, err)
+	}
+	// Iterators must keep working on the instance they were created on
+	var keys int
+	for it.Next() {
+		keys++
+	}
+	if err := it.Error(); err != nil || keys != 1 {
+		t.Fatalf("unexpected iteration over the outdated instance: %d keys, %v", keys, err)
+	}
+	// Catching up with an unmodified primary must not reopen the database
+	inst := secondary.current
+	if err := secondary.CatchUp(); err != nil {
+		t.Fatalf("failed to catch up: %v", err)
+	}
+	if secondary.current != inst {
+		t.Fatal("secondary reopened without modifications of the primary")
+	}
+	if err := db.Put([]byte("c"), []byte("3")); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SyncKeyValue(); err != nil {
+		t.Fatal(err)
+	}
+	if err := secondary.CatchUp(); err != nil {
+		t.Fatalf("failed to catch up: %v", err)
+	}
+	if secondary.current == inst {
+		t.Fatal("secondary not reopened after modifications of the primary")
+	}
+	if val, err := secondary.Get([]byte("c")); err != nil || !bytes.Equal(val, []byte("3")) {
+		t.Fatalf("unexpected secondary value after catch-up: %x, %v", val, err)
+	}
+}
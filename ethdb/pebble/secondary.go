@@ -0,0 +1,451 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package pebble
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// errSecondary is returned when attempting to write into a secondary database.
+var errSecondary = errors.New("pebble: secondary database is read-only")
+
+// secondaryFS is the filesystem of a secondary database. The directory lock is
+// held by the primary, so it's skipped; the secondary never modifies the files.
+type secondaryFS struct {
+	vfs.FS
+}
+
+// Lock implements vfs.FS, it's a noop as the lock is held by the primary.
+func (secondaryFS) Lock(name string) (io.Closer, error) {
+	return nopCloser{}, nil
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
+
+// secondaryInstance is an opened read-only instance of the database. It's closed
+// once it's superseded by a more recent instance and all the reads in flight on
+// it are done.
+type secondaryInstance struct {
+	db   *Database
+	refs atomic.Int64 // Number of reads in flight, plus one while being current
+}
+
+// release drops a reference of the instance, closing it if it was the last one.
+func (inst *secondaryInstance) release() {
+	if inst.refs.Add(-1) == 0 {
+		if err := inst.db.Close(); err != nil {
+			inst.db.log.Warn("Failed to close secondary database instance", "err", err)
+		}
+	}
+}
+
+// Secondary is a read-only view of a pebble database which is owned and written
+// concurrently by another process, the primary. Pebble can't tail the write-ahead
+// log of a live database, so the view is caught up by reopening the database in
+// read-only mode, replaying the log of the primary into a fresh memory table. The
+// reopening is skipped if neither the manifest nor the write-ahead logs changed
+// since the last catch-up, so a primary which isn't written costs no replays. The
+// block cache is shared across the reopened instances.
+//
+// The files of the database might be compacted away by the primary at any time,
+// failing the reads which hit them through an outdated instance. Such reads are
+// retried once against a freshly caught up instance.
+type Secondary struct {
+	fn        string
+	cache     *pebble.Cache
+	cacheSize int
+	handles   int
+	namespace string
+
+	lock    sync.RWMutex
+	current *secondaryInstance
+	files   []fileState // State of the files the current instance was opened on
+	closed  bool
+
+	log log.Logger
+}
+
+// NewSecondary opens the pebble database in the given directory as a secondary
+// of the primary process owning it.
+func NewSecondary(file string, cache int, handles int, namespace string) (*Secondary, error) {
+	// Ensure we have some minimal caching and file guarantees
+	if cache < minCache {
+		cache = minCache
+	}
+	if handles < minHandles {
+		handles = minHandles
+	}
+	logger := log.New("database", file)
+	logger.Info("Allocated cache and file handles", "cache", common.StorageSize(cache*1024*1024), "handles", handles, "secondary", true)
+
+	s := &Secondary{
+		fn:        file,
+		cache:     pebble.NewCache(int64(cache * 1024 * 1024)),
+		cacheSize: cache,
+		handles:   handles,
+		namespace: namespace,
+		log:       logger,
+	}
+	files, err := s.fileStates()
+	if err != nil {
+		s.cache.Unref()
+		return nil, err
+	}
+	inst, err := s.open()
+	if err != nil {
+		s.cache.Unref()
+		return nil, err
+	}
+	s.current, s.files = inst, files
+	return s, nil
+}
+
+// fileState is the size and modification time of a file of the database.
+type fileState struct {
+	name string
+	size int64
+	mod  time.Time
+}
+
+// fileStates returns the state of the files which are modified by the primary
+// whenever the content of the database changes: the manifest listing the live
+// tables, the pointer to the current manifest and the write-ahead logs.
+func (s *Secondary) fileStates() ([]fileState, error) {
+	entries, err := os.ReadDir(s.fn)
+	if err != nil {
+		return nil, err
+	}
+	var files []fileState
+	for _, entry := range entries {
+		name := entry.Name()
+		if name != "CURRENT" && !strings.HasPrefix(name, "MANIFEST-") && filepath.Ext(name) != ".log" {
+			continue
+		}
+		info, err := entry.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			continue // deleted by the primary in the meantime
+		}
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, fileState{name: name, size: info.Size(), mod: info.ModTime()})
+	}
+	return files, nil
+}
+
+// open opens a new read-only instance of the database, reflecting the current
+// content of the database files.
+func (s *Secondary) open() (*secondaryInstance, error) {
+	db, err := open(s.fn, s.cacheSize, s.handles, s.namespace, true, s.log, func(opt *pebble.Options) {
+		opt.Cache.Unref()
+		opt.Cache = s.cache
+		opt.FS = secondaryFS{vfs.Default}
+		opt.ErrorIfNotExists = true
+	})
+	if err != nil {
+		return nil, err
+	}
+	inst := &secondaryInstance{db: db}
+	inst.refs.Store(1)
+	return inst, nil
+}
+
+// acquire returns the current instance of the database, which must be released
+// after use.
+func (s *Secondary) acquire() (*secondaryInstance, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return nil, pebble.ErrClosed
+	}
+	s.current.refs.Add(1)
+	return s.current, nil
+}
+
+// CatchUp implements ethdb.Follower, reopening the database to reflect the
+// changes made by the primary since the last catch-up.
+func (s *Secondary) CatchUp() error {
+	return s.catchUp(false)
+}
+
+// catchUp reopens the database if the primary modified it since the current
+// instance was opened, or unconditionally if forced.
+func (s *Secondary) catchUp(force bool) error {
+	// The state of the files is taken before reopening, a modification racing
+	// with the reopening is thus picked up again by the next catch-up.
+	files, err := s.fileStates()
+	if err != nil {
+		return err
+	}
+	if !force {
+		s.lock.RLock()
+		unchanged := !s.closed && slices.Equal(files, s.files)
+		s.lock.RUnlock()
+		if unchanged {
+			return nil
+		}
+	}
+	inst, err := s.open()
+	if err != nil {
+		return err
+	}
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		inst.release()
+		return pebble.ErrClosed
+	}
+	prev := s.current
+	s.current, s.files = inst, files
+	s.lock.Unlock()
+
+	prev.release()
+	return nil
+}
+
+// read runs the read operation on the current instance, retrying it once after
+// catching up if it fails, as the files might have been deleted by the primary.
+func (s *Secondary) read(fn func(db *Database) error) error {
+	inst, err := s.acquire()
+	if err != nil {
+		return err
+	}
+	err = fn(inst.db)
+	inst.release()
+	if err == nil || errors.Is(err, pebble.ErrNotFound) {
+		return err
+	}
+	if err := s.catchUp(true); err != nil {
+		s.log.Debug("Failed to catch up secondary database", "err", err)
+		return err
+	}
+	if inst, err = s.acquire(); err != nil {
+		return err
+	}
+	defer inst.release()
+	return fn(inst.db)
+}
+
+// Close closes the current instance of the database, the instances with reads in
+// flight are closed once the reads are done.
+func (s *Secondary) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	s.current.release()
+	s.cache.Unref()
+	return nil
+}
+
+// Has retrieves if a key is present in the key-value store.
+func (s *Secondary) Has(key []byte) (bool, error) {
+	var has bool
+	err := s.read(func(db *Database) (err error) {
+		has, err = db.Has(key)
+		return err
+	})
+	return has, err
+}
+
+// Get retrieves the given key if it's present in the key-value store.
+func (s *Secondary) Get(key []byte) ([]byte, error) {
+	var value []byte
+	err := s.read(func(db *Database) (err error) {
+		value, err = db.Get(key)
+		return err
+	})
+	return value, err
+}
+
+// Put is not supported by the secondary database.
+func (s *Secondary) Put(key []byte, value []byte) error {
+	return errSecondary
+}
+
+// Delete is not supported by the secondary database.
+func (s *Secondary) Delete(key []byte) error {
+	return errSecondary
+}
+
+// DeleteRange is not supported by the secondary database.
+func (s *Secondary) DeleteRange(start, end []byte) error {
+	return errSecondary
+}
+
+// NewBatch creates a batch which fails to be written, as the secondary database
+// is read-only.
+func (s *Secondary) NewBatch() ethdb.Batch {
+	return new(secondaryBatch)
+}
+
+// NewBatchWithSize creates a batch which fails to be written, as the secondary
+// database is read-only.
+func (s *Secondary) NewBatchWithSize(size int) ethdb.Batch {
+	return new(secondaryBatch)
+}
+
+// NewIterator creates a binary-alphabetical iterator over a subset of database
+// content with a particular key prefix, starting at a particular initial key (or
+// after, if it does not exist). The iterator keeps iterating over the instance
+// it was created on, regardless of the subsequent catch-ups.
+func (s *Secondary) NewIterator(prefix []byte, start []byte) ethdb.Iterator {
+	inst, err := s.acquire()
+	if err != nil {
+		return &secondaryIterator{err: err}
+	}
+	return &secondaryIterator{Iterator: inst.db.NewIterator(prefix, start), inst: inst}
+}
+
+// Stat returns the internal metrics of the current instance.
+func (s *Secondary) Stat() (string, error) {
+	inst, err := s.acquire()
+	if err != nil {
+		return "", err
+	}
+	defer inst.release()
+	return inst.db.Stat()
+}
+
+// Compact is not supported by the secondary database.
+func (s *Secondary) Compact(start []byte, limit []byte) error {
+	return errSecondary
+}
+
+// SyncKeyValue is a noop, there are no writes to flush.
+func (s *Secondary) SyncKeyValue() error {
+	return nil
+}
+
+// Path returns the path to the database directory.
+func (s *Secondary) Path() string {
+	return s.fn
+}
+
+// secondaryIterator is an iterator of a secondary database, holding a reference
+// of the instance it's iterating over.
+type secondaryIterator struct {
+	ethdb.Iterator
+	inst *secondaryInstance
+	err  error
+}
+
+// Next moves the iterator to the next key/value pair.
+func (it *secondaryIterator) Next() bool {
+	if it.Iterator == nil {
+		return false
+	}
+	return it.Iterator.Next()
+}
+
+// Error returns any accumulated error.
+func (it *secondaryIterator) Error() error {
+	if it.Iterator == nil {
+		return it.err
+	}
+	return it.Iterator.Error()
+}
+
+// Key returns the key of the current key/value pair, or nil if done.
+func (it *secondaryIterator) Key() []byte {
+	if it.Iterator == nil {
+		return nil
+	}
+	return it.Iterator.Key()
+}
+
+// Value returns the value of the current key/value pair, or nil if done.
+func (it *secondaryIterator) Value() []byte {
+	if it.Iterator == nil {
+		return nil
+	}
+	return it.Iterator.Value()
+}
+
+// Release releases the iterator along with the reference of its instance.
+func (it *secondaryIterator) Release() {
+	if it.Iterator == nil {
+		return
+	}
+	it.Iterator.Release()
+	it.Iterator = nil
+	it.inst.release()
+}
+
+// secondaryBatch is a batch of a secondary database, which can't be written.
+type secondaryBatch struct {
+	size int
+}
+
+// Put inserts the given value into the batch for later committing.
+func (b *secondaryBatch) Put(key, value []byte) error {
+	b.size += len(key) + len(value)
+	return nil
+}
+
+// Delete inserts the key removal into the batch for later committing.
+func (b *secondaryBatch) Delete(key []byte) error {
+	b.size += len(key)
+	return nil
+}
+
+// DeleteRange inserts the range removal into the batch for later committing.
+func (b *secondaryBatch) DeleteRange(start, end []byte) error {
+	b.size += len(start) + len(end)
+	return nil
+}
+
+// ValueSize retrieves the amount of data queued up for writing.
+func (b *secondaryBatch) ValueSize() int {
+	return b.size
+}
+
+// Write fails, as the secondary database is read-only.
+func (b *secondaryBatch) Write() error {
+	return errSecondary
+}
+
+// Reset resets the batch for reuse.
+func (b *secondaryBatch) Reset() {
+	b.size = 0
+}
+
+// Replay is a noop, as the batch doesn't retain its content.
+func (b *secondaryBatch) Replay(w ethdb.KeyValueWriter) error {
+	return nil
+}
@@ -15,30 +15,44 @@
 // along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
 
 // Package remotedb implements the key-value database layer based on a remote geth
-// node. Under the hood, it utilises the `debug_dbGet` method to implement a
-// read-only database.
+// node. Under the hood, it utilises the `debug_db*` methods to access the
+// database of the remote node: reads, paginated iterations, snapshots and, if
+// enabled on the remote node, writes and batches.
 // There really are no guarantees in this database, since the local geth does not
-// exclusive access, but it can be used for basic diagnostics of a remote node.
+// exclusive access, but it can be used for diagnostics of a remote node. A
+// snapshot can be used for reading a consistent view of the key-value store.
 package remotedb
 
 import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
-// Database is a key-value lookup for a remote database via debug_dbGet.
+// iteratePageSize is the number of entries requested at once when iterating
+// over the remote database. The remote node might return less.
+const iteratePageSize = 1024
+
+// errNotSupported is returned for the operations which are not available on a
+// remote database.
+var errNotSupported = errors.New("not supported by remote database")
+
+// Database is a key-value lookup for a remote database via the debug_db* APIs.
 type Database struct {
 	remote *rpc.Client
 }
 
+// Has retrieves if a key is present in the remote key-value store.
 func (db *Database) Has(key []byte) (bool, error) {
-	if _, err := db.Get(key); err != nil {
-		return false, err
-	}
-	return true, nil
+	var resp bool
+	err := db.remote.Call(&resp, "debug_dbHas", hexutil.Bytes(key))
+	return resp, err
 }
 
+// Get retrieves the given key if it's present in the remote key-value store.
 func (db *Database) Get(key []byte) ([]byte, error) {
 	var resp hexutil.Bytes
 	err := db.remote.Call(&resp, "debug_dbGet", hexutil.Bytes(key))
@@ -57,8 +71,25 @@ func (db *Database) Ancient(kind string, number uint64) ([]byte, error) {
 	return resp, nil
 }
 
+// AncientRange retrieves multiple items in sequence, starting from the index
+// 'start'. The remote node caps the size of its responses, so the items are
+// requested in several rounds if maxBytes is not specified.
 func (db *Database) AncientRange(kind string, start, count, maxBytes uint64) ([][]byte, error) {
-	panic("not supported")
+	var items [][]byte
+	for uint64(len(items)) < count {
+		var resp []hexutil.Bytes
+		err := db.remote.Call(&resp, "debug_dbAncientRange", kind, start+uint64(len(items)), count-uint64(len(items)), maxBytes)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range resp {
+			items = append(items, item)
+		}
+		if len(resp) == 0 || maxBytes != 0 {
+			break
+		}
+	}
+	return items, nil
 }
 
 func (db *Database) Ancients() (uint64, error) {
@@ -68,67 +99,99 @@ func (db *Database) Ancients() (uint64, error) {
 }
 
 func (db *Database) Tail() (uint64, error) {
-	panic("not supported")
+	var resp uint64
+	err := db.remote.Call(&resp, "debug_dbAncientTail")
+	return resp, err
 }
 
 func (db *Database) AncientSize(kind string) (uint64, error) {
-	panic("not supported")
+	var resp uint64
+	err := db.remote.Call(&resp, "debug_dbAncientSize", kind)
+	return resp, err
 }
 
 func (db *Database) ReadAncients(fn func(op ethdb.AncientReaderOp) error) (err error) {
 	return fn(db)
 }
 
+// Put inserts the given value into the remote key-value store. It requires the
+// database modification over RPC to be enabled on the remote node.
 func (db *Database) Put(key []byte, value []byte) error {
-	panic("not supported")
+	return db.remote.Call(nil, "debug_dbPut", hexutil.Bytes(key), hexutil.Bytes(value))
 }
 
+// Delete removes the key from the remote key-value store. It requires the
+// database modification over RPC to be enabled on the remote node.
 func (db *Database) Delete(key []byte) error {
-	panic("not supported")
+	return db.remote.Call(nil, "debug_dbDelete", hexutil.Bytes(key))
 }
 
+// DeleteRange removes all keys in the range [start, end) from the remote
+// key-value store. It requires the database modification over RPC to be enabled
+// on the remote node.
 func (db *Database) DeleteRange(start, end []byte) error {
-	panic("not supported")
+	return db.remote.Call(nil, "debug_dbDeleteRange", optionalBytes(start), optionalBytes(end))
 }
 
 func (db *Database) ModifyAncients(f func(ethdb.AncientWriteOp) error) (int64, error) {
-	panic("not supported")
+	return 0, errNotSupported
 }
 
 func (db *Database) TruncateHead(n uint64) (uint64, error) {
-	panic("not supported")
+	return 0, errNotSupported
 }
 
 func (db *Database) TruncateTail(n uint64) (uint64, error) {
-	panic("not supported")
+	return 0, errNotSupported
 }
 
 func (db *Database) SyncAncient() error {
 	return nil
 }
 
+// NewBatch creates a write-only key-value store that buffers changes until a
+// final write is called, sending them to the remote node in a single request.
 func (db *Database) NewBatch() ethdb.Batch {
-	panic("not supported")
+	return &batch{db: db}
 }
 
+// NewBatchWithSize creates a write-only database batch with pre-allocated buffer.
 func (db *Database) NewBatchWithSize(size int) ethdb.Batch {
-	panic("not supported")
+	return &batch{db: db}
 }
 
+// NewIterator creates a binary-alphabetical iterator over a subset of the remote
+// database content with a particular key prefix, starting at a particular
+// initial key (or after, if it does not exist). The entries are fetched page by
+// page while iterating, without any consistency guarantee across the pages.
 func (db *Database) NewIterator(prefix []byte, start []byte) ethdb.Iterator {
-	panic("not supported")
+	return newIterator(db.remote, prefix, start, nil)
+}
+
+// NewSnapshot creates a point-in-time view of the remote key-value store, which
+// is held by the remote node until released.
+func (db *Database) NewSnapshot() (ethdb.Snapshot, error) {
+	var id string
+	if err := db.remote.Call(&id, "debug_dbNewSnapshot"); err != nil {
+		return nil, err
+	}
+	return &snapshot{remote: db.remote, id: id}, nil
 }
 
 func (db *Database) Stat() (string, error) {
-	return "", nil
+	var resp string
+	err := db.remote.Call(&resp, "debug_dbStat")
+	return resp, err
 }
 
 func (db *Database) AncientDatadir() (string, error) {
-	panic("not supported")
+	return "", errNotSupported
 }
 
+// Compact flattens the remote key-value store for the given key range. It
+// requires the database modification over RPC to be enabled on the remote node.
 func (db *Database) Compact(start []byte, limit []byte) error {
-	return nil
+	return db.remote.Call(nil, "debug_dbCompact", optionalBytes(start), optionalBytes(limit))
 }
 
 func (db *Database) SyncKeyValue() error {
@@ -146,3 +209,223 @@ func New(client *rpc.Client) ethdb.Database {
 	}
 	return &Database{remote: client}
 }
+
+// batchOp is a single operation of a batch, as sent to the remote node.
+type batchOp struct {
+	Op    string         `json:"op"`
+	Key   hexutil.Bytes  `json:"key"`
+	Value hexutil.Bytes  `json:"value,omitempty"`
+	End   *hexutil.Bytes `json:"end,omitempty"`
+}
+
+// optionalBytes converts a byte slice into an optional parameter, keeping nil
+// distinct from an empty slice.
+func optionalBytes(b []byte) *hexutil.Bytes {
+	if b == nil {
+		return nil
+	}
+	h := hexutil.Bytes(common.CopyBytes(b))
+	return &h
+}
+
+// batch is a write-only batch that commits changes to the remote database when
+// Write is called.
+type batch struct {
+	db   *Database
+	ops  []batchOp
+	size int
+}
+
+// Put inserts the given value into the batch for later committing.
+func (b *batch) Put(key, value []byte) error {
+	b.ops = append(b.ops, batchOp{Op: "put", Key: common.CopyBytes(key), Value: common.CopyBytes(value)})
+	b.size += len(key) + len(value)
+	return nil
+}
+
+// Delete inserts the key removal into the batch for later committing.
+func (b *batch) Delete(key []byte) error {
+	b.ops = append(b.ops, batchOp{Op: "delete", Key: common.CopyBytes(key)})
+	b.size += len(key)
+	return nil
+}
+
+// DeleteRange inserts the range removal into the batch for later committing.
+func (b *batch) DeleteRange(start, end []byte) error {
+	b.ops = append(b.ops, batchOp{Op: "deleteRange", Key: common.CopyBytes(start), End: optionalBytes(end)})
+	b.size += len(start) + len(end)
+	return nil
+}
+
+// ValueSize retrieves the amount of data queued up for writing.
+func (b *batch) ValueSize() int {
+	return b.size
+}
+
+// Write flushes the batch into the remote database atomically.
+func (b *batch) Write() error {
+	if len(b.ops) == 0 {
+		return nil
+	}
+	return b.db.remote.Call(nil, "debug_dbWrite", b.ops)
+}
+
+// Reset resets the batch for reuse.
+func (b *batch) Reset() {
+	b.ops = b.ops[:0]
+	b.size = 0
+}
+
+// Replay replays the batch contents.
+func (b *batch) Replay(w ethdb.KeyValueWriter) error {
+	for _, op := range b.ops {
+		var err error
+		switch op.Op {
+		case "put":
+			err = w.Put(op.Key, op.Value)
+		case "delete":
+			err = w.Delete(op.Key)
+		case "deleteRange":
+			rangeDeleter, ok := w.(ethdb.KeyValueRangeDeleter)
+			if !ok {
+				return errors.New("ethdb.KeyValueWriter does not implement DeleteRange")
+			}
+			var end []byte
+			if op.End != nil {
+				end = *op.End
+			}
+			err = rangeDeleter.DeleteRange(op.Key, end)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// iteratorPage is a page of consecutive entries returned by the remote node.
+type iteratorPage struct {
+	Keys   []hexutil.Bytes `json:"keys"`
+	Values []hexutil.Bytes `json:"values"`
+	More   bool            `json:"more"`
+}
+
+// iterator walks over the remote database, fetching the entries page by page.
+type iterator struct {
+	remote   *rpc.Client
+	prefix   []byte
+	start    []byte  // Start key of the next page, relative to the prefix
+	snapshot *string // Snapshot to iterate over, nil for the live database
+
+	page  iteratorPage
+	index int
+	done  bool // Flag whether the last page was fetched
+	err   error
+}
+
+func newIterator(remote *rpc.Client, prefix []byte, start []byte, snapshot *string) *iterator {
+	return &iterator{
+		remote:   remote,
+		prefix:   common.CopyBytes(prefix),
+		start:    common.CopyBytes(start),
+		snapshot: snapshot,
+		index:    -1,
+	}
+}
+
+// Next moves the iterator to the next key/value pair, fetching the next page of
+// entries if the current one is exhausted.
+func (it *iterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if it.index+1 < len(it.page.Keys) {
+		it.index++
+		return true
+	}
+	for !it.done {
+		var page iteratorPage
+		if err := it.remote.Call(&page, "debug_dbIterate", hexutil.Bytes(it.prefix), hexutil.Bytes(it.start), iteratePageSize, it.snapshot); err != nil {
+			it.err = err
+			it.page, it.index = iteratorPage{}, -1
+			return false
+		}
+		if len(page.Keys) != len(page.Values) {
+			it.err = errors.New("malformed iteration response")
+			it.page, it.index = iteratorPage{}, -1
+			return false
+		}
+		it.page, it.index, it.done = page, 0, !page.More
+		if len(page.Keys) > 0 {
+			// Continue right after the last key of the page
+			last := page.Keys[len(page.Keys)-1]
+			it.start = append(common.CopyBytes(last[len(it.prefix):]), 0)
+			return true
+		}
+	}
+	it.page, it.index = iteratorPage{}, -1
+	return false
+}
+
+// Error returns any accumulated error. Exhausting all the key/value pairs
+// is not considered to be an error.
+func (it *iterator) Error() error {
+	return it.err
+}
+
+// Key returns the key of the current key/value pair, or nil if done.
+func (it *iterator) Key() []byte {
+	if it.index < 0 || it.index >= len(it.page.Keys) {
+		return nil
+	}
+	return it.page.Keys[it.index]
+}
+
+// Value returns the value of the current key/value pair, or nil if done.
+func (it *iterator) Value() []byte {
+	if it.index < 0 || it.index >= len(it.page.Values) {
+		return nil
+	}
+	return it.page.Values[it.index]
+}
+
+// Release releases associated resources. Release should always succeed and can
+// be called multiple times without causing error.
+func (it *iterator) Release() {
+	it.page, it.index, it.done = iteratorPage{}, -1, true
+}
+
+// snapshot is a point-in-time view of the remote key-value store, held by the
+// remote node.
+type snapshot struct {
+	remote *rpc.Client
+	id     string
+}
+
+// Has retrieves if a key is present in the snapshot.
+func (snap *snapshot) Has(key []byte) (bool, error) {
+	var resp bool
+	err := snap.remote.Call(&resp, "debug_dbSnapshotHas", snap.id, hexutil.Bytes(key))
+	return resp, err
+}
+
+// Get retrieves the given key if it's present in the snapshot.
+func (snap *snapshot) Get(key []byte) ([]byte, error) {
+	var resp hexutil.Bytes
+	err := snap.remote.Call(&resp, "debug_dbSnapshotGet", snap.id, hexutil.Bytes(key))
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// NewIterator creates a binary-alphabetical iterator over a subset of the
+// snapshot content, consistent across the fetched pages.
+func (snap *snapshot) NewIterator(prefix []byte, start []byte) ethdb.Iterator {
+	return newIterator(snap.remote, prefix, start, &snap.id)
+}
+
+// Release releases the snapshot held by the remote node.
+func (snap *snapshot) Release() {
+	snap.remote.Call(nil, "debug_dbReleaseSnapshot", snap.id)
+}
@@ -0,0 +1,141 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package remotedb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/dbtest"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// testBackend is a backend of the debug API, exposing only the database.
+type testBackend struct {
+	ethapi.Backend
+	db    ethdb.Database
+	write bool
+}
+
+func (b *testBackend) ChainDb() ethdb.Database { return b.db }
+func (b *testBackend) RPCDatabaseWrite() bool  { return b.write }
+
+// newTestDatabase creates a remote database connected to an in-process node
+// serving the given database.
+func newTestDatabase(t *testing.T, db ethdb.Database, write bool) *Database {
+	server := rpc.NewServer()
+	if err := server.RegisterName("debug", ethapi.NewDebugAPI(&testBackend{db: db, write: write})); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Stop)
+	return New(rpc.DialInProc(server)).(*Database)
+}
+
+func TestRemoteDB(t *testing.T) {
+	t.Run("DatabaseSuite", func(t *testing.T) {
+		dbtest.TestDatabaseSuite(t, func() ethdb.KeyValueStore {
+			return newTestDatabase(t, rawdb.NewMemoryDatabase(), true)
+		})
+	})
+}
+
+func TestRemoteDBWriteDisabled(t *testing.T) {
+	db := newTestDatabase(t, rawdb.NewMemoryDatabase(), false)
+	if err := db.Put([]byte("key"), []byte("value")); err == nil {
+		t.Fatal("Put succeeded with writes disabled")
+	}
+	batch := db.NewBatch()
+	batch.Put([]byte("key"), []byte("value"))
+	if err := batch.Write(); err == nil {
+		t.Fatal("batch write succeeded with writes disabled")
+	}
+	if has, err := db.Has([]byte("key")); err != nil || has {
+		t.Fatalf("key written with writes disabled: has %v, err %v", has, err)
+	}
+}
+
+func TestRemoteDBPagedIteration(t *testing.T) {
+	var (
+		local = rawdb.NewMemoryDatabase()
+		db    = newTestDatabase(t, local, false)
+		count = 3*iteratePageSize + 10
+	)
+	for i := 0; i < count; i++ {
+		key := binary.BigEndian.AppendUint32([]byte("p"), uint32(i))
+		local.Put(key, key[1:])
+	}
+	local.Put([]byte("q"), []byte("other"))
+
+	it := db.NewIterator([]byte("p"), binary.BigEndian.AppendUint32(nil, 5))
+	defer it.Release()
+
+	want := 5
+	for it.Next() {
+		key := binary.BigEndian.AppendUint32([]byte("p"), uint32(want))
+		if !bytes.Equal(it.Key(), key) || !bytes.Equal(it.Value(), key[1:]) {
+			t.Fatalf("entry %d mismatch: have %x=%x", want, it.Key(), it.Value())
+		}
+		want++
+	}
+	if err := it.Error(); err != nil {
+		t.Fatal(err)
+	}
+	if want != count {
+		t.Fatalf("iterated entries mismatch: have %d, want %d", want, count)
+	}
+}
+
+func TestRemoteDBSnapshot(t *testing.T) {
+	var (
+		local = rawdb.NewMemoryDatabase()
+		db    = newTestDatabase(t, local, false)
+	)
+	local.Put([]byte("a"), []byte("1"))
+	local.Put([]byte("b"), []byte("2"))
+
+	snap, err := db.NewSnapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	local.Put([]byte("a"), []byte("3"))
+	local.Delete([]byte("b"))
+	local.Put([]byte("c"), []byte("4"))
+
+	if value, err := snap.Get([]byte("a")); err != nil || !bytes.Equal(value, []byte("1")) {
+		t.Fatalf("snapshot value mismatch: have %q, err %v", value, err)
+	}
+	if has, err := snap.Has([]byte("c")); err != nil || has {
+		t.Fatalf("key created after the snapshot is visible: has %v, err %v", has, err)
+	}
+	var keys []string
+	it := snap.NewIterator(nil, nil)
+	for it.Next() {
+		keys = append(keys, string(it.Key()))
+	}
+	it.Release()
+	if len(keys) != 2 || keys[0] != "a" || keys[1] != "b" {
+		t.Fatalf("snapshot keys mismatch: have %v", keys)
+	}
+	snap.Release()
+	if _, err := snap.Get([]byte("a")); err == nil {
+		t.Fatal("released snapshot is still readable")
+	}
+}
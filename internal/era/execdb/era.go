@@ -0,0 +1,355 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package execdb implements the EraE archive format, storing the execution
+// layer history of both pre- and post-merge blocks.
+package execdb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/internal/era"
+	"github.com/ethereum/go-ethereum/internal/era/e2store"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/golang/snappy"
+)
+
+var (
+	TypeVersion            uint16 = 0x3265
+	TypeCompressedHeader   uint16 = 0x03
+	TypeCompressedBody     uint16 = 0x04
+	TypeCompressedReceipts uint16 = 0x05
+	TypeHeaderAccumulator  uint16 = 0x08
+	TypeBlockIndex         uint16 = 0x3266
+
+	MaxEraESize = 8192
+)
+
+// Filename returns a recognizable EraE-formatted file name for the specified
+// epoch and network.
+func Filename(network string, epoch int, root common.Hash) string {
+	return fmt.Sprintf("%s-%05d-%s.erae", network, epoch, root.Hex()[2:10])
+}
+
+// ReadDir reads all the EraE files in a directory for a given network. Unlike
+// Era1 archives, EraE archives don't need to start at genesis, so the epoch of
+// the first file is returned along the consecutive list of files.
+// Format: <network>-<epoch>-<hexroot>.erae
+func ReadDir(dir, network string) (uint64, []string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, nil, fmt.Errorf("error reading directory %s: %w", dir, err)
+	}
+	var (
+		first uint64
+		eras  []string
+	)
+	for _, entry := range entries {
+		if path.Ext(entry.Name()) != ".erae" {
+			continue
+		}
+		parts := strings.Split(entry.Name(), "-")
+		if len(parts) != 3 || parts[0] != network {
+			// Invalid erae filename, skip.
+			continue
+		}
+		epoch, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			return 0, nil, fmt.Errorf("malformed erae filename: %s", entry.Name())
+		}
+		if len(eras) == 0 {
+			first = epoch
+		} else if next := first + uint64(len(eras)); epoch != next {
+			return 0, nil, fmt.Errorf("missing epoch %d", next)
+		}
+		eras = append(eras, entry.Name())
+	}
+	return first, eras, nil
+}
+
+// Era reads an EraE file.
+type Era struct {
+	f   era.ReadAtSeekCloser // backing erae file
+	s   *e2store.Reader      // e2store reader over f
+	m   metadata             // start, count, length info
+	mu  *sync.Mutex          // lock for buf
+	buf [8]byte              // buffer reading entry offsets
+}
+
+// From returns an Era backed by f.
+func From(f era.ReadAtSeekCloser) (*Era, error) {
+	m, err := readMetadata(f)
+	if err != nil {
+		return nil, err
+	}
+	return &Era{
+		f:  f,
+		s:  e2store.NewReader(f),
+		m:  m,
+		mu: new(sync.Mutex),
+	}, nil
+}
+
+// Open returns an Era backed by the given filename.
+func Open(filename string) (*Era, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	return From(f)
+}
+
+func (e *Era) Close() error {
+	return e.f.Close()
+}
+
+// GetHeaderByNumber returns the header for the given block number.
+func (e *Era) GetHeaderByNumber(num uint64) (*types.Header, error) {
+	if err := e.checkBounds(num); err != nil {
+		return nil, err
+	}
+	off, err := e.readOffset(num)
+	if err != nil {
+		return nil, err
+	}
+	r, _, err := newSnappyReader(e.s, TypeCompressedHeader, off)
+	if err != nil {
+		return nil, err
+	}
+	var header types.Header
+	if err := rlp.Decode(r, &header); err != nil {
+		return nil, err
+	}
+	return &header, nil
+}
+
+// GetBlockByNumber returns the block for the given block number.
+func (e *Era) GetBlockByNumber(num uint64) (*types.Block, error) {
+	if err := e.checkBounds(num); err != nil {
+		return nil, err
+	}
+	off, err := e.readOffset(num)
+	if err != nil {
+		return nil, err
+	}
+	r, n, err := newSnappyReader(e.s, TypeCompressedHeader, off)
+	if err != nil {
+		return nil, err
+	}
+	var header types.Header
+	if err := rlp.Decode(r, &header); err != nil {
+		return nil, err
+	}
+	off += n
+	r, _, err = newSnappyReader(e.s, TypeCompressedBody, off)
+	if err != nil {
+		return nil, err
+	}
+	var body types.Body
+	if err := rlp.Decode(r, &body); err != nil {
+		return nil, err
+	}
+	return types.NewBlockWithHeader(&header).WithBody(body), nil
+}
+
+// GetRawBodyByNumber returns the RLP-encoded body for the given block number.
+func (e *Era) GetRawBodyByNumber(num uint64) ([]byte, error) {
+	if err := e.checkBounds(num); err != nil {
+		return nil, err
+	}
+	off, err := e.readOffset(num)
+	if err != nil {
+		return nil, err
+	}
+	off, err = e.s.SkipN(off, 1)
+	if err != nil {
+		return nil, err
+	}
+	r, _, err := newSnappyReader(e.s, TypeCompressedBody, off)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+// GetRawReceiptsByNumber returns the RLP-encoded receipts for the given block number.
+func (e *Era) GetRawReceiptsByNumber(num uint64) ([]byte, error) {
+	if err := e.checkBounds(num); err != nil {
+		return nil, err
+	}
+	off, err := e.readOffset(num)
+	if err != nil {
+		return nil, err
+	}
+	// Skip over header and body.
+	off, err = e.s.SkipN(off, 2)
+	if err != nil {
+		return nil, err
+	}
+	r, _, err := newSnappyReader(e.s, TypeCompressedReceipts, off)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+// Accumulator reads the header accumulator entry in the EraE file.
+func (e *Era) Accumulator() (common.Hash, error) {
+	entry, err := e.s.Find(TypeHeaderAccumulator)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(entry.Value), nil
+}
+
+// Start returns the listed start block.
+func (e *Era) Start() uint64 {
+	return e.m.start
+}
+
+// Count returns the total number of blocks in the EraE.
+func (e *Era) Count() uint64 {
+	return e.m.count
+}
+
+// Verify checks that the archive is well-formed and self-consistent: the block
+// index can be walked, the transactions and receipts match the roots in their
+// headers, the headers link up and the recomputed header accumulator matches
+// the stored one. It returns the hashes of the archived blocks, which allows the
+// caller to check them against the canonical chain. The given hasher is used to
+// derive the transaction and receipt roots.
+func (e *Era) Verify(hasher types.TrieHasher) ([]common.Hash, error) {
+	want, err := e.Accumulator()
+	if err != nil {
+		return nil, fmt.Errorf("error reading accumulator: %w", err)
+	}
+	it, err := NewIterator(e)
+	if err != nil {
+		return nil, fmt.Errorf("error making era iterator: %w", err)
+	}
+	var hashes []common.Hash
+	for it.Next() {
+		if it.Error() != nil {
+			return nil, fmt.Errorf("error reading block %d: %w", it.Number(), it.Error())
+		}
+		block, receipts, err := it.BlockAndReceipts()
+		if err != nil {
+			return nil, fmt.Errorf("error reading block %d: %w", it.Number(), err)
+		}
+		if block.NumberU64() != it.Number() {
+			return nil, fmt.Errorf("block number mismatch: indexed %d, got %d", it.Number(), block.NumberU64())
+		}
+		if len(hashes) > 0 && block.ParentHash() != hashes[len(hashes)-1] {
+			return nil, fmt.Errorf("block %d not linked to its parent", block.NumberU64())
+		}
+		if tr := types.DeriveSha(block.Transactions(), hasher); tr != block.TxHash() {
+			return nil, fmt.Errorf("tx root in block %d mismatch: want %s, got %s", block.NumberU64(), block.TxHash(), tr)
+		}
+		if rr := types.DeriveSha(receipts, hasher); rr != block.ReceiptHash() {
+			return nil, fmt.Errorf("receipt root in block %d mismatch: want %s, got %s", block.NumberU64(), block.ReceiptHash(), rr)
+		}
+		hashes = append(hashes, block.Hash())
+	}
+	if it.Error() != nil {
+		return nil, it.Error()
+	}
+	if uint64(len(hashes)) != e.m.count {
+		return nil, fmt.Errorf("block count mismatch: indexed %d, got %d", e.m.count, len(hashes))
+	}
+	got, err := ComputeAccumulator(hashes)
+	if err != nil {
+		return nil, fmt.Errorf("error computing accumulator: %w", err)
+	}
+	if got != want {
+		return nil, fmt.Errorf("expected accumulator root does not match calculated: got %s, want %s", got, want)
+	}
+	return hashes, nil
+}
+
+// checkBounds returns an error if the given block is not within the archive.
+func (e *Era) checkBounds(num uint64) error {
+	if e.m.start > num || e.m.start+e.m.count <= num {
+		return fmt.Errorf("out-of-bounds: %d not in [%d, %d)", num, e.m.start, e.m.start+e.m.count)
+	}
+	return nil
+}
+
+// readOffset reads a specific block's offset from the block index. The value n
+// is the absolute block number desired.
+func (e *Era) readOffset(n uint64) (int64, error) {
+	var (
+		blockIndexRecordOffset = e.m.length - 24 - int64(e.m.count)*8 // skips start, count, and header
+		firstIndex             = blockIndexRecordOffset + 16          // first index after header / start-num
+		indexOffset            = int64(n-e.m.start) * 8               // desired index * size of indexes
+		offOffset              = firstIndex + indexOffset             // offset of block offset
+	)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	clear(e.buf[:])
+	if _, err := e.f.ReadAt(e.buf[:], offOffset); err != nil {
+		return 0, err
+	}
+	// Since the block offset is relative from the start of the block index record
+	// we need to add the record offset to it's offset to get the block's absolute
+	// offset.
+	return blockIndexRecordOffset + int64(binary.LittleEndian.Uint64(e.buf[:])), nil
+}
+
+// newSnappyReader returns a snappy.Reader for the e2store entry value at off.
+func newSnappyReader(e *e2store.Reader, expectedType uint16, off int64) (io.Reader, int64, error) {
+	r, n, err := e.ReaderAt(expectedType, off)
+	if err != nil {
+		return nil, 0, err
+	}
+	return snappy.NewReader(r), int64(n), err
+}
+
+// metadata wraps the metadata in the block index.
+type metadata struct {
+	start  uint64
+	count  uint64
+	length int64
+}
+
+// readMetadata reads the metadata stored in an EraE file's block index.
+func readMetadata(f era.ReadAtSeekCloser) (m metadata, err error) {
+	// Determine length of reader.
+	if m.length, err = f.Seek(0, io.SeekEnd); err != nil {
+		return
+	}
+	b := make([]byte, 16)
+	// Read count. It's the last 8 bytes of the file.
+	if _, err = f.ReadAt(b[:8], m.length-8); err != nil {
+		return
+	}
+	m.count = binary.LittleEndian.Uint64(b)
+	// Read start. It's at the offset -sizeof(m.count) -
+	// count*sizeof(indexEntry) - sizeof(m.start)
+	if _, err = f.ReadAt(b[8:], m.length-16-int64(m.count*8)); err != nil {
+		return
+	}
+	m.start = binary.LittleEndian.Uint64(b[8:])
+	return
+}
@@ -0,0 +1,215 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package execdb_test
+
+import (
+	"bytes"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/internal/era/execdb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// makeChain creates a chain of post-merge blocks with consistent transaction
+// and receipt roots, starting at the given block number.
+func makeChain(start uint64, n int) ([]*types.Block, []types.Receipts) {
+	var (
+		blocks   []*types.Block
+		receipts []types.Receipts
+		parent   common.Hash
+	)
+	for i := 0; i < n; i++ {
+		var (
+			number = start + uint64(i)
+			tx     = types.NewTransaction(number, common.Address{byte(i)}, big.NewInt(1), 21000, big.NewInt(1), nil)
+			rs     = types.Receipts{{Type: types.LegacyTxType, Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 21000, Logs: []*types.Log{}}}
+			header = &types.Header{
+				ParentHash: parent,
+				Number:     new(big.Int).SetUint64(number),
+				Difficulty: new(big.Int),
+				BaseFee:    big.NewInt(1),
+				Time:       number * 12,
+			}
+		)
+		block := types.NewBlock(header, &types.Body{Transactions: []*types.Transaction{tx}}, rs, trie.NewStackTrie(nil))
+		blocks = append(blocks, block)
+		receipts = append(receipts, rs)
+		parent = block.Hash()
+	}
+	return blocks, receipts
+}
+
+func TestEraEBuilder(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.CreateTemp(t.TempDir(), "erae-test")
+	if err != nil {
+		t.Fatalf("error creating temp file: %v", err)
+	}
+	defer f.Close()
+
+	var (
+		start            = uint64(15537393)
+		blocks, receipts = makeChain(start, 128)
+		builder          = execdb.NewBuilder(f)
+		hashes           []common.Hash
+	)
+	for i, block := range blocks {
+		if err := builder.Add(block, receipts[i]); err != nil {
+			t.Fatalf("error adding block %d: %v", i, err)
+		}
+		hashes = append(hashes, block.Hash())
+	}
+	root, err := builder.Finalize()
+	if err != nil {
+		t.Fatalf("error finalizing erae: %v", err)
+	}
+	if want, _ := execdb.ComputeAccumulator(hashes); root != want {
+		t.Fatalf("accumulator mismatch: have %x, want %x", root, want)
+	}
+	e, err := execdb.Open(f.Name())
+	if err != nil {
+		t.Fatalf("failed to open era: %v", err)
+	}
+	defer e.Close()
+
+	if e.Start() != start || e.Count() != uint64(len(blocks)) {
+		t.Fatalf("metadata mismatch: start %d, count %d", e.Start(), e.Count())
+	}
+	if acc, err := e.Accumulator(); err != nil || acc != root {
+		t.Fatalf("stored accumulator mismatch: have %x, want %x, err %v", acc, root, err)
+	}
+	for i, want := range blocks {
+		number := want.NumberU64()
+		block, err := e.GetBlockByNumber(number)
+		if err != nil {
+			t.Fatalf("error reading block %d: %v", number, err)
+		}
+		if block.Hash() != want.Hash() {
+			t.Fatalf("block %d mismatch", number)
+		}
+		header, err := e.GetHeaderByNumber(number)
+		if err != nil || header.Hash() != want.Hash() {
+			t.Fatalf("header %d mismatch, err %v", number, err)
+		}
+		body, err := e.GetRawBodyByNumber(number)
+		if err != nil {
+			t.Fatalf("error reading body %d: %v", number, err)
+		}
+		if enc, _ := rlp.EncodeToBytes(want.Body()); !bytes.Equal(body, enc) {
+			t.Fatalf("body %d mismatch", number)
+		}
+		raw, err := e.GetRawReceiptsByNumber(number)
+		if err != nil {
+			t.Fatalf("error reading receipts %d: %v", number, err)
+		}
+		if enc, _ := rlp.EncodeToBytes(receipts[i]); !bytes.Equal(raw, enc) {
+			t.Fatalf("receipts %d mismatch", number)
+		}
+	}
+	if _, err := e.GetBlockByNumber(start + e.Count()); err == nil {
+		t.Fatal("expected out-of-bounds error")
+	}
+	verified, err := e.Verify(trie.NewStackTrie(nil))
+	if err != nil {
+		t.Fatalf("failed to verify era: %v", err)
+	}
+	if !slices.Equal(verified, hashes) {
+		t.Fatal("verified hashes mismatch")
+	}
+}
+
+func TestEraEBuilderNonContiguous(t *testing.T) {
+	t.Parallel()
+
+	blocks, receipts := makeChain(0, 3)
+	builder := execdb.NewBuilder(new(bytes.Buffer))
+	if err := builder.Add(blocks[0], receipts[0]); err != nil {
+		t.Fatalf("error adding block: %v", err)
+	}
+	if err := builder.Add(blocks[2], receipts[2]); err == nil {
+		t.Fatal("expected error adding non-contiguous block")
+	}
+}
+
+func TestEraEVerifyTampered(t *testing.T) {
+	t.Parallel()
+
+	blocks, receipts := makeChain(100, 4)
+	receipts[2] = types.Receipts{{Type: types.LegacyTxType, Status: types.ReceiptStatusFailed, CumulativeGasUsed: 21000, Logs: []*types.Log{}}}
+
+	name := filepath.Join(t.TempDir(), execdb.Filename("mainnet", 0, common.Hash{}))
+	f, err := os.Create(name)
+	if err != nil {
+		t.Fatalf("error creating file: %v", err)
+	}
+	builder := execdb.NewBuilder(f)
+	for i, block := range blocks {
+		if err := builder.Add(block, receipts[i]); err != nil {
+			t.Fatalf("error adding block %d: %v", i, err)
+		}
+	}
+	if _, err := builder.Finalize(); err != nil {
+		t.Fatalf("error finalizing erae: %v", err)
+	}
+	f.Close()
+
+	e, err := execdb.Open(name)
+	if err != nil {
+		t.Fatalf("failed to open era: %v", err)
+	}
+	defer e.Close()
+	if _, err := e.Verify(trie.NewStackTrie(nil)); err == nil {
+		t.Fatal("expected receipt root mismatch")
+	}
+}
+
+func TestEraEReadDir(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, name := range []string{
+		execdb.Filename("mainnet", 1897, common.Hash{1}),
+		execdb.Filename("mainnet", 1898, common.Hash{2}),
+		execdb.Filename("sepolia", 0, common.Hash{3}),
+		"mainnet-00000-01000000.era1",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	first, entries, err := execdb.ReadDir(dir, "mainnet")
+	if err != nil {
+		t.Fatalf("failed to read dir: %v", err)
+	}
+	if first != 1897 || !slices.Equal(entries, []string{"mainnet-01897-01000000.erae", "mainnet-01898-02000000.erae"}) {
+		t.Fatalf("unexpected entries from epoch %d: %v", first, entries)
+	}
+	if err := os.WriteFile(filepath.Join(dir, execdb.Filename("mainnet", 1900, common.Hash{4})), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := execdb.ReadDir(dir, "mainnet"); err == nil {
+		t.Fatal("expected missing epoch error")
+	}
+}
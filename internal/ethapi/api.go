@@ -39,6 +39,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/eth/gasestimator"
+	"github.com/ethereum/go-ethereum/eth/gasprice"
 	"github.com/ethereum/go-ethereum/eth/tracers/logger"
 	"github.com/ethereum/go-ethereum/internal/ethapi/override"
 	"github.com/ethereum/go-ethereum/log"
@@ -47,6 +48,7 @@ import (
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/trie/utils"
 )
 
 // estimateGasErrorRatio is the amount of overestimation eth_estimateGas is
@@ -93,10 +95,57 @@ type feeHistoryResult struct {
 	GasUsedRatio     []float64        `json:"gasUsedRatio"`
 	BlobBaseFee      []*hexutil.Big   `json:"baseFeePerBlobGas,omitempty"`
 	BlobGasUsedRatio []float64        `json:"blobGasUsedRatio,omitempty"`
+
+	// Only filled in if the transaction type breakdown was requested
+	RewardByType map[string][][]*hexutil.Big `json:"rewardByType,omitempty"`
+	BlobCount    []hexutil.Uint64            `json:"blobCount,omitempty"`
+	BlobsPerTx   [][]hexutil.Uint64          `json:"blobsPerTransaction,omitempty"`
+}
+
+// FeeHistoryOptions are the optional settings of the fee history query.
+type FeeHistoryOptions struct {
+	// ByTxType requests the reward percentiles to be additionally reported
+	// separately for each transaction type, along with the distribution of
+	// blobs per transaction in each block.
+	ByTxType bool `json:"byTxType"`
+}
+
+// txTypeNames maps the transaction types to their names in the fee history.
+var txTypeNames = map[uint8]string{
+	types.LegacyTxType:     "legacy",
+	types.AccessListTxType: "accessList",
+	types.DynamicFeeTxType: "dynamicFee",
+	types.BlobTxType:       "blob",
+	types.SetCodeTxType:    "setCode",
+}
+
+func toHexRewards(reward [][]*big.Int) [][]*hexutil.Big {
+	res := make([][]*hexutil.Big, len(reward))
+	for i, w := range reward {
+		if w == nil {
+			continue
+		}
+		res[i] = make([]*hexutil.Big, len(w))
+		for j, v := range w {
+			res[i][j] = (*hexutil.Big)(v)
+		}
+	}
+	return res
+}
+
+func toHexBigs(values []*big.Int) []*hexutil.Big {
+	res := make([]*hexutil.Big, len(values))
+	for i, v := range values {
+		res[i] = (*hexutil.Big)(v)
+	}
+	return res
 }
 
 // FeeHistory returns the fee market history.
-func (api *EthereumAPI) FeeHistory(ctx context.Context, blockCount math.HexOrDecimal64, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*feeHistoryResult, error) {
+func (api *EthereumAPI) FeeHistory(ctx context.Context, blockCount math.HexOrDecimal64, lastBlock rpc.BlockNumber, rewardPercentiles []float64, opts *FeeHistoryOptions) (*feeHistoryResult, error) {
+	if opts != nil && opts.ByTxType {
+		return api.feeHistoryByType(ctx, blockCount, lastBlock, rewardPercentiles)
+	}
 	oldest, reward, baseFee, gasUsed, blobBaseFee, blobGasUsed, err := api.b.FeeHistory(ctx, uint64(blockCount), lastBlock, rewardPercentiles)
 	if err != nil {
 		return nil, err
@@ -106,25 +155,13 @@ func (api *EthereumAPI) FeeHistory(ctx context.Context, blockCount math.HexOrDec
 		GasUsedRatio: gasUsed,
 	}
 	if reward != nil {
-		results.Reward = make([][]*hexutil.Big, len(reward))
-		for i, w := range reward {
-			results.Reward[i] = make([]*hexutil.Big, len(w))
-			for j, v := range w {
-				results.Reward[i][j] = (*hexutil.Big)(v)
-			}
-		}
+		results.Reward = toHexRewards(reward)
 	}
 	if baseFee != nil {
-		results.BaseFee = make([]*hexutil.Big, len(baseFee))
-		for i, v := range baseFee {
-			results.BaseFee[i] = (*hexutil.Big)(v)
-		}
+		results.BaseFee = toHexBigs(baseFee)
 	}
 	if blobBaseFee != nil {
-		results.BlobBaseFee = make([]*hexutil.Big, len(blobBaseFee))
-		for i, v := range blobBaseFee {
-			results.BlobBaseFee[i] = (*hexutil.Big)(v)
-		}
+		results.BlobBaseFee = toHexBigs(blobBaseFee)
 	}
 	if blobGasUsed != nil {
 		results.BlobGasUsedRatio = blobGasUsed
@@ -132,6 +169,95 @@ func (api *EthereumAPI) FeeHistory(ctx context.Context, blockCount math.HexOrDec
 	return results, nil
 }
 
+// feeHistoryByType returns the fee market history with the rewards split up by
+// transaction type and the blob distribution of each block.
+func (api *EthereumAPI) feeHistoryByType(ctx context.Context, blockCount math.HexOrDecimal64, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*feeHistoryResult, error) {
+	history, err := api.b.FeeHistoryByType(ctx, uint64(blockCount), lastBlock, rewardPercentiles)
+	if err != nil {
+		return nil, err
+	}
+	results := &feeHistoryResult{
+		OldestBlock:      (*hexutil.Big)(history.OldestBlock),
+		GasUsedRatio:     history.GasUsedRatio,
+		BlobGasUsedRatio: history.BlobGasUsedRatio,
+	}
+	if history.BaseFee != nil {
+		results.BaseFee = toHexBigs(history.BaseFee)
+	}
+	if history.BlobBaseFee != nil {
+		results.BlobBaseFee = toHexBigs(history.BlobBaseFee)
+	}
+	if history.Reward != nil {
+		results.Reward = toHexRewards(history.Reward)
+	}
+	if history.RewardByType != nil {
+		results.RewardByType = make(map[string][][]*hexutil.Big, len(history.RewardByType))
+		for typ, reward := range history.RewardByType {
+			name, ok := txTypeNames[typ]
+			if !ok {
+				name = hexutil.EncodeUint64(uint64(typ))
+			}
+			results.RewardByType[name] = toHexRewards(reward)
+		}
+	}
+	if history.BlobCount != nil {
+		results.BlobCount = make([]hexutil.Uint64, len(history.BlobCount))
+		results.BlobsPerTx = make([][]hexutil.Uint64, len(history.BlobsPerTx))
+		for i, count := range history.BlobCount {
+			results.BlobCount[i] = hexutil.Uint64(count)
+			results.BlobsPerTx[i] = make([]hexutil.Uint64, len(history.BlobsPerTx[i]))
+			for j, n := range history.BlobsPerTx[i] {
+				results.BlobsPerTx[i][j] = hexutil.Uint64(n)
+			}
+		}
+	}
+	return results, nil
+}
+
+type feeEstimateResult struct {
+	MaxPriorityFeePerGas *hexutil.Big   `json:"maxPriorityFeePerGas"`
+	MaxFeePerGas         *hexutil.Big   `json:"maxFeePerGas"`
+	MaxFeePerBlobGas     *hexutil.Big   `json:"maxFeePerBlobGas,omitempty"`
+	Blocks               hexutil.Uint64 `json:"blocks"`
+	Probability          float64        `json:"probability"`
+}
+
+type feeEstimatesResult struct {
+	BlockNumber       hexutil.Uint64     `json:"blockNumber"`
+	BaseFeePerGas     *hexutil.Big       `json:"baseFeePerGas"`
+	BaseFeePerBlobGas *hexutil.Big       `json:"baseFeePerBlobGas,omitempty"`
+	Slow              *feeEstimateResult `json:"slow"`
+	Standard          *feeEstimateResult `json:"standard"`
+	Fast              *feeEstimateResult `json:"fast"`
+}
+
+func newFeeEstimateResult(est *gasprice.FeeEstimate) *feeEstimateResult {
+	return &feeEstimateResult{
+		MaxPriorityFeePerGas: (*hexutil.Big)(est.TipCap),
+		MaxFeePerGas:         (*hexutil.Big)(est.FeeCap),
+		MaxFeePerBlobGas:     (*hexutil.Big)(est.BlobFeeCap),
+		Blocks:               hexutil.Uint64(est.Blocks),
+		Probability:          est.Probability,
+	}
+}
+
+// FeeEstimates returns slow, standard and fast fee suggestions along with the
+// estimated probability of being included within the targeted number of blocks.
+func (api *EthereumAPI) FeeEstimates(ctx context.Context) (*feeEstimatesResult, error) {
+	est, err := api.b.FeeEstimates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &feeEstimatesResult{
+		BlockNumber:       hexutil.Uint64(est.BlockNumber),
+		BaseFeePerGas:     (*hexutil.Big)(est.BaseFee),
+		BaseFeePerBlobGas: (*hexutil.Big)(est.BlobBaseFee),
+		Slow:              newFeeEstimateResult(est.Slow),
+		Standard:          newFeeEstimateResult(est.Standard),
+		Fast:              newFeeEstimateResult(est.Fast),
+	}, nil
+}
+
 // BlobBaseFee returns the base fee for blob gas at the current head.
 func (api *EthereumAPI) BlobBaseFee(ctx context.Context) *hexutil.Big {
 	return (*hexutil.Big)(api.b.BlobBaseFee(ctx))
@@ -341,6 +467,11 @@ type AccountResult struct {
 	Nonce        hexutil.Uint64  `json:"nonce"`
 	StorageHash  common.Hash     `json:"storageHash"`
 	StorageProof []StorageResult `json:"storageProof"`
+
+	// VerkleProof is the multiproof of the account fields and storage slots,
+	// only set if the chain is in verkle mode. Account and storage proofs are
+	// left empty in that case.
+	VerkleProof *trie.VerkleProof `json:"verkleProof,omitempty"`
 }
 
 type StorageResult struct {
@@ -381,6 +512,9 @@ func (api *BlockChainAPI) GetProof(ctx context.Context, address common.Address,
 	if statedb == nil || err != nil {
 		return nil, err
 	}
+	if statedb.Database().TrieDB().IsVerkle() {
+		return getVerkleProof(statedb, header.Root, address, keys, keyLengths)
+	}
 	codeHash := statedb.GetCodeHash(address)
 	storageRoot := statedb.GetStorageRoot(address)
 
@@ -396,16 +530,7 @@ func (api *BlockChainAPI) GetProof(ctx context.Context, address common.Address,
 		}
 		// Create the proofs for the storageKeys.
 		for i, key := range keys {
-			// Output key encoding is a bit special: if the input was a 32-byte hash, it is
-			// returned as such. Otherwise, we apply the QUANTITY encoding mandated by the
-			// JSON-RPC spec for getProof. This behavior exists to preserve backwards
-			// compatibility with older client versions.
-			var outputKey string
-			if keyLengths[i] != 32 {
-				outputKey = hexutil.EncodeBig(key.Big())
-			} else {
-				outputKey = hexutil.Encode(key[:])
-			}
+			outputKey := encodeStorageKey(key, keyLengths[i])
 			if storageTrie == nil {
 				storageProof[i] = StorageResult{outputKey, &hexutil.Big{}, []string{}}
 				continue
@@ -439,6 +564,51 @@ func (api *BlockChainAPI) GetProof(ctx context.Context, address common.Address,
 	}, statedb.Error()
 }
 
+// getVerkleProof creates a single multiproof covering the account header fields
+// and the requested storage slots of the given address in a verkle state.
+func getVerkleProof(statedb *state.StateDB, root common.Hash, address common.Address, keys []common.Hash, keyLengths []int) (*AccountResult, error) {
+	tr, err := trie.NewVerkleTrie(root, statedb.Database().TrieDB(), statedb.Database().PointCache())
+	if err != nil {
+		return nil, err
+	}
+	var (
+		treeKeys     = [][]byte{utils.BasicDataKey(address.Bytes()), utils.CodeHashKey(address.Bytes())}
+		storageProof = make([]StorageResult, len(keys))
+	)
+	for i, key := range keys {
+		treeKeys = append(treeKeys, utils.StorageSlotKey(address.Bytes(), key.Bytes()))
+		storageProof[i] = StorageResult{
+			Key:   encodeStorageKey(key, keyLengths[i]),
+			Value: (*hexutil.Big)(statedb.GetState(address, key).Big()),
+			Proof: []string{},
+		}
+	}
+	proof, err := tr.ProveKeys(treeKeys)
+	if err != nil {
+		return nil, err
+	}
+	return &AccountResult{
+		Address:      address,
+		AccountProof: []string{},
+		Balance:      (*hexutil.Big)(statedb.GetBalance(address).ToBig()),
+		CodeHash:     statedb.GetCodeHash(address),
+		Nonce:        hexutil.Uint64(statedb.GetNonce(address)),
+		StorageProof: storageProof,
+		VerkleProof:  proof,
+	}, statedb.Error()
+}
+
+// encodeStorageKey returns the output encoding of a storage key: if the input
+// was a 32-byte hash, it is returned as such. Otherwise, we apply the QUANTITY
+// encoding mandated by the JSON-RPC spec for getProof. This behavior exists to
+// preserve backwards compatibility with older client versions.
+func encodeStorageKey(key common.Hash, length int) string {
+	if length != 32 {
+		return hexutil.EncodeBig(key.Big())
+	}
+	return hexutil.Encode(key[:])
+}
+
 // decodeHash parses a hex-encoded 32-byte hash. The input may optionally
 // be prefixed by 0x and can have a byte length up to 32.
 func decodeHash(s string) (h common.Hash, inputLength int, err error) {
@@ -1691,12 +1861,13 @@ func (api *TransactionAPI) Resend(ctx context.Context, sendArgs TransactionArgs,
 // DebugAPI is the collection of Ethereum APIs exposed over the debugging
 // namespace.
 type DebugAPI struct {
-	b Backend
+	b         Backend
+	snapshots *dbSnapshots
 }
 
 // NewDebugAPI creates a new instance of DebugAPI.
 func NewDebugAPI(b Backend) *DebugAPI {
-	return &DebugAPI{b: b}
+	return &DebugAPI{b: b, snapshots: newDbSnapshots()}
 }
 
 // GetRawHeader retrieves the RLP encoding for a single header.
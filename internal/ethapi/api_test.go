@@ -49,15 +49,20 @@ import (
 	"github.com/ethereum/go-ethereum/core/filtermaps"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/eth/gasprice"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/internal/blocktest"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/trie/utils"
+	"github.com/ethereum/go-ethereum/triedb"
 	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/require"
 )
@@ -476,6 +481,21 @@ func (b testBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
 func (b testBackend) FeeHistory(ctx context.Context, blockCount uint64, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*big.Int, [][]*big.Int, []*big.Int, []float64, []*big.Int, []float64, error) {
 	return nil, nil, nil, nil, nil, nil, nil
 }
+func (b testBackend) FeeHistoryByType(ctx context.Context, blockCount uint64, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*gasprice.TypedFeeHistory, error) {
+	return &gasprice.TypedFeeHistory{
+		OldestBlock:  big.NewInt(1),
+		BaseFee:      []*big.Int{big.NewInt(7), big.NewInt(7)},
+		GasUsedRatio: []float64{0.5},
+		Reward:       [][]*big.Int{{big.NewInt(1), big.NewInt(3)}},
+		RewardByType: map[uint8][][]*big.Int{
+			types.LegacyTxType:     {{big.NewInt(1), big.NewInt(1)}},
+			types.DynamicFeeTxType: {{big.NewInt(2), big.NewInt(3)}},
+		},
+	}, nil
+}
+func (b testBackend) FeeEstimates(ctx context.Context) (*gasprice.FeeEstimates, error) {
+	return nil, nil
+}
 func (b testBackend) BlobBaseFee(ctx context.Context) *big.Int { return new(big.Int) }
 func (b testBackend) ChainDb() ethdb.Database                  { return b.db }
 func (b testBackend) AccountManager() *accounts.Manager        { return b.accman }
@@ -484,6 +504,7 @@ func (b testBackend) RPCGasCap() uint64                        { return 10000000
 func (b testBackend) RPCEVMTimeout() time.Duration             { return time.Second }
 func (b testBackend) RPCTxFeeCap() float64                     { return 0 }
 func (b testBackend) UnprotectedAllowed() bool                 { return false }
+func (b testBackend) RPCDatabaseWrite() bool                   { return false }
 func (b testBackend) SetHead(number uint64)                    {}
 func (b testBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
 	if number == rpc.LatestBlockNumber {
@@ -634,6 +655,27 @@ func (b testBackend) HistoryPruningCutoff() uint64 {
 	return bn
 }
 
+func (b testBackend) HistoryFloor() uint64 {
+	return b.chain.HistoryFloor()
+}
+
+func TestFeeHistoryByType(t *testing.T) {
+	t.Parallel()
+
+	api := NewEthereumAPI(testBackend{})
+	res, err := api.FeeHistory(context.Background(), 1, rpc.LatestBlockNumber, []float64{10, 90}, &FeeHistoryOptions{ByTxType: true})
+	if err != nil {
+		t.Fatalf("Failed to retrieve fee history: %v", err)
+	}
+	// The typed breakdown must extend the response, not replace the combined rewards
+	if len(res.Reward) != 1 || len(res.Reward[0]) != 2 || res.Reward[0][1].ToInt().Int64() != 3 {
+		t.Fatalf("Combined reward mismatch: %v", res.Reward)
+	}
+	if len(res.RewardByType) != 2 || res.RewardByType["dynamicFee"][0][0].ToInt().Int64() != 2 {
+		t.Fatalf("Typed reward mismatch: %v", res.RewardByType)
+	}
+}
+
 func TestEstimateGas(t *testing.T) {
 	t.Parallel()
 	// Initialize test accounts
@@ -3726,3 +3768,56 @@ func TestCreateAccessListWithStateOverrides(t *testing.T) {
 	}}
 	require.Equal(t, expected, result.Accesslist)
 }
+
+func TestGetVerkleProof(t *testing.T) {
+	t.Parallel()
+
+	var (
+		sdb        = state.NewDatabase(triedb.NewDatabase(rawdb.NewMemoryDatabase(), triedb.VerkleDefaults), nil)
+		addr       = common.HexToAddress("0x1234")
+		slot       = common.HexToHash("0x01")
+		value      = common.HexToHash("0xdeadbeef")
+		absent     = common.HexToHash("0x02")
+		code       = []byte{0x60, 0x00}
+		statedb, _ = state.New(types.EmptyVerkleHash, sdb)
+	)
+	statedb.SetBalance(addr, uint256.NewInt(1000), tracing.BalanceChangeUnspecified)
+	statedb.SetNonce(addr, 3, tracing.NonceChangeUnspecified)
+	statedb.SetCode(addr, code)
+	statedb.SetState(addr, slot, value)
+	root, err := statedb.Commit(0, false, false)
+	if err != nil {
+		t.Fatalf("failed to commit state: %v", err)
+	}
+	statedb, _ = state.New(root, sdb)
+
+	result, err := getVerkleProof(statedb, root, addr, []common.Hash{slot, absent}, []int{1, 32})
+	if err != nil {
+		t.Fatalf("failed to create proof: %v", err)
+	}
+	if result.Balance.ToInt().Uint64() != 1000 || result.Nonce != 3 || result.CodeHash != crypto.Keccak256Hash(code) {
+		t.Fatalf("account mismatch: balance %v, nonce %d, code hash %x", result.Balance, result.Nonce, result.CodeHash)
+	}
+	if len(result.StorageProof) != 2 {
+		t.Fatalf("storage result count mismatch: have %d, want 2", len(result.StorageProof))
+	}
+	if have := result.StorageProof[0]; have.Key != "0x1" || have.Value.ToInt().Cmp(value.Big()) != 0 {
+		t.Fatalf("storage result mismatch: key %s, value %v", have.Key, have.Value)
+	}
+	if have := result.StorageProof[1]; have.Key != hexutil.Encode(absent[:]) || have.Value.ToInt().Sign() != 0 {
+		t.Fatalf("absent storage result mismatch: key %s, value %v", have.Key, have.Value)
+	}
+	values, err := trie.VerifyVerkleProof(root, result.VerkleProof)
+	if err != nil {
+		t.Fatalf("failed to verify proof: %v", err)
+	}
+	if have := values[common.BytesToHash(utils.CodeHashKey(addr.Bytes()))]; !bytes.Equal(have, crypto.Keccak256(code)) {
+		t.Fatalf("proven code hash mismatch: have %x", have)
+	}
+	if have := values[common.BytesToHash(utils.StorageSlotKey(addr.Bytes(), slot.Bytes()))]; !bytes.Equal(have, value[:]) {
+		t.Fatalf("proven storage mismatch: have %x, want %x", have, value)
+	}
+	if have := values[common.BytesToHash(utils.StorageSlotKey(addr.Bytes(), absent.Bytes()))]; have != nil {
+		t.Fatalf("absent slot proven with value %x", have)
+	}
+}
@@ -17,10 +17,133 @@
 package ethapi
 
 import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// dbResponseMaxBytes is the soft limit of the data returned by a single
+	// iteration or ancient range request. At least one item is always returned.
+	dbResponseMaxBytes = 4 * 1024 * 1024
+
+	// dbIterateMaxItems is the maximum number of entries returned by a single
+	// iteration request.
+	dbIterateMaxItems = 10000
+
+	// dbSnapshotTimeout is the time after which an unused database snapshot is
+	// released automatically.
+	dbSnapshotTimeout = 5 * time.Minute
+
+	// dbSnapshotLimit is the maximum number of database snapshots held at once.
+	dbSnapshotLimit = 16
 )
 
+var (
+	errDbWriteDisabled       = errors.New("database modification over RPC is disabled")
+	errDbSnapshotUnsupported = errors.New("database does not support snapshots")
+	errDbSnapshotLimit       = errors.New("too many database snapshots")
+)
+
+// DbIteratorPage is a page of consecutive entries of the database, returned by
+// the iteration requests.
+type DbIteratorPage struct {
+	Keys   []hexutil.Bytes `json:"keys"`
+	Values []hexutil.Bytes `json:"values"`
+	More   bool            `json:"more"` // Whether entries exist after the page
+}
+
+// DbBatchOp is a single operation of a database write batch.
+type DbBatchOp struct {
+	Op    string         `json:"op"` // One of "put", "delete" or "deleteRange"
+	Key   hexutil.Bytes  `json:"key"`
+	Value hexutil.Bytes  `json:"value,omitempty"` // Value to insert, for "put"
+	End   *hexutil.Bytes `json:"end,omitempty"`   // Exclusive end of the range, for "deleteRange"
+}
+
+// optionalBytes returns the content of an optional byte slice parameter, where
+// nil is distinct from an empty slice.
+func optionalBytes(b *hexutil.Bytes) []byte {
+	if b == nil {
+		return nil
+	}
+	return *b
+}
+
+// dbSnapshot is a database snapshot held on behalf of a remote client.
+type dbSnapshot struct {
+	snap  ethdb.Snapshot
+	timer *time.Timer  // Timer releasing the snapshot once unused
+	lock  sync.RWMutex // Lock preventing the release of the snapshot while in use
+}
+
+// dbSnapshots is the set of database snapshots held on behalf of remote clients.
+type dbSnapshots struct {
+	snaps map[string]*dbSnapshot
+	lock  sync.Mutex
+}
+
+func newDbSnapshots() *dbSnapshots {
+	return &dbSnapshots{snaps: make(map[string]*dbSnapshot)}
+}
+
+// add tracks the given snapshot, returning its identifier.
+func (s *dbSnapshots) add(snap ethdb.Snapshot) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if len(s.snaps) >= dbSnapshotLimit {
+		snap.Release()
+		return "", errDbSnapshotLimit
+	}
+	id := string(rpc.NewID())
+	s.snaps[id] = &dbSnapshot{
+		snap:  snap,
+		timer: time.AfterFunc(dbSnapshotTimeout, func() { s.release(id) }),
+	}
+	return id, nil
+}
+
+// acquire retrieves the snapshot with the given identifier and extends its
+// lifetime. The snapshot must be unlocked after use.
+func (s *dbSnapshots) acquire(id string) (*dbSnapshot, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	snap, ok := s.snaps[id]
+	if !ok {
+		return nil, fmt.Errorf("database snapshot %s not found", id)
+	}
+	snap.timer.Reset(dbSnapshotTimeout)
+	snap.lock.RLock()
+	return snap, nil
+}
+
+// release releases the snapshot with the given identifier, once it's not used
+// anymore.
+func (s *dbSnapshots) release(id string) error {
+	s.lock.Lock()
+	snap, ok := s.snaps[id]
+	delete(s.snaps, id)
+	s.lock.Unlock()
+
+	if !ok {
+		return fmt.Errorf("database snapshot %s not found", id)
+	}
+	snap.timer.Stop()
+
+	snap.lock.Lock()
+	defer snap.lock.Unlock()
+	snap.snap.Release()
+	return nil
+}
+
 // DbGet returns the raw value of a key stored in the database.
 func (api *DebugAPI) DbGet(key string) (hexutil.Bytes, error) {
 	blob, err := common.ParseHexOrString(key)
@@ -30,14 +153,206 @@ func (api *DebugAPI) DbGet(key string) (hexutil.Bytes, error) {
 	return api.b.ChainDb().Get(blob)
 }
 
+// DbHas returns whether a key is stored in the database.
+func (api *DebugAPI) DbHas(key string) (bool, error) {
+	blob, err := common.ParseHexOrString(key)
+	if err != nil {
+		return false, err
+	}
+	return api.b.ChainDb().Has(blob)
+}
+
+// DbIterate returns the entries of the database with the given key prefix,
+// starting at the given key (or after, if it does not exist). At most limit
+// entries are returned, the following ones are retrieved by iterating again
+// after the last returned key.
+//
+// If a snapshot identifier is given, the entries are read from the snapshot.
+func (api *DebugAPI) DbIterate(prefix hexutil.Bytes, start hexutil.Bytes, limit int, snapshot *string) (*DbIteratorPage, error) {
+	if limit <= 0 || limit > dbIterateMaxItems {
+		limit = dbIterateMaxItems
+	}
+	if snapshot != nil {
+		snap, err := api.snapshots.acquire(*snapshot)
+		if err != nil {
+			return nil, err
+		}
+		defer snap.lock.RUnlock()
+		return iteratePage(snap.snap.NewIterator(prefix, start), limit)
+	}
+	return iteratePage(api.b.ChainDb().NewIterator(prefix, start), limit)
+}
+
+// iteratePage collects a page of at most limit entries from the iterator.
+func iteratePage(it ethdb.Iterator, limit int) (*DbIteratorPage, error) {
+	defer it.Release()
+
+	var (
+		page = &DbIteratorPage{Keys: []hexutil.Bytes{}, Values: []hexutil.Bytes{}}
+		size int
+	)
+	for it.Next() {
+		if len(page.Keys) >= limit || size >= dbResponseMaxBytes {
+			page.More = true
+			break
+		}
+		page.Keys = append(page.Keys, common.CopyBytes(it.Key()))
+		page.Values = append(page.Values, common.CopyBytes(it.Value()))
+		size += len(it.Key()) + len(it.Value())
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// DbNewSnapshot creates a point-in-time view of the database key-value store,
+// returning its identifier. The snapshot is released automatically if it's not
+// used for a while.
+func (api *DebugAPI) DbNewSnapshot() (string, error) {
+	snapshotter, ok := api.b.ChainDb().(ethdb.Snapshotter)
+	if !ok {
+		return "", errDbSnapshotUnsupported
+	}
+	snap, err := snapshotter.NewSnapshot()
+	if err != nil {
+		return "", err
+	}
+	return api.snapshots.add(snap)
+}
+
+// DbSnapshotGet returns the raw value of a key stored in the database snapshot.
+func (api *DebugAPI) DbSnapshotGet(snapshot string, key hexutil.Bytes) (hexutil.Bytes, error) {
+	snap, err := api.snapshots.acquire(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	defer snap.lock.RUnlock()
+	return snap.snap.Get(key)
+}
+
+// DbSnapshotHas returns whether a key is stored in the database snapshot.
+func (api *DebugAPI) DbSnapshotHas(snapshot string, key hexutil.Bytes) (bool, error) {
+	snap, err := api.snapshots.acquire(snapshot)
+	if err != nil {
+		return false, err
+	}
+	defer snap.lock.RUnlock()
+	return snap.snap.Has(key)
+}
+
+// DbReleaseSnapshot releases the database snapshot with the given identifier.
+func (api *DebugAPI) DbReleaseSnapshot(snapshot string) error {
+	return api.snapshots.release(snapshot)
+}
+
 // DbAncient retrieves an ancient binary blob from the append-only immutable files.
 // It is a mapping to the `AncientReaderOp.Ancient` method
 func (api *DebugAPI) DbAncient(kind string, number uint64) (hexutil.Bytes, error) {
 	return api.b.ChainDb().Ancient(kind, number)
 }
 
+// DbAncientRange retrieves multiple ancient binary blobs in sequence, starting
+// from the index 'start'. It is a mapping to the `AncientReaderOp.AncientRange`
+// method, the size of the response being capped regardless of maxBytes.
+func (api *DebugAPI) DbAncientRange(kind string, start, count, maxBytes uint64) ([]hexutil.Bytes, error) {
+	if maxBytes == 0 || maxBytes > dbResponseMaxBytes {
+		maxBytes = dbResponseMaxBytes
+	}
+	blobs, err := api.b.ChainDb().AncientRange(kind, start, count, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]hexutil.Bytes, len(blobs))
+	for i, blob := range blobs {
+		items[i] = blob
+	}
+	return items, nil
+}
+
 // DbAncients returns the ancient item numbers in the ancient store.
 // It is a mapping to the `AncientReaderOp.Ancients` method
 func (api *DebugAPI) DbAncients() (uint64, error) {
 	return api.b.ChainDb().Ancients()
 }
+
+// DbAncientTail returns the number of the first stored item in the ancient store.
+// It is a mapping to the `AncientReaderOp.Tail` method
+func (api *DebugAPI) DbAncientTail() (uint64, error) {
+	return api.b.ChainDb().Tail()
+}
+
+// DbAncientSize returns the size of the given ancient data category.
+// It is a mapping to the `AncientReaderOp.AncientSize` method
+func (api *DebugAPI) DbAncientSize(kind string) (uint64, error) {
+	return api.b.ChainDb().AncientSize(kind)
+}
+
+// DbStat returns the statistic data of the database key-value store.
+func (api *DebugAPI) DbStat() (string, error) {
+	return api.b.ChainDb().Stat()
+}
+
+// DbPut inserts the given value into the database. It requires the database
+// modification over RPC to be enabled.
+func (api *DebugAPI) DbPut(key hexutil.Bytes, value hexutil.Bytes) error {
+	if !api.b.RPCDatabaseWrite() {
+		return errDbWriteDisabled
+	}
+	return api.b.ChainDb().Put(key, value)
+}
+
+// DbDelete removes the key from the database. It requires the database
+// modification over RPC to be enabled.
+func (api *DebugAPI) DbDelete(key hexutil.Bytes) error {
+	if !api.b.RPCDatabaseWrite() {
+		return errDbWriteDisabled
+	}
+	return api.b.ChainDb().Delete(key)
+}
+
+// DbDeleteRange removes all the keys in the range [start, end) from the database,
+// a nil bound being treated as unbounded. It requires the database modification
+// over RPC to be enabled.
+func (api *DebugAPI) DbDeleteRange(start *hexutil.Bytes, end *hexutil.Bytes) error {
+	if !api.b.RPCDatabaseWrite() {
+		return errDbWriteDisabled
+	}
+	return api.b.ChainDb().DeleteRange(optionalBytes(start), optionalBytes(end))
+}
+
+// DbWrite applies the given operations to the database atomically, as a single
+// batch. It requires the database modification over RPC to be enabled.
+func (api *DebugAPI) DbWrite(ops []DbBatchOp) error {
+	if !api.b.RPCDatabaseWrite() {
+		return errDbWriteDisabled
+	}
+	batch := api.b.ChainDb().NewBatch()
+	for i, op := range ops {
+		var err error
+		switch op.Op {
+		case "put":
+			err = batch.Put(op.Key, op.Value)
+		case "delete":
+			err = batch.Delete(op.Key)
+		case "deleteRange":
+			err = batch.DeleteRange(op.Key, optionalBytes(op.End))
+		default:
+			err = fmt.Errorf("unknown operation %q", op.Op)
+		}
+		if err != nil {
+			return fmt.Errorf("batch operation %d: %w", i, err)
+		}
+	}
+	return batch.Write()
+}
+
+// DbCompact flattens the underlying data store for the given key range, a nil
+// bound being treated as unbounded. It requires the database modification over
+// RPC to be enabled.
+func (api *DebugAPI) DbCompact(start *hexutil.Bytes, limit *hexutil.Bytes) error {
+	if !api.b.RPCDatabaseWrite() {
+		return errDbWriteDisabled
+	}
+	return api.b.ChainDb().Compact(optionalBytes(start), optionalBytes(limit))
+}
@@ -116,6 +116,11 @@ web3._extend({
 			params: 3,
 			inputFormatter: [null, null, null]
 		}),
+		new web3._extend.Method({
+			name: 'backup',
+			call: 'admin_backup',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'importChain',
 			call: 'admin_importChain',
@@ -374,6 +379,12 @@ web3._extend({
 			params: 2,
 			inputFormatter: [null, null]
 		}),
+		new web3._extend.Method({
+			name: 'witnessBreakdown',
+			call: 'debug_witnessBreakdown',
+			params: 2,
+			inputFormatter: [null, null]
+		}),
 		new web3._extend.Method({
 			name: 'standardTraceBlockToFile',
 			call: 'debug_standardTraceBlockToFile',
@@ -437,6 +448,18 @@ web3._extend({
 			call: 'debug_freezeClient',
 			params: 1,
 		}),
+		new web3._extend.Method({
+			name: 'getAccountHistory',
+			call: 'debug_getAccountHistory',
+			params: 4,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter, null],
+		}),
+		new web3._extend.Method({
+			name: 'getStorageHistory',
+			call: 'debug_getStorageHistory',
+			params: 5,
+			inputFormatter: [null, null, web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter, null],
+		}),
 		new web3._extend.Method({
 			name: 'getAccessibleState',
 			call: 'debug_getAccessibleState',
@@ -453,11 +476,42 @@ web3._extend({
 			call: 'debug_dbAncient',
 			params: 2
 		}),
+		new web3._extend.Method({
+			name: 'dbHas',
+			call: 'debug_dbHas',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'dbAncients',
 			call: 'debug_dbAncients',
 			params: 0
 		}),
+		new web3._extend.Method({
+			name: 'dbAncientRange',
+			call: 'debug_dbAncientRange',
+			params: 4
+		}),
+		new web3._extend.Method({
+			name: 'dbAncientTail',
+			call: 'debug_dbAncientTail',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'dbAncientSize',
+			call: 'debug_dbAncientSize',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'dbStat',
+			call: 'debug_dbStat',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'dbIterate',
+			call: 'debug_dbIterate',
+			params: 4,
+			inputFormatter: [null, null, null, null]
+		}),
 		new web3._extend.Method({
 			name: 'setTrieFlushInterval',
 			call: 'debug_setTrieFlushInterval',
@@ -578,6 +632,11 @@ web3._extend({
 			params: 3,
 			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter, null]
 		}),
+		new web3._extend.Method({
+			name: 'inclusionEstimate',
+			call: 'eth_inclusionEstimate',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'getLogs',
 			call: 'eth_getLogs',
@@ -619,6 +678,10 @@ web3._extend({
 			getter: 'eth_maxPriorityFeePerGas',
 			outputFormatter: web3._extend.utils.toBigNumber
 		}),
+		new web3._extend.Property({
+			name: 'feeEstimates',
+			getter: 'eth_feeEstimates'
+		}),
 	]
 });
 `
@@ -644,8 +707,27 @@ web3._extend({
 			params: 1,
 			inputFormatter: [web3._extend.utils.fromDecimal]
 		}),
+		new web3._extend.Method({
+			name: 'setForcedSenders',
+			call: 'miner_setForcedSenders',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'payloadDiagnostics',
+			call: 'miner_payloadDiagnostics',
+			params: 1
+		}),
 	],
-	properties: []
+	properties: [
+		new web3._extend.Property({
+			name: 'forcedSenders',
+			getter: 'miner_forcedSenders'
+		}),
+		new web3._extend.Property({
+			name: 'forcedInclusionReport',
+			getter: 'miner_forcedInclusionReport'
+		}),
+	]
 });
 `
 
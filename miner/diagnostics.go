@@ -0,0 +1,188 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"errors"
+	"maps"
+	"math/big"
+	"slices"
+	"time"
+
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core"
+)
+
+const (
+	// diagnosticsLimit is the number of most recent payloads the build
+	// diagnostics are retained for.
+	diagnosticsLimit = 64
+
+	// maxSkippedTxs is the maximum number of skipped transactions recorded
+	// individually per build iteration, beyond which only the counters are
+	// updated.
+	maxSkippedTxs = 256
+)
+
+// Reasons for a transaction being skipped during block building.
+const (
+	SkipUnderpriced     = "underpriced"      // Filtered out for paying less than the required tip or fees
+	SkipNonceTooLow     = "nonce too low"    // Nonce already used, the pool lagging behind the chain
+	SkipNonceGap        = "nonce gap"        // A preceding transaction of the sender is missing
+	SkipOutOfGas        = "out of gas"       // Not enough gas left in the block
+	SkipBlobLimit       = "blob limit"       // Not enough blob space left in the block
+	SkipSizeLimit       = "size limit"       // Transaction would exceed the maximum block size
+	SkipEvicted         = "evicted"          // Transaction was dropped from the pool in the meantime
+	SkipReplayProtected = "replay protected" // Replay protected transaction before EIP-155
+	SkipInvalid         = "invalid"          // Transaction failed to execute for any other reason
+)
+
+// SkippedTx is a transaction which was considered, but not included during a
+// build iteration.
+type SkippedTx struct {
+	Hash   common.Hash
+	Reason string
+	Error  error // Execution error, if the transaction failed to execute
+}
+
+// BuildIteration contains the diagnostics of a single payload build iteration.
+type BuildIteration struct {
+	Start      time.Time
+	Duration   time.Duration
+	Empty      bool           // Whether the empty payload was built, without any transactions
+	Considered int            // Number of transactions considered for inclusion
+	Included   int            // Number of transactions included
+	Skipped    map[string]int // Number of skipped transactions by reason
+	SkippedTxs []*SkippedTx   // Individual skipped transactions, capped at maxSkippedTxs
+	Fees       *big.Int       // Total fees of the built block, nil if the build failed
+	Error      error          // Error aborting the build iteration, if any
+}
+
+// skip records a transaction being skipped for the given reason.
+func (it *BuildIteration) skip(hash common.Hash, reason string, err error) {
+	if it == nil {
+		return
+	}
+	it.Skipped[reason]++
+	if len(it.SkippedTxs) < maxSkippedTxs {
+		it.SkippedTxs = append(it.SkippedTxs, &SkippedTx{Hash: hash, Reason: reason, Error: err})
+	}
+}
+
+// copy creates a deep copy of the build iteration.
+func (it *BuildIteration) copy() *BuildIteration {
+	cpy := *it
+	cpy.Skipped = maps.Clone(it.Skipped)
+	cpy.SkippedTxs = slices.Clone(it.SkippedTxs)
+	if it.Fees != nil {
+		cpy.Fees = new(big.Int).Set(it.Fees)
+	}
+	return &cpy
+}
+
+// PayloadDiagnostics contains the diagnostics of all build iterations of a
+// payload, along with the value of the best block built.
+type PayloadDiagnostics struct {
+	ID         engine.PayloadID
+	ParentHash common.Hash
+	Timestamp  uint64
+	Iterations []*BuildIteration
+	Value      *big.Int // Fees of the best block built so far, delivered upon resolution
+}
+
+// copy creates a deep copy of the payload diagnostics.
+func (d *PayloadDiagnostics) copy() *PayloadDiagnostics {
+	cpy := *d
+	cpy.Iterations = make([]*BuildIteration, len(d.Iterations))
+	for i, it := range d.Iterations {
+		cpy.Iterations[i] = it.copy()
+	}
+	cpy.Value = new(big.Int).Set(d.Value)
+	return &cpy
+}
+
+// newPayloadDiagnostics starts tracking the diagnostics of a new payload.
+func (miner *Miner) newPayloadDiagnostics(args *BuildPayloadArgs) {
+	miner.diagMu.Lock()
+	defer miner.diagMu.Unlock()
+
+	miner.diagnostics.Add(args.Id(), &PayloadDiagnostics{
+		ID:         args.Id(),
+		ParentHash: args.Parent,
+		Timestamp:  args.Timestamp,
+		Value:      new(big.Int),
+	})
+}
+
+// addBuildIteration records the outcome of a build iteration of a payload.
+func (miner *Miner) addBuildIteration(id engine.PayloadID, it *BuildIteration) {
+	miner.diagMu.Lock()
+	defer miner.diagMu.Unlock()
+
+	diag, ok := miner.diagnostics.Peek(id)
+	if !ok {
+		return
+	}
+	diag.Iterations = append(diag.Iterations, it)
+	if it.Fees != nil && it.Fees.Cmp(diag.Value) > 0 {
+		diag.Value = new(big.Int).Set(it.Fees)
+	}
+}
+
+// PayloadDiagnostics returns the build diagnostics of the payload with the given
+// id, or nil if the payload is unknown or was built too long ago.
+func (miner *Miner) PayloadDiagnostics(id engine.PayloadID) *PayloadDiagnostics {
+	miner.diagMu.Lock()
+	defer miner.diagMu.Unlock()
+
+	diag, ok := miner.diagnostics.Get(id)
+	if !ok {
+		return nil
+	}
+	return diag.copy()
+}
+
+// skipReason maps the error returned when committing a transaction to the
+// reason it is reported as skipped for.
+func skipReason(err error) string {
+	switch {
+	case errors.Is(err, core.ErrNonceTooLow):
+		return SkipNonceTooLow
+	case errors.Is(err, core.ErrNonceTooHigh), errors.Is(err, errNonceGap), errors.Is(err, errPrecedingTxFailed):
+		return SkipNonceGap
+	case errors.Is(err, core.ErrGasLimitReached):
+		return SkipOutOfGas
+	case errors.Is(err, errMaxBlobsReached):
+		return SkipBlobLimit
+	case errors.Is(err, core.ErrFeeCapTooLow):
+		return SkipUnderpriced
+	case errors.Is(err, errBlockSizeReached):
+		return SkipSizeLimit
+	case errors.Is(err, errReplayProtected):
+		return SkipReplayProtected
+	default:
+		return SkipInvalid
+	}
+}
+
+// newDiagnosticsCache creates the cache retaining the build diagnostics of the
+// most recent payloads.
+func newDiagnosticsCache() lru.BasicLRU[engine.PayloadID, *PayloadDiagnostics] {
+	return lru.NewBasicLRU[engine.PayloadID, *PayloadDiagnostics](diagnosticsLimit)
+}
@@ -0,0 +1,145 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestPayloadDiagnostics(t *testing.T) {
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		engine  = ethash.NewFaker()
+		backend = newTestWorkerBackend(t, params.TestChainConfig, engine, db, 0)
+		signer  = types.LatestSigner(params.TestChainConfig)
+	)
+	config := testConfig
+	config.GasPrice = big.NewInt(params.GWei)
+	miner := New(backend, config, engine)
+
+	// The second transaction pays less than the minimum tip and must be reported
+	// as underpriced.
+	txs := []*types.Transaction{
+		types.MustSignNewTx(testBankKey, signer, &types.LegacyTx{Nonce: 0, To: &testUserAddress, Gas: params.TxGas, GasPrice: big.NewInt(params.InitialBaseFee + 2*params.GWei)}),
+		types.MustSignNewTx(testBankKey, signer, &types.LegacyTx{Nonce: 1, To: &testUserAddress, Gas: params.TxGas, GasPrice: big.NewInt(params.InitialBaseFee)}),
+	}
+	for i, err := range backend.txPool.Add(txs, true) {
+		if err != nil {
+			t.Fatalf("Failed to add transaction %d: %v", i, err)
+		}
+	}
+	args := &BuildPayloadArgs{
+		Parent:    backend.chain.CurrentBlock().Hash(),
+		Timestamp: uint64(time.Now().Unix()),
+	}
+	payload, err := miner.BuildPayload(args, false)
+	if err != nil {
+		t.Fatalf("Failed to build payload: %v", err)
+	}
+	full := payload.ResolveFull()
+
+	diag := miner.PayloadDiagnostics(args.Id())
+	if diag == nil {
+		t.Fatal("Missing payload diagnostics")
+	}
+	if diag.ID != args.Id() || diag.ParentHash != args.Parent || diag.Timestamp != args.Timestamp {
+		t.Fatalf("Diagnostics mismatch: %+v", diag)
+	}
+	if len(diag.Iterations) < 2 {
+		t.Fatalf("Build iterations missing: have %d, want at least 2", len(diag.Iterations))
+	}
+	if it := diag.Iterations[0]; !it.Empty || it.Error != nil || it.Considered != 0 || it.Included != 0 {
+		t.Fatalf("Empty build iteration mismatch: %+v", it)
+	}
+	it := diag.Iterations[1]
+	if it.Empty {
+		t.Fatal("Full build iteration reported as empty")
+	}
+	if it.Error != nil {
+		t.Fatalf("Build iteration failed: %v", it.Error)
+	}
+	if it.Considered != 1 || it.Included != 1 {
+		t.Fatalf("Transaction count mismatch: considered %d, included %d", it.Considered, it.Included)
+	}
+	if it.Skipped[SkipUnderpriced] != 1 {
+		t.Fatalf("Underpriced transaction not reported: %v", it.Skipped)
+	}
+	if it.Fees == nil || it.Fees.Sign() <= 0 {
+		t.Fatalf("Invalid fees %v", it.Fees)
+	}
+	if diag.Value.Cmp(full.BlockValue) != 0 {
+		t.Fatalf("Block value mismatch: have %v, want %v", diag.Value, full.BlockValue)
+	}
+	unknown := &BuildPayloadArgs{Parent: args.Parent}
+	if miner.PayloadDiagnostics(unknown.Id()) != nil {
+		t.Fatal("Diagnostics returned for unknown payload")
+	}
+}
+
+func TestDiagnosticsOutOfGas(t *testing.T) {
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		engine  = ethash.NewFaker()
+		backend = newTestWorkerBackend(t, params.TestChainConfig, engine, db, 0)
+		signer  = types.LatestSigner(params.TestChainConfig)
+		price   = big.NewInt(2 * params.InitialBaseFee)
+	)
+	miner := New(backend, testConfig, engine)
+
+	txs := []*types.Transaction{
+		types.MustSignNewTx(testBankKey, signer, &types.LegacyTx{Nonce: 0, To: &testUserAddress, Gas: params.TxGas, GasPrice: price}),
+		types.MustSignNewTx(testBankKey, signer, &types.LegacyTx{Nonce: 1, To: &testUserAddress, Gas: params.TxGas, GasPrice: price}),
+	}
+	for i, err := range backend.txPool.Add(txs, true) {
+		if err != nil {
+			t.Fatalf("Failed to add transaction %d: %v", i, err)
+		}
+	}
+	env, err := miner.prepareWork(&generateParams{
+		timestamp:  uint64(time.Now().Unix()),
+		forceTime:  true,
+		parentHash: backend.chain.CurrentBlock().Hash(),
+	}, false)
+	if err != nil {
+		t.Fatalf("Failed to prepare work: %v", err)
+	}
+	// Leave room for the first transaction only, the second one must be reported
+	// once the block runs out of gas for any further transaction.
+	env.gasPool = new(core.GasPool).AddGas(params.TxGas + params.TxGas/2)
+	env.diag = &BuildIteration{Skipped: make(map[string]int)}
+
+	if err := miner.fillTransactions(nil, env); err != nil {
+		t.Fatalf("Failed to fill transactions: %v", err)
+	}
+	if len(env.txs) != 1 {
+		t.Fatalf("Included transaction count mismatch: have %d, want %d", len(env.txs), 1)
+	}
+	if env.diag.Considered != 2 || env.diag.Skipped[SkipOutOfGas] != 1 {
+		t.Fatalf("Out of gas transaction not reported: considered %d, skipped %v", env.diag.Considered, env.diag.Skipped)
+	}
+	if len(env.diag.SkippedTxs) != 1 || env.diag.SkippedTxs[0].Hash != txs[1].Hash() {
+		t.Fatalf("Unexpected skipped transactions: %v", env.diag.SkippedTxs)
+	}
+}
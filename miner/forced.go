@@ -0,0 +1,221 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+var (
+	// errNonceGap is reported for forced transactions which are not executable
+	// because a transaction with a lower nonce from the same sender is missing.
+	errNonceGap = errors.New("nonce gap")
+
+	// errPrecedingTxFailed is reported for forced transactions following another
+	// transaction of the same sender which could not be included.
+	errPrecedingTxFailed = errors.New("preceding transaction of sender not included")
+
+	// errBlockSizeReached is reported for forced transactions which would push
+	// the block over the maximum allowed size.
+	errBlockSizeReached = errors.New("block size limit reached")
+
+	// errReplayProtected is reported for forced transactions which are replay
+	// protected before the EIP-155 fork is active.
+	errReplayProtected = errors.New("replay protected transaction before EIP-155")
+
+	// errEmptyPayload is reported for forced transactions which were not included
+	// because the payload was built without any transactions.
+	errEmptyPayload = errors.New("empty payload")
+)
+
+// ForcedInclusion is the outcome of including a transaction of a sender on the
+// forced inclusion list into a locally built block.
+type ForcedInclusion struct {
+	Hash     common.Hash
+	Sender   common.Address
+	Nonce    uint64
+	Included bool
+	Error    error // Reason why the transaction could not be included
+}
+
+// ForcedInclusionReport contains the outcome of the forced inclusion list for
+// the most recently built payload.
+type ForcedInclusionReport struct {
+	ParentHash   common.Hash
+	Number       uint64
+	Transactions []*ForcedInclusion
+}
+
+// SetForcedSenders sets the list of senders whose pending transactions are to
+// be included into locally built payloads before any other transaction.
+func (miner *Miner) SetForcedSenders(senders []common.Address) {
+	miner.confMu.Lock()
+	miner.forced = senders
+	miner.confMu.Unlock()
+}
+
+// ForcedSenders returns the list of senders on the forced inclusion list.
+func (miner *Miner) ForcedSenders() []common.Address {
+	miner.confMu.RLock()
+	defer miner.confMu.RUnlock()
+
+	return append([]common.Address(nil), miner.forced...)
+}
+
+// ForcedInclusionReport returns the outcome of the forced inclusion list for the
+// most recently built payload, or nil if no payload was built with a non-empty
+// forced inclusion list yet.
+func (miner *Miner) ForcedInclusionReport() *ForcedInclusionReport {
+	miner.forcedMu.Lock()
+	defer miner.forcedMu.Unlock()
+
+	return miner.forcedReport
+}
+
+// setForcedInclusionReport stores the outcome of the forced inclusion list for
+// the given payload build result.
+func (miner *Miner) setForcedInclusionReport(r *newPayloadResult) {
+	if r.forced == nil {
+		return
+	}
+	miner.forcedMu.Lock()
+	defer miner.forcedMu.Unlock()
+
+	miner.forcedReport = &ForcedInclusionReport{
+		ParentHash:   r.block.ParentHash(),
+		Number:       r.block.NumberU64(),
+		Transactions: r.forced,
+	}
+}
+
+// forcedContent returns the pending transactions of the sender in nonce order,
+// including its blob transactions, together with its queued transactions. The
+// blob transactions are not retrievable by sender from the pool, they have to
+// be looked up among the given pending ones.
+func (miner *Miner) forcedContent(sender common.Address, blobs map[common.Address][]*txpool.LazyTransaction) ([]*types.Transaction, []*types.Transaction) {
+	pending, queued := miner.txpool.ContentFrom(sender)
+	for _, ltx := range blobs[sender] {
+		if tx := ltx.Resolve(); tx != nil {
+			pending = append(pending, tx)
+		}
+	}
+	slices.SortStableFunc(pending, func(a, b *types.Transaction) int {
+		return cmp.Compare(a.Nonce(), b.Nonce())
+	})
+	return pending, queued
+}
+
+// forcedBlobTxs returns the pending blob transactions of the pool, regardless of
+// the fees they pay, if there are any senders on the forced inclusion list.
+func (miner *Miner) forcedBlobTxs(senders []common.Address) map[common.Address][]*txpool.LazyTransaction {
+	if len(senders) == 0 {
+		return nil
+	}
+	return miner.txpool.Pending(txpool.PendingFilter{OnlyBlobTxs: true})
+}
+
+// skipForcedTransactions records every transaction of the senders on the forced
+// inclusion list as not included, used for payloads built without transactions.
+func (miner *Miner) skipForcedTransactions(env *environment, senders []common.Address) {
+	if len(senders) == 0 {
+		return
+	}
+	var (
+		blobs  = miner.forcedBlobTxs(senders)
+		forced = []*ForcedInclusion{}
+	)
+	for _, sender := range senders {
+		pending, queued := miner.forcedContent(sender, blobs)
+		for _, tx := range pending {
+			forced = append(forced, &ForcedInclusion{Hash: tx.Hash(), Sender: sender, Nonce: tx.Nonce(), Error: errEmptyPayload})
+		}
+		for _, tx := range queued {
+			forced = append(forced, &ForcedInclusion{Hash: tx.Hash(), Sender: sender, Nonce: tx.Nonce(), Error: errNonceGap})
+		}
+	}
+	env.forced = forced
+}
+
+// commitForcedTransactions includes the pending transactions of the senders on
+// the forced inclusion list into the block, ahead of any other transaction and
+// regardless of the configured minimum tip. The outcome of every transaction of
+// the listed senders is recorded in the environment.
+func (miner *Miner) commitForcedTransactions(env *environment, senders []common.Address, interrupt *atomic.Int32) error {
+	if env.gasPool == nil {
+		env.gasPool = new(core.GasPool).AddGas(env.header.GasLimit)
+	}
+	var (
+		blobs   = miner.forcedBlobTxs(senders)
+		isOsaka = miner.chainConfig.IsOsaka(env.header.Number, env.header.Time)
+	)
+	env.forced = []*ForcedInclusion{}
+	for _, sender := range senders {
+		pending, queued := miner.forcedContent(sender, blobs)
+
+		var failed bool
+		for _, tx := range pending {
+			if interrupt != nil {
+				if signal := interrupt.Load(); signal != commitInterruptNone {
+					return signalToErr(signal)
+				}
+			}
+			res := &ForcedInclusion{Hash: tx.Hash(), Sender: sender, Nonce: tx.Nonce()}
+			env.forced = append(env.forced, res)
+			if env.diag != nil {
+				env.diag.Considered++
+			}
+			// Make sure all blob transactions after osaka have cell proofs
+			if sidecar := tx.BlobTxSidecar(); isOsaka && !failed && sidecar != nil && sidecar.Version == types.BlobSidecarVersion0 {
+				if err := sidecar.ToV1(); err != nil {
+					res.Error = err
+				}
+			}
+			switch {
+			case res.Error != nil:
+			case failed:
+				res.Error = errPrecedingTxFailed
+			case !env.txFitsSize(tx):
+				res.Error = errBlockSizeReached
+			case tx.Protected() && !miner.chainConfig.IsEIP155(env.header.Number):
+				res.Error = errReplayProtected
+			default:
+				env.state.SetTxContext(tx.Hash(), env.tcount)
+				res.Error = miner.commitTransaction(env, tx)
+			}
+			if res.Error != nil {
+				log.Debug("Failed to include forced transaction", "hash", tx.Hash(), "sender", sender, "err", res.Error)
+				env.diag.skip(tx.Hash(), skipReason(res.Error), res.Error)
+				failed = true
+				continue
+			}
+			res.Included = true
+		}
+		for _, tx := range queued {
+			env.forced = append(env.forced, &ForcedInclusion{Hash: tx.Hash(), Sender: sender, Nonce: tx.Nonce(), Error: errNonceGap})
+		}
+	}
+	return nil
+}
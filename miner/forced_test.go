@@ -0,0 +1,125 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestForcedInclusion(t *testing.T) {
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		engine  = ethash.NewFaker()
+		backend = newTestWorkerBackend(t, params.TestChainConfig, engine, db, 0)
+		signer  = types.LatestSigner(params.TestChainConfig)
+		price   = big.NewInt(2 * params.InitialBaseFee)
+	)
+	// Configure a minimum tip which the transactions don't pay, so they only
+	// make it into the block through the forced inclusion list.
+	config := testConfig
+	config.GasPrice = big.NewInt(1000 * params.GWei)
+	config.ForcedSenders = []common.Address{testBankAddress}
+	miner := New(backend, config, engine)
+
+	txs := []*types.Transaction{
+		types.MustSignNewTx(testBankKey, signer, &types.LegacyTx{Nonce: 0, To: &testUserAddress, Gas: params.TxGas, GasPrice: price}),
+		types.MustSignNewTx(testBankKey, signer, &types.LegacyTx{Nonce: 1, To: &testUserAddress, Gas: params.TxGas, GasPrice: price}),
+		types.MustSignNewTx(testBankKey, signer, &types.LegacyTx{Nonce: 3, To: &testUserAddress, Gas: params.TxGas, GasPrice: price}),
+	}
+	for i, err := range backend.txPool.Add(txs, true) {
+		if err != nil {
+			t.Fatalf("Failed to add transaction %d: %v", i, err)
+		}
+	}
+	payload, err := miner.BuildPayload(&BuildPayloadArgs{
+		Parent:    backend.chain.CurrentBlock().Hash(),
+		Timestamp: uint64(time.Now().Unix()),
+	}, false)
+	if err != nil {
+		t.Fatalf("Failed to build payload: %v", err)
+	}
+	full := payload.ResolveFull()
+	if have := len(full.ExecutionPayload.Transactions); have != 2 {
+		t.Fatalf("Forced transactions not included, have %d, want %d", have, 2)
+	}
+	report := miner.ForcedInclusionReport()
+	if report == nil {
+		t.Fatal("Missing forced inclusion report")
+	}
+	if report.Number != 1 || len(report.Transactions) != len(txs) {
+		t.Fatalf("Unexpected report for block %d with %d transactions", report.Number, len(report.Transactions))
+	}
+	for i, res := range report.Transactions {
+		if res.Hash != txs[i].Hash() || res.Sender != testBankAddress || res.Nonce != txs[i].Nonce() {
+			t.Fatalf("Report entry %d mismatch: %+v", i, res)
+		}
+	}
+	if !report.Transactions[0].Included || !report.Transactions[1].Included {
+		t.Fatalf("Executable transactions reported as not included")
+	}
+	if report.Transactions[2].Included || !errors.Is(report.Transactions[2].Error, errNonceGap) {
+		t.Fatalf("Nonce gap not reported, got %v", report.Transactions[2].Error)
+	}
+}
+
+func TestForcedInclusionEmptyPayload(t *testing.T) {
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		engine  = ethash.NewFaker()
+		backend = newTestWorkerBackend(t, params.TestChainConfig, engine, db, 0)
+		signer  = types.LatestSigner(params.TestChainConfig)
+		price   = big.NewInt(2 * params.InitialBaseFee)
+	)
+	config := testConfig
+	config.ForcedSenders = []common.Address{testBankAddress}
+	miner := New(backend, config, engine)
+
+	tx := types.MustSignNewTx(testBankKey, signer, &types.LegacyTx{Nonce: 0, To: &testUserAddress, Gas: params.TxGas, GasPrice: price})
+	if err := backend.txPool.Add([]*types.Transaction{tx}, true)[0]; err != nil {
+		t.Fatalf("Failed to add transaction: %v", err)
+	}
+	empty := miner.generateWork(&generateParams{
+		timestamp:  uint64(time.Now().Unix()),
+		forceTime:  true,
+		parentHash: backend.chain.CurrentBlock().Hash(),
+		noTxs:      true,
+	}, false)
+	if empty.err != nil {
+		t.Fatalf("Failed to build empty payload: %v", empty.err)
+	}
+	miner.setForcedInclusionReport(empty)
+
+	report := miner.ForcedInclusionReport()
+	if report == nil {
+		t.Fatal("Missing forced inclusion report for empty payload")
+	}
+	if len(report.Transactions) != 1 {
+		t.Fatalf("Unexpected report with %d transactions", len(report.Transactions))
+	}
+	if res := report.Transactions[0]; res.Hash != tx.Hash() || res.Included || !errors.Is(res.Error, errEmptyPayload) {
+		t.Fatalf("Unexpected report entry: %+v", res)
+	}
+}
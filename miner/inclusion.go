@@ -0,0 +1,174 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"cmp"
+	"errors"
+	"math/big"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// errPendingUnavailable is returned if the inclusion of transactions is to be
+// estimated, but no pending block could be generated.
+var errPendingUnavailable = errors.New("pending block not available")
+
+// InclusionEstimate describes where a transaction would land if a block was
+// built on top of the current chain head right now.
+type InclusionEstimate struct {
+	Hash         common.Hash // Hash of the transaction the estimate belongs to
+	BlockNumber  uint64      // Number of the pending block the estimate was made against
+	Included     bool        // Whether the transaction is part of the pending block
+	Position     int         // Index of the transaction in the pending block (or where it would land), -1 if not executable
+	Reverted     bool        // Whether the (simulated) execution of the transaction reverted
+	GasUsed      uint64      // Gas used by the (simulated) execution of the transaction
+	EffectiveTip *big.Int    // Tip paid per gas on top of the pending block's base fee
+	Error        error       // Reason why the transaction cannot be executed, if any
+}
+
+// EstimateInclusion reports for each of the given transactions whether it made
+// it into the current pending block, and if so, at which position and with what
+// outcome. Transactions missing from the pending block are simulated on top of
+// it instead, in the order the miner would pick them, reporting the position
+// they would land at, whether they would revert or why they cannot be executed
+// at all (e.g. nonce gap, insufficient funds or block full).
+func (miner *Miner) EstimateInclusion(txs []*types.Transaction) ([]*InclusionEstimate, error) {
+	pending := miner.getPending()
+	if pending == nil {
+		return nil, errPendingUnavailable
+	}
+	var (
+		header   = pending.block.Header()
+		baseFee  = header.BaseFee
+		included = make(map[common.Hash]int, len(pending.block.Transactions()))
+		results  = make([]*InclusionEstimate, len(txs))
+		missing  []int
+	)
+	for i, tx := range pending.block.Transactions() {
+		included[tx.Hash()] = i
+	}
+	for i, tx := range txs {
+		res := &InclusionEstimate{
+			Hash:        tx.Hash(),
+			BlockNumber: header.Number.Uint64(),
+			Position:    -1,
+		}
+		if tip, err := tx.EffectiveGasTip(baseFee); err == nil {
+			res.EffectiveTip = tip
+		}
+		if index, ok := included[tx.Hash()]; ok {
+			receipt := pending.receipts[index]
+			res.Included = true
+			res.Position = index
+			res.Reverted = receipt.Status == types.ReceiptStatusFailed
+			res.GasUsed = receipt.GasUsed
+		} else {
+			missing = append(missing, i)
+		}
+		results[i] = res
+	}
+	if len(missing) == 0 {
+		return results, nil
+	}
+	// Simulate the transactions missing from the pending block on top of it,
+	// in the same order as the miner would include them.
+	var (
+		signer   = types.MakeSigner(miner.chainConfig, header.Number, header.Time)
+		statedb  = pending.stateDB.Copy()
+		gasPool  = new(core.GasPool).AddGas(header.GasLimit - header.GasUsed)
+		usedGas  = header.GasUsed
+		evm      = vm.NewEVM(core.NewEVMBlockContext(header, miner.chain, &header.Coinbase), statedb, miner.chainConfig, vm.Config{})
+		position = len(pending.block.Transactions())
+	)
+	for _, i := range simulationOrder(signer, baseFee, txs, missing) {
+		statedb.SetTxContext(txs[i].Hash(), position)
+
+		snap, gas := statedb.Snapshot(), gasPool.Gas()
+		receipt, err := core.ApplyTransaction(evm, gasPool, statedb, header, txs[i], &usedGas)
+		if err != nil {
+			statedb.RevertToSnapshot(snap)
+			gasPool.SetGas(gas)
+			results[i].Error = err
+			continue
+		}
+		results[i].Position = position
+		results[i].Reverted = receipt.Status == types.ReceiptStatusFailed
+		results[i].GasUsed = receipt.GasUsed
+		position++
+	}
+	return results, nil
+}
+
+// simulationOrder returns the indices of the given transactions in the order the
+// miner would pick them: the transactions of each sender in nonce order, with the
+// senders interleaved by the effective tip of their next transaction, the same as
+// done by transactionsByPriceAndNonce.
+func simulationOrder(signer types.Signer, baseFee *big.Int, txs []*types.Transaction, indices []int) []int {
+	// Group the transactions by sender, the ones with an invalid signature are
+	// kept in separate groups as they fail regardless of the order.
+	var (
+		groups  [][]int
+		senders = make(map[common.Address]int)
+	)
+	for _, i := range indices {
+		from, err := types.Sender(signer, txs[i])
+		if err != nil {
+			groups = append(groups, []int{i})
+			continue
+		}
+		if g, ok := senders[from]; ok {
+			groups[g] = append(groups[g], i)
+			continue
+		}
+		senders[from] = len(groups)
+		groups = append(groups, []int{i})
+	}
+	for _, group := range groups {
+		slices.SortStableFunc(group, func(a, b int) int {
+			return cmp.Compare(txs[a].Nonce(), txs[b].Nonce())
+		})
+	}
+	// Repeatedly pick the next transaction paying the highest tip. Transactions
+	// not covering the base fee are treated as paying no tip at all.
+	tip := func(i int) *big.Int {
+		tip, err := txs[i].EffectiveGasTip(baseFee)
+		if err != nil {
+			return new(big.Int).SetInt64(-1)
+		}
+		return tip
+	}
+	order := make([]int, 0, len(indices))
+	for len(order) < len(indices) {
+		best := -1
+		for g, group := range groups {
+			if len(group) == 0 {
+				continue
+			}
+			if best == -1 || tip(group[0]).Cmp(tip(groups[best][0])) > 0 {
+				best = g
+			}
+		}
+		order = append(order, groups[best][0])
+		groups[best] = groups[best][1:]
+	}
+	return order
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestEstimateInclusion(t *testing.T) {
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		engine  = ethash.NewFaker()
+		backend = newTestWorkerBackend(t, ethashChainConfig, engine, db, 0)
+		miner   = New(backend, testConfig, engine)
+		signer  = types.LatestSigner(ethashChainConfig)
+		gas     = big.NewInt(2 * params.InitialBaseFee)
+	)
+	// Transfer that executes fine, a contract creation reverting right away and
+	// a transaction leaving a nonce gap. The simulation should follow the nonces.
+	txs := []*types.Transaction{
+		types.MustSignNewTx(testBankKey, signer, &types.LegacyTx{Nonce: 5, To: &testUserAddress, Gas: params.TxGas, GasPrice: gas}),
+		types.MustSignNewTx(testBankKey, signer, &types.LegacyTx{Nonce: 1, Gas: 100000, GasPrice: gas, Data: []byte{0x60, 0x00, 0x60, 0x00, 0xfd}}),
+		types.MustSignNewTx(testBankKey, signer, &types.LegacyTx{Nonce: 0, To: &testUserAddress, Value: big.NewInt(1000), Gas: params.TxGas, GasPrice: gas}),
+	}
+	estimates, err := miner.EstimateInclusion(txs)
+	if err != nil {
+		t.Fatalf("Failed to estimate inclusion: %v", err)
+	}
+	for i, est := range estimates {
+		if est.Hash != txs[i].Hash() {
+			t.Fatalf("Estimate %d: hash mismatch, want %x, got %x", i, txs[i].Hash(), est.Hash)
+		}
+		if est.Included {
+			t.Fatalf("Estimate %d: unexpected inclusion in empty pending block", i)
+		}
+		if est.BlockNumber != 1 {
+			t.Fatalf("Estimate %d: block number mismatch, want %d, got %d", i, 1, est.BlockNumber)
+		}
+		if est.EffectiveTip == nil || est.EffectiveTip.Sign() <= 0 {
+			t.Fatalf("Estimate %d: invalid effective tip %v", i, est.EffectiveTip)
+		}
+	}
+	if !errors.Is(estimates[0].Error, core.ErrNonceTooHigh) || estimates[0].Position != -1 {
+		t.Errorf("Nonce gap not detected, got error %v at position %d", estimates[0].Error, estimates[0].Position)
+	}
+	// The executable transactions must report where they would land
+	if estimates[2].Position != 0 || estimates[1].Position != 1 {
+		t.Errorf("Position mismatch, want 0 and 1, got %d and %d", estimates[2].Position, estimates[1].Position)
+	}
+	if estimates[1].Error != nil || !estimates[1].Reverted {
+		t.Errorf("Revert not detected, error %v, reverted %v", estimates[1].Error, estimates[1].Reverted)
+	}
+	if estimates[2].Error != nil || estimates[2].Reverted || estimates[2].GasUsed != params.TxGas {
+		t.Errorf("Transfer failed, error %v, reverted %v, gas used %d", estimates[2].Error, estimates[2].Reverted, estimates[2].GasUsed)
+	}
+}
+
+func TestSimulationOrder(t *testing.T) {
+	var (
+		signer  = types.LatestSigner(ethashChainConfig)
+		baseFee = big.NewInt(params.InitialBaseFee)
+		keys    = []*ecdsa.PrivateKey{testBankKey, testUserKey}
+		txs     []*types.Transaction
+	)
+	// The second sender pays more, but only for its second transaction
+	for _, tx := range []struct {
+		key, nonce int
+		tip        int64
+	}{
+		{0, 1, 3}, {0, 0, 4}, {1, 0, 2}, {1, 1, 10},
+	} {
+		txs = append(txs, types.MustSignNewTx(keys[tx.key], signer, &types.DynamicFeeTx{
+			ChainID:   ethashChainConfig.ChainID,
+			Nonce:     uint64(tx.nonce),
+			To:        &testUserAddress,
+			Gas:       params.TxGas,
+			GasTipCap: big.NewInt(tx.tip),
+			GasFeeCap: new(big.Int).Add(baseFee, big.NewInt(tx.tip)),
+		}))
+	}
+	order := simulationOrder(signer, baseFee, txs, []int{0, 1, 2, 3})
+	if want := []int{1, 0, 2, 3}; !slices.Equal(order, want) {
+		t.Fatalf("Order mismatch, want %v, got %v", want, order)
+	}
+}
@@ -23,8 +23,10 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum/beacon/engine"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/lru"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
@@ -48,6 +50,8 @@ type Config struct {
 	GasCeil             uint64         // Target gas ceiling for mined blocks.
 	GasPrice            *big.Int       // Minimum gas price for mining a transaction
 	Recommit            time.Duration  // The time interval for miner to re-create mining work.
+
+	ForcedSenders []common.Address `toml:",omitempty"` // Senders whose pending transactions are included first
 }
 
 // DefaultConfig contains default settings for miner.
@@ -71,9 +75,16 @@ type Miner struct {
 	engine      consensus.Engine
 	txpool      *txpool.TxPool
 	prio        []common.Address // A list of senders to prioritize
+	forced      []common.Address // A list of senders whose transactions are force-included
 	chain       *core.BlockChain
 	pending     *pending
 	pendingMu   sync.Mutex // Lock protects the pending block
+
+	forcedReport *ForcedInclusionReport // Outcome of the forced inclusion list for the last payload
+	forcedMu     sync.Mutex             // Lock protects the forced inclusion report
+
+	diagnostics lru.BasicLRU[engine.PayloadID, *PayloadDiagnostics] // Build diagnostics of the recent payloads
+	diagMu      sync.Mutex                                          // Lock protects the build diagnostics
 }
 
 // New creates a new miner with provided config.
@@ -83,8 +94,10 @@ func New(eth Backend, config Config, engine consensus.Engine) *Miner {
 		chainConfig: eth.BlockChain().Config(),
 		engine:      engine,
 		txpool:      eth.TxPool(),
+		forced:      config.ForcedSenders,
 		chain:       eth.BlockChain(),
 		pending:     &pending{},
+		diagnostics: newDiagnosticsCache(),
 	}
 }
 
@@ -224,8 +224,11 @@ func (miner *Miner) buildPayload(args *BuildPayloadArgs, witness bool) (*Payload
 	if empty.err != nil {
 		return nil, empty.err
 	}
+	miner.setForcedInclusionReport(empty)
 	// Construct a payload object for return.
 	payload := newPayload(empty.block, empty.requests, empty.witness, args.Id())
+	miner.newPayloadDiagnostics(args)
+	miner.addBuildIteration(payload.id, empty.diag)
 
 	// Spin up a routine for updating the payload in background. This strategy
 	// can maximum the revenue for including transactions with highest fee.
@@ -257,9 +260,12 @@ func (miner *Miner) buildPayload(args *BuildPayloadArgs, witness bool) (*Payload
 				start := time.Now()
 				r := miner.generateWork(fullParams, witness)
 				if r.err == nil {
+					miner.setForcedInclusionReport(r)
+					miner.addBuildIteration(payload.id, r.diag)
 					payload.update(r, time.Since(start))
 				} else {
 					log.Info("Error while generating work", "id", payload.id, "err", r.err)
+					miner.addBuildIteration(payload.id, &BuildIteration{Start: start, Duration: time.Since(start), Error: r.err})
 				}
 				timer.Reset(miner.config.Recommit)
 			case <-payload.stop:
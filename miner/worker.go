@@ -38,6 +38,7 @@ import (
 )
 
 var (
+	errMaxBlobsReached            = errors.New("max data blobs reached")
 	errBlockInterruptedByNewHead  = errors.New("new head arrived while building block")
 	errBlockInterruptedByRecommit = errors.New("recommit interrupt while building block")
 	errBlockInterruptedByTimeout  = errors.New("timeout while building block")
@@ -61,6 +62,8 @@ type environment struct {
 	blobs    int
 
 	witness *stateless.Witness
+	forced  []*ForcedInclusion // outcome of the forced inclusion list, nil if not applied
+	diag    *BuildIteration    // diagnostics of the build, nil if not collected
 }
 
 // txFits reports whether the transaction fits into the block size limit.
@@ -90,6 +93,8 @@ type newPayloadResult struct {
 	receipts []*types.Receipt       // Receipts collected during construction
 	requests [][]byte               // Consensus layer requests collected during block construction
 	witness  *stateless.Witness     // Witness is an optional stateless proof
+	forced   []*ForcedInclusion     // Outcome of the forced inclusion list, nil if not applied
+	diag     *BuildIteration        // Diagnostics of the build iteration
 }
 
 // generateParams wraps various settings for generating sealing task.
@@ -106,10 +111,12 @@ type generateParams struct {
 
 // generateWork generates a sealing block based on the given parameters.
 func (miner *Miner) generateWork(genParam *generateParams, witness bool) *newPayloadResult {
+	start := time.Now()
 	work, err := miner.prepareWork(genParam, witness)
 	if err != nil {
 		return &newPayloadResult{err: err}
 	}
+	work.diag = &BuildIteration{Start: start, Empty: genParam.noTxs, Skipped: make(map[string]int)}
 
 	// Check withdrawals fit max block size.
 	// Due to the cap on withdrawal count, this can actually never happen, but we still need to
@@ -132,6 +139,12 @@ func (miner *Miner) generateWork(genParam *generateParams, witness bool) *newPay
 		if errors.Is(err, errBlockInterruptedByTimeout) {
 			log.Warn("Block building is interrupted", "allowance", common.PrettyDuration(miner.config.Recommit))
 		}
+	} else {
+		miner.confMu.RLock()
+		forced := miner.forced
+		miner.confMu.RUnlock()
+
+		miner.skipForcedTransactions(work, forced)
 	}
 	body := types.Body{Transactions: work.txs, Withdrawals: genParam.withdrawals}
 
@@ -166,14 +179,22 @@ func (miner *Miner) generateWork(genParam *generateParams, witness bool) *newPay
 	if err != nil {
 		return &newPayloadResult{err: err}
 	}
+	fees := totalFees(block, work.receipts)
+
+	work.diag.Duration = time.Since(start)
+	work.diag.Included = len(work.txs)
+	work.diag.Fees = fees
+
 	return &newPayloadResult{
 		block:    block,
-		fees:     totalFees(block, work.receipts),
+		fees:     fees,
 		sidecars: work.sidecars,
 		stateDB:  work.state,
 		receipts: work.receipts,
 		requests: requests,
 		witness:  work.witness,
+		forced:   work.forced,
+		diag:     work.diag,
 	}
 }
 
@@ -250,6 +271,10 @@ func (miner *Miner) prepareWork(genParams *generateParams, witness bool) (*envir
 		log.Error("Failed to create sealing context", "err", err)
 		return nil, err
 	}
+	if err := core.ProcessVerkleTransition(miner.chainConfig, header, env.state); err != nil {
+		log.Error("Failed to convert state into verkle tree", "err", err)
+		return nil, err
+	}
 	if header.ParentBeaconRoot != nil {
 		core.ProcessBeaconBlockRoot(*header.ParentBeaconRoot, env.evm)
 	}
@@ -262,7 +287,7 @@ func (miner *Miner) prepareWork(genParams *generateParams, witness bool) (*envir
 // makeEnv creates a new environment for the sealing block.
 func (miner *Miner) makeEnv(parent *types.Header, header *types.Header, coinbase common.Address, witness bool) (*environment, error) {
 	// Retrieve the parent state to execute on top.
-	state, err := miner.chain.StateAt(parent.Root)
+	state, err := miner.chain.PreState(parent.Root, header)
 	if err != nil {
 		return nil, err
 	}
@@ -311,7 +336,7 @@ func (miner *Miner) commitBlobTransaction(env *environment, tx *types.Transactio
 	// tx has too many blobs. So we have to explicitly check it here.
 	maxBlobs := eip4844.MaxBlobsPerBlock(miner.chainConfig, env.header.Time)
 	if env.blobs+len(sc.Blobs) > maxBlobs {
-		return errors.New("max data blobs reached")
+		return errMaxBlobsReached
 	}
 	receipt, err := miner.applyTransaction(env, tx)
 	if err != nil {
@@ -361,6 +386,15 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 		// If we don't have enough gas for any further transactions then we're done.
 		if env.gasPool.Gas() < params.TxGas {
 			log.Trace("Not enough gas for further transactions", "have", env.gasPool, "want", params.TxGas)
+			if env.diag != nil {
+				for _, txs := range []*transactionsByPriceAndNonce{plainTxs, blobTxs} {
+					for ltx, _ := txs.Peek(); ltx != nil; ltx, _ = txs.Peek() {
+						env.diag.Considered++
+						env.diag.skip(ltx.Hash, SkipOutOfGas, nil)
+						txs.Pop()
+					}
+				}
+			}
 			break
 		}
 		// If we don't have enough blob space for any further blob transactions,
@@ -393,9 +427,13 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 		if ltx == nil {
 			break
 		}
+		if env.diag != nil {
+			env.diag.Considered++
+		}
 		// If we don't have enough space for the next transaction, skip the account.
 		if env.gasPool.Gas() < ltx.Gas {
 			log.Trace("Not enough gas left for transaction", "hash", ltx.Hash, "left", env.gasPool.Gas(), "needed", ltx.Gas)
+			env.diag.skip(ltx.Hash, SkipOutOfGas, nil)
 			txs.Pop()
 			continue
 		}
@@ -407,6 +445,7 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 			left := eip4844.MaxBlobsPerBlock(miner.chainConfig, env.header.Time) - env.blobs
 			if left < int(ltx.BlobGas/params.BlobTxBlobGasPerBlob) {
 				log.Trace("Not enough blob space left for transaction", "hash", ltx.Hash, "left", left, "needed", ltx.BlobGas/params.BlobTxBlobGasPerBlob)
+				env.diag.skip(ltx.Hash, SkipBlobLimit, nil)
 				txs.Pop()
 				continue
 			}
@@ -416,6 +455,7 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 		tx := ltx.Resolve()
 		if tx == nil {
 			log.Trace("Ignoring evicted transaction", "hash", ltx.Hash)
+			env.diag.skip(ltx.Hash, SkipEvicted, nil)
 			txs.Pop()
 			continue
 		}
@@ -423,6 +463,7 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 		// if inclusion of the transaction would put the block size over the
 		// maximum we allow, don't add any more txs to the payload.
 		if !env.txFitsSize(tx) {
+			env.diag.skip(ltx.Hash, SkipSizeLimit, nil)
 			break
 		}
 
@@ -432,6 +473,7 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 				if sidecar.Version == types.BlobSidecarVersion0 {
 					log.Info("Including blob tx with v0 sidecar, recomputing proofs", "hash", ltx.Hash)
 					if err := sidecar.ToV1(); err != nil {
+						env.diag.skip(ltx.Hash, SkipInvalid, err)
 						txs.Pop()
 						log.Warn("Failed to recompute cell proofs", "hash", ltx.Hash, "err", err)
 						continue
@@ -448,6 +490,7 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 		// phase, start ignoring the sender until we do.
 		if tx.Protected() && !miner.chainConfig.IsEIP155(env.header.Number) {
 			log.Trace("Ignoring replay protected transaction", "hash", ltx.Hash, "eip155", miner.chainConfig.EIP155Block)
+			env.diag.skip(ltx.Hash, SkipReplayProtected, nil)
 			txs.Pop()
 			continue
 		}
@@ -459,6 +502,7 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 		case errors.Is(err, core.ErrNonceTooLow):
 			// New head notification data race between the transaction pool and miner, shift
 			log.Trace("Skipping transaction with low nonce", "hash", ltx.Hash, "sender", from, "nonce", tx.Nonce())
+			env.diag.skip(ltx.Hash, SkipNonceTooLow, err)
 			txs.Shift()
 
 		case errors.Is(err, nil):
@@ -469,6 +513,7 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 			// Transaction is regarded as invalid, drop all consecutive transactions from
 			// the same sender because of `nonce-too-high` clause.
 			log.Debug("Transaction failed, account skipped", "hash", ltx.Hash, "err", err)
+			env.diag.skip(ltx.Hash, skipReason(err), err)
 			txs.Pop()
 		}
 	}
@@ -482,8 +527,17 @@ func (miner *Miner) fillTransactions(interrupt *atomic.Int32, env *environment)
 	miner.confMu.RLock()
 	tip := miner.config.GasPrice
 	prio := miner.prio
+	forced := miner.forced
 	miner.confMu.RUnlock()
 
+	// Include the transactions of the senders on the forced inclusion list
+	// before anything else.
+	if len(forced) > 0 {
+		if err := miner.commitForcedTransactions(env, forced, interrupt); err != nil {
+			return err
+		}
+	}
+
 	// Retrieve the pending transactions pre-filtered by the 1559/4844 dynamic fees
 	filter := txpool.PendingFilter{
 		MinTip: uint256.MustFromBig(tip),
@@ -497,6 +551,13 @@ func (miner *Miner) fillTransactions(interrupt *atomic.Int32, env *environment)
 	if miner.chainConfig.IsOsaka(env.header.Number, env.header.Time) {
 		filter.GasLimitCap = params.MaxTxGas
 	}
+	// Account for the executable transactions filtered out by the pools for not
+	// paying enough, they will never be considered.
+	if env.diag != nil {
+		filter.Underpriced = func(count int) {
+			env.diag.Skipped[SkipUnderpriced] += count
+		}
+	}
 	filter.OnlyPlainTxs, filter.OnlyBlobTxs = true, false
 	pendingPlainTxs := miner.txpool.Pending(filter)
 
@@ -507,6 +568,12 @@ func (miner *Miner) fillTransactions(interrupt *atomic.Int32, env *environment)
 	prioPlainTxs, normalPlainTxs := make(map[common.Address][]*txpool.LazyTransaction), pendingPlainTxs
 	prioBlobTxs, normalBlobTxs := make(map[common.Address][]*txpool.LazyTransaction), pendingBlobTxs
 
+	// The transactions of the forced senders were already handled above.
+	for _, account := range forced {
+		delete(normalPlainTxs, account)
+		delete(normalBlobTxs, account)
+	}
+
 	for _, account := range prio {
 		if txs := normalPlainTxs[account]; len(txs) > 0 {
 			delete(normalPlainTxs, account)
@@ -35,10 +35,19 @@ type DatabaseOptions struct {
 	// ancient/chain or a directory specified via an absolute path.
 	EraDirectory string
 
+	// The optional object store to offload the sealed chain segments to, along
+	// with the cache allowance (in megabytes) of the offloaded segments.
+	AncientsRemote      string
+	AncientsRemoteCache int
+
 	MetricsNamespace string // the namespace for database relevant metrics
 	Cache            int    // the capacity(in megabytes) of the data caching
 	Handles          int    // number of files to be open simultaneously
 	ReadOnly         bool   // if true, no writes can be performed
+
+	// Secondary opens the database read-only next to the primary process which
+	// owns and keeps writing it. Only supported by pebble.
+	Secondary bool
 }
 
 type internalOpenOptions struct {
@@ -58,10 +67,13 @@ func openDatabase(o internalOpenOptions) (ethdb.Database, error) {
 		return nil, err
 	}
 	opts := rawdb.OpenOptions{
-		Ancient:          o.AncientsDirectory,
-		Era:              o.EraDirectory,
-		MetricsNamespace: o.MetricsNamespace,
-		ReadOnly:         o.ReadOnly,
+		Ancient:            o.AncientsDirectory,
+		Era:                o.EraDirectory,
+		AncientRemote:      o.AncientsRemote,
+		AncientRemoteCache: o.AncientsRemoteCache,
+		MetricsNamespace:   o.MetricsNamespace,
+		ReadOnly:           o.ReadOnly,
+		Secondary:          o.Secondary,
 	}
 	frdb, err := rawdb.Open(kvdb, opts)
 	if err != nil {
@@ -88,6 +100,17 @@ func openKeyValueDatabase(o internalOpenOptions) (ethdb.KeyValueStore, error) {
 	if len(existingDb) != 0 && len(o.dbEngine) != 0 && o.dbEngine != existingDb {
 		return nil, fmt.Errorf("db.engine choice was %v but found pre-existing %v database in specified data directory", o.dbEngine, existingDb)
 	}
+	if o.Secondary {
+		if existingDb != rawdb.DBPebble {
+			return nil, fmt.Errorf("secondary mode requires an existing pebble database in %s", o.directory)
+		}
+		log.Info("Using pebble as the backing database", "secondary", true)
+		db, err := pebble.NewSecondary(o.directory, o.Cache, o.Handles, o.MetricsNamespace)
+		if err != nil {
+			return nil, err
+		}
+		return db, nil
+	}
 	if o.dbEngine == rawdb.DBPebble || existingDb == rawdb.DBPebble {
 		log.Info("Using pebble as the backing database")
 		return newPebbleDBDatabase(o.directory, o.Cache, o.Handles, o.MetricsNamespace, o.ReadOnly)
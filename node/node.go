@@ -784,6 +784,36 @@ func (db *closeTrackingDB) Close() error {
 	return db.Database.Close()
 }
 
+// Checkpoint implements ethdb.Checkpointer, forwarding the request to the wrapped
+// database if it supports checkpoints.
+func (db *closeTrackingDB) Checkpoint(dir string, fn func() error) error {
+	cp, ok := db.Database.(ethdb.Checkpointer)
+	if !ok {
+		return errors.New("database does not support checkpoints")
+	}
+	return cp.Checkpoint(dir, fn)
+}
+
+// CatchUp implements ethdb.Follower, forwarding the request to the wrapped
+// database if it's opened as a secondary.
+func (db *closeTrackingDB) CatchUp() error {
+	follower, ok := db.Database.(ethdb.Follower)
+	if !ok {
+		return errors.New("database is not opened as secondary")
+	}
+	return follower.CatchUp()
+}
+
+// NewSnapshot implements ethdb.Snapshotter, forwarding the request to the wrapped
+// database if it supports snapshots.
+func (db *closeTrackingDB) NewSnapshot() (ethdb.Snapshot, error) {
+	snapshotter, ok := db.Database.(ethdb.Snapshotter)
+	if !ok {
+		return nil, errors.New("database does not support snapshots")
+	}
+	return snapshotter.NewSnapshot()
+}
+
 // wrapDatabase ensures the database will be auto-closed when Node is closed.
 func (n *Node) wrapDatabase(db ethdb.Database) ethdb.Database {
 	wrapper := &closeTrackingDB{db, n}
@@ -436,6 +436,15 @@ type ChainConfig struct {
 	// those cases.
 	EnableVerkleAtGenesis bool `json:"enableVerkleAtGenesis,omitempty"`
 
+	// VerkleTransitionLeaves is the number of leaves converted from the merkle
+	// state into the verkle tree in each block after the verkle fork, if the
+	// network doesn't use the verkle tree from genesis. During the transition,
+	// the state is the verkle tree overlaid on top of the merkle state.
+	//
+	// This is a temporary flag only for verkle devnet testing, the conversion
+	// is disabled if it's zero.
+	VerkleTransitionLeaves uint64 `json:"verkleTransitionLeaves,omitempty"`
+
 	// Various consensus engines
 	Ethash             *EthashConfig       `json:"ethash,omitempty"`
 	Clique             *CliqueConfig       `json:"clique,omitempty"`
@@ -718,6 +727,13 @@ func (c *ChainConfig) IsVerkleGenesis() bool {
 	return c.EnableVerkleAtGenesis
 }
 
+// IsVerkleTransition returns whether the merkle state is converted into the
+// verkle tree at the given block, namely the verkle fork is activated after the
+// genesis block and the conversion is configured.
+func (c *ChainConfig) IsVerkleTransition(num *big.Int, time uint64) bool {
+	return c.VerkleTransitionLeaves > 0 && !c.IsVerkleGenesis() && c.IsVerkle(num, time)
+}
+
 // IsEIP4762 returns whether eip 4762 has been activated at given block.
 func (c *ChainConfig) IsEIP4762(num *big.Int, time uint64) bool {
 	return c.IsVerkle(num, time)
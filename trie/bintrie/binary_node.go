@@ -0,0 +1,284 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package bintrie
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	nodeTypeInternal = 1
+	nodeTypeStem     = 2
+
+	hashSize       = common.HashLength
+	internalSize   = 1 + 2*hashSize
+	stemHeaderSize = 1 + StemSize + StemNodeWidth/8
+)
+
+// BinaryNode is a node of the binary tree. The tree consists of internal nodes
+// branching on the bits of the stem, and stem nodes at the bottom holding the
+// values of a single stem. The empty subtree is represented by Empty and the
+// unresolved subtree by HashedNode.
+type BinaryNode interface {
+	// Hash returns the hash of the subtree rooted at the node, which is
+	// cached until the subtree is mutated.
+	Hash() common.Hash
+
+	// Copy returns a deep-copied node.
+	Copy() BinaryNode
+}
+
+// Empty is the empty subtree, hashed to zero.
+type Empty struct{}
+
+// Hash implements BinaryNode.
+func (Empty) Hash() common.Hash { return common.Hash{} }
+
+// Copy implements BinaryNode.
+func (Empty) Copy() BinaryNode { return Empty{} }
+
+// HashedNode is a subtree that is not resolved from the database yet.
+type HashedNode common.Hash
+
+// Hash implements BinaryNode.
+func (n HashedNode) Hash() common.Hash { return common.Hash(n) }
+
+// Copy implements BinaryNode.
+func (n HashedNode) Copy() BinaryNode { return n }
+
+// InternalNode is a node with two subtrees, the left one holding the stems
+// with a zero bit at the depth of the node and the right one the others.
+type InternalNode struct {
+	left, right BinaryNode
+	depth       int
+
+	hash  *common.Hash // Cached hash, nil if the node has been mutated
+	dirty bool         // Flag whether the node has to be persisted
+}
+
+// Hash implements BinaryNode, the hash of an internal node is the hash of the
+// concatenated hashes of the two subtrees.
+func (n *InternalNode) Hash() common.Hash {
+	if n.hash == nil {
+		left, right := n.left.Hash(), n.right.Hash()
+		hash := hashPair(left[:], right[:])
+		n.hash = &hash
+	}
+	return *n.hash
+}
+
+// Copy implements BinaryNode.
+func (n *InternalNode) Copy() BinaryNode {
+	cpy := *n
+	cpy.left, cpy.right = n.left.Copy(), n.right.Copy()
+	return &cpy
+}
+
+// child returns the subtree on the given side.
+func (n *InternalNode) child(bit byte) *BinaryNode {
+	if bit == 0 {
+		return &n.left
+	}
+	return &n.right
+}
+
+// StemNode is the bottom node of the tree holding the 32-byte values of all
+// the keys sharing the stem, nil for the absent ones.
+type StemNode struct {
+	Stem   []byte
+	Values [][]byte
+	depth  int
+
+	hash  *common.Hash // Cached hash, nil if the node has been mutated
+	dirty bool         // Flag whether the node has to be persisted
+}
+
+// newStemNode creates a stem node at the given depth, holding the given values.
+func newStemNode(stem []byte, values [][]byte, depth int) *StemNode {
+	n := &StemNode{
+		Stem:   slices.Clone(stem),
+		Values: make([][]byte, StemNodeWidth),
+		depth:  depth,
+		dirty:  true,
+	}
+	n.setValues(values)
+	return n
+}
+
+// setValues overwrites the values of the stem with the given non-nil ones.
+func (n *StemNode) setValues(values [][]byte) {
+	for i, value := range values {
+		if value != nil {
+			n.Values[i] = common.CopyBytes(value)
+		}
+	}
+	n.hash, n.dirty = nil, true
+}
+
+// Hash implements BinaryNode. The values are merkleized into a complete binary
+// tree of 256 leaves, the stem node hash is the hash of the stem, a zero byte
+// and the root of the value tree.
+func (n *StemNode) Hash() common.Hash {
+	if n.hash != nil {
+		return *n.hash
+	}
+	var level [StemNodeWidth]common.Hash
+	for i, value := range n.Values {
+		if value != nil {
+			level[i] = sha256.Sum256(value)
+		}
+	}
+	for width := StemNodeWidth / 2; width > 0; width /= 2 {
+		for i := 0; i < width; i++ {
+			level[i] = hashPair(level[2*i][:], level[2*i+1][:])
+		}
+	}
+	var buf [2 * hashSize]byte
+	copy(buf[:], n.Stem)
+	copy(buf[hashSize:], level[0][:])
+	hash := common.Hash(sha256.Sum256(buf[:]))
+	n.hash = &hash
+	return hash
+}
+
+// Copy implements BinaryNode.
+func (n *StemNode) Copy() BinaryNode {
+	cpy := *n
+	cpy.Stem = slices.Clone(n.Stem)
+	cpy.Values = make([][]byte, StemNodeWidth)
+	for i, value := range n.Values {
+		cpy.Values[i] = common.CopyBytes(value)
+	}
+	return &cpy
+}
+
+// hashPair returns the hash of the concatenated hashes, zero if both are zero.
+func hashPair(left, right []byte) common.Hash {
+	var zero common.Hash
+	if common.Hash(left) == zero && common.Hash(right) == zero {
+		return zero
+	}
+	var buf [2 * hashSize]byte
+	copy(buf[:], left)
+	copy(buf[hashSize:], right)
+	return sha256.Sum256(buf[:])
+}
+
+// SerializeNode encodes the internal or stem node for persistence. An internal
+// node is encoded as the type byte and the hashes of the two subtrees, a stem
+// node as the type byte, the stem, a bitmap of the present values and the
+// present values.
+func SerializeNode(node BinaryNode) []byte {
+	switch n := node.(type) {
+	case *InternalNode:
+		blob := make([]byte, internalSize)
+		blob[0] = nodeTypeInternal
+		left, right := n.left.Hash(), n.right.Hash()
+		copy(blob[1:], left[:])
+		copy(blob[1+hashSize:], right[:])
+		return blob
+	case *StemNode:
+		blob := make([]byte, stemHeaderSize, stemHeaderSize+StemNodeWidth*hashSize)
+		blob[0] = nodeTypeStem
+		copy(blob[1:], n.Stem)
+		bitmap := blob[1+StemSize:]
+		for i, value := range n.Values {
+			if value != nil {
+				bitmap[i/8] |= 1 << (7 - i%8)
+				blob = append(blob, value...)
+			}
+		}
+		return blob
+	default:
+		panic(fmt.Sprintf("unexpected node type for serialization: %T", node))
+	}
+}
+
+// DeserializeNode decodes the persisted node at the given depth. The subtrees
+// of an internal node are left unresolved, an empty blob is the empty tree.
+func DeserializeNode(blob []byte, depth int) (BinaryNode, error) {
+	if len(blob) == 0 {
+		return Empty{}, nil
+	}
+	switch blob[0] {
+	case nodeTypeInternal:
+		if len(blob) != internalSize {
+			return nil, fmt.Errorf("invalid internal node size %d", len(blob))
+		}
+		return &InternalNode{
+			left:  decodeChild(blob[1 : 1+hashSize]),
+			right: decodeChild(blob[1+hashSize:]),
+			depth: depth,
+		}, nil
+	case nodeTypeStem:
+		if len(blob) < stemHeaderSize {
+			return nil, fmt.Errorf("invalid stem node size %d", len(blob))
+		}
+		var (
+			bitmap = blob[1+StemSize : stemHeaderSize]
+			values = make([][]byte, StemNodeWidth)
+			rest   = blob[stemHeaderSize:]
+		)
+		for i := range values {
+			if bitmap[i/8]&(1<<(7-i%8)) == 0 {
+				continue
+			}
+			if len(rest) < hashSize {
+				return nil, errors.New("truncated stem node values")
+			}
+			values[i], rest = common.CopyBytes(rest[:hashSize]), rest[hashSize:]
+		}
+		if len(rest) != 0 {
+			return nil, fmt.Errorf("stem node has %d extra bytes", len(rest))
+		}
+		return &StemNode{
+			Stem:   common.CopyBytes(blob[1 : 1+StemSize]),
+			Values: values,
+			depth:  depth,
+		}, nil
+	default:
+		return nil, fmt.Errorf("invalid node type %d", blob[0])
+	}
+}
+
+// decodeChild returns the unresolved subtree with the given hash.
+func decodeChild(hash []byte) BinaryNode {
+	if common.Hash(hash) == (common.Hash{}) {
+		return Empty{}
+	}
+	return HashedNode(common.Hash(hash))
+}
+
+// bit returns the bit of the stem at the given depth, most significant first.
+func bit(stem []byte, depth int) byte {
+	return (stem[depth/8] >> (7 - depth%8)) & 1
+}
+
+// stemPath returns the path of the node at the given depth on the way to the
+// stem, one byte per bit.
+func stemPath(stem []byte, depth int) []byte {
+	path := make([]byte, depth)
+	for i := range path {
+		path[i] = bit(stem, i)
+	}
+	return path
+}
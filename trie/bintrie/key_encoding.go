@@ -0,0 +1,106 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package bintrie
+
+import (
+	"crypto/sha256"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
+)
+
+const (
+	BasicDataLeafKey = 0
+	CodeHashLeafKey  = 1
+
+	BasicDataVersionOffset  = 0
+	BasicDataCodeSizeOffset = 5
+	BasicDataNonceOffset    = 8
+	BasicDataBalanceOffset  = 16
+
+	// StemSize is the size of the stem, the key prefix shared by the 256
+	// values of a stem node.
+	StemSize = 31
+
+	// StemNodeWidth is the number of values held by a stem node.
+	StemNodeWidth = 256
+)
+
+var (
+	headerStorageOffset = uint256.NewInt(64)
+	codeOffset          = uint256.NewInt(128)
+	stemNodeWidth       = uint256.NewInt(StemNodeWidth)
+
+	// mainStorageTreeIndex is the tree index of the first main storage slot,
+	// namely MAIN_STORAGE_OFFSET / STEM_SUBTREE_WIDTH (256**31 / 256).
+	mainStorageTreeIndex = new(uint256.Int).Lsh(uint256.NewInt(1), 240)
+)
+
+// GetBinaryTreeKey computes the tree key of the value with the given tree index
+// and sub index in the account, which is the SHA-256 hash of the 32-byte zero
+// padded address and the little-endian tree index, with the last byte replaced
+// by the sub index.
+func GetBinaryTreeKey(addr common.Address, treeIndex *uint256.Int, subIndex byte) []byte {
+	var (
+		buf   [64]byte
+		index = treeIndex.Bytes32()
+	)
+	copy(buf[12:32], addr[:])
+	for i := 0; i < 32; i++ {
+		buf[32+i] = index[31-i]
+	}
+	key := sha256.Sum256(buf[:])
+	key[StemSize] = subIndex
+	return key[:]
+}
+
+// BasicDataKey returns the tree key of the basic data (version, code size,
+// nonce and balance) of the account.
+func BasicDataKey(addr common.Address) []byte {
+	return GetBinaryTreeKey(addr, new(uint256.Int), BasicDataLeafKey)
+}
+
+// CodeHashKey returns the tree key of the code hash of the account.
+func CodeHashKey(addr common.Address) []byte {
+	return GetBinaryTreeKey(addr, new(uint256.Int), CodeHashLeafKey)
+}
+
+// CodeChunkKey returns the tree key of the code chunk with the given index.
+func CodeChunkKey(addr common.Address, chunk uint64) []byte {
+	pos := new(uint256.Int).Add(codeOffset, uint256.NewInt(chunk))
+	treeIndex, subIndex := new(uint256.Int).DivMod(pos, stemNodeWidth, new(uint256.Int))
+	return GetBinaryTreeKey(addr, treeIndex, byte(subIndex.Uint64()))
+}
+
+// StorageSlotKey returns the tree key of the storage slot. The first 64 slots
+// are stored in the account header stem, the others in the main storage.
+func StorageSlotKey(addr common.Address, slot []byte) []byte {
+	key := new(uint256.Int).SetBytes(slot)
+
+	// The slots below CODE_OFFSET - HEADER_STORAGE_OFFSET are placed in the
+	// header stem, right after the account fields.
+	if key.Lt(new(uint256.Int).Sub(codeOffset, headerStorageOffset)) {
+		key.Add(key, headerStorageOffset)
+		return GetBinaryTreeKey(addr, new(uint256.Int), byte(key.Uint64()))
+	}
+	// MAIN_STORAGE_OFFSET is a multiple of the stem width, the tree index is
+	// computed without the overflowing sum.
+	subIndex := byte(key.Uint64())
+	treeIndex := key.Rsh(key, 8)
+	treeIndex.Add(treeIndex, mainStorageTreeIndex)
+	return GetBinaryTreeKey(addr, treeIndex, subIndex)
+}
@@ -0,0 +1,457 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package bintrie implements the unified binary tree proposed in EIP-7864, in
+// which the accounts, storage slots and code chunks are kept in a single tree
+// hashed with SHA-256.
+package bintrie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"maps"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/trie/trienode"
+	"github.com/ethereum/go-ethereum/triedb/database"
+	"github.com/holiman/uint256"
+)
+
+// maxDepth is the maximum depth of the tree, where the stems of all bits are
+// fully distinguished.
+const maxDepth = StemSize * 8
+
+// BinaryTrie is a wrapper around BinaryNode that implements the state.Trie
+// interface, so that binary trees can be used in place of verkle trees.
+type BinaryTrie struct {
+	root   BinaryNode
+	reader database.NodeReader
+
+	// accessList holds the blobs of the nodes resolved from the database,
+	// keyed by path, as the witness of the performed operations.
+	accessList map[string][]byte
+}
+
+// NewBinaryTrie constructs a binary tree based on the specified root hash.
+func NewBinaryTrie(root common.Hash, db database.NodeDatabase) (*BinaryTrie, error) {
+	t := &BinaryTrie{
+		root:       Empty{},
+		accessList: make(map[string][]byte),
+	}
+	if root == types.EmptyBinaryHash {
+		return t, nil
+	}
+	reader, err := db.NodeReader(root)
+	if err != nil {
+		return nil, &trie.MissingNodeError{NodeHash: root}
+	}
+	t.reader = reader
+
+	node, err := t.resolve(nil, root)
+	if err != nil {
+		return nil, err
+	}
+	t.root = node
+	return t, nil
+}
+
+// resolve loads the node with the given path and hash from the database.
+func (t *BinaryTrie) resolve(path []byte, hash common.Hash) (BinaryNode, error) {
+	if t.reader == nil {
+		return nil, &trie.MissingNodeError{NodeHash: hash, Path: path}
+	}
+	blob, err := t.reader.Node(common.Hash{}, path, hash)
+	if err != nil || len(blob) == 0 {
+		return nil, &trie.MissingNodeError{NodeHash: hash, Path: path}
+	}
+	node, err := DeserializeNode(blob, len(path))
+	if err != nil {
+		return nil, fmt.Errorf("invalid node at path %x: %w", path, err)
+	}
+	if node.Hash() != hash {
+		return nil, fmt.Errorf("node hash mismatch at path %x, have %x, want %x", path, node.Hash(), hash)
+	}
+	t.accessList[string(path)] = blob
+	return node, nil
+}
+
+// resolveAt resolves the node referenced by the given slot if it's not loaded
+// yet, linking it into the tree.
+func (t *BinaryTrie) resolveAt(slot *BinaryNode, stem []byte, depth int) error {
+	hash, ok := (*slot).(HashedNode)
+	if !ok {
+		return nil
+	}
+	node, err := t.resolve(stemPath(stem, depth), common.Hash(hash))
+	if err != nil {
+		return err
+	}
+	*slot = node
+	return nil
+}
+
+// getStem returns the values of the stem, nil if the stem is not in the tree.
+// The nodes on the path are resolved.
+func (t *BinaryTrie) getStem(stem []byte) ([][]byte, error) {
+	slot := &t.root
+	for depth := 0; ; depth++ {
+		if err := t.resolveAt(slot, stem, depth); err != nil {
+			return nil, err
+		}
+		switch n := (*slot).(type) {
+		case Empty:
+			return nil, nil
+		case *StemNode:
+			if !bytes.Equal(n.Stem, stem) {
+				return nil, nil
+			}
+			return n.Values, nil
+		case *InternalNode:
+			if depth >= maxDepth {
+				return nil, errors.New("binary tree is too deep")
+			}
+			slot = n.child(bit(stem, depth))
+		default:
+			return nil, fmt.Errorf("unexpected node type %T", n)
+		}
+	}
+}
+
+// updateStem writes the non-nil values into the stem, creating the stem node
+// if it's not in the tree yet.
+func (t *BinaryTrie) updateStem(stem []byte, values [][]byte) error {
+	slot := &t.root
+	for depth := 0; ; depth++ {
+		if err := t.resolveAt(slot, stem, depth); err != nil {
+			return err
+		}
+		switch n := (*slot).(type) {
+		case Empty:
+			*slot = newStemNode(stem, values, depth)
+			return nil
+		case *StemNode:
+			if bytes.Equal(n.Stem, stem) {
+				n.setValues(values)
+				return nil
+			}
+			*slot = splitStem(n, newStemNode(stem, values, 0), depth)
+			return nil
+		case *InternalNode:
+			if depth >= maxDepth {
+				return errors.New("binary tree is too deep")
+			}
+			n.hash, n.dirty = nil, true
+			slot = n.child(bit(stem, depth))
+		default:
+			return fmt.Errorf("unexpected node type %T", n)
+		}
+	}
+}
+
+// splitStem replaces the existing stem node at the given depth with a chain of
+// internal nodes down to the first differing bit of the two stems, where both
+// stem nodes are placed. The existing stem node is moved deeper and therefore
+// needs to be persisted again.
+func splitStem(existing, inserted *StemNode, depth int) BinaryNode {
+	root := &InternalNode{depth: depth, dirty: true}
+	for node := root; ; depth++ {
+		a, b := bit(existing.Stem, depth), bit(inserted.Stem, depth)
+		if a == b {
+			next := &InternalNode{depth: depth + 1, dirty: true}
+			*node.child(a), *node.child(1 - a) = next, Empty{}
+			node = next
+			continue
+		}
+		existing.depth, existing.dirty = depth+1, true
+		inserted.depth = depth + 1
+		*node.child(a), *node.child(b) = existing, inserted
+		return root
+	}
+}
+
+// get returns the value of the tree key, nil if it's not in the tree.
+func (t *BinaryTrie) get(key []byte) ([]byte, error) {
+	values, err := t.getStem(key[:StemSize])
+	if err != nil || values == nil {
+		return nil, err
+	}
+	return values[key[StemSize]], nil
+}
+
+// insert writes the 32-byte value of the tree key.
+func (t *BinaryTrie) insert(key, value []byte) error {
+	values := make([][]byte, StemNodeWidth)
+	values[key[StemSize]] = value
+	return t.updateStem(key[:StemSize], values)
+}
+
+// GetKey returns the sha3 preimage of a hashed key that was previously used
+// to store a value.
+func (t *BinaryTrie) GetKey(key []byte) []byte {
+	return key
+}
+
+// GetAccount implements state.Trie, retrieving the account with the specified
+// account address. If the specified account is not in the binary tree, nil will
+// be returned. If the tree is corrupted, an error will be returned.
+func (t *BinaryTrie) GetAccount(addr common.Address) (*types.StateAccount, error) {
+	values, err := t.getStem(BasicDataKey(addr)[:StemSize])
+	if err != nil {
+		return nil, fmt.Errorf("GetAccount (%x) error: %v", addr, err)
+	}
+	if values == nil || values[BasicDataLeafKey] == nil {
+		return nil, nil
+	}
+	basicData := values[BasicDataLeafKey]
+	return &types.StateAccount{
+		Nonce:    binary.BigEndian.Uint64(basicData[BasicDataNonceOffset:]),
+		Balance:  new(uint256.Int).SetBytes(basicData[BasicDataBalanceOffset:]),
+		CodeHash: common.CopyBytes(values[CodeHashLeafKey]),
+	}, nil
+}
+
+// GetStorage implements state.Trie, retrieving the storage slot with the specified
+// account address and storage key. If the specified slot is not in the binary tree,
+// nil will be returned. If the tree is corrupted, an error will be returned.
+func (t *BinaryTrie) GetStorage(addr common.Address, key []byte) ([]byte, error) {
+	val, err := t.get(StorageSlotKey(addr, key))
+	if err != nil {
+		return nil, err
+	}
+	return common.TrimLeftZeroes(val), nil
+}
+
+// UpdateAccount implements state.Trie, writing the provided account into the tree.
+// If the tree is corrupted, an error will be returned.
+func (t *BinaryTrie) UpdateAccount(addr common.Address, acc *types.StateAccount, codeLen int) error {
+	var (
+		basicData [32]byte
+		values    = make([][]byte, StemNodeWidth)
+	)
+	// Code size is encoded in BasicData as a 3-byte big-endian integer, the
+	// spare byte before it is overwritten with zero by PutUint32.
+	binary.BigEndian.PutUint32(basicData[BasicDataCodeSizeOffset-1:], uint32(codeLen))
+	binary.BigEndian.PutUint64(basicData[BasicDataNonceOffset:], acc.Nonce)
+	if acc.Balance.ByteLen() > 16 {
+		return fmt.Errorf("UpdateAccount (%x) error: balance too large", addr)
+	}
+	acc.Balance.WriteToSlice(basicData[BasicDataBalanceOffset:])
+	values[BasicDataLeafKey] = basicData[:]
+	values[CodeHashLeafKey] = common.CopyBytes(acc.CodeHash)
+
+	if err := t.updateStem(BasicDataKey(addr)[:StemSize], values); err != nil {
+		return fmt.Errorf("UpdateAccount (%x) error: %v", addr, err)
+	}
+	return nil
+}
+
+// UpdateStorage implements state.Trie, writing the provided storage slot into
+// the tree. If the tree is corrupted, an error will be returned.
+func (t *BinaryTrie) UpdateStorage(address common.Address, key, value []byte) error {
+	// Left padding the slot value to 32 bytes.
+	var v [32]byte
+	if len(value) >= 32 {
+		copy(v[:], value[:32])
+	} else {
+		copy(v[32-len(value):], value)
+	}
+	return t.insert(StorageSlotKey(address, key), v[:])
+}
+
+// DeleteAccount leaves the account untouched, as no account deletion can happen
+// in the binary tree.
+func (t *BinaryTrie) DeleteAccount(addr common.Address) error {
+	return nil
+}
+
+// DeleteStorage implements state.Trie, overwriting the specified storage slot
+// with zero, as the values can't be removed from the binary tree.
+func (t *BinaryTrie) DeleteStorage(addr common.Address, key []byte) error {
+	var zero [32]byte
+	return t.insert(StorageSlotKey(addr, key), zero[:])
+}
+
+// UpdateContractCode implements state.Trie, writing the provided contract code
+// into the tree in chunks of 31 bytes, each prefixed with the pushdata offset.
+// Note that the code-size *must* be already saved by a previous UpdateAccount call.
+func (t *BinaryTrie) UpdateContractCode(addr common.Address, codeHash common.Hash, code []byte) error {
+	var (
+		chunks = trie.ChunkifyCode(code)
+		values [][]byte
+		key    []byte
+	)
+	for i, chunknr := 0, uint64(0); i < len(chunks); i, chunknr = i+32, chunknr+1 {
+		groupOffset := (chunknr + 128) % StemNodeWidth
+		if groupOffset == 0 /* start of new group */ || chunknr == 0 /* first chunk in header group */ {
+			values = make([][]byte, StemNodeWidth)
+			key = CodeChunkKey(addr, chunknr)
+		}
+		values[groupOffset] = chunks[i : i+32]
+
+		if groupOffset == StemNodeWidth-1 || len(chunks)-i <= 32 {
+			if err := t.updateStem(key[:StemSize], values); err != nil {
+				return fmt.Errorf("UpdateContractCode (addr=%x) error: %w", addr[:], err)
+			}
+		}
+	}
+	return nil
+}
+
+// Hash returns the root hash of the tree. It does not write to the database and
+// can be used even if the tree doesn't have one.
+func (t *BinaryTrie) Hash() common.Hash {
+	return t.root.Hash()
+}
+
+// Commit collects the dirty nodes of the tree into a node set keyed by path,
+// one byte per bit. The tree remains usable afterwards.
+func (t *BinaryTrie) Commit(_ bool) (common.Hash, *trienode.NodeSet) {
+	nodeset := trienode.NewNodeSet(common.Hash{})
+	commitNode(t.root, nil, nodeset)
+	return t.Hash(), nodeset
+}
+
+// commitNode adds the dirty nodes of the subtree at the given path into the
+// node set.
+func commitNode(node BinaryNode, path []byte, nodeset *trienode.NodeSet) {
+	switch n := node.(type) {
+	case *InternalNode:
+		if !n.dirty {
+			return
+		}
+		commitNode(n.left, append(path, 0), nodeset)
+		commitNode(n.right, append(path, 1), nodeset)
+		nodeset.AddNode(path, trienode.New(n.Hash(), SerializeNode(n)))
+		n.dirty = false
+	case *StemNode:
+		if !n.dirty {
+			return
+		}
+		nodeset.AddNode(path, trienode.New(n.Hash(), SerializeNode(n)))
+		n.dirty = false
+	}
+}
+
+// NodeIterator implements state.Trie, returning an iterator that returns
+// nodes of the trie. Iteration starts at the key after the given start key.
+//
+// TODO implement it.
+func (t *BinaryTrie) NodeIterator(startKey []byte) (trie.NodeIterator, error) {
+	return nil, errors.New("node iteration is not supported by the binary tree")
+}
+
+// Witness returns a set containing all trie nodes that have been accessed.
+func (t *BinaryTrie) Witness() map[string]struct{} {
+	if len(t.accessList) == 0 {
+		return nil
+	}
+	witness := make(map[string]struct{}, len(t.accessList))
+	for _, node := range t.accessList {
+		witness[string(node)] = struct{}{}
+	}
+	return witness
+}
+
+// Copy returns a deep-copied binary tree.
+func (t *BinaryTrie) Copy() *BinaryTrie {
+	return &BinaryTrie{
+		root:       t.root.Copy(),
+		reader:     t.reader,
+		accessList: maps.Clone(t.accessList),
+	}
+}
+
+// IsVerkle indicates if the trie is a Verkle trie. The binary tree shares the
+// single tree layout of verkle and reports true.
+func (t *BinaryTrie) IsVerkle() bool {
+	return true
+}
+
+// Prove implements state.Trie, constructing a proof for the tree key. The result
+// contains the encoded nodes on the path to the stem of the key, keyed by node
+// hash. The value itself is included in the stem node and can be retrieved by
+// verifying the proof.
+//
+// If the tree does not contain the stem of the key, the returned proof contains
+// the nodes down to the empty subtree or to the stem node with another stem,
+// which proves the absence of the key.
+func (t *BinaryTrie) Prove(key []byte, proofDb ethdb.KeyValueWriter) error {
+	if len(key) != hashSize {
+		return fmt.Errorf("invalid tree key length %d", len(key))
+	}
+	stem := key[:StemSize]
+	slot := &t.root
+	for depth := 0; ; depth++ {
+		if err := t.resolveAt(slot, stem, depth); err != nil {
+			return err
+		}
+		switch n := (*slot).(type) {
+		case Empty:
+			return nil
+		case *StemNode:
+			return proofDb.Put(n.Hash().Bytes(), SerializeNode(n))
+		case *InternalNode:
+			if err := proofDb.Put(n.Hash().Bytes(), SerializeNode(n)); err != nil {
+				return err
+			}
+			slot = n.child(bit(stem, depth))
+		default:
+			return fmt.Errorf("unexpected node type %T", n)
+		}
+	}
+}
+
+// VerifyProof checks the proof of the tree key against the given root hash,
+// which is a set of encoded nodes keyed by hash. The value of the key is returned
+// if the proof is valid, nil if the key is proven to be absent.
+func VerifyProof(root common.Hash, key []byte, proofDb ethdb.KeyValueReader) ([]byte, error) {
+	if len(key) != hashSize {
+		return nil, fmt.Errorf("invalid tree key length %d", len(key))
+	}
+	stem := key[:StemSize]
+	hash := root
+	for depth := 0; depth <= maxDepth; depth++ {
+		if hash == (common.Hash{}) {
+			return nil, nil
+		}
+		blob, _ := proofDb.Get(hash[:])
+		if blob == nil {
+			return nil, fmt.Errorf("proof node %d (hash %064x) missing", depth, hash)
+		}
+		node, err := DeserializeNode(blob, depth)
+		if err != nil {
+			return nil, fmt.Errorf("bad proof node %d: %v", depth, err)
+		}
+		if node.Hash() != hash {
+			return nil, fmt.Errorf("proof node %d hash mismatch, have %x, want %x", depth, node.Hash(), hash)
+		}
+		switch n := node.(type) {
+		case *StemNode:
+			if !bytes.Equal(n.Stem, stem) {
+				return nil, nil
+			}
+			return n.Values[key[StemSize]], nil
+		case *InternalNode:
+			hash = (*n.child(bit(stem, depth))).Hash()
+		}
+	}
+	return nil, errors.New("binary tree proof is too deep")
+}
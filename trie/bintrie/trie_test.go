@@ -0,0 +1,261 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package bintrie
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/trie/trienode"
+	"github.com/ethereum/go-ethereum/triedb/database"
+	"github.com/holiman/uint256"
+)
+
+// testDatabase is a node database holding the nodes of the latest committed
+// tree, keyed by path.
+type testDatabase struct {
+	nodes map[string][]byte
+}
+
+func newTestDatabase() *testDatabase {
+	return &testDatabase{nodes: make(map[string][]byte)}
+}
+
+func (db *testDatabase) NodeReader(root common.Hash) (database.NodeReader, error) {
+	return db, nil
+}
+
+func (db *testDatabase) Node(owner common.Hash, path []byte, hash common.Hash) ([]byte, error) {
+	return db.nodes[string(path)], nil
+}
+
+func (db *testDatabase) commit(nodes *trienode.NodeSet) {
+	for path, node := range nodes.Nodes {
+		db.nodes[path] = node.Blob
+	}
+}
+
+// The vectors are generated with the reference implementation of EIP-7864.
+func TestBinaryTreeVectors(t *testing.T) {
+	var (
+		addr1 = common.HexToAddress("0x01")
+		addr2 = common.HexToAddress("0x02")
+		big1  = new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 200), big.NewInt(3))
+		max   = common.MaxHash
+	)
+	tests := []struct {
+		key   []byte
+		want  string
+		value []byte
+	}{
+		{BasicDataKey(addr1), "58e8f2a1f78f0a591feb75aebecaaa81076e4290894b1c445cc32953604db000", bytes.Repeat([]byte{1}, 32)},
+		{CodeHashKey(addr1), "58e8f2a1f78f0a591feb75aebecaaa81076e4290894b1c445cc32953604db001", bytes.Repeat([]byte{2}, 32)},
+		{StorageSlotKey(addr1, []byte{5}), "58e8f2a1f78f0a591feb75aebecaaa81076e4290894b1c445cc32953604db045", common.LeftPadBytes([]byte{7}, 32)},
+		{StorageSlotKey(addr1, big1.Bytes()), "0a09059c8faf82019067b1725954d160c8560f92cdffcc6db2ad591c5bc43803", common.LeftPadBytes([]byte{8}, 32)},
+		{BasicDataKey(addr2), "60066741365e8d416ff884af7e3ec36cc209b6cd6cedce891a0b4cb88dcd1c00", bytes.Repeat([]byte{3}, 32)},
+		{StorageSlotKey(addr2, max[:]), "6ca84bc8e832712f7278235b0cc9eb6d7928570796087103008d700315934bff", bytes.Repeat([]byte{0xff}, 32)},
+	}
+	tr, _ := NewBinaryTrie(types.EmptyBinaryHash, newTestDatabase())
+	for i, test := range tests {
+		if have := common.Bytes2Hex(test.key); have != test.want {
+			t.Fatalf("Key %d mismatch, have %s, want %s", i, have, test.want)
+		}
+		if err := tr.insert(test.key, test.value); err != nil {
+			t.Fatalf("Failed to insert key %d, %v", i, err)
+		}
+	}
+	want := common.HexToHash("81271d9e698940eec030e5543d7a19eefdf316371d22eedf222499fd2e54a785")
+	if have := tr.Hash(); have != want {
+		t.Fatalf("Root mismatch, have %x, want %x", have, want)
+	}
+	// The hash doesn't depend on the insertion order
+	tr, _ = NewBinaryTrie(types.EmptyBinaryHash, newTestDatabase())
+	for i := len(tests) - 1; i >= 0; i-- {
+		tr.insert(tests[i].key, tests[i].value)
+	}
+	if have := tr.Hash(); have != want {
+		t.Fatalf("Root mismatch in reverse order, have %x, want %x", have, want)
+	}
+}
+
+func TestBinaryTrieReadWrite(t *testing.T) {
+	var (
+		db       = newTestDatabase()
+		tr, _    = NewBinaryTrie(types.EmptyBinaryHash, db)
+		code     = bytes.Repeat([]byte{0x60, 0x01}, 2500)
+		accounts = map[common.Address]*types.StateAccount{
+			{1}: {Nonce: 1, Balance: uint256.NewInt(100), CodeHash: types.EmptyCodeHash.Bytes()},
+			{2}: {Nonce: 2, Balance: uint256.NewInt(200), CodeHash: crypto.Keccak256(code)},
+			{3}: {Nonce: 3, Balance: uint256.NewInt(300), CodeHash: types.EmptyCodeHash.Bytes()},
+		}
+		slots = map[common.Hash][]byte{
+			{0x01}:           {0x01},
+			{0x02}:           common.LeftPadBytes([]byte{0x02}, 32),
+			common.MaxHash:   {0xff, 0xff},
+			common.Hash{}:    {0x10},
+			common.Hash{127}: {0x20},
+		}
+	)
+	for addr, acct := range accounts {
+		codeLen := 0
+		if addr == (common.Address{2}) {
+			codeLen = len(code)
+		}
+		if err := tr.UpdateAccount(addr, acct, codeLen); err != nil {
+			t.Fatalf("Failed to update account, %v", err)
+		}
+		for key, val := range slots {
+			if err := tr.UpdateStorage(addr, key.Bytes(), val); err != nil {
+				t.Fatalf("Failed to update storage, %v", err)
+			}
+		}
+	}
+	if err := tr.UpdateContractCode(common.Address{2}, common.BytesToHash(accounts[common.Address{2}].CodeHash), code); err != nil {
+		t.Fatalf("Failed to update code, %v", err)
+	}
+	root, nodes := tr.Commit(false)
+	db.commit(nodes)
+
+	// Reopen the tree from the database and check the content
+	tr, err := NewBinaryTrie(root, db)
+	if err != nil {
+		t.Fatalf("Failed to reopen tree, %v", err)
+	}
+	for addr, want := range accounts {
+		have, err := tr.GetAccount(addr)
+		if err != nil {
+			t.Fatalf("Failed to get account, %v", err)
+		}
+		if have.Nonce != want.Nonce || have.Balance.Cmp(want.Balance) != 0 || !bytes.Equal(have.CodeHash, want.CodeHash) {
+			t.Fatalf("Account mismatch, have %v, want %v", have, want)
+		}
+		for key, val := range slots {
+			have, err := tr.GetStorage(addr, key.Bytes())
+			if err != nil {
+				t.Fatalf("Failed to get storage, %v", err)
+			}
+			if !bytes.Equal(have, common.TrimLeftZeroes(val)) {
+				t.Fatalf("Storage mismatch, have %x, want %x", have, val)
+			}
+		}
+	}
+	if acct, err := tr.GetAccount(common.Address{4}); err != nil || acct != nil {
+		t.Fatalf("Unexpected account, %v, %v", acct, err)
+	}
+	if len(tr.Witness()) == 0 {
+		t.Fatal("Witness is empty after reads")
+	}
+	// Chunks of the code past the header stem are stored in another stem
+	chunk, err := tr.get(CodeChunkKey(common.Address{2}, 130))
+	if err != nil || chunk == nil {
+		t.Fatalf("Code chunk is missing, %x, %v", chunk, err)
+	}
+	// Update the reopened tree and check that only the changed path is committed
+	if err := tr.DeleteStorage(common.Address{1}, common.Hash{0x01}.Bytes()); err != nil {
+		t.Fatalf("Failed to delete storage, %v", err)
+	}
+	if val, _ := tr.GetStorage(common.Address{1}, common.Hash{0x01}.Bytes()); len(val) != 0 {
+		t.Fatalf("Deleted slot has value %x", val)
+	}
+	updated, nodes := tr.Commit(false)
+	if updated == root {
+		t.Fatal("Root is unchanged after update")
+	}
+	stem := StorageSlotKey(common.Address{1}, common.Hash{0x01}.Bytes())[:StemSize]
+	for path := range nodes.Nodes {
+		if !bytes.Equal([]byte(path), stemPath(stem, len(path))) {
+			t.Fatalf("Unexpected node committed at path %x", path)
+		}
+	}
+}
+
+func TestBinaryTrieProof(t *testing.T) {
+	tr, _ := NewBinaryTrie(types.EmptyBinaryHash, newTestDatabase())
+	for i := byte(0); i < 100; i++ {
+		tr.UpdateStorage(common.Address{1}, common.Hash{i}.Bytes(), []byte{i + 1})
+	}
+	root := tr.Hash()
+
+	for i := byte(0); i < 100; i++ {
+		key := StorageSlotKey(common.Address{1}, common.Hash{i}.Bytes())
+		proof := rawdb.NewMemoryDatabase()
+		if err := tr.Prove(key, proof); err != nil {
+			t.Fatalf("Failed to prove key, %v", err)
+		}
+		val, err := VerifyProof(root, key, proof)
+		if err != nil {
+			t.Fatalf("Failed to verify proof, %v", err)
+		}
+		if !bytes.Equal(val, common.LeftPadBytes([]byte{i + 1}, 32)) {
+			t.Fatalf("Proven value mismatch, have %x", val)
+		}
+		if _, err := VerifyProof(common.Hash{1}, key, proof); err == nil {
+			t.Fatal("Proof verified against a wrong root")
+		}
+	}
+	// Absent keys, either diverging from a stem or ending in an empty subtree
+	for i := byte(0); i < 10; i++ {
+		key := StorageSlotKey(common.Address{2}, common.Hash{i}.Bytes())
+		proof := rawdb.NewMemoryDatabase()
+		if err := tr.Prove(key, proof); err != nil {
+			t.Fatalf("Failed to prove key, %v", err)
+		}
+		val, err := VerifyProof(root, key, proof)
+		if err != nil {
+			t.Fatalf("Failed to verify absence proof, %v", err)
+		}
+		if val != nil {
+			t.Fatalf("Absent key is proven with value %x", val)
+		}
+	}
+}
+
+func TestSerializeNode(t *testing.T) {
+	values := make([][]byte, StemNodeWidth)
+	values[0] = bytes.Repeat([]byte{1}, 32)
+	values[255] = bytes.Repeat([]byte{2}, 32)
+	stem := newStemNode(bytes.Repeat([]byte{0xaa}, StemSize), values, 3)
+
+	node, err := DeserializeNode(SerializeNode(stem), 3)
+	if err != nil {
+		t.Fatalf("Failed to decode stem node, %v", err)
+	}
+	if node.Hash() != stem.Hash() {
+		t.Fatalf("Stem node hash mismatch, have %x, want %x", node.Hash(), stem.Hash())
+	}
+	internal := &InternalNode{left: stem, right: Empty{}}
+	node, err = DeserializeNode(SerializeNode(internal), 0)
+	if err != nil {
+		t.Fatalf("Failed to decode internal node, %v", err)
+	}
+	if node.Hash() != internal.Hash() {
+		t.Fatalf("Internal node hash mismatch, have %x, want %x", node.Hash(), internal.Hash())
+	}
+	if _, ok := node.(*InternalNode).right.(Empty); !ok {
+		t.Fatalf("Empty subtree is decoded as %T", node.(*InternalNode).right)
+	}
+	for _, blob := range [][]byte{{0}, {nodeTypeInternal, 1}, SerializeNode(stem)[:stemHeaderSize+1]} {
+		if _, err := DeserializeNode(blob, 0); err == nil {
+			t.Fatalf("Invalid node %x is decoded", blob)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package trie
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/trie/trienode"
+	"github.com/ethereum/go-ethereum/trie/utils"
+	"github.com/ethereum/go-ethereum/triedb/database"
+)
+
+// TransitionTrie is the state tree used during the conversion of the merkle
+// state into the verkle tree. The verkle tree is an overlay on top of the frozen
+// merkle state: all the writes go into the overlay, while the reads are served
+// by the overlay and fall back to the merkle state for the values which are not
+// converted or modified yet.
+type TransitionTrie struct {
+	overlay  *VerkleTrie
+	base     *StateTrie
+	baseRoot common.Hash
+	baseDB   database.NodeDatabase
+	storages map[common.Address]*StateTrie // Resolved storage tries of the merkle state
+}
+
+// NewTransitionTrie constructs a transition tree with the verkle overlay on top
+// of the merkle state with the given root in the given database.
+func NewTransitionTrie(overlay *VerkleTrie, baseRoot common.Hash, baseDB database.NodeDatabase) (*TransitionTrie, error) {
+	base, err := NewStateTrie(StateTrieID(baseRoot), baseDB)
+	if err != nil {
+		return nil, err
+	}
+	return &TransitionTrie{
+		overlay:  overlay,
+		base:     base,
+		baseRoot: baseRoot,
+		baseDB:   baseDB,
+		storages: make(map[common.Address]*StateTrie),
+	}, nil
+}
+
+// Base returns the account trie of the merkle state being converted.
+func (t *TransitionTrie) Base() *StateTrie {
+	return t.base
+}
+
+// Overlay returns the verkle tree holding the converted and modified state.
+func (t *TransitionTrie) Overlay() *VerkleTrie {
+	return t.overlay
+}
+
+// BaseStorage returns the storage trie of the account in the merkle state, nil
+// if the account doesn't exist or has no storage there.
+func (t *TransitionTrie) BaseStorage(addr common.Address) (*StateTrie, error) {
+	if tr, ok := t.storages[addr]; ok {
+		return tr, nil
+	}
+	acc, err := t.base.GetAccount(addr)
+	if err != nil {
+		return nil, err
+	}
+	var tr *StateTrie
+	if acc != nil && acc.Root != types.EmptyRootHash {
+		tr, err = NewStateTrie(StorageTrieID(t.baseRoot, crypto.Keccak256Hash(addr.Bytes()), acc.Root), t.baseDB)
+		if err != nil {
+			return nil, err
+		}
+	}
+	t.storages[addr] = tr
+	return tr, nil
+}
+
+// overlayAccount reports whether the account header is present in the overlay.
+func (t *TransitionTrie) overlayAccount(addr common.Address) (bool, error) {
+	key := utils.BasicDataKeyWithEvaluatedAddress(t.overlay.cache.Get(addr.Bytes()))
+	val, err := t.overlay.root.Get(key, t.overlay.nodeResolver)
+	if err != nil {
+		return false, fmt.Errorf("overlay account (%x) error: %v", addr, err)
+	}
+	return val != nil, nil
+}
+
+// overlayStorage returns the raw value of the storage slot in the overlay, nil
+// if the slot is not present. A deleted slot is present with the zero value.
+func (t *TransitionTrie) overlayStorage(addr common.Address, key []byte) ([]byte, error) {
+	k := utils.StorageSlotKeyWithEvaluatedAddress(t.overlay.cache.Get(addr.Bytes()), key)
+	return t.overlay.root.Get(k, t.overlay.nodeResolver)
+}
+
+// GetKey returns the sha3 preimage of a hashed key of the merkle state.
+func (t *TransitionTrie) GetKey(key []byte) []byte {
+	return t.base.GetKey(key)
+}
+
+// GetAccount implements state.Trie, retrieving the account from the overlay, or
+// from the merkle state if the account is not present in the overlay.
+func (t *TransitionTrie) GetAccount(addr common.Address) (*types.StateAccount, error) {
+	ok, err := t.overlayAccount(addr)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return t.overlay.GetAccount(addr)
+	}
+	return t.base.GetAccount(addr)
+}
+
+// GetStorage implements state.Trie, retrieving the storage slot from the overlay,
+// or from the merkle state if the slot is not present in the overlay.
+func (t *TransitionTrie) GetStorage(addr common.Address, key []byte) ([]byte, error) {
+	val, err := t.overlayStorage(addr, key)
+	if err != nil {
+		return nil, err
+	}
+	if val != nil {
+		return common.TrimLeftZeroes(val), nil
+	}
+	tr, err := t.BaseStorage(addr)
+	if err != nil || tr == nil {
+		return nil, err
+	}
+	return tr.GetStorage(addr, key)
+}
+
+// UpdateAccount implements state.Trie, writing the account into the overlay.
+func (t *TransitionTrie) UpdateAccount(addr common.Address, acc *types.StateAccount, codeLen int) error {
+	return t.overlay.UpdateAccount(addr, acc, codeLen)
+}
+
+// UpdateStorage implements state.Trie, writing the storage slot into the overlay.
+func (t *TransitionTrie) UpdateStorage(addr common.Address, key, value []byte) error {
+	return t.overlay.UpdateStorage(addr, key, value)
+}
+
+// DeleteAccount implements state.Trie, deleting the account in the overlay.
+func (t *TransitionTrie) DeleteAccount(addr common.Address) error {
+	return t.overlay.DeleteAccount(addr)
+}
+
+// DeleteStorage implements state.Trie, deleting the storage slot in the overlay.
+// The slot is overwritten with zero, shadowing the value in the merkle state.
+func (t *TransitionTrie) DeleteStorage(addr common.Address, key []byte) error {
+	return t.overlay.DeleteStorage(addr, key)
+}
+
+// UpdateContractCode implements state.Trie, writing the code into the overlay.
+func (t *TransitionTrie) UpdateContractCode(addr common.Address, codeHash common.Hash, code []byte) error {
+	return t.overlay.UpdateContractCode(addr, codeHash, code)
+}
+
+// ConvertAccount copies the account header and the contract code from the merkle
+// state into the overlay, unless the account is present in the overlay already.
+// It returns whether the account has been written.
+func (t *TransitionTrie) ConvertAccount(addr common.Address, acc *types.StateAccount, code []byte) (bool, error) {
+	ok, err := t.overlayAccount(addr)
+	if err != nil || ok {
+		return false, err
+	}
+	if err := t.overlay.UpdateAccount(addr, acc, len(code)); err != nil {
+		return false, err
+	}
+	if len(code) > 0 {
+		if err := t.overlay.UpdateContractCode(addr, common.BytesToHash(acc.CodeHash), code); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// ConvertStorage copies the storage slot from the merkle state into the overlay,
+// unless the slot is present in the overlay already. It returns whether the slot
+// has been written.
+func (t *TransitionTrie) ConvertStorage(addr common.Address, key []byte, value []byte) (bool, error) {
+	val, err := t.overlayStorage(addr, key)
+	if err != nil || val != nil {
+		return false, err
+	}
+	if err := t.overlay.UpdateStorage(addr, key, value); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Hash returns the root hash of the overlay, which is the root of the state.
+func (t *TransitionTrie) Hash() common.Hash {
+	return t.overlay.Hash()
+}
+
+// Commit writes the dirty nodes of the overlay, the merkle state is read-only.
+func (t *TransitionTrie) Commit(collectLeaf bool) (common.Hash, *trienode.NodeSet) {
+	return t.overlay.Commit(collectLeaf)
+}
+
+// NodeIterator implements state.Trie. Iterating the union of the two states is
+// not supported.
+func (t *TransitionTrie) NodeIterator(startKey []byte) (NodeIterator, error) {
+	return nil, errors.New("not implemented")
+}
+
+// Prove implements state.Trie. Proving the union of the two states is not
+// supported.
+func (t *TransitionTrie) Prove(key []byte, proofDb ethdb.KeyValueWriter) error {
+	return errors.New("not implemented")
+}
+
+// Witness implements state.Trie. Witness collection is not supported during the
+// transition.
+func (t *TransitionTrie) Witness() map[string]struct{} {
+	panic("not implemented")
+}
+
+// IsVerkle indicates that the state is hashed as a verkle tree.
+func (t *TransitionTrie) IsVerkle() bool {
+	return true
+}
+
+// Copy returns a deep-copied transition tree.
+func (t *TransitionTrie) Copy() *TransitionTrie {
+	storages := make(map[common.Address]*StateTrie, len(t.storages))
+	for addr, tr := range t.storages {
+		if tr != nil {
+			tr = tr.Copy()
+		}
+		storages[addr] = tr
+	}
+	return &TransitionTrie{
+		overlay:  t.overlay.Copy(),
+		base:     t.base.Copy(),
+		baseRoot: t.baseRoot,
+		baseDB:   t.baseDB,
+		storages: storages,
+	}
+}
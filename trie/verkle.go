@@ -19,9 +19,12 @@ package trie
 import (
 	"bytes"
 	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 
+	"github.com/crate-crypto/go-ipa/banderwagon"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethdb"
@@ -70,7 +73,7 @@ func NewVerkleTrie(root common.Hash, db database.NodeDatabase, cache *utils.Poin
 }
 
 func (t *VerkleTrie) FlatdbNodeResolver(path []byte) ([]byte, error) {
-	return t.reader.node(path, common.Hash{})
+	return t.nodeResolver(path)
 }
 
 // GetKey returns the sha3 preimage of a hashed key that was previously used
@@ -282,17 +285,80 @@ func (t *VerkleTrie) NodeIterator(startKey []byte) (NodeIterator, error) {
 	panic("not implemented")
 }
 
-// Prove implements state.Trie, constructing a Merkle proof for key. The result
-// contains all encoded nodes on the path to the value at key. The value itself
-// is also included in the last node and can be retrieved by verifying the proof.
-//
-// If the trie does not contain a value for key, the returned proof contains all
-// nodes of the longest existing prefix of the key (at least the root), ending
-// with the node that proves the absence of the key.
-//
-// TODO(gballet, rjl493456442) implement it.
+// Prove implements state.Trie, constructing a verkle proof for the tree key.
+// Unlike the Merkle proofs consisting of the nodes on the path, the proof is a
+// single JSON-encoded VerkleProof stored in proofDb with the tree key, carrying
+// the value of the key or proving its absence. It can be checked with
+// VerifyVerkleProof.
 func (t *VerkleTrie) Prove(key []byte, proofDb ethdb.KeyValueWriter) error {
-	panic("not implemented")
+	proof, err := t.ProveKeys([][]byte{key})
+	if err != nil {
+		return err
+	}
+	blob, err := json.Marshal(proof)
+	if err != nil {
+		return err
+	}
+	return proofDb.Put(key, blob)
+}
+
+// VerkleProof is a verkle multiproof, along with the state diff carrying the
+// values of the proven tree keys.
+type VerkleProof struct {
+	Proof     *verkle.VerkleProof `json:"proof"`
+	StateDiff verkle.StateDiff    `json:"stateDiff"`
+}
+
+// ProveKeys constructs a verkle multiproof for the given tree keys against the
+// current tree. The absent keys are proven as well, without a current value in
+// the returned state diff.
+func (t *VerkleTrie) ProveKeys(keys [][]byte) (*VerkleProof, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("no key provided for proof")
+	}
+	// The keys are sorted in place by the proof generation, keep the original
+	// order intact.
+	keys = slices.Clone(keys)
+
+	// Make sure the commitments are computed before collecting them.
+	t.root.Commit()
+	proof, diff, err := t.Proof(nil, keys)
+	if err != nil {
+		return nil, err
+	}
+	return &VerkleProof{Proof: proof, StateDiff: diff}, nil
+}
+
+// VerifyVerkleProof verifies the verkle multiproof against the given root. The
+// proven values are returned keyed by the tree keys, with the absent keys having
+// a nil value.
+func VerifyVerkleProof(root common.Hash, proof *VerkleProof) (map[common.Hash][]byte, error) {
+	if proof == nil || proof.Proof == nil {
+		return nil, errors.New("empty verkle proof")
+	}
+	values := make(map[common.Hash][]byte)
+	for _, stemDiff := range proof.StateDiff {
+		for _, suffixDiff := range stemDiff.SuffixDiffs {
+			if suffixDiff.NewValue != nil {
+				return nil, fmt.Errorf("unexpected post-state value in proof, stem %x, suffix %d", stemDiff.Stem, suffixDiff.Suffix)
+			}
+			var key common.Hash
+			copy(key[:], stemDiff.Stem[:])
+			key[verkle.StemSize] = suffixDiff.Suffix
+
+			var value []byte
+			if suffixDiff.CurrentValue != nil {
+				value = common.CopyBytes(suffixDiff.CurrentValue[:])
+			}
+			values[key] = value
+		}
+	}
+	// Without the post-state values, the post-state tree regenerated from the
+	// proof must match the pre-state root as well.
+	if err := verkle.Verify(proof.Proof, root[:], root[:], proof.StateDiff); err != nil {
+		return nil, err
+	}
+	return values, nil
 }
 
 // Copy returns a deep-copied verkle tree.
@@ -421,7 +487,73 @@ func (t *VerkleTrie) ToDot() string {
 }
 
 func (t *VerkleTrie) nodeResolver(path []byte) ([]byte, error) {
-	return t.reader.node(path, common.Hash{})
+	blob, err := t.reader.node(path, common.Hash{})
+	if err != nil {
+		return nil, err
+	}
+	return expandLeafNode(blob), nil
+}
+
+// Encoding of the verkle leaf nodes, see go-verkle/encoding.go.
+const (
+	verkleLeafType       = 2
+	verkleEoAccountType  = 3
+	verkleSingleSlotType = 4
+
+	verkleStemOffset     = 1
+	verkleBitlistSize    = verkle.NodeWidth / 8
+	verklePointSize      = banderwagon.UncompressedSize
+	verkleCompactOffset  = verkleStemOffset + verkle.StemSize // Commitments of the compact leaves
+	verkleBitlistOffset  = verkleStemOffset + verkle.StemSize // Bitlist of the full leaves
+	verkleChildrenOffset = verkleBitlistOffset + verkleBitlistSize + 3*verklePointSize
+)
+
+// verkleIdentity is the serialized commitment of an empty vector, captured at
+// startup before any node can alias it.
+var verkleIdentity = banderwagon.Identity.BytesUncompressedTrusted()
+
+// expandLeafNode converts the compact encodings of the leaf nodes into the full
+// leaf encoding, leaving any other node untouched.
+//
+// go-verkle decodes the commitment missing from a compact leaf as a pointer to
+// the shared identity point, which is then updated in place as soon as a value
+// in that half of the leaf is written, corrupting every other empty commitment
+// in the process. The full encoding is decoded into freshly allocated points.
+func expandLeafNode(blob []byte) []byte {
+	if len(blob) == 0 || (blob[0] != verkleEoAccountType && blob[0] != verkleSingleSlotType) {
+		return blob
+	}
+	var (
+		stem   = blob[verkleStemOffset:verkleCompactOffset]
+		cn     = blob[verkleCompactOffset : verkleCompactOffset+verklePointSize]
+		comm   = blob[verkleCompactOffset+verklePointSize : verkleCompactOffset+2*verklePointSize]
+		values = make(map[int][]byte)
+		c1, c2 = cn, verkleIdentity[:]
+	)
+	if blob[0] == verkleEoAccountType {
+		values[0] = blob[verkleCompactOffset+2*verklePointSize:]
+		values[1] = verkle.EmptyCodeHash
+	} else {
+		index := int(blob[verkleCompactOffset+2*verklePointSize])
+		values[index] = blob[verkleCompactOffset+2*verklePointSize+1:]
+		if index >= verkle.NodeWidth/2 {
+			c1, c2 = verkleIdentity[:], cn
+		}
+	}
+	full := make([]byte, verkleChildrenOffset, verkleChildrenOffset+len(values)*verkle.LeafValueSize)
+	full[0] = verkleLeafType
+	copy(full[verkleStemOffset:], stem)
+	for i := 0; i < verkle.NodeWidth; i++ {
+		if value, ok := values[i]; ok {
+			full[verkleBitlistOffset+i/8] |= 0x80 >> (i % 8)
+			full = append(full, common.RightPadBytes(value, verkle.LeafValueSize)...)
+		}
+	}
+	offset := verkleBitlistOffset + verkleBitlistSize
+	copy(full[offset:], comm)
+	copy(full[offset+verklePointSize:], c1)
+	copy(full[offset+2*verklePointSize:], c2)
+	return full
 }
 
 // Witness returns a set containing all trie nodes that have been accessed.
@@ -18,6 +18,7 @@ package trie
 
 import (
 	"bytes"
+	"encoding/json"
 	"reflect"
 	"testing"
 
@@ -26,6 +27,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/trie/utils"
+	"github.com/ethereum/go-verkle"
 	"github.com/holiman/uint256"
 )
 
@@ -171,3 +173,166 @@ func TestVerkleRollBack(t *testing.T) {
 		t.Fatal("account was not deleted")
 	}
 }
+
+func TestVerkleProof(t *testing.T) {
+	db := newTestDatabase(rawdb.NewMemoryDatabase(), rawdb.PathScheme)
+	tr, _ := NewVerkleTrie(types.EmptyVerkleHash, db, utils.NewPointCache(100))
+
+	for addr, acct := range accounts {
+		if err := tr.UpdateAccount(addr, acct, 0); err != nil {
+			t.Fatalf("Failed to update account, %v", err)
+		}
+		for key, val := range storages[addr] {
+			if err := tr.UpdateStorage(addr, key.Bytes(), val); err != nil {
+				t.Fatalf("Failed to update storage, %v", err)
+			}
+		}
+	}
+	root := tr.Hash()
+
+	// Prove the account fields and storage slots, along with an absent account
+	// and an absent slot of an existing account.
+	var keys [][]byte
+	for addr := range accounts {
+		keys = append(keys, utils.BasicDataKey(addr.Bytes()), utils.CodeHashKey(addr.Bytes()))
+		for key := range storages[addr] {
+			keys = append(keys, utils.StorageSlotKey(addr.Bytes(), key.Bytes()))
+		}
+	}
+	absent := [][]byte{
+		utils.BasicDataKey(common.Address{3}.Bytes()),
+		utils.StorageSlotKey(common.Address{1}.Bytes(), common.Hash{12}.Bytes()),
+	}
+	keys = append(keys, absent...)
+
+	proof, err := tr.ProveKeys(keys)
+	if err != nil {
+		t.Fatalf("Failed to prove keys, %v", err)
+	}
+	values, err := VerifyVerkleProof(root, proof)
+	if err != nil {
+		t.Fatalf("Failed to verify proof, %v", err)
+	}
+	if len(values) != len(keys) {
+		t.Fatalf("Proven key count mismatch, have %d, want %d", len(values), len(keys))
+	}
+	for _, key := range keys {
+		want, err := tr.root.Get(key, tr.nodeResolver)
+		if err != nil {
+			t.Fatalf("Failed to read key %x, %v", key, err)
+		}
+		have, ok := values[common.BytesToHash(key)]
+		if !ok {
+			t.Fatalf("Key %x is not proven", key)
+		}
+		if !bytes.Equal(have, want) {
+			t.Fatalf("Proven value mismatch for key %x, have %x, want %x", key, have, want)
+		}
+	}
+	for _, key := range absent {
+		if values[common.BytesToHash(key)] != nil {
+			t.Fatalf("Absent key %x is proven with a value", key)
+		}
+	}
+	// The proof must not verify against another root
+	if _, err := VerifyVerkleProof(types.EmptyVerkleHash, proof); err == nil {
+		t.Fatal("Proof verified against a wrong root")
+	}
+	// The proof must not verify with a tampered value
+	tampered := &VerkleProof{Proof: proof.Proof, StateDiff: proof.StateDiff.Copy()}
+	for i, diff := range tampered.StateDiff[0].SuffixDiffs {
+		if diff.CurrentValue != nil {
+			value := *diff.CurrentValue
+			value[31]++
+			tampered.StateDiff[0].SuffixDiffs[i].CurrentValue = &value
+			break
+		}
+	}
+	if _, err := VerifyVerkleProof(root, tampered); err == nil {
+		t.Fatal("Proof verified with a tampered value")
+	}
+}
+
+func TestVerkleProve(t *testing.T) {
+	db := newTestDatabase(rawdb.NewMemoryDatabase(), rawdb.PathScheme)
+	tr, _ := NewVerkleTrie(types.EmptyVerkleHash, db, utils.NewPointCache(100))
+
+	for addr, acct := range accounts {
+		if err := tr.UpdateAccount(addr, acct, 0); err != nil {
+			t.Fatalf("Failed to update account, %v", err)
+		}
+	}
+	root := tr.Hash()
+
+	key := utils.BasicDataKey(common.Address{1}.Bytes())
+	proofDb := rawdb.NewMemoryDatabase()
+	if err := tr.Prove(key, proofDb); err != nil {
+		t.Fatalf("Failed to prove key, %v", err)
+	}
+	blob, err := proofDb.Get(key)
+	if err != nil {
+		t.Fatalf("Proof is not stored, %v", err)
+	}
+	var proof VerkleProof
+	if err := json.Unmarshal(blob, &proof); err != nil {
+		t.Fatalf("Failed to decode proof, %v", err)
+	}
+	values, err := VerifyVerkleProof(root, &proof)
+	if err != nil {
+		t.Fatalf("Failed to verify proof, %v", err)
+	}
+	stored, err := tr.root.Get(key, tr.nodeResolver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(values[common.BytesToHash(key)], stored) {
+		t.Fatalf("Proven value mismatch, have %x, want %x", values[common.BytesToHash(key)], stored)
+	}
+}
+
+func TestVerkleExpandLeafNode(t *testing.T) {
+	var (
+		root = verkle.New()
+		slot = utils.StorageSlotKey(common.Address{2}.Bytes(), common.Hash{}.Bytes())
+	)
+	root.Insert(utils.BasicDataKey(common.Address{1}.Bytes()), common.Hash{1}.Bytes(), nil)
+	root.Insert(utils.CodeHashKey(common.Address{1}.Bytes()), types.EmptyCodeHash.Bytes(), nil)
+	root.Insert(slot, common.Hash{2}.Bytes(), nil)
+	root.Commit()
+
+	nodes, err := root.(*verkle.InternalNode).BatchSerialize()
+	if err != nil {
+		t.Fatalf("Failed to serialize tree, %v", err)
+	}
+	var compact int
+	for _, node := range nodes {
+		blob := node.SerializedBytes
+		if blob[0] != verkleEoAccountType && blob[0] != verkleSingleSlotType {
+			continue
+		}
+		compact++
+
+		full := expandLeafNode(blob)
+		if full[0] != verkleLeafType {
+			t.Fatalf("Leaf %x is not expanded", blob)
+		}
+		leaf, err := verkle.ParseNode(full, byte(len(node.Path)))
+		if err != nil {
+			t.Fatalf("Failed to parse expanded leaf, %v", err)
+		}
+		// The re-serialized leaf is encoded back in the compact form
+		if enc, _ := leaf.Serialize(); !bytes.Equal(enc, blob) {
+			t.Fatalf("Expanded leaf mismatch, have %x, want %x", enc, blob)
+		}
+		// Updating the missing half of the leaf doesn't alter the empty commitment
+		if err := leaf.Insert(append(leaf.(*verkle.LeafNode).Key(0)[:31:31], 200), common.Hash{3}.Bytes(), nil); err != nil {
+			t.Fatalf("Failed to update leaf, %v", err)
+		}
+		if hash := verkle.New().Commit().Bytes(); hash != types.EmptyVerkleHash {
+			t.Fatalf("Empty commitment is altered, %x", hash)
+		}
+	}
+	if compact != 2 {
+		t.Fatalf("Compact leaf count mismatch, have %d, want 2", compact)
+	}
+}
@@ -33,6 +33,7 @@ import (
 type Config struct {
 	Preimages bool           // Flag whether the preimage of node key is recorded
 	IsVerkle  bool           // Flag whether the db is holding a verkle tree
+	IsBinary  bool           // Flag whether the verkle tree held is replaced by a binary tree (EIP-7864)
 	HashDB    *hashdb.Config // Configs for hash-based scheme
 	PathDB    *pathdb.Config // Configs for experimental path-based scheme
 }
@@ -53,6 +54,15 @@ var VerkleDefaults = &Config{
 	PathDB:    pathdb.Defaults,
 }
 
+// BinaryDefaults represents a config for holding binary trie data
+// using path-based scheme with default settings.
+var BinaryDefaults = &Config{
+	Preimages: false,
+	IsVerkle:  true,
+	IsBinary:  true,
+	PathDB:    pathdb.Defaults,
+}
+
 // backend defines the methods needed to access/update trie nodes in different
 // state scheme.
 type backend interface {
@@ -109,7 +119,9 @@ func NewDatabase(diskdb ethdb.Database, config *Config) *Database {
 		log.Crit("Both 'hash' and 'path' mode are configured")
 	}
 	if config.PathDB != nil {
-		db.backend = pathdb.New(diskdb, config.PathDB, config.IsVerkle)
+		pconfig := *config.PathDB
+		pconfig.IsBinary = config.IsBinary
+		db.backend = pathdb.New(diskdb, &pconfig, config.IsVerkle)
 	} else {
 		db.backend = hashdb.New(diskdb, config.HashDB)
 	}
@@ -243,6 +255,19 @@ func (db *Database) Cap(limit common.StorageSize) error {
 	return hdb.Cap(limit)
 }
 
+// SetWriteHook installs the callback invoked with the hash of every trie node
+// before it's persisted into the disk, or removes it if nil is given.
+//
+// It's only supported by hash-based database and will return an error for others.
+func (db *Database) SetWriteHook(hook func(hash common.Hash)) error {
+	hdb, ok := db.backend.(*hashdb.Database)
+	if !ok {
+		return errors.New("not supported")
+	}
+	hdb.SetWriteHook(hook)
+	return nil
+}
+
 // Reference adds a new reference from a parent node to a child node. This function
 // is used to add reference between internal trie node and external node(e.g. storage
 // trie root), all internal trie nodes are referenced together by database itself.
@@ -371,6 +396,22 @@ func (db *Database) IsVerkle() bool {
 	return db.config.IsVerkle
 }
 
+// IsBinary returns the indicator if the database is holding a binary tree in
+// place of the verkle tree.
+func (db *Database) IsBinary() bool {
+	return db.config.IsVerkle && db.config.IsBinary
+}
+
+// Checkpoint creates a consistent point-in-time copy of the disk database in the
+// given directory, which must not exist yet. The state histories are included
+// if the path-based scheme is used.
+func (db *Database) Checkpoint(dir string) error {
+	if pdb, ok := db.backend.(*pathdb.Database); ok {
+		return pdb.Checkpoint(dir)
+	}
+	return rawdb.Checkpoint(db.disk, dir, nil)
+}
+
 // Disk returns the underlying disk database.
 func (db *Database) Disk() ethdb.Database {
 	return db.disk
@@ -95,6 +95,8 @@ type Database struct {
 	dirtiesSize  common.StorageSize // Storage size of the dirty node cache (exc. metadata)
 	childrenSize common.StorageSize // Storage size of the external children tracking
 
+	writeHook func(hash common.Hash) // Callback invoked before persisting a node, nil if not set
+
 	lock sync.RWMutex
 }
 
@@ -318,6 +320,16 @@ func (db *Database) dereference(hash common.Hash) {
 	}
 }
 
+// SetWriteHook installs the callback invoked with the hash of every trie node
+// before it's persisted into the disk, or removes it if nil is given. The hook
+// is invoked with the database lock held, it must not call back into it.
+func (db *Database) SetWriteHook(hook func(hash common.Hash)) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	db.writeHook = hook
+}
+
 // Cap iteratively flushes old but still referenced trie nodes until the total
 // memory usage goes below the given threshold.
 func (db *Database) Cap(limit common.StorageSize) error {
@@ -342,6 +354,9 @@ func (db *Database) Cap(limit common.StorageSize) error {
 	for size > limit && oldest != (common.Hash{}) {
 		// Fetch the oldest referenced node and push into the batch
 		node := db.dirties[oldest]
+		if db.writeHook != nil {
+			db.writeHook(oldest)
+		}
 		rawdb.WriteLegacyTrieNode(batch, oldest, node.node)
 
 		// If we exceeded the ideal batch size, commit and reset
@@ -464,6 +479,9 @@ func (db *Database) commit(hash common.Hash, batch ethdb.Batch, uncacher *cleane
 	if err != nil {
 		return err
 	}
+	if db.writeHook != nil {
+		db.writeHook(hash)
+	}
 	// If we've reached an optimal batch size, commit and start over
 	rawdb.WriteLegacyTrieNode(batch, hash, node.node)
 	if batch.ValueSize() >= ethdb.IdealBatchSize {
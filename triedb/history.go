@@ -70,3 +70,43 @@ func (db *Database) HistoryRange() (uint64, uint64, error) {
 	}
 	return pdb.HistoryRange()
 }
+
+// AccountChanges lists the mutations of the account within the block range
+// [from, to] using the state history index, at most limit of them. Whether more
+// mutations are available in the range is also returned.
+//
+// This function is only supported by path mode database.
+func (db *Database) AccountChanges(address common.Address, from, to uint64, limit int) ([]pathdb.StateChange, bool, error) {
+	pdb, ok := db.backend.(*pathdb.Database)
+	if !ok {
+		return nil, false, errors.New("not supported")
+	}
+	return pdb.AccountChanges(address, from, to, limit)
+}
+
+// StorageChanges lists the mutations of the storage slot within the block range
+// [from, to] using the state history index, at most limit of them. Whether more
+// mutations are available in the range is also returned.
+//
+// Note, slot refers to the raw slot key.
+//
+// This function is only supported by path mode database.
+func (db *Database) StorageChanges(address common.Address, slot common.Hash, from, to uint64, limit int) ([]pathdb.StateChange, bool, error) {
+	pdb, ok := db.backend.(*pathdb.Database)
+	if !ok {
+		return nil, false, errors.New("not supported")
+	}
+	return pdb.StorageChanges(address, slot, from, to, limit)
+}
+
+// StorageGrowth inspects the storage slots created and deleted by every contract
+// within the block range [from, to] using the state histories.
+//
+// This function is only supported by path mode database.
+func (db *Database) StorageGrowth(from, to uint64) (*pathdb.StorageGrowthStats, error) {
+	pdb, ok := db.backend.(*pathdb.Database)
+	if !ok {
+		return nil, errors.New("not supported")
+	}
+	return pdb.StorageGrowth(from, to)
+}
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"path/filepath"
 	"sync"
 	"time"
@@ -32,6 +33,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/trie/bintrie"
 	"github.com/ethereum/go-ethereum/trie/trienode"
 	"github.com/ethereum/go-verkle"
 )
@@ -122,6 +124,7 @@ type Config struct {
 	WriteBufferSize     int    // Maximum memory allowance (in bytes) for write buffer
 	ReadOnly            bool   // Flag whether the database is opened in read only mode
 	JournalDirectory    string // Absolute path of journal directory (null means the journal data is persisted in key-value store)
+	IsBinary            bool   // Flag whether the unified tree is a binary tree (EIP-7864) instead of verkle
 
 	// Testing configurations
 	SnapshotNoBuild   bool // Flag Whether the state generation is allowed
@@ -198,6 +201,15 @@ func verkleNodeHasher(blob []byte) (common.Hash, error) {
 	return n.Commit().Bytes(), nil
 }
 
+// binaryNodeHasher computes the hash of the given binary tree node.
+func binaryNodeHasher(blob []byte) (common.Hash, error) {
+	n, err := bintrie.DeserializeNode(blob, 0)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return n.Hash(), nil
+}
+
 // Database is a multiple-layered structure for maintaining in-memory states
 // along with its dirty trie nodes. It consists of one persistent base layer
 // backed by a key-value store, on top of which arbitrarily many in-memory diff
@@ -251,6 +263,9 @@ func New(diskdb ethdb.Database, config *Config, isVerkle bool) *Database {
 	if isVerkle {
 		db.diskdb = rawdb.NewTable(diskdb, string(rawdb.VerklePrefix))
 		db.hasher = verkleNodeHasher
+		if config.IsBinary {
+			db.hasher = binaryNodeHasher
+		}
 	}
 	// Construct the layer tree by resolving the in-disk singleton state
 	// and in-memory layer journal.
@@ -282,6 +297,9 @@ func New(diskdb ethdb.Database, config *Config, isVerkle bool) *Database {
 	fields := config.fields()
 	if db.isVerkle {
 		fields = append(fields, "verkle", true)
+		if db.config.IsBinary {
+			fields = append(fields, "binary", true)
+		}
 	}
 	log.Info("Initialized path database", fields...)
 	return db
@@ -656,6 +674,43 @@ func (db *Database) Close() error {
 	return db.freezer.Close()
 }
 
+// Checkpoint creates a consistent point-in-time copy of the disk database in the
+// given directory, along with the state histories placed in the matching sub
+// folder of the ancient directory. The mutations of the state histories are
+// blocked until the disk database is captured, so that the persisted state is
+// always covered by the copied histories.
+//
+// The layers cached in memory are not part of the checkpoint, the restored
+// database starts from the persisted disk layer.
+func (db *Database) Checkpoint(dir string) error {
+	// Wait for the background flushing of the disk layer, ensuring the state
+	// committed so far is covered by the checkpoint.
+	if err := db.tree.bottom().waitFlush(); err != nil {
+		return err
+	}
+	if db.freezer == nil {
+		return rawdb.Checkpoint(db.diskdb, dir, nil)
+	}
+	cp, err := rawdb.CaptureFreezer(db.freezer)
+	if err != nil {
+		return err
+	}
+	defer cp.Release()
+
+	unblock := func() error {
+		cp.Unblock()
+		return nil
+	}
+	if err := rawdb.Checkpoint(db.diskdb, dir, unblock); err != nil {
+		return err
+	}
+	name := rawdb.MerkleStateFreezerName
+	if db.isVerkle {
+		name = rawdb.VerkleStateFreezerName
+	}
+	return cp.Write(filepath.Join(dir, "ancient", name))
+}
+
 // Size returns the current storage size of the memory cache in front of the
 // persistent database layer.
 func (db *Database) Size() (diffs common.StorageSize, nodes common.StorageSize) {
@@ -726,6 +781,131 @@ func (db *Database) HistoryRange() (uint64, uint64, error) {
 	return historyRange(db.freezer)
 }
 
+// AccountChanges lists the mutations of the account within the block range
+// [from, to] using the state history index, at most limit of them. The account
+// values are in the slim RLP format. Whether more mutations are available in
+// the range is also returned.
+//
+// Note, only the mutations persisted in the disk layer are covered, the ones in
+// the recent blocks held by the diff layers are not included.
+func (db *Database) AccountChanges(address common.Address, from, to uint64, limit int) ([]StateChange, bool, error) {
+	hash := crypto.Keccak256Hash(address.Bytes())
+	return db.stateChanges(newAccountIdentQuery(address, hash), from, to, limit, func(dl *diskLayer) ([]byte, error) {
+		return dl.account(hash, 0)
+	})
+}
+
+// StorageChanges lists the mutations of the storage slot within the block range
+// [from, to] using the state history index, at most limit of them. The slot
+// values are RLP-encoded. Whether more mutations are available in the range is
+// also returned.
+//
+// Note, slot refers to the raw slot key. Only the mutations persisted in the disk
+// layer are covered, the ones in the recent blocks held by the diff layers are
+// not included.
+func (db *Database) StorageChanges(address common.Address, slot common.Hash, from, to uint64, limit int) ([]StateChange, bool, error) {
+	var (
+		addrHash = crypto.Keccak256Hash(address.Bytes())
+		slotHash = crypto.Keccak256Hash(slot.Bytes())
+	)
+	return db.stateChanges(newStorageIdentQuery(address, addrHash, slot, slotHash), from, to, limit, func(dl *diskLayer) ([]byte, error) {
+		return dl.storage(addrHash, slotHash, 0)
+	})
+}
+
+// stateChanges lists the mutations of the state element within the block range,
+// resolving the value after the last mutation with the supplied function if the
+// element is not mutated afterwards.
+func (db *Database) stateChanges(state stateIdentQuery, from, to uint64, limit int, latest func(*diskLayer) ([]byte, error)) ([]StateChange, bool, error) {
+	if db.indexer == nil || !db.indexer.inited() {
+		return nil, false, errors.New("state histories haven't been fully indexed yet")
+	}
+	if db.freezer == nil {
+		return nil, false, errors.New("state histories are not available")
+	}
+	if limit <= 0 || from > to {
+		return nil, false, nil
+	}
+	tail, err := db.freezer.Tail()
+	if err != nil {
+		return nil, false, err
+	}
+	ancients, err := db.freezer.Ancients()
+	if err != nil {
+		return nil, false, err
+	}
+	dl := db.tree.bottom()
+	head := min(ancients, dl.stateID())
+	if head <= tail {
+		return nil, false, nil
+	}
+	first, err := historyIDByBlock(db.freezer, tail, head, from)
+	if err != nil {
+		return nil, false, err
+	}
+	last := head
+	if to != math.MaxUint64 {
+		end, err := historyIDByBlock(db.freezer, tail, head, to+1)
+		if err != nil {
+			return nil, false, err
+		}
+		last = end - 1
+	}
+	if first > last {
+		return nil, false, nil
+	}
+	value, err := latest(dl)
+	if err != nil {
+		return nil, false, err
+	}
+	return newHistoryReader(db.diskdb, db.freezer).changes(state, first, last, limit, dl.stateID(), value)
+}
+
+// StorageGrowth inspects the storage slots created and deleted by every contract
+// within the block range [from, to] using the state histories.
+//
+// Only the mutations persisted in the disk layer are covered, the ones in the
+// recent blocks held by the diff layers are not included.
+func (db *Database) StorageGrowth(from, to uint64) (*StorageGrowthStats, error) {
+	if db.freezer == nil {
+		return nil, errors.New("state histories are not available")
+	}
+	if from > to {
+		return nil, fmt.Errorf("invalid block range, from: %d, to: %d", from, to)
+	}
+	tail, err := db.freezer.Tail()
+	if err != nil {
+		return nil, err
+	}
+	ancients, err := db.freezer.Ancients()
+	if err != nil {
+		return nil, err
+	}
+	dl := db.tree.bottom()
+	head := min(ancients, dl.stateID())
+	if head <= tail {
+		return nil, errors.New("no state history available")
+	}
+	first, err := historyIDByBlock(db.freezer, tail, head, from)
+	if err != nil {
+		return nil, err
+	}
+	last := head
+	if to != math.MaxUint64 {
+		end, err := historyIDByBlock(db.freezer, tail, head, to+1)
+		if err != nil {
+			return nil, err
+		}
+		last = end - 1
+	}
+	if first > last {
+		return nil, fmt.Errorf("no state history within block range [#%d-#%d]", from, to)
+	}
+	return storageGrowth(db.freezer, first, last, head, func(addrHash common.Hash, slotHash common.Hash) ([]byte, error) {
+		return dl.storage(addrHash, slotHash, 0)
+	})
+}
+
 // IndexProgress returns the indexing progress made so far. It provides the
 // number of states that remain unindexed.
 func (db *Database) IndexProgress() (uint64, error) {
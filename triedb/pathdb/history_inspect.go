@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
@@ -129,6 +130,106 @@ func storageHistory(freezer ethdb.AncientReader, address common.Address, slot co
 	})
 }
 
+// StorageGrowth contains the number of storage slots of a contract created and
+// deleted within the inspected range.
+type StorageGrowth struct {
+	Created uint64 // Number of slots empty before the range and occupied after it
+	Deleted uint64 // Number of slots occupied before the range and empty after it
+}
+
+// StorageGrowthStats wraps the storage growth inspection statistics.
+type StorageGrowthStats struct {
+	Start     uint64                            // Block number of the first inspected history
+	End       uint64                            // Block number of the last inspected history
+	Contracts map[common.Address]*StorageGrowth // Storage growth of the mutated contracts
+}
+
+// storageGrowth inspects the storage slots created and deleted by the contracts
+// within the history range [first, last]. The value of a slot before the range
+// is the origin of its first mutation within the range, while the value after
+// the range is the origin of its first mutation within (last, head], or the
+// latest value at the disk layer if there is none.
+func storageGrowth(freezer ethdb.AncientReader, first, last, head uint64, latest func(addrHash common.Hash, slotHash common.Hash) ([]byte, error)) (*StorageGrowthStats, error) {
+	var (
+		stats   = &StorageGrowthStats{Contracts: make(map[common.Address]*StorageGrowth)}
+		prev    = make(map[common.Address]map[common.Hash][]byte) // Slot values before the range
+		post    = make(map[common.Address]map[common.Hash][]byte) // Slot values after the range
+		pending int                                               // Number of slots without value after the range
+		init    = time.Now()
+		logged  = time.Now()
+	)
+	for id := first; id <= head && (id <= last || pending > 0); id++ {
+		h, err := readHistory(freezer, id)
+		if err != nil {
+			return nil, err
+		}
+		if id == first {
+			stats.Start = h.meta.block
+		}
+		if id == last {
+			stats.End = h.meta.block
+		}
+		for addr, slots := range h.storages {
+			for key, blob := range slots {
+				// The hash of the slot key is used as the identifier, regardless
+				// of the history version.
+				if h.meta.version != stateHistoryV0 {
+					key = crypto.Keccak256Hash(key.Bytes())
+				}
+				if id <= last {
+					if _, ok := prev[addr]; !ok {
+						prev[addr] = make(map[common.Hash][]byte)
+						post[addr] = make(map[common.Hash][]byte)
+					}
+					if _, ok := prev[addr][key]; !ok {
+						prev[addr][key] = blob
+						pending++
+					}
+					continue
+				}
+				if _, ok := prev[addr][key]; !ok {
+					continue
+				}
+				if _, ok := post[addr][key]; !ok {
+					post[addr][key] = blob
+					pending--
+				}
+			}
+		}
+		if time.Since(logged) > time.Second*8 {
+			logged = time.Now()
+			log.Info("Inspecting storage growth", "checked", id-first+1, "pending", pending, "elapsed", common.PrettyDuration(time.Since(init)))
+		}
+	}
+	for addr, slots := range prev {
+		var (
+			addrHash = crypto.Keccak256Hash(addr.Bytes())
+			growth   = new(StorageGrowth)
+		)
+		for key, before := range slots {
+			after, ok := post[addr][key]
+			if !ok {
+				blob, err := latest(addrHash, key)
+				if err != nil {
+					return nil, err
+				}
+				after = blob
+			}
+			switch {
+			case len(before) == 0 && len(after) != 0:
+				growth.Created++
+			case len(before) != 0 && len(after) == 0:
+				growth.Deleted++
+			}
+		}
+		if growth.Created != 0 || growth.Deleted != 0 {
+			stats.Contracts[addr] = growth
+		}
+	}
+	log.Info("Inspected storage growth", "histories", last-first+1, "contracts", len(stats.Contracts), "elapsed", common.PrettyDuration(time.Since(init)))
+	return stats, nil
+}
+
 // historyRange returns the block number range of local state histories.
 func historyRange(freezer ethdb.AncientReader) (uint64, uint64, error) {
 	// Load the id of the first history object in local store.
@@ -155,3 +256,48 @@ func historyRange(freezer ethdb.AncientReader) (uint64, uint64, error) {
 	}
 	return fh.meta.block, lh.meta.block, nil
 }
+
+// VerifyHistories checks the integrity of the state histories in the freezer.
+// The histories must be contiguous, each one transitioning from the post-state
+// root of its predecessor, with the root->id mapping of every post-state root
+// present. They must also cover the persisted disk state, the histories above
+// it belonging to the journaled layers. The number of verified histories is
+// returned.
+func VerifyHistories(db ethdb.KeyValueReader, freezer ethdb.AncientReader) (uint64, error) {
+	tail, err := freezer.Tail()
+	if err != nil {
+		return 0, err
+	}
+	head, err := freezer.Ancients()
+	if err != nil {
+		return 0, err
+	}
+	if persistent := rawdb.ReadPersistentStateID(db); head < persistent && head != tail {
+		return 0, fmt.Errorf("state histories are behind the persistent state, head: %d, persistent: %d", head, persistent)
+	}
+	var (
+		prev  *meta
+		count uint64
+	)
+	err = checkHistories(freezer, tail+1, head-tail, func(m *meta) error {
+		id := tail + 1 + count
+		if prev != nil {
+			if m.parent != prev.root {
+				return fmt.Errorf("state history %d is not contiguous, parent: %x, previous root: %x", id, m.parent, prev.root)
+			}
+			if m.block <= prev.block {
+				return fmt.Errorf("state history %d is not ordered, block: %d, previous block: %d", id, m.block, prev.block)
+			}
+		}
+		if rawdb.ReadStateID(db, m.root) == nil {
+			return fmt.Errorf("state id of history %d is missing, root: %x", id, m.root)
+		}
+		prev = m
+		count++
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
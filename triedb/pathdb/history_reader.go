@@ -363,8 +363,107 @@ func (r *historyReader) read(state stateIdentQuery, stateID uint64, lastID uint6
 	// that the associated state histories are no longer available due to a rollback.
 	// Such truncation should be captured by the state resolver below, rather than returning
 	// invalid data.
+	return r.readState(state, historyID)
+}
+
+// readState retrieves the state element data from the specified state history.
+func (r *historyReader) readState(state stateIdentQuery, historyID uint64) ([]byte, error) {
 	if state.account {
 		return r.readAccount(state.address, historyID)
 	}
 	return r.readStorage(state.address, state.storageKey, state.storageHash, historyID)
 }
+
+// StateChange represents a mutation of a state element recorded in the state
+// histories, along with the values before and after it. An empty value denotes
+// the state element is not existent.
+type StateChange struct {
+	Block uint64 // Number of the block in which the state element was mutated
+	Prev  []byte // Value of the state element before the mutation
+	Post  []byte // Value of the state element after the mutation
+}
+
+// changes lists the mutations of the state element recorded in the state
+// histories within the id range [first, last], at most limit of them. The value
+// after each mutation is resolved from the history of the subsequent mutation,
+// or is the latest value at the disk layer with lastID if there is none.
+//
+// Whether more mutations are available within the range is also returned.
+func (r *historyReader) changes(state stateIdentQuery, first, last uint64, limit int, lastID uint64, latestValue []byte) ([]StateChange, bool, error) {
+	metadata := loadIndexMetadata(r.disk)
+	if metadata == nil || metadata.Last < lastID {
+		indexed := "null"
+		if metadata != nil {
+			indexed = fmt.Sprintf("%d", metadata.Last)
+		}
+		return nil, false, fmt.Errorf("state history is not fully indexed, requested: %d, indexed: %s", lastID, indexed)
+	}
+	ir, err := newIndexReaderWithLimitTag(r.disk, state.stateIdent, metadata.Last)
+	if err != nil {
+		return nil, false, err
+	}
+	// Locate the histories of the mutations within the range, along with the
+	// history of the subsequent mutation which is MaxUint64 if not found.
+	var (
+		ids  []uint64
+		next = first - 1
+	)
+	for {
+		id, err := ir.readGreaterThan(next, lastID)
+		if err != nil {
+			return nil, false, err
+		}
+		next = id
+		if id > last || len(ids) == limit {
+			break
+		}
+		ids = append(ids, id)
+	}
+	changes := make([]StateChange, len(ids))
+	for i, id := range ids {
+		var m meta
+		if err := m.decode(rawdb.ReadStateHistoryMeta(r.freezer, id)); err != nil {
+			return nil, false, err
+		}
+		prev, err := r.readState(state, id)
+		if err != nil {
+			return nil, false, err
+		}
+		changes[i].Block, changes[i].Prev = m.block, prev
+		if i > 0 {
+			changes[i-1].Post = prev
+		}
+	}
+	if len(changes) > 0 {
+		post := latestValue
+		if next != math.MaxUint64 {
+			if post, err = r.readState(state, next); err != nil {
+				return nil, false, err
+			}
+		}
+		changes[len(changes)-1].Post = post
+	}
+	return changes, next <= last, nil
+}
+
+// historyIDByBlock returns the id of the first state history within the range
+// (tail, head] whose associated block number is not lower than the given one,
+// or head+1 if there is no such history.
+func historyIDByBlock(freezer ethdb.AncientReader, tail, head, number uint64) (uint64, error) {
+	var failure error
+	n := sort.Search(int(head-tail), func(i int) bool {
+		if failure != nil {
+			return true
+		}
+		var m meta
+		if err := m.decode(rawdb.ReadStateHistoryMeta(freezer, tail+1+uint64(i))); err != nil {
+			failure = err
+			return true
+		}
+		return m.block >= number
+	})
+	if failure != nil {
+		return 0, failure
+	}
+	return tail + 1 + uint64(n), nil
+}
@@ -19,11 +19,13 @@ package pathdb
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
 func waitIndexing(db *Database) {
@@ -157,3 +159,180 @@ func testHistoryReader(t *testing.T, historyLimit uint64) {
 		}
 	}
 }
+
+func TestHistoryChanges(t *testing.T) {
+	maxDiffLayers = 4
+	defer func() {
+		maxDiffLayers = 128
+	}()
+	env := newTester(t, 0, false, 64, true, "")
+	defer env.release()
+	waitIndexing(env.db)
+
+	// The state history of block n transitions the state from the root of the
+	// block n-1, to the root of block n.
+	var (
+		blocks = env.db.tree.bottom().stateID() // blocks [0, blocks) are covered
+		states = func(n uint64) common.Hash {
+			if n == 0 {
+				return types.EmptyRootHash
+			}
+			return env.roots[n-1]
+		}
+	)
+	expect := func(value func(root common.Hash) []byte, from, to uint64) []StateChange {
+		var changes []StateChange
+		for n := from; n <= to && n < blocks; n++ {
+			prev, post := value(states(n)), value(env.roots[n])
+			if !bytes.Equal(prev, post) {
+				changes = append(changes, StateChange{Block: n, Prev: prev, Post: post})
+			}
+		}
+		return changes
+	}
+	check := func(have []StateChange, want []StateChange) error {
+		if len(have) != len(want) {
+			return fmt.Errorf("change count mismatch, have %d, want %d", len(have), len(want))
+		}
+		for i := range have {
+			if have[i].Block != want[i].Block || !bytes.Equal(have[i].Prev, want[i].Prev) || !bytes.Equal(have[i].Post, want[i].Post) {
+				return fmt.Errorf("change %d mismatch, have %v, want %v", i, have[i], want[i])
+			}
+		}
+		return nil
+	}
+	// Collect the accounts and slots touched across the covered blocks
+	accounts := make(map[common.Hash]struct{})
+	slots := make(map[common.Hash]map[common.Hash]struct{})
+	for n := uint64(0); n < blocks; n++ {
+		for addrHash := range env.snapAccounts[env.roots[n]] {
+			accounts[addrHash] = struct{}{}
+		}
+		for addrHash, storage := range env.snapStorages[env.roots[n]] {
+			if slots[addrHash] == nil {
+				slots[addrHash] = make(map[common.Hash]struct{})
+			}
+			for slotHash := range storage {
+				slots[addrHash][slotHash] = struct{}{}
+			}
+		}
+	}
+	for addrHash := range accounts {
+		value := func(root common.Hash) []byte { return env.snapAccounts[root][addrHash] }
+		address := env.accountPreimage(addrHash)
+
+		changes, more, err := env.db.AccountChanges(address, 0, math.MaxUint64, 1000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if more {
+			t.Fatal("unexpected pagination")
+		}
+		if err := check(changes, expect(value, 0, math.MaxUint64)); err != nil {
+			t.Fatalf("account %x: %v", address, err)
+		}
+		// Page through a sub-range of blocks with a single change per page
+		var (
+			all  []StateChange
+			from = uint64(10)
+		)
+		for {
+			changes, more, err := env.db.AccountChanges(address, from, 40, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			all = append(all, changes...)
+			if !more {
+				break
+			}
+			from = changes[0].Block + 1
+		}
+		if err := check(all, expect(value, 10, 40)); err != nil {
+			t.Fatalf("account %x, paginated: %v", address, err)
+		}
+	}
+	for addrHash, storage := range slots {
+		for slotHash := range storage {
+			value := func(root common.Hash) []byte { return env.snapStorages[root][addrHash][slotHash] }
+			changes, _, err := env.db.StorageChanges(env.accountPreimage(addrHash), env.hashPreimage(slotHash), 0, math.MaxUint64, 1000)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := check(changes, expect(value, 0, math.MaxUint64)); err != nil {
+				t.Fatalf("slot %x %x: %v", addrHash, slotHash, err)
+			}
+		}
+	}
+}
+
+func TestStorageGrowth(t *testing.T) {
+	maxDiffLayers = 4
+	defer func() {
+		maxDiffLayers = 128
+	}()
+	env := newTester(t, 0, false, 64, false, "")
+	defer env.release()
+
+	var (
+		blocks = env.db.tree.bottom().stateID() // blocks [0, blocks) are covered
+		states = func(n uint64) common.Hash {
+			if n == 0 {
+				return types.EmptyRootHash
+			}
+			return env.roots[n-1]
+		}
+	)
+	for _, r := range [][2]uint64{{0, math.MaxUint64}, {10, 40}, {25, 25}} {
+		from, to := r[0], min(r[1], blocks-1)
+
+		// Compare the slots before and after the range
+		var (
+			want          = make(map[common.Address]*StorageGrowth)
+			before, after = env.snapStorages[states(from)], env.snapStorages[env.roots[to]]
+			keys          = make(map[common.Hash]map[common.Hash]struct{})
+		)
+		for _, set := range []map[common.Hash]map[common.Hash][]byte{before, after} {
+			for addrHash, slots := range set {
+				if keys[addrHash] == nil {
+					keys[addrHash] = make(map[common.Hash]struct{})
+				}
+				for slotHash := range slots {
+					keys[addrHash][slotHash] = struct{}{}
+				}
+			}
+		}
+		for addrHash, slots := range keys {
+			growth := new(StorageGrowth)
+			for slotHash := range slots {
+				prev, post := before[addrHash][slotHash], after[addrHash][slotHash]
+				switch {
+				case len(prev) == 0 && len(post) != 0:
+					growth.Created++
+				case len(prev) != 0 && len(post) == 0:
+					growth.Deleted++
+				}
+			}
+			want[env.accountPreimage(addrHash)] = growth
+		}
+		for address, growth := range want {
+			if growth.Created == 0 && growth.Deleted == 0 {
+				delete(want, address)
+			}
+		}
+		stats, err := env.db.StorageGrowth(r[0], r[1])
+		if err != nil {
+			t.Fatalf("Failed to inspect storage growth: %v", err)
+		}
+		if stats.Start != from || stats.End != to {
+			t.Fatalf("Unexpected range, want [%d-%d], got [%d-%d]", from, to, stats.Start, stats.End)
+		}
+		if len(stats.Contracts) != len(want) {
+			t.Fatalf("Unexpected number of contracts in [%d-%d], want %d, got %d", from, to, len(want), len(stats.Contracts))
+		}
+		for address, growth := range want {
+			if have := stats.Contracts[address]; have == nil || *have != *growth {
+				t.Fatalf("Unexpected growth of %x in [%d-%d], want %v, got %v", address, from, to, growth, have)
+			}
+		}
+	}
+}
@@ -158,6 +158,49 @@ func TestTruncateHeadHistory(t *testing.T) {
 	}
 }
 
+func TestVerifyHistories(t *testing.T) {
+	write := func(hs []*history) (ethdb.Database, ethdb.ResettableAncientStore) {
+		db := rawdb.NewMemoryDatabase()
+		freezer, _ := rawdb.NewStateFreezer(t.TempDir(), false, false)
+		t.Cleanup(func() { freezer.Close() })
+
+		for i := 0; i < len(hs); i++ {
+			accountData, storageData, accountIndex, storageIndex := hs[i].encode()
+			rawdb.WriteStateHistory(freezer, uint64(i+1), hs[i].meta.encode(), accountIndex, storageIndex, accountData, storageData)
+			rawdb.WriteStateID(db, hs[i].meta.root, uint64(i+1))
+		}
+		return db, freezer
+	}
+	hs := makeHistories(10)
+	db, freezer := write(hs)
+	if n, err := VerifyHistories(db, freezer); err != nil || n != 10 {
+		t.Fatalf("Failed to verify histories, verified: %d, err: %v", n, err)
+	}
+	// Histories above the persistent state are valid, not below
+	rawdb.WritePersistentStateID(db, 8)
+	if _, err := VerifyHistories(db, freezer); err != nil {
+		t.Fatalf("Failed to verify histories ahead of the persistent state: %v", err)
+	}
+	rawdb.WritePersistentStateID(db, 11)
+	if _, err := VerifyHistories(db, freezer); err == nil {
+		t.Fatal("Histories behind the persistent state are not detected")
+	}
+	rawdb.WritePersistentStateID(db, 10)
+
+	// Missing state id mappings must be detected
+	rawdb.DeleteStateID(db, hs[4].meta.root)
+	if _, err := VerifyHistories(db, freezer); err == nil {
+		t.Fatal("Missing state id is not detected")
+	}
+	// Non-contiguous histories must be detected
+	hs = makeHistories(10)
+	hs[5].meta.parent = common.Hash{0x1}
+	db, freezer = write(hs)
+	if _, err := VerifyHistories(db, freezer); err == nil {
+		t.Fatal("Non-contiguous histories are not detected")
+	}
+}
+
 func TestTruncateTailHistory(t *testing.T) {
 	var (
 		roots      []common.Hash